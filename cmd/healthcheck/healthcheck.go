@@ -1,8 +1,10 @@
 package healthcheck
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"time"
@@ -75,5 +77,16 @@ func Do(staticConfiguration static.Configuration) (*http.Response, error) {
 
 	path := "/"
 
+	if pingEntryPoint.IsUnixSocket() {
+		client.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", pingEntryPoint.GetAddress())
+			},
+		}
+
+		return client.Head(protocol + "://localhost" + path + "ping")
+	}
+
 	return client.Head(protocol + "://" + pingEntryPoint.GetAddress() + path + "ping")
 }
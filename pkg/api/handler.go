@@ -55,12 +55,30 @@ type Handler struct {
 
 	// runtimeConfiguration is the data set used to create all the data representations exposed by the API.
 	runtimeConfiguration *runtime.Configuration
+
+	cachePurger       CachePurger
+	maintenanceSwitch MaintenanceSwitch
+}
+
+// CachePurger purges the responses cached by the cache middlewares.
+type CachePurger interface {
+	Purge(middlewareName, url, prefix string) (int, bool)
+}
+
+// MaintenanceSwitch toggles the maintenance mode of the maintenance middlewares.
+type MaintenanceSwitch interface {
+	Get(middlewareName string) (bool, bool)
+	Set(middlewareName string, enabled bool) error
+	Reset(middlewareName string) error
 }
 
 // NewBuilder returns a http.Handler builder based on runtime.Configuration.
-func NewBuilder(staticConfig static.Configuration) func(*runtime.Configuration) http.Handler {
+func NewBuilder(staticConfig static.Configuration, cachePurger CachePurger, maintenanceSwitch MaintenanceSwitch) func(*runtime.Configuration) http.Handler {
 	return func(configuration *runtime.Configuration) http.Handler {
-		return New(staticConfig, configuration).createRouter()
+		handler := New(staticConfig, configuration)
+		handler.cachePurger = cachePurger
+		handler.maintenanceSwitch = maintenanceSwitch
+		return handler.createRouter()
 	}
 }
 
@@ -105,6 +123,10 @@ func (h Handler) createRouter() *mux.Router {
 	router.Methods(http.MethodGet).Path("/api/http/services/{serviceID}").HandlerFunc(h.getService)
 	router.Methods(http.MethodGet).Path("/api/http/middlewares").HandlerFunc(h.getMiddlewares)
 	router.Methods(http.MethodGet).Path("/api/http/middlewares/{middlewareID}").HandlerFunc(h.getMiddleware)
+	router.Methods(http.MethodDelete).Path("/api/http/middlewares/{middlewareID}/cache").HandlerFunc(h.purgeMiddlewareCache)
+	router.Methods(http.MethodGet).Path("/api/http/middlewares/{middlewareID}/maintenance").HandlerFunc(h.getMiddlewareMaintenance)
+	router.Methods(http.MethodPut).Path("/api/http/middlewares/{middlewareID}/maintenance").HandlerFunc(h.setMiddlewareMaintenance)
+	router.Methods(http.MethodDelete).Path("/api/http/middlewares/{middlewareID}/maintenance").HandlerFunc(h.resetMiddlewareMaintenance)
 
 	router.Methods(http.MethodGet).Path("/api/tcp/routers").HandlerFunc(h.getTCPRouters)
 	router.Methods(http.MethodGet).Path("/api/tcp/routers/{routerID}").HandlerFunc(h.getTCPRouter)
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/gorilla/mux"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/log"
 )
@@ -29,35 +30,39 @@ func newRouterRepresentation(name string, rt *runtime.RouterInfo) routerRepresen
 
 type serviceRepresentation struct {
 	*runtime.ServiceInfo
-	ServerStatus map[string]string `json:"serverStatus,omitempty"`
-	Name         string            `json:"name,omitempty"`
-	Provider     string            `json:"provider,omitempty"`
-	Type         string            `json:"type,omitempty"`
+	ServerStatus     map[string]string        `json:"serverStatus,omitempty"`
+	MirrorMismatches []runtime.MirrorMismatch `json:"mirrorMismatches,omitempty"`
+	Name             string                   `json:"name,omitempty"`
+	Provider         string                   `json:"provider,omitempty"`
+	Type             string                   `json:"type,omitempty"`
 }
 
 func newServiceRepresentation(name string, si *runtime.ServiceInfo) serviceRepresentation {
 	return serviceRepresentation{
-		ServiceInfo:  si,
-		Name:         name,
-		Provider:     getProviderName(name),
-		ServerStatus: si.GetAllStatus(),
-		Type:         strings.ToLower(extractType(si.Service)),
+		ServiceInfo:      si,
+		Name:             name,
+		Provider:         getProviderName(name),
+		ServerStatus:     si.GetAllStatus(),
+		MirrorMismatches: si.GetMirrorMismatches(),
+		Type:             strings.ToLower(extractType(si.Service)),
 	}
 }
 
 type middlewareRepresentation struct {
 	*runtime.MiddlewareInfo
-	Name     string `json:"name,omitempty"`
-	Provider string `json:"provider,omitempty"`
-	Type     string `json:"type,omitempty"`
+	Name                 string            `json:"name,omitempty"`
+	Provider             string            `json:"provider,omitempty"`
+	Type                 string            `json:"type,omitempty"`
+	CircuitBreakerStates map[string]string `json:"circuitBreakerStates,omitempty"`
 }
 
 func newMiddlewareRepresentation(name string, mi *runtime.MiddlewareInfo) middlewareRepresentation {
 	return middlewareRepresentation{
-		MiddlewareInfo: mi,
-		Name:           name,
-		Provider:       getProviderName(name),
-		Type:           strings.ToLower(extractType(mi.Middleware)),
+		MiddlewareInfo:       mi,
+		Name:                 name,
+		Provider:             getProviderName(name),
+		Type:                 strings.ToLower(extractType(mi.Middleware)),
+		CircuitBreakerStates: mi.GetCircuitBreakerStates(),
 	}
 }
 
@@ -217,6 +222,143 @@ func (h Handler) getMiddleware(rw http.ResponseWriter, request *http.Request) {
 	}
 }
 
+type cachePurgeRepresentation struct {
+	Purged int `json:"purged"`
+}
+
+func (h Handler) purgeMiddlewareCache(rw http.ResponseWriter, request *http.Request) {
+	middlewareID := mux.Vars(request)["middlewareID"]
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	middleware, ok := h.runtimeConfiguration.Middlewares[middlewareID]
+	if !ok {
+		writeError(rw, fmt.Sprintf("middleware not found: %s", middlewareID), http.StatusNotFound)
+		return
+	}
+
+	if middleware.Middleware == nil || middleware.Cache == nil {
+		writeError(rw, fmt.Sprintf("middleware is not a cache middleware: %s", middlewareID), http.StatusBadRequest)
+		return
+	}
+
+	key := request.URL.Query().Get("key")
+	prefix := request.URL.Query().Get("prefix")
+	if key != "" && prefix != "" {
+		writeError(rw, "key and prefix are mutually exclusive", http.StatusBadRequest)
+		return
+	}
+
+	var purged int
+	if h.cachePurger != nil {
+		// A cache middleware without store has not been used by any router yet, so there is nothing to purge.
+		purged, _ = h.cachePurger.Purge(middlewareID, key, prefix)
+	}
+
+	err := json.NewEncoder(rw).Encode(cachePurgeRepresentation{Purged: purged})
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+type maintenanceRepresentation struct {
+	Enabled bool `json:"enabled"`
+}
+
+func (h Handler) getMiddlewareMaintenance(rw http.ResponseWriter, request *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	middlewareID, config, ok := h.maintenanceMiddleware(rw, request)
+	if !ok {
+		return
+	}
+
+	h.writeMaintenance(rw, request, middlewareID, config)
+}
+
+func (h Handler) setMiddlewareMaintenance(rw http.ResponseWriter, request *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	middlewareID, config, ok := h.maintenanceMiddleware(rw, request)
+	if !ok {
+		return
+	}
+
+	var state maintenanceRepresentation
+	if err := json.NewDecoder(request.Body).Decode(&state); err != nil {
+		writeError(rw, fmt.Sprintf("invalid maintenance state: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if h.maintenanceSwitch == nil {
+		writeError(rw, "maintenance mode cannot be toggled", http.StatusNotImplemented)
+		return
+	}
+
+	if err := h.maintenanceSwitch.Set(middlewareID, state.Enabled); err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeMaintenance(rw, request, middlewareID, config)
+}
+
+func (h Handler) resetMiddlewareMaintenance(rw http.ResponseWriter, request *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	middlewareID, config, ok := h.maintenanceMiddleware(rw, request)
+	if !ok {
+		return
+	}
+
+	if h.maintenanceSwitch != nil {
+		if err := h.maintenanceSwitch.Reset(middlewareID); err != nil {
+			log.FromContext(request.Context()).Error(err)
+			writeError(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	h.writeMaintenance(rw, request, middlewareID, config)
+}
+
+// maintenanceMiddleware returns the maintenance middleware of the request, or writes an error if there is none.
+func (h Handler) maintenanceMiddleware(rw http.ResponseWriter, request *http.Request) (string, *dynamic.Maintenance, bool) {
+	middlewareID := mux.Vars(request)["middlewareID"]
+
+	middleware, ok := h.runtimeConfiguration.Middlewares[middlewareID]
+	if !ok {
+		writeError(rw, fmt.Sprintf("middleware not found: %s", middlewareID), http.StatusNotFound)
+		return "", nil, false
+	}
+
+	if middleware.Middleware == nil || middleware.Maintenance == nil {
+		writeError(rw, fmt.Sprintf("middleware is not a maintenance middleware: %s", middlewareID), http.StatusBadRequest)
+		return "", nil, false
+	}
+
+	return middlewareID, middleware.Maintenance, true
+}
+
+// writeMaintenance writes the effective maintenance state of the middleware,
+// that is the one set through the API, if any, or the configured one.
+func (h Handler) writeMaintenance(rw http.ResponseWriter, request *http.Request, middlewareID string, config *dynamic.Maintenance) {
+	enabled := config.Enabled
+	if h.maintenanceSwitch != nil {
+		if state, ok := h.maintenanceSwitch.Get(middlewareID); ok {
+			enabled = state
+		}
+	}
+
+	err := json.NewEncoder(rw).Encode(maintenanceRepresentation{Enabled: enabled})
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
 func keepRouter(name string, item *runtime.RouterInfo, criterion *searchCriterion) bool {
 	if criterion == nil {
 		return true
@@ -9,6 +9,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -865,3 +866,237 @@ func generateHTTPRouters(nbRouters int) map[string]*runtime.RouterInfo {
 	}
 	return routers
 }
+
+type purgeCall struct {
+	middleware string
+	url        string
+	prefix     string
+}
+
+type fakeCachePurger struct {
+	calls []purgeCall
+}
+
+func (f *fakeCachePurger) Purge(middlewareName, url, prefix string) (int, bool) {
+	f.calls = append(f.calls, purgeCall{middleware: middlewareName, url: url, prefix: prefix})
+	return 2, true
+}
+
+func TestHandler_PurgeMiddlewareCache(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		path               string
+		expectedStatusCode int
+		expectedCalls      []purgeCall
+	}{
+		{
+			desc:               "purge by key",
+			path:               "/api/http/middlewares/cache@myprovider/cache?key=http://foo.bar/products",
+			expectedStatusCode: http.StatusOK,
+			expectedCalls:      []purgeCall{{middleware: "cache@myprovider", url: "http://foo.bar/products"}},
+		},
+		{
+			desc:               "purge by prefix",
+			path:               "/api/http/middlewares/cache@myprovider/cache?prefix=http://foo.bar/",
+			expectedStatusCode: http.StatusOK,
+			expectedCalls:      []purgeCall{{middleware: "cache@myprovider", prefix: "http://foo.bar/"}},
+		},
+		{
+			desc:               "purge all",
+			path:               "/api/http/middlewares/cache@myprovider/cache",
+			expectedStatusCode: http.StatusOK,
+			expectedCalls:      []purgeCall{{middleware: "cache@myprovider"}},
+		},
+		{
+			desc:               "key and prefix",
+			path:               "/api/http/middlewares/cache@myprovider/cache?key=http://foo.bar/products&prefix=http://foo.bar/",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			desc:               "not a cache middleware",
+			path:               "/api/http/middlewares/addPrefix@myprovider/cache",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			desc:               "unknown middleware",
+			path:               "/api/http/middlewares/foo@myprovider/cache",
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			rtConf := &runtime.Configuration{
+				Middlewares: map[string]*runtime.MiddlewareInfo{
+					"cache@myprovider": {
+						Middleware: &dynamic.Middleware{Cache: &dynamic.Cache{}},
+					},
+					"addPrefix@myprovider": {
+						Middleware: &dynamic.Middleware{AddPrefix: &dynamic.AddPrefix{Prefix: "/toto"}},
+					},
+				},
+			}
+
+			purger := &fakeCachePurger{}
+			handler := NewBuilder(static.Configuration{API: &static.API{}, Global: &static.Global{}}, purger, nil)(rtConf)
+			server := httptest.NewServer(handler)
+			t.Cleanup(server.Close)
+
+			req, err := http.NewRequest(http.MethodDelete, server.URL+test.path, nil)
+			require.NoError(t, err)
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedStatusCode, resp.StatusCode)
+			assert.Equal(t, test.expectedCalls, purger.calls)
+
+			if test.expectedStatusCode != http.StatusOK {
+				return
+			}
+
+			var result cachePurgeRepresentation
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+			assert.Equal(t, 2, result.Purged)
+		})
+	}
+}
+
+type fakeMaintenanceSwitch struct {
+	states map[string]bool
+}
+
+func (f *fakeMaintenanceSwitch) Get(middlewareName string) (bool, bool) {
+	enabled, ok := f.states[middlewareName]
+	return enabled, ok
+}
+
+func (f *fakeMaintenanceSwitch) Set(middlewareName string, enabled bool) error {
+	f.states[middlewareName] = enabled
+	return nil
+}
+
+func (f *fakeMaintenanceSwitch) Reset(middlewareName string) error {
+	delete(f.states, middlewareName)
+	return nil
+}
+
+func TestHandler_MiddlewareMaintenance(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		method             string
+		path               string
+		body               string
+		states             map[string]bool
+		expectedStatusCode int
+		expectedEnabled    bool
+		expectedStates     map[string]bool
+	}{
+		{
+			desc:               "get configured state",
+			method:             http.MethodGet,
+			path:               "/api/http/middlewares/maintenance@myprovider/maintenance",
+			states:             map[string]bool{},
+			expectedStatusCode: http.StatusOK,
+			expectedEnabled:    true,
+			expectedStates:     map[string]bool{},
+		},
+		{
+			desc:               "get toggled state",
+			method:             http.MethodGet,
+			path:               "/api/http/middlewares/maintenance@myprovider/maintenance",
+			states:             map[string]bool{"maintenance@myprovider": false},
+			expectedStatusCode: http.StatusOK,
+			expectedEnabled:    false,
+			expectedStates:     map[string]bool{"maintenance@myprovider": false},
+		},
+		{
+			desc:               "disable",
+			method:             http.MethodPut,
+			path:               "/api/http/middlewares/maintenance@myprovider/maintenance",
+			body:               `{"enabled":false}`,
+			states:             map[string]bool{},
+			expectedStatusCode: http.StatusOK,
+			expectedEnabled:    false,
+			expectedStates:     map[string]bool{"maintenance@myprovider": false},
+		},
+		{
+			desc:               "invalid state",
+			method:             http.MethodPut,
+			path:               "/api/http/middlewares/maintenance@myprovider/maintenance",
+			body:               `{"enabled":`,
+			states:             map[string]bool{},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedStates:     map[string]bool{},
+		},
+		{
+			desc:               "reset",
+			method:             http.MethodDelete,
+			path:               "/api/http/middlewares/maintenance@myprovider/maintenance",
+			states:             map[string]bool{"maintenance@myprovider": false},
+			expectedStatusCode: http.StatusOK,
+			expectedEnabled:    true,
+			expectedStates:     map[string]bool{},
+		},
+		{
+			desc:               "not a maintenance middleware",
+			method:             http.MethodPut,
+			path:               "/api/http/middlewares/addPrefix@myprovider/maintenance",
+			body:               `{"enabled":true}`,
+			states:             map[string]bool{},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedStates:     map[string]bool{},
+		},
+		{
+			desc:               "unknown middleware",
+			method:             http.MethodGet,
+			path:               "/api/http/middlewares/foo@myprovider/maintenance",
+			states:             map[string]bool{},
+			expectedStatusCode: http.StatusNotFound,
+			expectedStates:     map[string]bool{},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			rtConf := &runtime.Configuration{
+				Middlewares: map[string]*runtime.MiddlewareInfo{
+					"maintenance@myprovider": {
+						Middleware: &dynamic.Middleware{Maintenance: &dynamic.Maintenance{Enabled: true}},
+					},
+					"addPrefix@myprovider": {
+						Middleware: &dynamic.Middleware{AddPrefix: &dynamic.AddPrefix{Prefix: "/toto"}},
+					},
+				},
+			}
+
+			maintenanceSwitch := &fakeMaintenanceSwitch{states: test.states}
+			handler := NewBuilder(static.Configuration{API: &static.API{}, Global: &static.Global{}}, nil, maintenanceSwitch)(rtConf)
+			server := httptest.NewServer(handler)
+			t.Cleanup(server.Close)
+
+			req, err := http.NewRequest(test.method, server.URL+test.path, strings.NewReader(test.body))
+			require.NoError(t, err)
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedStatusCode, resp.StatusCode)
+			assert.Equal(t, test.expectedStates, maintenanceSwitch.states)
+
+			if test.expectedStatusCode != http.StatusOK {
+				return
+			}
+
+			var result maintenanceRepresentation
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+			assert.Equal(t, test.expectedEnabled, result.Enabled)
+		})
+	}
+}
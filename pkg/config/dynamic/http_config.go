@@ -35,6 +35,7 @@ type Service struct {
 	LoadBalancer *ServersLoadBalancer `json:"loadBalancer,omitempty" toml:"loadBalancer,omitempty" yaml:"loadBalancer,omitempty" export:"true"`
 	Weighted     *WeightedRoundRobin  `json:"weighted,omitempty" toml:"weighted,omitempty" yaml:"weighted,omitempty" label:"-" export:"true"`
 	Mirroring    *Mirroring           `json:"mirroring,omitempty" toml:"mirroring,omitempty" yaml:"mirroring,omitempty" label:"-" export:"true"`
+	Failover     *Failover            `json:"failover,omitempty" toml:"failover,omitempty" yaml:"failover,omitempty" label:"-" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -65,6 +66,8 @@ type Mirroring struct {
 	Service     string          `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
 	MaxBodySize *int64          `json:"maxBodySize,omitempty" toml:"maxBodySize,omitempty" yaml:"maxBodySize,omitempty" export:"true"`
 	Mirrors     []MirrorService `json:"mirrors,omitempty" toml:"mirrors,omitempty" yaml:"mirrors,omitempty" export:"true"`
+	// Compare enables the comparison of the responses of the mirrors with the response of the service.
+	Compare *MirroringCompare `json:"compare,omitempty" toml:"compare,omitempty" yaml:"compare,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 }
 
 // SetDefaults Default values for a WRRService.
@@ -75,18 +78,96 @@ func (m *Mirroring) SetDefaults() {
 
 // +k8s:deepcopy-gen=true
 
+// MirroringCompare holds the configuration of the comparison of the responses of the mirrors with the response of the service.
+type MirroringCompare struct {
+	// IgnoredHeaders is the list of the response headers which are not compared.
+	IgnoredHeaders []string `json:"ignoredHeaders,omitempty" toml:"ignoredHeaders,omitempty" yaml:"ignoredHeaders,omitempty" export:"true"`
+	// IgnoredJSONFields is the list of the fields of the JSON response bodies which are not compared, as dot-separated paths.
+	IgnoredJSONFields []string `json:"ignoredJSONFields,omitempty" toml:"ignoredJSONFields,omitempty" yaml:"ignoredJSONFields,omitempty" export:"true"`
+	// MaxBodySize is the maximum size in bytes of the JSON response bodies for which fields are ignored.
+	// Larger bodies are compared as is.
+	MaxBodySize *int64 `json:"maxBodySize,omitempty" toml:"maxBodySize,omitempty" yaml:"maxBodySize,omitempty" export:"true"`
+	// SampleSize is the number of the latest mismatches reported by the API.
+	SampleSize *int `json:"sampleSize,omitempty" toml:"sampleSize,omitempty" yaml:"sampleSize,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a MirroringCompare.
+func (m *MirroringCompare) SetDefaults() {
+	var defaultMaxBodySize int64 = 1024 * 1024
+	m.MaxBodySize = &defaultMaxBodySize
+
+	defaultSampleSize := 10
+	m.SampleSize = &defaultSampleSize
+}
+
+// +k8s:deepcopy-gen=true
+
+// Failover holds the Failover configuration.
+type Failover struct {
+	Service  string `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
+	Fallback string `json:"fallback,omitempty" toml:"fallback,omitempty" yaml:"fallback,omitempty" export:"true"`
+	// Status is the list of response status codes of the service for which the request is sent again to the fallback.
+	Status      []string `json:"status,omitempty" toml:"status,omitempty" yaml:"status,omitempty" export:"true"`
+	MaxBodySize *int64   `json:"maxBodySize,omitempty" toml:"maxBodySize,omitempty" yaml:"maxBodySize,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a Failover.
+func (f *Failover) SetDefaults() {
+	var defaultMaxBodySize int64 = -1
+	f.MaxBodySize = &defaultMaxBodySize
+}
+
+// +k8s:deepcopy-gen=true
+
 // MirrorService holds the MirrorService configuration.
 type MirrorService struct {
 	Name    string `json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty" export:"true"`
 	Percent int    `json:"percent,omitempty" toml:"percent,omitempty" yaml:"percent,omitempty" export:"true"`
+	// Rule restricts the mirroring to the requests matching it, with the same syntax as the rules of the routers.
+	Rule string `json:"rule,omitempty" toml:"rule,omitempty" yaml:"rule,omitempty"`
+	// HashHeader is the name of the header whose value selects the mirrored requests, instead of a counter.
+	// The requests with the same value are either all mirrored or none of them.
+	HashHeader string `json:"hashHeader,omitempty" toml:"hashHeader,omitempty" yaml:"hashHeader,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
 
 // WeightedRoundRobin is a weighted round robin load-balancer of services.
+// It cannot be defined with labels (see Service.Weighted), and neither can its overrides and its user stickiness.
 type WeightedRoundRobin struct {
 	Services []WRRService `json:"services,omitempty" toml:"services,omitempty" yaml:"services,omitempty" export:"true"`
 	Sticky   *Sticky      `json:"sticky,omitempty" toml:"sticky,omitempty" yaml:"sticky,omitempty" export:"true"`
+	// StickyUser makes the weights sticky per user, rather than per cookie.
+	StickyUser *StickyUser `json:"stickyUser,omitempty" toml:"stickyUser,omitempty" yaml:"stickyUser,omitempty" export:"true"`
+	// Overrides forward the requests with a given header or cookie to one of the services, regardless of the weights.
+	Overrides []WRROverride `json:"overrides,omitempty" toml:"overrides,omitempty" yaml:"overrides,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// StickyUser holds the configuration of the stickiness per user.
+// The ID of the user is read from a header, or else from a cookie, and is hashed to pick a service according to the weights.
+type StickyUser struct {
+	Header string `json:"header,omitempty" toml:"header,omitempty" yaml:"header,omitempty" export:"true"`
+	Cookie string `json:"cookie,omitempty" toml:"cookie,omitempty" yaml:"cookie,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// WRROverride forwards the requests matching a header or a cookie to one of the services of a WeightedRoundRobin.
+type WRROverride struct {
+	Service string    `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
+	Header  *WRRMatch `json:"header,omitempty" toml:"header,omitempty" yaml:"header,omitempty" export:"true"`
+	Cookie  *WRRMatch `json:"cookie,omitempty" toml:"cookie,omitempty" yaml:"cookie,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// WRRMatch matches the requests on a header or a cookie.
+type WRRMatch struct {
+	Name string `json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty" export:"true"`
+	// Value is the value of the header or of the cookie. When empty, any value matches.
+	Value string `json:"value,omitempty" toml:"value,omitempty" yaml:"value,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -127,9 +208,21 @@ type ServersLoadBalancer struct {
 	Sticky             *Sticky             `json:"sticky,omitempty" toml:"sticky,omitempty" yaml:"sticky,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	Servers            []Server            `json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" label-slice-as-struct:"server" export:"true"`
 	HealthCheck        *HealthCheck        `json:"healthCheck,omitempty" toml:"healthCheck,omitempty" yaml:"healthCheck,omitempty" export:"true"`
+	PassiveHealthCheck *PassiveHealthCheck `json:"passiveHealthCheck,omitempty" toml:"passiveHealthCheck,omitempty" yaml:"passiveHealthCheck,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	PassHostHeader     *bool               `json:"passHostHeader" toml:"passHostHeader" yaml:"passHostHeader" export:"true"`
 	ResponseForwarding *ResponseForwarding `json:"responseForwarding,omitempty" toml:"responseForwarding,omitempty" yaml:"responseForwarding,omitempty" export:"true"`
 	ServersTransport   string              `json:"serversTransport,omitempty" toml:"serversTransport,omitempty" yaml:"serversTransport,omitempty" export:"true"`
+	// Strategy is the algorithm used to pick the server a request is forwarded to.
+	// It is one of wrr (weighted round robin, the default), hash (bounded-load consistent hashing),
+	// leastrequest (least outstanding requests), or p2c (power of two choices).
+	Strategy     string                `json:"strategy,omitempty" toml:"strategy,omitempty" yaml:"strategy,omitempty" export:"true"`
+	Hash         *HashStrategy         `json:"hash,omitempty" toml:"hash,omitempty" yaml:"hash,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	LeastRequest *LeastRequestStrategy `json:"leastRequest,omitempty" toml:"leastRequest,omitempty" yaml:"leastRequest,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	// SlowStart is the duration over which the weight of a new or recovered server ramps up linearly to its weight.
+	SlowStart ptypes.Duration `json:"slowStart,omitempty" toml:"slowStart,omitempty" yaml:"slowStart,omitempty" export:"true"`
+	// DNSDiscovery discovers the servers by resolving a DNS name, instead of listing them in Servers.
+	// It is not supported by the label-based providers, which build the servers from the containers.
+	DNSDiscovery *DNSDiscovery `json:"dnsDiscovery,omitempty" toml:"dnsDiscovery,omitempty" yaml:"dnsDiscovery,omitempty" label:"-" export:"true"`
 }
 
 // Mergeable tells if the given service is mergeable.
@@ -155,6 +248,67 @@ func (l *ServersLoadBalancer) SetDefaults() {
 	l.PassHostHeader = &defaultPassHostHeader
 }
 
+// HTTP load-balancing strategies.
+const (
+	// StrategyWRR forwards requests using weighted round robin.
+	StrategyWRR = "wrr"
+	// StrategyHash forwards requests using bounded-load consistent hashing.
+	StrategyHash = "hash"
+	// StrategyLeastRequest forwards requests to the server with the least outstanding requests.
+	StrategyLeastRequest = "leastrequest"
+	// StrategyP2C forwards requests to the least busy of two servers picked at random.
+	StrategyP2C = "p2c"
+)
+
+// DNS record types of the DNS discovery.
+const (
+	// DNSRecordA discovers the servers from the A and AAAA records of a name.
+	DNSRecordA = "A"
+	// DNSRecordSRV discovers the servers from the SRV records of a name.
+	DNSRecordSRV = "SRV"
+)
+
+// Keys of the hash strategy.
+const (
+	HashKeyClientIP = "clientIP"
+	HashKeyHeader   = "header"
+	HashKeyCookie   = "cookie"
+	HashKeyPath     = "path"
+)
+
+// +k8s:deepcopy-gen=true
+
+// HashStrategy holds the configuration of the consistent hashing strategy.
+type HashStrategy struct {
+	// Key is the request attribute which is hashed: clientIP (the default), header, cookie or path.
+	Key string `json:"key,omitempty" toml:"key,omitempty" yaml:"key,omitempty" export:"true"`
+	// Name is the name of the hashed header or cookie.
+	Name string `json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty" export:"true"`
+	// LoadFactor bounds the load of each server to LoadFactor times its share of the in-flight requests.
+	LoadFactor float64 `json:"loadFactor,omitempty" toml:"loadFactor,omitempty" yaml:"loadFactor,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a HashStrategy.
+func (h *HashStrategy) SetDefaults() {
+	h.Key = HashKeyClientIP
+	h.LoadFactor = 1.25
+}
+
+// +k8s:deepcopy-gen=true
+
+// LeastRequestStrategy holds the configuration of the least-request and power-of-two-choices strategies.
+type LeastRequestStrategy struct {
+	// EWMA weights the outstanding requests of each server by an exponentially weighted moving average of its latency.
+	EWMA bool `json:"ewma,omitempty" toml:"ewma,omitempty" yaml:"ewma,omitempty" export:"true"`
+	// DecayTime is the time after which the weight of a latency sample in the moving average is divided by e.
+	DecayTime ptypes.Duration `json:"decayTime,omitempty" toml:"decayTime,omitempty" yaml:"decayTime,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a LeastRequestStrategy.
+func (l *LeastRequestStrategy) SetDefaults() {
+	l.DecayTime = ptypes.Duration(10 * time.Second)
+}
+
 // +k8s:deepcopy-gen=true
 
 // ResponseForwarding holds configuration for the forward of the response.
@@ -167,6 +321,7 @@ type ResponseForwarding struct {
 // Server holds the server configuration.
 type Server struct {
 	URL    string `json:"url,omitempty" toml:"url,omitempty" yaml:"url,omitempty" label:"-"`
+	Weight *int   `json:"weight,omitempty" toml:"weight,omitempty" yaml:"weight,omitempty" label:"-"`
 	Scheme string `toml:"-" json:"-" yaml:"-" file:"-"`
 	Port   string `toml:"-" json:"-" yaml:"-" file:"-"`
 }
@@ -200,6 +355,63 @@ func (h *HealthCheck) SetDefaults() {
 
 // +k8s:deepcopy-gen=true
 
+// DNSDiscovery holds the configuration of the discovery of the servers of a load-balancer with DNS.
+type DNSDiscovery struct {
+	// Name is the DNS name which is resolved.
+	Name string `json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty" export:"true"`
+	// RecordType is the type of the DNS records: A (for the A and AAAA records), or SRV.
+	RecordType string `json:"recordType,omitempty" toml:"recordType,omitempty" yaml:"recordType,omitempty" export:"true"`
+	// Port is the port of the servers discovered from A records.
+	// The port of the servers discovered from SRV records is given by the records.
+	Port int `json:"port,omitempty" toml:"port,omitempty" yaml:"port,omitempty" export:"true"`
+	// Scheme is the scheme of the URLs of the servers.
+	Scheme string `json:"scheme,omitempty" toml:"scheme,omitempty" yaml:"scheme,omitempty" export:"true"`
+	// RefreshInterval is the interval between two resolutions of the name.
+	RefreshInterval ptypes.Duration `json:"refreshInterval,omitempty" toml:"refreshInterval,omitempty" yaml:"refreshInterval,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a DNSDiscovery.
+func (d *DNSDiscovery) SetDefaults() {
+	d.RecordType = DNSRecordA
+	d.Scheme = "http"
+	d.RefreshInterval = ptypes.Duration(30 * time.Second)
+}
+
+// +k8s:deepcopy-gen=true
+
+// PassiveHealthCheck holds the passive health check configuration,
+// which ejects from the load balancer the servers failing to handle the forwarded requests.
+type PassiveHealthCheck struct {
+	// ConsecutiveErrors is the number of consecutive failed requests after which a server is ejected.
+	// Zero disables the consecutive errors detection.
+	ConsecutiveErrors int `json:"consecutiveErrors,omitempty" toml:"consecutiveErrors,omitempty" yaml:"consecutiveErrors,omitempty" export:"true"`
+	// ErrorRate is the percentage of failed requests over an interval above which a server is ejected.
+	// Zero disables the error rate detection.
+	ErrorRate int `json:"errorRate,omitempty" toml:"errorRate,omitempty" yaml:"errorRate,omitempty" export:"true"`
+	// MinRequests is the minimum number of requests over an interval for the error rate to be evaluated.
+	MinRequests int `json:"minRequests,omitempty" toml:"minRequests,omitempty" yaml:"minRequests,omitempty" export:"true"`
+	// Interval is the duration over which the error rate is computed.
+	Interval ptypes.Duration `json:"interval,omitempty" toml:"interval,omitempty" yaml:"interval,omitempty" export:"true"`
+	// BaseEjectionTime is the duration of the first ejection of a server, which doubles with each new ejection.
+	BaseEjectionTime ptypes.Duration `json:"baseEjectionTime,omitempty" toml:"baseEjectionTime,omitempty" yaml:"baseEjectionTime,omitempty" export:"true"`
+	// MaxEjectionTime is the maximum duration of an ejection.
+	MaxEjectionTime ptypes.Duration `json:"maxEjectionTime,omitempty" toml:"maxEjectionTime,omitempty" yaml:"maxEjectionTime,omitempty" export:"true"`
+	// MaxEjectionPercent is the maximum percentage of the servers which can be ejected at once.
+	MaxEjectionPercent int `json:"maxEjectionPercent,omitempty" toml:"maxEjectionPercent,omitempty" yaml:"maxEjectionPercent,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a PassiveHealthCheck.
+func (p *PassiveHealthCheck) SetDefaults() {
+	p.ConsecutiveErrors = 5
+	p.MinRequests = 20
+	p.Interval = ptypes.Duration(10 * time.Second)
+	p.BaseEjectionTime = ptypes.Duration(30 * time.Second)
+	p.MaxEjectionTime = ptypes.Duration(300 * time.Second)
+	p.MaxEjectionPercent = 50
+}
+
+// +k8s:deepcopy-gen=true
+
 // ServersTransport options to configure communication between Traefik and the servers.
 type ServersTransport struct {
 	ServerName          string              `description:"ServerName used to contact the server" json:"serverName,omitempty" toml:"serverName,omitempty" yaml:"serverName,omitempty"`
@@ -9,6 +9,7 @@ import (
 
 	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/ip"
+	traefiktls "github.com/traefik/traefik/v2/pkg/tls"
 )
 
 // +k8s:deepcopy-gen=true
@@ -20,6 +21,7 @@ type Middleware struct {
 	StripPrefixRegex  *StripPrefixRegex  `json:"stripPrefixRegex,omitempty" toml:"stripPrefixRegex,omitempty" yaml:"stripPrefixRegex,omitempty" export:"true"`
 	ReplacePath       *ReplacePath       `json:"replacePath,omitempty" toml:"replacePath,omitempty" yaml:"replacePath,omitempty" export:"true"`
 	ReplacePathRegex  *ReplacePathRegex  `json:"replacePathRegex,omitempty" toml:"replacePathRegex,omitempty" yaml:"replacePathRegex,omitempty" export:"true"`
+	RewriteBody       *RewriteBody       `json:"rewriteBody,omitempty" toml:"rewriteBody,omitempty" yaml:"rewriteBody,omitempty" export:"true"`
 	Chain             *Chain             `json:"chain,omitempty" toml:"chain,omitempty" yaml:"chain,omitempty" export:"true"`
 	IPWhiteList       *IPWhiteList       `json:"ipWhiteList,omitempty" toml:"ipWhiteList,omitempty" yaml:"ipWhiteList,omitempty" export:"true"`
 	Headers           *Headers           `json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty" export:"true"`
@@ -30,9 +32,14 @@ type Middleware struct {
 	BasicAuth         *BasicAuth         `json:"basicAuth,omitempty" toml:"basicAuth,omitempty" yaml:"basicAuth,omitempty" export:"true"`
 	DigestAuth        *DigestAuth        `json:"digestAuth,omitempty" toml:"digestAuth,omitempty" yaml:"digestAuth,omitempty" export:"true"`
 	ForwardAuth       *ForwardAuth       `json:"forwardAuth,omitempty" toml:"forwardAuth,omitempty" yaml:"forwardAuth,omitempty" export:"true"`
+	OIDC              *OIDC              `json:"oidc,omitempty" toml:"oidc,omitempty" yaml:"oidc,omitempty" export:"true"`
 	InFlightReq       *InFlightReq       `json:"inFlightReq,omitempty" toml:"inFlightReq,omitempty" yaml:"inFlightReq,omitempty" export:"true"`
+	JWT               *JWT               `json:"jwt,omitempty" toml:"jwt,omitempty" yaml:"jwt,omitempty" export:"true"`
 	Buffering         *Buffering         `json:"buffering,omitempty" toml:"buffering,omitempty" yaml:"buffering,omitempty" export:"true"`
+	Cache             *Cache             `json:"cache,omitempty" toml:"cache,omitempty" yaml:"cache,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	CircuitBreaker    *CircuitBreaker    `json:"circuitBreaker,omitempty" toml:"circuitBreaker,omitempty" yaml:"circuitBreaker,omitempty" export:"true"`
+	Limits            *Limits            `json:"limits,omitempty" toml:"limits,omitempty" yaml:"limits,omitempty" export:"true"`
+	Maintenance       *Maintenance       `json:"maintenance,omitempty" toml:"maintenance,omitempty" yaml:"maintenance,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	Compress          *Compress          `json:"compress,omitempty" toml:"compress,omitempty" yaml:"compress,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	PassTLSClientCert *PassTLSClientCert `json:"passTLSClientCert,omitempty" toml:"passTLSClientCert,omitempty" yaml:"passTLSClientCert,omitempty" export:"true"`
 	Retry             *Retry             `json:"retry,omitempty" toml:"retry,omitempty" yaml:"retry,omitempty" export:"true"`
@@ -87,6 +94,55 @@ type Buffering struct {
 
 // +k8s:deepcopy-gen=true
 
+// Cache holds the HTTP cache configuration.
+type Cache struct {
+	// MaxSize is the maximum size, in bytes, of the responses kept in memory.
+	// It defaults to 64MiB.
+	MaxSize int64 `json:"maxSize,omitempty" toml:"maxSize,omitempty" yaml:"maxSize,omitempty" export:"true"`
+
+	// MaxEntrySize is the maximum size, in bytes, of a cached response body.
+	// It defaults to 1MiB.
+	MaxEntrySize int64 `json:"maxEntrySize,omitempty" toml:"maxEntrySize,omitempty" yaml:"maxEntrySize,omitempty" export:"true"`
+
+	// DefaultTTL is the freshness lifetime of the cacheable responses which define neither an explicit expiration time, nor a Last-Modified date.
+	// It defaults to 0, which means such responses are not cached.
+	DefaultTTL ptypes.Duration `json:"defaultTTL,omitempty" toml:"defaultTTL,omitempty" yaml:"defaultTTL,omitempty" export:"true"`
+
+	Key  *CacheKey  `json:"key,omitempty" toml:"key,omitempty" yaml:"key,omitempty" export:"true"`
+	Disk *CacheDisk `json:"disk,omitempty" toml:"disk,omitempty" yaml:"disk,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+}
+
+// SetDefaults sets the default values on a Cache.
+func (c *Cache) SetDefaults() {
+	c.MaxSize = 64 * 1024 * 1024
+	c.MaxEntrySize = 1024 * 1024
+}
+
+// +k8s:deepcopy-gen=true
+
+// CacheKey holds the request parts, besides the method and the URL, which the responses are cached by.
+type CacheKey struct {
+	Headers []string `json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty" export:"true"`
+	Cookies []string `json:"cookies,omitempty" toml:"cookies,omitempty" yaml:"cookies,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// CacheDisk holds the configuration of the on-disk tier of a cache, where the responses evicted from memory are kept.
+// The directory of the responses comes from the static configuration.
+type CacheDisk struct {
+	// MaxSize is the maximum size, in bytes, of the responses kept on disk.
+	// It defaults to 1GiB.
+	MaxSize int64 `json:"maxSize,omitempty" toml:"maxSize,omitempty" yaml:"maxSize,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on a CacheDisk.
+func (c *CacheDisk) SetDefaults() {
+	c.MaxSize = 1024 * 1024 * 1024
+}
+
+// +k8s:deepcopy-gen=true
+
 // Chain holds a chain of middlewares.
 type Chain struct {
 	Middlewares []string `json:"middlewares,omitempty" toml:"middlewares,omitempty" yaml:"middlewares,omitempty" export:"true"`
@@ -97,6 +153,22 @@ type Chain struct {
 // CircuitBreaker holds the circuit breaker configuration.
 type CircuitBreaker struct {
 	Expression string `json:"expression,omitempty" toml:"expression,omitempty" yaml:"expression,omitempty" export:"true"`
+
+	// FallbackDuration is the duration for which the circuit breaker stays open once tripped.
+	// It defaults to 10s.
+	FallbackDuration ptypes.Duration `json:"fallbackDuration,omitempty" toml:"fallbackDuration,omitempty" yaml:"fallbackDuration,omitempty" export:"true"`
+
+	// RecoveryDuration is the duration of the half-open state, during which the requests are progressively let through again.
+	// It defaults to 10s.
+	RecoveryDuration ptypes.Duration `json:"recoveryDuration,omitempty" toml:"recoveryDuration,omitempty" yaml:"recoveryDuration,omitempty" export:"true"`
+
+	// ProbeRatio is the ratio of requests let through at the end of the half-open state, between 0 (excluded) and 1.
+	// It defaults to 0.5.
+	ProbeRatio float64 `json:"probeRatio,omitempty" toml:"probeRatio,omitempty" yaml:"probeRatio,omitempty" export:"true"`
+
+	// FallbackService is the service handling the requests rejected by the circuit breaker.
+	// When not set, the rejected requests get a 503 Service Unavailable response.
+	FallbackService string `json:"fallbackService,omitempty" toml:"fallbackService,omitempty" yaml:"fallbackService,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -104,6 +176,12 @@ type CircuitBreaker struct {
 // Compress holds the compress configuration.
 type Compress struct {
 	ExcludedContentTypes []string `json:"excludedContentTypes,omitempty" toml:"excludedContentTypes,omitempty" yaml:"excludedContentTypes,omitempty" export:"true"`
+	IncludedContentTypes []string `json:"includedContentTypes,omitempty" toml:"includedContentTypes,omitempty" yaml:"includedContentTypes,omitempty" export:"true"`
+	MinResponseBodyBytes int      `json:"minResponseBodyBytes,omitempty" toml:"minResponseBodyBytes,omitempty" yaml:"minResponseBodyBytes,omitempty" export:"true"`
+	Encodings            []string `json:"encodings,omitempty" toml:"encodings,omitempty" yaml:"encodings,omitempty" export:"true"`
+	GzipLevel            int      `json:"gzipLevel,omitempty" toml:"gzipLevel,omitempty" yaml:"gzipLevel,omitempty" export:"true"`
+	BrotliLevel          int      `json:"brotliLevel,omitempty" toml:"brotliLevel,omitempty" yaml:"brotliLevel,omitempty" export:"true"`
+	ZstdLevel            int      `json:"zstdLevel,omitempty" toml:"zstdLevel,omitempty" yaml:"zstdLevel,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -121,21 +199,57 @@ type DigestAuth struct {
 
 // ErrorPage holds the custom error page configuration.
 type ErrorPage struct {
-	Status  []string `json:"status,omitempty" toml:"status,omitempty" yaml:"status,omitempty" export:"true"`
-	Service string   `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
-	Query   string   `json:"query,omitempty" toml:"query,omitempty" yaml:"query,omitempty" export:"true"`
+	Status  []string           `json:"status,omitempty" toml:"status,omitempty" yaml:"status,omitempty" export:"true"`
+	Service string             `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
+	Query   string             `json:"query,omitempty" toml:"query,omitempty" yaml:"query,omitempty" export:"true"`
+	Pages   []ErrorPageContent `json:"pages,omitempty" toml:"pages,omitempty" yaml:"pages,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ErrorPageContent holds an error page served without a service, from an inline or a file template.
+type ErrorPageContent struct {
+	ContentType string `json:"contentType,omitempty" toml:"contentType,omitempty" yaml:"contentType,omitempty" export:"true"`
+	Body        string `json:"body,omitempty" toml:"body,omitempty" yaml:"body,omitempty"`
+	// File is only allowed with the file provider, as the other providers could otherwise serve any file Traefik can read.
+	File string `json:"file,omitempty" toml:"file,omitempty" yaml:"file,omitempty" label:"-"`
 }
 
 // +k8s:deepcopy-gen=true
 
 // ForwardAuth holds the http forward authentication configuration.
 type ForwardAuth struct {
-	Address                  string     `json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
-	TLS                      *ClientTLS `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
-	TrustForwardHeader       bool       `json:"trustForwardHeader,omitempty" toml:"trustForwardHeader,omitempty" yaml:"trustForwardHeader,omitempty" export:"true"`
-	AuthResponseHeaders      []string   `json:"authResponseHeaders,omitempty" toml:"authResponseHeaders,omitempty" yaml:"authResponseHeaders,omitempty" export:"true"`
-	AuthResponseHeadersRegex string     `json:"authResponseHeadersRegex,omitempty" toml:"authResponseHeadersRegex,omitempty" yaml:"authResponseHeadersRegex,omitempty" export:"true"`
-	AuthRequestHeaders       []string   `json:"authRequestHeaders,omitempty" toml:"authRequestHeaders,omitempty" yaml:"authRequestHeaders,omitempty" export:"true"`
+	Address                  string            `json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
+	TLS                      *ClientTLS        `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+	TrustForwardHeader       bool              `json:"trustForwardHeader,omitempty" toml:"trustForwardHeader,omitempty" yaml:"trustForwardHeader,omitempty" export:"true"`
+	AuthResponseHeaders      []string          `json:"authResponseHeaders,omitempty" toml:"authResponseHeaders,omitempty" yaml:"authResponseHeaders,omitempty" export:"true"`
+	AuthResponseHeadersRegex string            `json:"authResponseHeadersRegex,omitempty" toml:"authResponseHeadersRegex,omitempty" yaml:"authResponseHeadersRegex,omitempty" export:"true"`
+	AuthRequestHeaders       []string          `json:"authRequestHeaders,omitempty" toml:"authRequestHeaders,omitempty" yaml:"authRequestHeaders,omitempty" export:"true"`
+	ForwardBody              bool              `json:"forwardBody,omitempty" toml:"forwardBody,omitempty" yaml:"forwardBody,omitempty" export:"true"`
+	MaxBodySize              int64             `json:"maxBodySize,omitempty" toml:"maxBodySize,omitempty" yaml:"maxBodySize,omitempty" export:"true"`
+	PreserveRequestMethod    bool              `json:"preserveRequestMethod,omitempty" toml:"preserveRequestMethod,omitempty" yaml:"preserveRequestMethod,omitempty" export:"true"`
+	Cache                    *ForwardAuthCache `json:"cache,omitempty" toml:"cache,omitempty" yaml:"cache,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ForwardAuthCache holds the configuration of the cache of the authentication server responses.
+type ForwardAuthCache struct {
+	// Headers are the request headers the responses are cached by.
+	// It defaults to the Authorization header.
+	Headers []string `json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty" export:"true"`
+	// TTL is the duration a response is cached for.
+	// It defaults to 1 minute.
+	TTL ptypes.Duration `json:"ttl,omitempty" toml:"ttl,omitempty" yaml:"ttl,omitempty" export:"true"`
+	// MaxEntries is the maximum number of cached responses.
+	// It defaults to 1000.
+	MaxEntries int `json:"maxEntries,omitempty" toml:"maxEntries,omitempty" yaml:"maxEntries,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on a ForwardAuthCache.
+func (f *ForwardAuthCache) SetDefaults() {
+	f.TTL = ptypes.Duration(time.Minute)
+	f.MaxEntries = 1000
 }
 
 // +k8s:deepcopy-gen=true
@@ -287,6 +401,130 @@ type InFlightReq struct {
 
 // +k8s:deepcopy-gen=true
 
+// JWT holds the JWT authentication configuration.
+type JWT struct {
+	// Keys are the keys the token signatures are verified with:
+	// PEM-encoded public keys or certificates, or secrets for the HMAC algorithms.
+	// Each key is given either as a file path or as its content.
+	Keys []traefiktls.FileOrContent `json:"keys,omitempty" toml:"keys,omitempty" yaml:"keys,omitempty"`
+	JWKS *JWKS                      `json:"jwks,omitempty" toml:"jwks,omitempty" yaml:"jwks,omitempty" export:"true"`
+
+	// Issuers are the accepted values of the iss claim. Any issuer is accepted when empty.
+	Issuers []string `json:"issuers,omitempty" toml:"issuers,omitempty" yaml:"issuers,omitempty" export:"true"`
+	// Audiences are the accepted values of the aud claim. The token must be intended for at least one of them.
+	Audiences []string `json:"audiences,omitempty" toml:"audiences,omitempty" yaml:"audiences,omitempty" export:"true"`
+	// ClockSkew is the tolerance applied when checking the exp, nbf and iat claims.
+	ClockSkew ptypes.Duration `json:"clockSkew,omitempty" toml:"clockSkew,omitempty" yaml:"clockSkew,omitempty" export:"true"`
+
+	// Claims are the claims the token must have, by name, with their expected value.
+	// An array claim matches when it contains the expected value.
+	Claims map[string]string `json:"claims,omitempty" toml:"claims,omitempty" yaml:"claims,omitempty" export:"true"`
+	// ForwardClaims are the claims forwarded to the service, by the name of the request header they are set in.
+	ForwardClaims map[string]string `json:"forwardClaims,omitempty" toml:"forwardClaims,omitempty" yaml:"forwardClaims,omitempty" export:"true"`
+
+	RemoveHeader bool `json:"removeHeader,omitempty" toml:"removeHeader,omitempty" yaml:"removeHeader,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// JWKS holds the configuration of a JSON Web Key Set the JWT signatures are verified with.
+type JWKS struct {
+	URL string `json:"url,omitempty" toml:"url,omitempty" yaml:"url,omitempty"`
+	// RefreshInterval is the interval at which the key set is fetched again.
+	// It is also fetched again, at most every few seconds, when a token is signed with a key it does not hold.
+	// It defaults to 15 minutes.
+	RefreshInterval ptypes.Duration `json:"refreshInterval,omitempty" toml:"refreshInterval,omitempty" yaml:"refreshInterval,omitempty" export:"true"`
+	TLS             *ClientTLS      `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on a JWKS.
+func (j *JWKS) SetDefaults() {
+	j.RefreshInterval = ptypes.Duration(15 * time.Minute)
+}
+
+// +k8s:deepcopy-gen=true
+
+// Limits holds the request limits configuration.
+// A limit is not enforced when it is 0.
+// When several limits middlewares apply to a router, for instance one set on its entry point and one on the router,
+// the limits set by the last one override the ones set by the previous ones.
+type Limits struct {
+	MaxRequestBodyBytes int64 `json:"maxRequestBodyBytes,omitempty" toml:"maxRequestBodyBytes,omitempty" yaml:"maxRequestBodyBytes,omitempty" export:"true"`
+	MaxHeaderCount      int   `json:"maxHeaderCount,omitempty" toml:"maxHeaderCount,omitempty" yaml:"maxHeaderCount,omitempty" export:"true"`
+	MaxHeaderBytes      int   `json:"maxHeaderBytes,omitempty" toml:"maxHeaderBytes,omitempty" yaml:"maxHeaderBytes,omitempty" export:"true"`
+	MaxURLLength        int   `json:"maxURLLength,omitempty" toml:"maxURLLength,omitempty" yaml:"maxURLLength,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// Maintenance holds the maintenance mode configuration.
+// The enabled state toggled through the API takes precedence over the configured one.
+type Maintenance struct {
+	Enabled bool `json:"enabled,omitempty" toml:"enabled,omitempty" yaml:"enabled,omitempty" export:"true"`
+	// RetryAfter is the delay advertised in the Retry-After header of the maintenance responses, none if it is 0.
+	RetryAfter        ptypes.Duration    `json:"retryAfter,omitempty" toml:"retryAfter,omitempty" yaml:"retryAfter,omitempty" export:"true"`
+	SourceRange       []string           `json:"sourceRange,omitempty" toml:"sourceRange,omitempty" yaml:"sourceRange,omitempty"`
+	IPStrategy        *IPStrategy        `json:"ipStrategy,omitempty" toml:"ipStrategy,omitempty" yaml:"ipStrategy,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	BypassHeaderName  string             `json:"bypassHeaderName,omitempty" toml:"bypassHeaderName,omitempty" yaml:"bypassHeaderName,omitempty" export:"true"`
+	BypassHeaderValue string             `json:"bypassHeaderValue,omitempty" toml:"bypassHeaderValue,omitempty" yaml:"bypassHeaderValue,omitempty"`
+	Pages             []ErrorPageContent `json:"pages,omitempty" toml:"pages,omitempty" yaml:"pages,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// OIDC holds the OpenID Connect authentication configuration.
+type OIDC struct {
+	// Issuer is the URL of the OpenID provider, its configuration is discovered from.
+	Issuer       string   `json:"issuer,omitempty" toml:"issuer,omitempty" yaml:"issuer,omitempty" export:"true"`
+	ClientID     string   `json:"clientID,omitempty" toml:"clientID,omitempty" yaml:"clientID,omitempty"`
+	ClientSecret string   `json:"clientSecret,omitempty" toml:"clientSecret,omitempty" yaml:"clientSecret,omitempty"`
+	Scopes       []string `json:"scopes,omitempty" toml:"scopes,omitempty" yaml:"scopes,omitempty" export:"true"`
+
+	// CallbackPath is the path of the redirection URI, on the host of the request, where the provider sends the users back to.
+	CallbackPath string `json:"callbackPath,omitempty" toml:"callbackPath,omitempty" yaml:"callbackPath,omitempty" export:"true"`
+	// LogoutPath is the path ending the session of the users, if any.
+	LogoutPath string `json:"logoutPath,omitempty" toml:"logoutPath,omitempty" yaml:"logoutPath,omitempty" export:"true"`
+
+	// SessionSecret is the secret the session cookies are encrypted with.
+	SessionSecret string      `json:"sessionSecret,omitempty" toml:"sessionSecret,omitempty" yaml:"sessionSecret,omitempty"`
+	Cookie        *OIDCCookie `json:"cookie,omitempty" toml:"cookie,omitempty" yaml:"cookie,omitempty" export:"true"`
+
+	// AllowedGroups and AllowedEmails restrict the access to the users who belong to one of the groups, or have one of the emails.
+	// An email starting with @ allows all the emails of the domain.
+	AllowedGroups []string `json:"allowedGroups,omitempty" toml:"allowedGroups,omitempty" yaml:"allowedGroups,omitempty" export:"true"`
+	AllowedEmails []string `json:"allowedEmails,omitempty" toml:"allowedEmails,omitempty" yaml:"allowedEmails,omitempty" export:"true"`
+	// GroupsClaim is the claim of the ID token holding the groups of the user.
+	GroupsClaim string `json:"groupsClaim,omitempty" toml:"groupsClaim,omitempty" yaml:"groupsClaim,omitempty" export:"true"`
+	// ForwardClaims are the claims of the ID token forwarded to the service, by the name of the request header they are set in.
+	ForwardClaims map[string]string `json:"forwardClaims,omitempty" toml:"forwardClaims,omitempty" yaml:"forwardClaims,omitempty" export:"true"`
+
+	TLS *ClientTLS `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on an OIDC.
+func (o *OIDC) SetDefaults() {
+	o.Scopes = []string{"openid", "profile", "email"}
+	o.CallbackPath = "/oidc/callback"
+	o.GroupsClaim = "groups"
+}
+
+// +k8s:deepcopy-gen=true
+
+// OIDCCookie holds the configuration of the session cookies.
+type OIDCCookie struct {
+	Name   string `json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty" export:"true"`
+	Domain string `json:"domain,omitempty" toml:"domain,omitempty" yaml:"domain,omitempty" export:"true"`
+	Path   string `json:"path,omitempty" toml:"path,omitempty" yaml:"path,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on an OIDCCookie.
+func (c *OIDCCookie) SetDefaults() {
+	c.Name = "traefik_oidc"
+	c.Path = "/"
+}
+
+// +k8s:deepcopy-gen=true
+
 // PassTLSClientCert holds the TLS client cert headers configuration.
 type PassTLSClientCert struct {
 	PEM  bool                      `json:"pem,omitempty" toml:"pem,omitempty" yaml:"pem,omitempty" export:"true"`
@@ -374,6 +612,28 @@ type Retry struct {
 
 // +k8s:deepcopy-gen=true
 
+// RewriteBody holds the body rewriting configuration.
+type RewriteBody struct {
+	// Rewrites are the rewrites of the response bodies, applied in order.
+	Rewrites []RewriteBodyRule `json:"rewrites,omitempty" toml:"rewrites,omitempty" yaml:"rewrites,omitempty" export:"true"`
+	// RequestRewrites are the rewrites of the request bodies, applied in order.
+	RequestRewrites []RewriteBodyRule `json:"requestRewrites,omitempty" toml:"requestRewrites,omitempty" yaml:"requestRewrites,omitempty" export:"true"`
+	// ContentTypes are the media types of the rewritten bodies.
+	// It defaults to text/html.
+	ContentTypes []string `json:"contentTypes,omitempty" toml:"contentTypes,omitempty" yaml:"contentTypes,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// RewriteBodyRule holds a body rewrite, replacing the matches of either a regular expression or a literal string.
+type RewriteBodyRule struct {
+	Regex       string `json:"regex,omitempty" toml:"regex,omitempty" yaml:"regex,omitempty" export:"true"`
+	Literal     string `json:"literal,omitempty" toml:"literal,omitempty" yaml:"literal,omitempty" export:"true"`
+	Replacement string `json:"replacement,omitempty" toml:"replacement,omitempty" yaml:"replacement,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // StripPrefix holds the StripPrefix configuration.
 type StripPrefix struct {
 	Prefixes   []string `json:"prefixes,omitempty" toml:"prefixes,omitempty" yaml:"prefixes,omitempty" export:"true"`
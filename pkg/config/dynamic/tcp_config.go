@@ -2,16 +2,30 @@ package dynamic
 
 import (
 	"reflect"
+	"time"
 
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/tls"
 	"github.com/traefik/traefik/v2/pkg/types"
 )
 
+// TCP load-balancing strategies.
+const (
+	// TCPStrategyWRR forwards connections using weighted round robin.
+	TCPStrategyWRR = "wrr"
+	// TCPStrategyLeastConn forwards connections to the server with the least active connections.
+	TCPStrategyLeastConn = "leastconn"
+	// TCPStrategyP2C forwards connections to the least busy of two servers picked at random.
+	TCPStrategyP2C = "p2c"
+)
+
 // +k8s:deepcopy-gen=true
 
 // TCPConfiguration contains all the TCP configuration parameters.
 type TCPConfiguration struct {
-	Routers  map[string]*TCPRouter  `json:"routers,omitempty" toml:"routers,omitempty" yaml:"routers,omitempty" export:"true"`
-	Services map[string]*TCPService `json:"services,omitempty" toml:"services,omitempty" yaml:"services,omitempty" export:"true"`
+	Routers           map[string]*TCPRouter           `json:"routers,omitempty" toml:"routers,omitempty" yaml:"routers,omitempty" export:"true"`
+	Services          map[string]*TCPService          `json:"services,omitempty" toml:"services,omitempty" yaml:"services,omitempty" export:"true"`
+	ServersTransports map[string]*TCPServersTransport `json:"serversTransports,omitempty" toml:"serversTransports,omitempty" yaml:"serversTransports,omitempty" label:"-" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -75,6 +89,16 @@ type TCPServersLoadBalancer struct {
 	TerminationDelay *int           `json:"terminationDelay,omitempty" toml:"terminationDelay,omitempty" yaml:"terminationDelay,omitempty" export:"true"`
 	ProxyProtocol    *ProxyProtocol `json:"proxyProtocol,omitempty" toml:"proxyProtocol,omitempty" yaml:"proxyProtocol,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	Servers          []TCPServer    `json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" label-slice-as-struct:"server" export:"true"`
+	// Strategy is the algorithm used to pick the server a connection is forwarded to.
+	// It is one of wrr (weighted round robin, the default), leastconn (least active connections),
+	// or p2c (least active connections among two servers picked at random).
+	Strategy string `json:"strategy,omitempty" toml:"strategy,omitempty" yaml:"strategy,omitempty" export:"true"`
+	// ServersTransport is the name of the TCPServersTransport used to originate TLS connections to the servers.
+	// When empty, plain TCP connections are used.
+	ServersTransport string `json:"serversTransport,omitempty" toml:"serversTransport,omitempty" yaml:"serversTransport,omitempty" export:"true"`
+	// DNSDiscovery discovers the servers by resolving a DNS name, instead of listing them in Servers.
+	// It is not supported by the label-based providers, which build the servers from the containers.
+	DNSDiscovery *TCPDNSDiscovery `json:"dnsDiscovery,omitempty" toml:"dnsDiscovery,omitempty" yaml:"dnsDiscovery,omitempty" label:"-" export:"true"`
 }
 
 // SetDefaults Default values for a TCPServersLoadBalancer.
@@ -102,6 +126,27 @@ func (l *TCPServersLoadBalancer) Mergeable(loadBalancer *TCPServersLoadBalancer)
 
 // +k8s:deepcopy-gen=true
 
+// TCPDNSDiscovery holds the configuration of the discovery of the servers of a TCP load-balancer with DNS.
+type TCPDNSDiscovery struct {
+	// Name is the DNS name which is resolved.
+	Name string `json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty" export:"true"`
+	// RecordType is the type of the DNS records: A (for the A and AAAA records), or SRV.
+	RecordType string `json:"recordType,omitempty" toml:"recordType,omitempty" yaml:"recordType,omitempty" export:"true"`
+	// Port is the port of the servers discovered from A records.
+	// The port of the servers discovered from SRV records is given by the records.
+	Port int `json:"port,omitempty" toml:"port,omitempty" yaml:"port,omitempty" export:"true"`
+	// RefreshInterval is the interval between two resolutions of the name.
+	RefreshInterval ptypes.Duration `json:"refreshInterval,omitempty" toml:"refreshInterval,omitempty" yaml:"refreshInterval,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a TCPDNSDiscovery.
+func (d *TCPDNSDiscovery) SetDefaults() {
+	d.RecordType = DNSRecordA
+	d.RefreshInterval = ptypes.Duration(30 * time.Second)
+}
+
+// +k8s:deepcopy-gen=true
+
 // TCPServer holds a TCP Server configuration.
 type TCPServer struct {
 	Address string `json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty" label:"-"`
@@ -112,10 +157,30 @@ type TCPServer struct {
 
 // ProxyProtocol holds the ProxyProtocol configuration.
 type ProxyProtocol struct {
-	Version int `json:"version,omitempty" toml:"version,omitempty" yaml:"version,omitempty" export:"true"`
+	Version int                `json:"version,omitempty" toml:"version,omitempty" yaml:"version,omitempty" export:"true"`
+	TLVs    *ProxyProtocolTLVs `json:"tlvs,omitempty" toml:"tlvs,omitempty" yaml:"tlvs,omitempty" export:"true"`
 }
 
 // SetDefaults Default values for a ProxyProtocol.
 func (p *ProxyProtocol) SetDefaults() {
 	p.Version = 2
 }
+
+// +k8s:deepcopy-gen=true
+
+// ProxyProtocolTLVs holds the TLVs appended to the PROXY protocol v2 header.
+type ProxyProtocolTLVs struct {
+	SNI        bool `json:"sni,omitempty" toml:"sni,omitempty" yaml:"sni,omitempty" export:"true"`
+	ALPN       bool `json:"alpn,omitempty" toml:"alpn,omitempty" yaml:"alpn,omitempty" export:"true"`
+	RouterName bool `json:"routerName,omitempty" toml:"routerName,omitempty" yaml:"routerName,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPServersTransport options to configure the TLS connections between Traefik and the TCP servers.
+type TCPServersTransport struct {
+	ServerName         string              `description:"ServerName used to contact the server." json:"serverName,omitempty" toml:"serverName,omitempty" yaml:"serverName,omitempty"`
+	InsecureSkipVerify bool                `description:"Disable TLS certificate verification." json:"insecureSkipVerify,omitempty" toml:"insecureSkipVerify,omitempty" yaml:"insecureSkipVerify,omitempty" export:"true"`
+	RootCAs            []tls.FileOrContent `description:"Add cert file for self-signed certificate." json:"rootCAs,omitempty" toml:"rootCAs,omitempty" yaml:"rootCAs,omitempty"`
+	Certificates       tls.Certificates    `description:"Certificates for mTLS." json:"certificates,omitempty" toml:"certificates,omitempty" yaml:"certificates,omitempty" export:"true"`
+}
@@ -37,6 +37,12 @@ func TestDecodeConfiguration(t *testing.T) {
 		"traefik.http.middlewares.Middleware7.forwardauth.address":                                 "foobar",
 		"traefik.http.middlewares.Middleware7.forwardauth.authresponseheaders":                     "foobar, fiibar",
 		"traefik.http.middlewares.Middleware7.forwardauth.authrequestheaders":                      "foobar, fiibar",
+		"traefik.http.middlewares.Middleware7.forwardauth.cache.headers":                           "foobar, fiibar",
+		"traefik.http.middlewares.Middleware7.forwardauth.cache.maxentries":                        "42",
+		"traefik.http.middlewares.Middleware7.forwardauth.cache.ttl":                               "42s",
+		"traefik.http.middlewares.Middleware7.forwardauth.forwardbody":                             "true",
+		"traefik.http.middlewares.Middleware7.forwardauth.maxbodysize":                             "42",
+		"traefik.http.middlewares.Middleware7.forwardauth.preserverequestmethod":                   "true",
 		"traefik.http.middlewares.Middleware7.forwardauth.tls.ca":                                  "foobar",
 		"traefik.http.middlewares.Middleware7.forwardauth.tls.caoptional":                          "true",
 		"traefik.http.middlewares.Middleware7.forwardauth.tls.cert":                                "foobar",
@@ -125,6 +131,10 @@ func TestDecodeConfiguration(t *testing.T) {
 		"traefik.http.middlewares.Middleware17.stripprefix.prefixes":                               "foobar, fiibar",
 		"traefik.http.middlewares.Middleware18.stripprefixregex.regex":                             "foobar, fiibar",
 		"traefik.http.middlewares.Middleware19.compress":                                           "true",
+		"traefik.http.middlewares.Middleware19b.compress.minresponsebodybytes":                     "1024",
+		"traefik.http.middlewares.Middleware19b.compress.gziplevel":                                "6",
+		"traefik.http.middlewares.Middleware19b.compress.brotlilevel":                              "5",
+		"traefik.http.middlewares.Middleware19b.compress.zstdlevel":                                "3",
 		"traefik.http.middlewares.Middleware20.plugin.tomato.aaa":                                  "foo1",
 		"traefik.http.middlewares.Middleware20.plugin.tomato.bbb":                                  "foo2",
 		"traefik.http.routers.Router0.entrypoints":                                                 "foobar, fiibar",
@@ -444,6 +454,14 @@ func TestDecodeConfiguration(t *testing.T) {
 				"Middleware19": {
 					Compress: &dynamic.Compress{},
 				},
+				"Middleware19b": {
+					Compress: &dynamic.Compress{
+						MinResponseBodyBytes: 1024,
+						GzipLevel:            6,
+						BrotliLevel:          5,
+						ZstdLevel:            3,
+					},
+				},
 				"Middleware2": {
 					Buffering: &dynamic.Buffering{
 						MaxRequestBodyBytes:  42,
@@ -507,6 +525,17 @@ func TestDecodeConfiguration(t *testing.T) {
 							"foobar",
 							"fiibar",
 						},
+						ForwardBody:           true,
+						MaxBodySize:           42,
+						PreserveRequestMethod: true,
+						Cache: &dynamic.ForwardAuthCache{
+							Headers: []string{
+								"foobar",
+								"fiibar",
+							},
+							TTL:        ptypes.Duration(42 * time.Second),
+							MaxEntries: 42,
+						},
 					},
 				},
 				"Middleware8": {
@@ -912,6 +941,14 @@ func TestEncodeConfiguration(t *testing.T) {
 				"Middleware19": {
 					Compress: &dynamic.Compress{},
 				},
+				"Middleware19b": {
+					Compress: &dynamic.Compress{
+						MinResponseBodyBytes: 1024,
+						GzipLevel:            6,
+						BrotliLevel:          5,
+						ZstdLevel:            3,
+					},
+				},
 				"Middleware2": {
 					Buffering: &dynamic.Buffering{
 						MaxRequestBodyBytes:  42,
@@ -983,6 +1020,17 @@ func TestEncodeConfiguration(t *testing.T) {
 							"foobar",
 							"fiibar",
 						},
+						ForwardBody:           true,
+						MaxBodySize:           42,
+						PreserveRequestMethod: true,
+						Cache: &dynamic.ForwardAuthCache{
+							Headers: []string{
+								"foobar",
+								"fiibar",
+							},
+							TTL:        ptypes.Duration(42 * time.Second),
+							MaxEntries: 42,
+						},
 					},
 				},
 				"Middleware8": {
@@ -1146,6 +1194,9 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Middlewares.Middleware2.Buffering.RetryExpression":                           "foobar",
 		"traefik.HTTP.Middlewares.Middleware3.Chain.Middlewares":                                   "foobar, fiibar",
 		"traefik.HTTP.Middlewares.Middleware4.CircuitBreaker.Expression":                           "foobar",
+		"traefik.HTTP.Middlewares.Middleware4.CircuitBreaker.FallbackDuration":                     "0",
+		"traefik.HTTP.Middlewares.Middleware4.CircuitBreaker.ProbeRatio":                           "0.000000",
+		"traefik.HTTP.Middlewares.Middleware4.CircuitBreaker.RecoveryDuration":                     "0",
 		"traefik.HTTP.Middlewares.Middleware5.DigestAuth.HeaderField":                              "foobar",
 		"traefik.HTTP.Middlewares.Middleware5.DigestAuth.Realm":                                    "foobar",
 		"traefik.HTTP.Middlewares.Middleware5.DigestAuth.RemoveHeader":                             "true",
@@ -1157,6 +1208,12 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.Address":                                 "foobar",
 		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.AuthResponseHeaders":                     "foobar, fiibar",
 		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.AuthRequestHeaders":                      "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.Cache.Headers":                           "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.Cache.MaxEntries":                        "42",
+		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.Cache.TTL":                               "42000000000",
+		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.ForwardBody":                             "true",
+		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.MaxBodySize":                             "42",
+		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.PreserveRequestMethod":                   "true",
 		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.TLS.CA":                                  "foobar",
 		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.TLS.CAOptional":                          "true",
 		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.TLS.Cert":                                "foobar",
@@ -1245,7 +1302,14 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Middlewares.Middleware17.StripPrefix.Prefixes":                               "foobar, fiibar",
 		"traefik.HTTP.Middlewares.Middleware17.StripPrefix.ForceSlash":                             "true",
 		"traefik.HTTP.Middlewares.Middleware18.StripPrefixRegex.Regex":                             "foobar, fiibar",
-		"traefik.HTTP.Middlewares.Middleware19.Compress":                                           "true",
+		"traefik.HTTP.Middlewares.Middleware19.Compress.BrotliLevel":                               "0",
+		"traefik.HTTP.Middlewares.Middleware19.Compress.GzipLevel":                                 "0",
+		"traefik.HTTP.Middlewares.Middleware19.Compress.MinResponseBodyBytes":                      "0",
+		"traefik.HTTP.Middlewares.Middleware19.Compress.ZstdLevel":                                 "0",
+		"traefik.HTTP.Middlewares.Middleware19b.Compress.BrotliLevel":                              "5",
+		"traefik.HTTP.Middlewares.Middleware19b.Compress.GzipLevel":                                "6",
+		"traefik.HTTP.Middlewares.Middleware19b.Compress.MinResponseBodyBytes":                     "1024",
+		"traefik.HTTP.Middlewares.Middleware19b.Compress.ZstdLevel":                                "3",
 		"traefik.HTTP.Middlewares.Middleware20.Plugin.tomato.aaa":                                  "foo1",
 		"traefik.HTTP.Middlewares.Middleware20.Plugin.tomato.bbb":                                  "foo2",
 
@@ -1272,6 +1336,7 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Services.Service0.LoadBalancer.ResponseForwarding.FlushInterval": "foobar",
 		"traefik.HTTP.Services.Service0.LoadBalancer.server.Port":                      "8080",
 		"traefik.HTTP.Services.Service0.LoadBalancer.server.Scheme":                    "foobar",
+		"traefik.HTTP.Services.Service0.LoadBalancer.SlowStart":                        "0",
 		"traefik.HTTP.Services.Service0.LoadBalancer.Sticky.Cookie.Name":               "foobar",
 		"traefik.HTTP.Services.Service0.LoadBalancer.Sticky.Cookie.HTTPOnly":           "true",
 		"traefik.HTTP.Services.Service0.LoadBalancer.Sticky.Cookie.Secure":             "false",
@@ -1287,6 +1352,7 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Services.Service1.LoadBalancer.ResponseForwarding.FlushInterval": "foobar",
 		"traefik.HTTP.Services.Service1.LoadBalancer.server.Port":                      "8080",
 		"traefik.HTTP.Services.Service1.LoadBalancer.server.Scheme":                    "foobar",
+		"traefik.HTTP.Services.Service1.LoadBalancer.SlowStart":                        "0",
 		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Headers.name0":        "foobar",
 
 		"traefik.TCP.Routers.Router0.Rule":                            "foobar",
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/log"
@@ -107,6 +108,9 @@ type MiddlewareInfo struct {
 	Err    []string `json:"error,omitempty"`
 	Status string   `json:"status,omitempty"`
 	UsedBy []string `json:"usedBy,omitempty"` // list of routers and services using that middleware.
+
+	circuitBreakerStatesMu sync.RWMutex
+	circuitBreakerStates   map[string]string // keyed by router name, as each router gets its own circuit breaker.
 }
 
 // AddError adds err to s.Err, if it does not already exist.
@@ -130,6 +134,37 @@ func (m *MiddlewareInfo) AddError(err error, critical bool) {
 	}
 }
 
+// UpdateCircuitBreakerState sets the state of the circuit breaker of the given router in the MiddlewareInfo.
+// It is the responsibility of the caller to check that m is not nil.
+func (m *MiddlewareInfo) UpdateCircuitBreakerState(routerName, state string) {
+	m.circuitBreakerStatesMu.Lock()
+	defer m.circuitBreakerStatesMu.Unlock()
+
+	if m.circuitBreakerStates == nil {
+		m.circuitBreakerStates = make(map[string]string)
+	}
+
+	m.circuitBreakerStates[routerName] = state
+}
+
+// GetCircuitBreakerStates returns the states of the circuit breakers in the MiddlewareInfo, keyed by router name, if any.
+// It is the responsibility of the caller to check that m is not nil.
+func (m *MiddlewareInfo) GetCircuitBreakerStates() map[string]string {
+	m.circuitBreakerStatesMu.RLock()
+	defer m.circuitBreakerStatesMu.RUnlock()
+
+	if len(m.circuitBreakerStates) == 0 {
+		return nil
+	}
+
+	states := make(map[string]string, len(m.circuitBreakerStates))
+	for routerName, state := range m.circuitBreakerStates {
+		states[routerName] = state
+	}
+
+	return states
+}
+
 // ServiceInfo holds information about a currently running service.
 type ServiceInfo struct {
 	*dynamic.Service // dynamic configuration
@@ -143,6 +178,25 @@ type ServiceInfo struct {
 
 	serverStatusMu sync.RWMutex
 	serverStatus   map[string]string // keyed by server URL
+
+	mirrorMismatchesMu sync.RWMutex
+	mirrorMismatches   []MirrorMismatch
+}
+
+// MirrorMismatch describes a difference between the response of a mirror and the response of the mirrored service.
+type MirrorMismatch struct {
+	Time    time.Time `json:"time"`
+	Mirror  string    `json:"mirror"`
+	Method  string    `json:"method"`
+	Path    string    `json:"path"`
+	Reasons []string  `json:"reasons"`
+
+	Status       int `json:"status"`
+	MirrorStatus int `json:"mirrorStatus"`
+	// Headers is the list of the headers which differ.
+	Headers        []string `json:"headers,omitempty"`
+	BodyHash       string   `json:"bodyHash,omitempty"`
+	MirrorBodyHash string   `json:"mirrorBodyHash,omitempty"`
 }
 
 // AddError adds err to s.Err, if it does not already exist.
@@ -178,6 +232,16 @@ func (s *ServiceInfo) UpdateServerStatus(server, status string) {
 	s.serverStatus[server] = status
 }
 
+// RemoveServerStatus removes the status of the server from the ServiceInfo,
+// e.g. when the server is not part of the service anymore.
+// It is the responsibility of the caller to check that s is not nil.
+func (s *ServiceInfo) RemoveServerStatus(server string) {
+	s.serverStatusMu.Lock()
+	defer s.serverStatusMu.Unlock()
+
+	delete(s.serverStatus, server)
+}
+
 // GetAllStatus returns all the statuses of all the servers in ServiceInfo.
 // It is the responsibility of the caller to check that s is not nil.
 func (s *ServiceInfo) GetAllStatus() map[string]string {
@@ -194,3 +258,30 @@ func (s *ServiceInfo) GetAllStatus() map[string]string {
 	}
 	return allStatus
 }
+
+// AddMirrorMismatch adds a mismatch to the ServiceInfo, keeping only the sampleSize latest mismatches.
+// It is the responsibility of the caller to check that s is not nil.
+func (s *ServiceInfo) AddMirrorMismatch(mismatch MirrorMismatch, sampleSize int) {
+	s.mirrorMismatchesMu.Lock()
+	defer s.mirrorMismatchesMu.Unlock()
+
+	s.mirrorMismatches = append(s.mirrorMismatches, mismatch)
+	if len(s.mirrorMismatches) > sampleSize {
+		s.mirrorMismatches = s.mirrorMismatches[len(s.mirrorMismatches)-sampleSize:]
+	}
+}
+
+// GetMirrorMismatches returns the latest mismatches between the responses of the mirrors and of the service.
+// It is the responsibility of the caller to check that s is not nil.
+func (s *ServiceInfo) GetMirrorMismatches() []MirrorMismatch {
+	s.mirrorMismatchesMu.RLock()
+	defer s.mirrorMismatchesMu.RUnlock()
+
+	if len(s.mirrorMismatches) == 0 {
+		return nil
+	}
+
+	mismatches := make([]MirrorMismatch, len(s.mirrorMismatches))
+	copy(mismatches, s.mirrorMismatches)
+	return mismatches
+}
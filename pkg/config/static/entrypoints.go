@@ -3,6 +3,8 @@ package static
 import (
 	"fmt"
 	"math"
+	"os"
+	"strconv"
 	"strings"
 
 	ptypes "github.com/traefik/paerser/types"
@@ -18,18 +20,35 @@ type EntryPoint struct {
 	HTTP             HTTPConfig            `description:"HTTP configuration." json:"http,omitempty" toml:"http,omitempty" yaml:"http,omitempty" export:"true"`
 	EnableHTTP3      bool                  `description:"Enable HTTP3." json:"enableHTTP3,omitempty" toml:"enableHTTP3,omitempty" yaml:"enableHTTP3,omitempty" export:"true"`
 	UDP              *UDPConfig            `description:"UDP configuration." json:"udp,omitempty" toml:"udp,omitempty" yaml:"udp,omitempty"`
+	UnixSocket       *UnixSocket           `description:"Unix socket configuration, when the address is a unix socket." json:"unixSocket,omitempty" toml:"unixSocket,omitempty" yaml:"unixSocket,omitempty" export:"true"`
 }
 
 // GetAddress strips any potential protocol part of the address field of the
 // entry point, in order to return the actual address.
+// For a unix socket, it returns the path of the socket.
 func (ep EntryPoint) GetAddress() string {
+	if path, ok := types.UnixSocketPath(ep.Address); ok {
+		return path
+	}
+
 	splitN := strings.SplitN(ep.Address, "/", 2)
 	return splitN[0]
 }
 
+// IsUnixSocket returns whether the entry point listens on a unix socket.
+func (ep EntryPoint) IsUnixSocket() bool {
+	_, ok := types.UnixSocketPath(ep.Address)
+	return ok
+}
+
 // GetProtocol returns the protocol part of the address field of the entry point.
 // If none is specified, it defaults to "tcp".
+// A unix socket is always a "tcp" entry point.
 func (ep EntryPoint) GetProtocol() (string, error) {
+	if ep.IsUnixSocket() {
+		return "tcp", nil
+	}
+
 	splitN := strings.SplitN(ep.Address, "/", 2)
 	if len(splitN) < 2 {
 		return "tcp", nil
@@ -124,3 +143,24 @@ type UDPConfig struct {
 func (u *UDPConfig) SetDefaults() {
 	u.Timeout = ptypes.Duration(DefaultUDPTimeout)
 }
+
+// UnixSocket holds the configuration of the unix socket of an entry point.
+type UnixSocket struct {
+	Mode string `description:"File mode of the socket, in octal (e.g. 0660)." json:"mode,omitempty" toml:"mode,omitempty" yaml:"mode,omitempty" export:"true"`
+	UID  *int   `description:"User ID owning the socket." json:"uid,omitempty" toml:"uid,omitempty" yaml:"uid,omitempty" export:"true"`
+	GID  *int   `description:"Group ID owning the socket." json:"gid,omitempty" toml:"gid,omitempty" yaml:"gid,omitempty" export:"true"`
+}
+
+// FileMode returns the file mode of the socket, and whether one is configured.
+func (u *UnixSocket) FileMode() (os.FileMode, bool, error) {
+	if u == nil || u.Mode == "" {
+		return 0, false, nil
+	}
+
+	mode, err := strconv.ParseUint(u.Mode, 8, 32)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid unix socket mode %q: %w", u.Mode, err)
+	}
+
+	return os.FileMode(mode) & os.ModePerm, true, nil
+}
@@ -42,6 +42,20 @@ func TestEntryPointProtocol(t *testing.T) {
 			expectedProtocol: "udp",
 			expectedError:    false,
 		},
+		{
+			name:             "Unix socket",
+			address:          "unix:///run/traefik/traefik.sock",
+			expectedAddress:  "/run/traefik/traefik.sock",
+			expectedProtocol: "tcp",
+			expectedError:    false,
+		},
+		{
+			name:             "Relative unix socket",
+			address:          "unix://traefik.sock",
+			expectedAddress:  "traefik.sock",
+			expectedProtocol: "tcp",
+			expectedError:    false,
+		},
 
 		{
 			name:          "With invalid protocol",
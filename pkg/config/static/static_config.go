@@ -75,6 +75,8 @@ type Configuration struct {
 
 	HostResolver *types.HostResolverConfig `description:"Enable CNAME Flattening." json:"hostResolver,omitempty" toml:"hostResolver,omitempty" yaml:"hostResolver,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 
+	Cache *Cache `description:"Cache middlewares settings." json:"cache,omitempty" toml:"cache,omitempty" yaml:"cache,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+
 	CertificatesResolvers map[string]CertificateResolver `description:"Certificates resolvers configuration." json:"certificatesResolvers,omitempty" toml:"certificatesResolvers,omitempty" yaml:"certificatesResolvers,omitempty" export:"true"`
 
 	Pilot *Pilot `description:"Traefik Pilot configuration." json:"pilot,omitempty" toml:"pilot,omitempty" yaml:"pilot,omitempty" export:"true"`
@@ -106,6 +108,8 @@ type API struct {
 	Insecure  bool `description:"Activate API directly on the entryPoint named traefik." json:"insecure,omitempty" toml:"insecure,omitempty" yaml:"insecure,omitempty" export:"true"`
 	Dashboard bool `description:"Activate dashboard." json:"dashboard,omitempty" toml:"dashboard,omitempty" yaml:"dashboard,omitempty" export:"true"`
 	Debug     bool `description:"Enable additional endpoints for debugging and profiling." json:"debug,omitempty" toml:"debug,omitempty" yaml:"debug,omitempty" export:"true"`
+	// MaintenanceStorage is the file the maintenance states toggled through the API are persisted to.
+	MaintenanceStorage string `description:"File where the maintenance states toggled through the API are persisted." json:"maintenanceStorage,omitempty" toml:"maintenanceStorage,omitempty" yaml:"maintenanceStorage,omitempty" export:"true"`
 	// TODO: Re-enable statistics
 	// Statistics      *types.Statistics `description:"Enable more detailed statistics." json:"statistics,omitempty" toml:"statistics,omitempty" yaml:"statistics,omitempty" export:"true" label:"allowEmpty" file:"allowEmpty"`
 	DashboardAssets *assetfs.AssetFS `json:"-" toml:"-" yaml:"-" label:"-" file:"-"`
@@ -114,6 +118,18 @@ type API struct {
 // SetDefaults sets the default values.
 func (a *API) SetDefaults() {
 	a.Dashboard = true
+	a.MaintenanceStorage = "maintenance.json"
+}
+
+// Cache holds the settings of the cache middlewares which cannot come from the dynamic configuration.
+type Cache struct {
+	// DiskPath is the directory where the cache middlewares with a disk tier keep their responses, each in its own subdirectory.
+	DiskPath string `description:"Directory where the cache middlewares with a disk tier keep their responses." json:"diskPath,omitempty" toml:"diskPath,omitempty" yaml:"diskPath,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (c *Cache) SetDefaults() {
+	c.DiskPath = "cache"
 }
 
 // RespondingTimeouts contains timeout configurations for incoming requests to the Traefik instance.
@@ -280,6 +296,20 @@ func (c *Configuration) initACMEProvider() {
 
 // ValidateConfiguration validate that configuration is coherent.
 func (c *Configuration) ValidateConfiguration() error {
+	for name, entryPoint := range c.EntryPoints {
+		if !entryPoint.IsUnixSocket() {
+			continue
+		}
+
+		if entryPoint.EnableHTTP3 {
+			return fmt.Errorf("unable to initialize entry point %q: HTTP/3 is not supported on a unix socket", name)
+		}
+
+		if _, _, err := entryPoint.UnixSocket.FileMode(); err != nil {
+			return fmt.Errorf("unable to initialize entry point %q: %w", name, err)
+		}
+	}
+
 	var acmeEmail string
 	for name, resolver := range c.CertificatesResolvers {
 		if resolver.ACME == nil {
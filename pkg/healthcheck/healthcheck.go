@@ -15,6 +15,7 @@ import (
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/metrics"
 	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/traefik/traefik/v2/pkg/types"
 	"github.com/vulcand/oxy/roundrobin"
 )
 
@@ -35,6 +36,19 @@ type Balancer interface {
 	UpsertServer(u *url.URL, options ...roundrobin.ServerOption) error
 }
 
+// ServerWeighter is a Balancer which knows the weight of its servers.
+type ServerWeighter interface {
+	ServerWeight(u *url.URL) (int, bool)
+}
+
+// StatusUpdater is a handler which notifies its parents of the changes of its status,
+// e.g. when none of its servers are up anymore.
+type StatusUpdater interface {
+	// RegisterStatusUpdater registers a function called with the current status,
+	// and then each time the status changes.
+	RegisterStatusUpdater(fn func(up bool)) error
+}
+
 // BalancerHandler includes functionality for load-balancing management.
 type BalancerHandler interface {
 	ServeHTTP(w http.ResponseWriter, req *http.Request)
@@ -76,6 +90,16 @@ type BackendConfig struct {
 }
 
 func (b *BackendConfig) newRequest(serverURL *url.URL) (*http.Request, error) {
+	// The server listening on a unix socket is reached through the host standing for the socket.
+	if serverURL.Scheme == types.UnixSocketScheme {
+		u, err := (&url.URL{Scheme: types.UnixSocketScheme, Host: types.UnixSocketHost(serverURL.Path)}).Parse(b.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		return http.NewRequest(http.MethodGet, u.String(), http.NoBody)
+	}
+
 	u, err := serverURL.Parse(b.Path)
 	if err != nil {
 		return nil, err
@@ -179,15 +203,7 @@ func (hc *HealthCheck) checkBackend(ctx context.Context, backend *BackendConfig)
 		serverUpMetricValue := float64(1)
 
 		if err := checkHealth(enableURL, backend); err != nil {
-			weight := 1
-			rr, ok := backend.LB.(*roundrobin.RoundRobin)
-			if ok {
-				var gotWeight bool
-				weight, gotWeight = rr.ServerWeight(enableURL)
-				if !gotWeight {
-					weight = 1
-				}
-			}
+			weight := serverWeight(backend.LB, enableURL)
 
 			logger.Warnf("Health check failed, removing from server list. Backend: %q URL: %q Weight: %d Reason: %s",
 				backend.name, enableURL.String(), weight, err)
@@ -204,6 +220,21 @@ func (hc *HealthCheck) checkBackend(ctx context.Context, backend *BackendConfig)
 	}
 }
 
+// serverWeight returns the weight of the given server in the balancer, or 1 if it is unknown.
+func serverWeight(lb Balancer, u *url.URL) int {
+	weighter, ok := lb.(ServerWeighter)
+	if !ok {
+		return 1
+	}
+
+	weight, ok := weighter.ServerWeight(u)
+	if !ok {
+		return 1
+	}
+
+	return weight
+}
+
 // GetHealthCheck returns the health check which is guaranteed to be a singleton.
 func GetHealthCheck(registry metrics.Registry) *HealthCheck {
 	once.Do(func() {
@@ -274,9 +305,41 @@ func NewLBStatusUpdater(bh BalancerHandler, info *runtime.ServiceInfo) *LbStatus
 
 // LbStatusUpdater wraps a BalancerHandler and a ServiceInfo,
 // so it can keep track of the status of a server in the ServiceInfo.
+// It also notifies the registered status updaters when it has no servers anymore, and when it has servers again.
 type LbStatusUpdater struct {
 	BalancerHandler
 	serviceInfo *runtime.ServiceInfo // can be nil
+
+	updatersMu sync.Mutex
+	updaters   []func(up bool)
+	up         bool
+}
+
+// RegisterStatusUpdater registers a function called with the current status of the BalancerHandler,
+// and then each time the BalancerHandler has no servers anymore, or has servers again.
+func (lb *LbStatusUpdater) RegisterStatusUpdater(fn func(up bool)) error {
+	lb.updatersMu.Lock()
+	defer lb.updatersMu.Unlock()
+
+	lb.updaters = append(lb.updaters, fn)
+	fn(lb.up)
+
+	return nil
+}
+
+func (lb *LbStatusUpdater) updateStatus() {
+	lb.updatersMu.Lock()
+	defer lb.updatersMu.Unlock()
+
+	up := len(lb.BalancerHandler.Servers()) > 0
+	if up == lb.up {
+		return
+	}
+
+	lb.up = up
+	for _, fn := range lb.updaters {
+		fn(up)
+	}
 }
 
 // RemoveServer removes the given server from the BalancerHandler,
@@ -286,9 +349,20 @@ func (lb *LbStatusUpdater) RemoveServer(u *url.URL) error {
 	if err == nil && lb.serviceInfo != nil {
 		lb.serviceInfo.UpdateServerStatus(u.String(), serverDown)
 	}
+	lb.updateStatus()
 	return err
 }
 
+// ServerWeight returns the weight of the given server,
+// if the BalancerHandler knows the weight of its servers.
+func (lb *LbStatusUpdater) ServerWeight(u *url.URL) (int, bool) {
+	weighter, ok := lb.BalancerHandler.(ServerWeighter)
+	if !ok {
+		return 0, false
+	}
+	return weighter.ServerWeight(u)
+}
+
 // UpsertServer adds the given server to the BalancerHandler,
 // and updates the status of the server to "UP".
 func (lb *LbStatusUpdater) UpsertServer(u *url.URL, options ...roundrobin.ServerOption) error {
@@ -296,6 +370,7 @@ func (lb *LbStatusUpdater) UpsertServer(u *url.URL, options ...roundrobin.Server
 	if err == nil && lb.serviceInfo != nil {
 		lb.serviceInfo.UpdateServerStatus(u.String(), serverUp)
 	}
+	lb.updateStatus()
 	return err
 }
 
@@ -323,6 +398,21 @@ func (b Balancers) RemoveServer(u *url.URL) error {
 	return nil
 }
 
+// ServerWeight returns the weight of the given server in the first BalancerHandler which knows it.
+func (b Balancers) ServerWeight(u *url.URL) (int, bool) {
+	for _, lb := range b {
+		weighter, ok := lb.(ServerWeighter)
+		if !ok {
+			continue
+		}
+
+		if weight, ok := weighter.ServerWeight(u); ok {
+			return weight, true
+		}
+	}
+	return 0, false
+}
+
 // UpsertServer adds the given server to all the BalancerHandler,
 // and updates the status of the server to "UP".
 func (b Balancers) UpsertServer(u *url.URL, options ...roundrobin.ServerOption) error {
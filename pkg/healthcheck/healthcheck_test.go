@@ -251,6 +251,19 @@ func TestNewRequest(t *testing.T) {
 				value: "http://backend1:80/health?powpow=do&do=powpow",
 			},
 		},
+		{
+			desc:      "unix socket",
+			serverURL: "unix:///run/app.sock",
+			options: Options{
+				Scheme: "https",
+				Path:   "/health?powpow=do",
+				Port:   8080,
+			},
+			expected: expected{
+				err:   false,
+				value: "unix://2f72756e2f6170702e736f636b/health?powpow=do",
+			},
+		},
 		{
 			desc:      "path with invalid path",
 			serverURL: "http://backend1:80",
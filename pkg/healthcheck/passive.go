@@ -0,0 +1,307 @@
+package healthcheck
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+// PassiveOptions are the passive health check options.
+type PassiveOptions struct {
+	ConsecutiveErrors  int
+	ErrorRate          int
+	MinRequests        int
+	Interval           time.Duration
+	BaseEjectionTime   time.Duration
+	MaxEjectionTime    time.Duration
+	MaxEjectionPercent int
+}
+
+func (opt PassiveOptions) String() string {
+	return fmt.Sprintf("[ConsecutiveErrors: %d ErrorRate: %d MinRequests: %d Interval: %s BaseEjectionTime: %s MaxEjectionTime: %s MaxEjectionPercent: %d]",
+		opt.ConsecutiveErrors, opt.ErrorRate, opt.MinRequests, opt.Interval, opt.BaseEjectionTime, opt.MaxEjectionTime, opt.MaxEjectionPercent)
+}
+
+type serverOutcomes struct {
+	consecutiveErrors int
+
+	windowStart time.Time
+	requests    int
+	errors      int
+
+	ejected    bool
+	ejections  int
+	ejectedFor time.Duration
+	returnedAt time.Time
+}
+
+// PassiveHealthCheck ejects from a load balancer the servers failing to handle the forwarded requests,
+// either because of consecutive errors, or because of their error rate.
+// An error is a 5xx response, including the ones returned by the proxy when the server is unreachable.
+// An ejected server returns to the load balancer after an ejection time,
+// which doubles with each new ejection of the server, up to a maximum.
+// The last server of the load balancer is never ejected.
+type PassiveHealthCheck struct {
+	name          string
+	opts          PassiveOptions
+	serverUpGauge gokitmetrics.Gauge
+	now           func() time.Time
+
+	mutex   sync.Mutex
+	lb      Balancer
+	active  *BackendConfig // can be nil
+	servers map[string]*serverOutcomes
+	ejected int
+	// timers are the timers returning the ejected servers to the load balancer, keyed by server URL.
+	timers  map[string]*time.Timer
+	stopped bool
+}
+
+// NewPassiveHealthCheck creates a new PassiveHealthCheck for the given service.
+// The balancer the servers are ejected from must be set with SetBalancer.
+func NewPassiveHealthCheck(name string, opts PassiveOptions, serverUpGauge gokitmetrics.Gauge) *PassiveHealthCheck {
+	return &PassiveHealthCheck{
+		name:          name,
+		opts:          opts,
+		serverUpGauge: serverUpGauge,
+		now:           time.Now,
+		servers:       make(map[string]*serverOutcomes),
+		timers:        make(map[string]*time.Timer),
+	}
+}
+
+// SetBalancer sets the balancer the servers are ejected from.
+func (p *PassiveHealthCheck) SetBalancer(lb Balancer) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.lb = lb
+}
+
+// SetActiveHealthCheck sets the active health check of the service,
+// which an ejected server must pass before returning to the load balancer.
+func (p *PassiveHealthCheck) SetActiveHealthCheck(active *BackendConfig) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.active = active
+}
+
+// Stop stops returning the ejected servers to the load balancer, once it is replaced by the one of a new configuration.
+func (p *PassiveHealthCheck) Stop() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.stopped = true
+	for _, timer := range p.timers {
+		timer.Stop()
+	}
+}
+
+// Wrap returns a handler recording the outcome of the requests forwarded by next,
+// to the server URL set on the request by the load balancer.
+func (p *PassiveHealthCheck) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		serverURL := *req.URL
+
+		recorder := &statusRecorder{ResponseWriter: rw, statusCode: http.StatusOK}
+		next.ServeHTTP(recorder, req)
+
+		p.record(req.Context(), &serverURL, recorder.statusCode >= http.StatusInternalServerError)
+	})
+}
+
+func (p *PassiveHealthCheck) record(ctx context.Context, u *url.URL, failed bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	outcomes, ok := p.servers[u.String()]
+	if !ok {
+		outcomes = &serverOutcomes{}
+		p.servers[u.String()] = outcomes
+	}
+
+	// The requests forwarded before the ejection are ignored.
+	if outcomes.ejected {
+		return
+	}
+
+	now := p.now()
+	if now.Sub(outcomes.windowStart) >= p.opts.Interval {
+		outcomes.windowStart = now
+		outcomes.requests = 0
+		outcomes.errors = 0
+	}
+
+	outcomes.requests++
+	if !failed {
+		outcomes.consecutiveErrors = 0
+		return
+	}
+
+	outcomes.errors++
+	outcomes.consecutiveErrors++
+
+	var reason string
+	switch {
+	case p.opts.ConsecutiveErrors > 0 && outcomes.consecutiveErrors >= p.opts.ConsecutiveErrors:
+		reason = fmt.Sprintf("%d consecutive errors", outcomes.consecutiveErrors)
+	case p.opts.ErrorRate > 0 && outcomes.requests >= p.opts.MinRequests && outcomes.errors*100 >= p.opts.ErrorRate*outcomes.requests:
+		reason = fmt.Sprintf("%d errors out of %d requests", outcomes.errors, outcomes.requests)
+	default:
+		return
+	}
+
+	p.eject(ctx, u, outcomes, reason)
+}
+
+func (p *PassiveHealthCheck) eject(ctx context.Context, u *url.URL, outcomes *serverOutcomes, reason string) {
+	logger := log.FromContext(ctx)
+
+	if p.lb == nil || p.stopped {
+		return
+	}
+
+	remaining := len(p.lb.Servers())
+	if remaining <= 1 {
+		logger.Debugf("Passive health check: not ejecting server %q of service %q (%s), it is the last server", u, p.name, reason)
+		return
+	}
+
+	// At least one server can be ejected, whatever the number of servers.
+	total := remaining + p.ejected
+	if p.ejected > 0 && (p.ejected+1)*100 > p.opts.MaxEjectionPercent*total {
+		logger.Debugf("Passive health check: not ejecting server %q of service %q (%s), too many servers are already ejected", u, p.name, reason)
+		return
+	}
+
+	weight := serverWeight(p.lb, u)
+
+	if err := p.lb.RemoveServer(u); err != nil {
+		// The server was removed by the active health check in the meantime.
+		logger.Debugf("Passive health check: unable to eject server %q of service %q: %v", u, p.name, err)
+		return
+	}
+
+	// The ejection time grows back to its base value once the server works for as long as its last ejection.
+	if now := p.now(); outcomes.ejections > 0 && now.Sub(outcomes.returnedAt) > outcomes.ejectedFor {
+		outcomes.ejections = 0
+	}
+
+	outcomes.ejections++
+	outcomes.ejectedFor = p.ejectionTime(outcomes.ejections)
+	outcomes.ejected = true
+	p.ejected++
+
+	logger.Warnf("Passive health check failed, ejecting from server list. Backend: %q URL: %q Weight: %d Duration: %s Reason: %s",
+		p.name, u.String(), weight, outcomes.ejectedFor, reason)
+
+	p.serverUpGauge.With("service", p.name, "url", u.String()).Set(0)
+
+	p.scheduleRestore(ctx, u, weight, outcomes.ejectedFor)
+}
+
+// scheduleRestore returns the server to the load balancer after the given duration.
+// It is the responsibility of the caller to hold the lock.
+func (p *PassiveHealthCheck) scheduleRestore(ctx context.Context, u *url.URL, weight int, after time.Duration) {
+	p.timers[u.String()] = time.AfterFunc(after, func() {
+		p.restore(ctx, u, weight)
+	})
+}
+
+// ejectionTime returns the duration of the given ejection of a server.
+func (p *PassiveHealthCheck) ejectionTime(ejections int) time.Duration {
+	ejectionTime := p.opts.BaseEjectionTime
+	for i := 1; i < ejections && ejectionTime < p.opts.MaxEjectionTime; i++ {
+		ejectionTime *= 2
+	}
+
+	if ejectionTime > p.opts.MaxEjectionTime {
+		return p.opts.MaxEjectionTime
+	}
+
+	return ejectionTime
+}
+
+func (p *PassiveHealthCheck) restore(ctx context.Context, u *url.URL, weight int) {
+	logger := log.FromContext(ctx)
+
+	p.mutex.Lock()
+	active := p.active
+	stopped := p.stopped
+	p.mutex.Unlock()
+
+	if stopped {
+		return
+	}
+
+	// The server is checked outside of the lock, as it can take up to the timeout of the active health check.
+	var activeErr error
+	if active != nil {
+		activeErr = checkHealth(u, active)
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.stopped {
+		return
+	}
+
+	outcomes := p.servers[u.String()]
+
+	if activeErr != nil {
+		logger.Warnf("Passive health check: not returning to server list, the active health check failed. Backend: %q URL: %q Reason: %s", p.name, u.String(), activeErr)
+		p.scheduleRestore(ctx, u, weight, outcomes.ejectedFor)
+		return
+	}
+
+	delete(p.timers, u.String())
+	outcomes.ejected = false
+	outcomes.returnedAt = p.now()
+	outcomes.consecutiveErrors = 0
+	outcomes.windowStart = time.Time{}
+	p.ejected--
+
+	logger.Warnf("Passive health check: returning to server list. Backend: %q URL: %q Weight: %d", p.name, u.String(), weight)
+
+	if err := p.lb.UpsertServer(u, roundrobin.Weight(weight)); err != nil {
+		logger.Error(err)
+		return
+	}
+
+	p.serverUpGauge.With("service", p.name, "url", u.String()).Set(1)
+}
+
+// statusRecorder captures the status code of the response.
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+// WriteHeader captures the status code for later retrieval.
+func (r *statusRecorder) WriteHeader(status int) {
+	r.ResponseWriter.WriteHeader(status)
+	r.statusCode = status
+}
+
+// Hijack hijacks the connection.
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return r.ResponseWriter.(http.Hijacker).Hijack()
+}
+
+// Flush sends any buffered data to the client.
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
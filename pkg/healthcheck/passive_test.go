@@ -0,0 +1,267 @@
+package healthcheck
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/testhelpers"
+)
+
+func TestPassiveHealthCheck(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		opts            PassiveOptions
+		servers         int
+		statuses        []int
+		expectedEjected int
+	}{
+		{
+			desc:            "consecutive errors",
+			opts:            PassiveOptions{ConsecutiveErrors: 3},
+			servers:         2,
+			statuses:        []int{http.StatusBadGateway, http.StatusInternalServerError, http.StatusServiceUnavailable},
+			expectedEjected: 1,
+		},
+		{
+			desc:            "consecutive errors interrupted by a success",
+			opts:            PassiveOptions{ConsecutiveErrors: 3},
+			servers:         1,
+			statuses:        []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusOK, http.StatusBadGateway, http.StatusBadGateway},
+			expectedEjected: 0,
+		},
+		{
+			desc:            "client errors are not errors",
+			opts:            PassiveOptions{ConsecutiveErrors: 3},
+			servers:         1,
+			statuses:        []int{http.StatusNotFound, http.StatusNotFound, http.StatusNotFound},
+			expectedEjected: 0,
+		},
+		{
+			desc:            "error rate",
+			opts:            PassiveOptions{ConsecutiveErrors: 10, ErrorRate: 50, MinRequests: 4, Interval: time.Minute},
+			servers:         2,
+			statuses:        []int{http.StatusOK, http.StatusBadGateway, http.StatusOK, http.StatusBadGateway},
+			expectedEjected: 1,
+		},
+		{
+			desc:            "error rate below the minimum number of requests",
+			opts:            PassiveOptions{ConsecutiveErrors: 10, ErrorRate: 50, MinRequests: 5, Interval: time.Minute},
+			servers:         1,
+			statuses:        []int{http.StatusOK, http.StatusBadGateway, http.StatusOK, http.StatusBadGateway},
+			expectedEjected: 0,
+		},
+		{
+			desc:            "consecutive errors disabled",
+			opts:            PassiveOptions{ErrorRate: 100, MinRequests: 10, Interval: time.Minute},
+			servers:         2,
+			statuses:        []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway},
+			expectedEjected: 0,
+		},
+		{
+			desc:            "last server never ejected",
+			opts:            PassiveOptions{ConsecutiveErrors: 1, MaxEjectionPercent: 100},
+			servers:         1,
+			statuses:        []int{http.StatusBadGateway, http.StatusBadGateway},
+			expectedEjected: 0,
+		},
+		{
+			desc:            "max ejection percent",
+			opts:            PassiveOptions{ConsecutiveErrors: 1, MaxEjectionPercent: 50},
+			servers:         4,
+			statuses:        []int{http.StatusBadGateway},
+			expectedEjected: 2,
+		},
+		{
+			desc:            "at least one server can be ejected",
+			opts:            PassiveOptions{ConsecutiveErrors: 1, MaxEjectionPercent: 10},
+			servers:         4,
+			statuses:        []int{http.StatusBadGateway},
+			expectedEjected: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			test.opts.BaseEjectionTime = time.Hour
+			test.opts.MaxEjectionTime = time.Hour
+
+			lb := &testLoadBalancer{RWMutex: &sync.RWMutex{}}
+			for i := 0; i < test.servers; i++ {
+				lb.servers = append(lb.servers, testhelpers.MustParseURL(fmt.Sprintf("http://10.10.10.%d", i)))
+			}
+			servers := lb.Servers()
+
+			gauge := &testhelpers.CollectingGauge{}
+			passive := NewPassiveHealthCheck("foo", test.opts, gauge)
+			passive.SetBalancer(lb)
+
+			var status int
+			handler := passive.Wrap(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(status)
+			}))
+
+			for _, server := range servers {
+				for _, status = range test.statuses {
+					req := httptest.NewRequest(http.MethodGet, "/", nil)
+					req.URL = server
+					handler.ServeHTTP(httptest.NewRecorder(), req)
+				}
+			}
+
+			assert.Equal(t, test.expectedEjected, lb.numRemovedServers)
+			assert.Len(t, lb.Servers(), test.servers-test.expectedEjected)
+		})
+	}
+}
+
+func TestPassiveHealthCheck_Restore(t *testing.T) {
+	lb := &testLoadBalancer{RWMutex: &sync.RWMutex{}}
+	serviceInfo := &runtime.ServiceInfo{}
+	lbsu := NewLBStatusUpdater(lb, serviceInfo)
+
+	server := testhelpers.MustParseURL("http://10.10.10.1")
+	other := testhelpers.MustParseURL("http://10.10.10.2")
+	assert.NoError(t, lbsu.UpsertServer(server))
+	assert.NoError(t, lbsu.UpsertServer(other))
+
+	gauge := &testhelpers.CollectingGauge{}
+	passive := NewPassiveHealthCheck("foo", PassiveOptions{
+		ConsecutiveErrors: 1,
+		BaseEjectionTime:  50 * time.Millisecond,
+		MaxEjectionTime:   time.Second,
+	}, gauge)
+	passive.SetBalancer(lbsu)
+
+	handler := passive.Wrap(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusBadGateway)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.URL = server
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, []*url.URL{other}, lbsu.Servers())
+	assert.Equal(t, serverDown, serviceInfo.GetAllStatus()[server.String()])
+	assert.Equal(t, float64(0), gauge.GaugeValue)
+
+	assert.Eventually(t, func() bool {
+		passive.mutex.Lock()
+		defer passive.mutex.Unlock()
+		return passive.ejected == 0
+	}, time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, []*url.URL{other, server}, lbsu.Servers())
+	assert.Equal(t, serverUp, serviceInfo.GetAllStatus()[server.String()])
+	assert.Equal(t, float64(1), gauge.GaugeValue)
+}
+
+func TestPassiveHealthCheck_RestoreActiveHealthCheck(t *testing.T) {
+	var healthy int32
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if atomic.LoadInt32(&healthy) == 0 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	lb := &testLoadBalancer{RWMutex: &sync.RWMutex{}}
+
+	server := testhelpers.MustParseURL(ts.URL)
+	other := testhelpers.MustParseURL("http://10.10.10.2")
+	lb.servers = []*url.URL{server, other}
+
+	passive := NewPassiveHealthCheck("foo", PassiveOptions{
+		ConsecutiveErrors: 1,
+		BaseEjectionTime:  20 * time.Millisecond,
+		MaxEjectionTime:   time.Second,
+	}, &testhelpers.CollectingGauge{})
+	passive.SetBalancer(lb)
+	passive.SetActiveHealthCheck(NewBackendConfig(Options{Path: "/health", Timeout: time.Second, LB: lb}, "foo"))
+
+	handler := passive.Wrap(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusBadGateway)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.URL = server
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, []*url.URL{other}, lb.Servers())
+
+	// The server stays ejected while it fails the active health check.
+	time.Sleep(100 * time.Millisecond)
+
+	lb.RLock()
+	assert.Equal(t, []*url.URL{other}, lb.servers)
+	lb.RUnlock()
+
+	atomic.StoreInt32(&healthy, 1)
+
+	assert.Eventually(t, func() bool {
+		passive.mutex.Lock()
+		defer passive.mutex.Unlock()
+		return passive.ejected == 0
+	}, time.Second, 10*time.Millisecond)
+
+	lb.RLock()
+	assert.Equal(t, []*url.URL{other, server}, lb.servers)
+	lb.RUnlock()
+}
+
+func TestPassiveHealthCheck_Stop(t *testing.T) {
+	lb := &testLoadBalancer{RWMutex: &sync.RWMutex{}}
+
+	server := testhelpers.MustParseURL("http://10.10.10.1")
+	other := testhelpers.MustParseURL("http://10.10.10.2")
+	lb.servers = []*url.URL{server, other}
+
+	gauge := &testhelpers.CollectingGauge{}
+	passive := NewPassiveHealthCheck("foo", PassiveOptions{
+		ConsecutiveErrors: 1,
+		BaseEjectionTime:  20 * time.Millisecond,
+		MaxEjectionTime:   time.Second,
+	}, gauge)
+	passive.SetBalancer(lb)
+
+	handler := passive.Wrap(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusBadGateway)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.URL = server
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	passive.Stop()
+
+	// The ejected server does not return to the load balancer of the previous configuration.
+	time.Sleep(100 * time.Millisecond)
+
+	lb.RLock()
+	assert.Equal(t, []*url.URL{other}, lb.servers)
+	lb.RUnlock()
+	assert.Equal(t, float64(0), gauge.GaugeValue)
+}
+
+func TestPassiveHealthCheck_ejectionTime(t *testing.T) {
+	passive := NewPassiveHealthCheck("foo", PassiveOptions{
+		BaseEjectionTime: 10 * time.Second,
+		MaxEjectionTime:  time.Minute,
+	}, &testhelpers.CollectingGauge{})
+
+	assert.Equal(t, 10*time.Second, passive.ejectionTime(1))
+	assert.Equal(t, 20*time.Second, passive.ejectionTime(2))
+	assert.Equal(t, 40*time.Second, passive.ejectionTime(3))
+	assert.Equal(t, time.Minute, passive.ejectionTime(4))
+	assert.Equal(t, time.Minute, passive.ejectionTime(100))
+}
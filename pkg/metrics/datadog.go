@@ -26,6 +26,9 @@ const (
 	ddLastConfigReloadFailureName   = "config.reload.lastFailureTimestamp"
 	ddTLSCertsNotAfterTimestampName = "tls.certs.notAfterTimestamp"
 
+	ddMiddlewareCacheRequestsName             = "middleware.cache.request.total"
+	ddMiddlewareCircuitBreakerTransitionsName = "middleware.circuitbreaker.transition.total"
+
 	ddEntryPointReqsName        = "entrypoint.request.total"
 	ddEntryPointReqsTLSName     = "entrypoint.request.tls.total"
 	ddEntryPointReqDurationName = "entrypoint.request.duration"
@@ -42,6 +45,32 @@ const (
 	ddRetriesTotalName               = "service.retries.total"
 	ddOpenConnsName                  = "service.connections.open"
 	ddServerUpName                   = "service.server.up"
+	ddMirrorMismatchesTotalName      = "service.mirror.mismatches.total"
+
+	ddTCPServiceServerOpenConnsName = "tcp.service.server.connections.open"
+
+	ddTCPEntryPointConnsName        = "tcp.entrypoint.connections.total"
+	ddTCPEntryPointOpenConnsName    = "tcp.entrypoint.connections.open"
+	ddTCPEntryPointConnDurationName = "tcp.entrypoint.connection.duration"
+	ddTCPEntryPointBytesName        = "tcp.entrypoint.bytes.total"
+
+	ddTCPRouterConnsName        = "tcp.router.connections.total"
+	ddTCPRouterOpenConnsName    = "tcp.router.connections.open"
+	ddTCPRouterConnDurationName = "tcp.router.connection.duration"
+	ddTCPRouterBytesName        = "tcp.router.bytes.total"
+
+	ddTCPServiceConnsName        = "tcp.service.connections.total"
+	ddTCPServiceOpenConnsName    = "tcp.service.connections.open"
+	ddTCPServiceConnDurationName = "tcp.service.connection.duration"
+	ddTCPServiceBytesName        = "tcp.service.bytes.total"
+
+	ddUDPEntryPointSessionsName = "udp.entrypoint.sessions.total"
+
+	ddUDPRouterSessionsName = "udp.router.sessions.total"
+
+	ddUDPServiceSessionsName  = "udp.service.sessions.total"
+	ddUDPServiceDatagramsName = "udp.service.datagrams.total"
+	ddUDPServiceBytesName     = "udp.service.bytes.total"
 )
 
 // RegisterDatadog registers the metrics pusher if this didn't happen yet and creates a datadog Registry instance.
@@ -51,11 +80,13 @@ func RegisterDatadog(ctx context.Context, config *types.Datadog) Registry {
 	}
 
 	registry := &standardRegistry{
-		configReloadsCounter:           datadogClient.NewCounter(ddConfigReloadsName, 1.0),
-		configReloadsFailureCounter:    datadogClient.NewCounter(ddConfigReloadsName, 1.0).With(ddConfigReloadsFailureTagName, "true"),
-		lastConfigReloadSuccessGauge:   datadogClient.NewGauge(ddLastConfigReloadSuccessName),
-		lastConfigReloadFailureGauge:   datadogClient.NewGauge(ddLastConfigReloadFailureName),
-		tlsCertsNotAfterTimestampGauge: datadogClient.NewGauge(ddTLSCertsNotAfterTimestampName),
+		configReloadsCounter:                       datadogClient.NewCounter(ddConfigReloadsName, 1.0),
+		configReloadsFailureCounter:                datadogClient.NewCounter(ddConfigReloadsName, 1.0).With(ddConfigReloadsFailureTagName, "true"),
+		lastConfigReloadSuccessGauge:               datadogClient.NewGauge(ddLastConfigReloadSuccessName),
+		lastConfigReloadFailureGauge:               datadogClient.NewGauge(ddLastConfigReloadFailureName),
+		tlsCertsNotAfterTimestampGauge:             datadogClient.NewGauge(ddTLSCertsNotAfterTimestampName),
+		middlewareCacheRequestsCounter:             datadogClient.NewCounter(ddMiddlewareCacheRequestsName, 1.0),
+		middlewareCircuitBreakerTransitionsCounter: datadogClient.NewCounter(ddMiddlewareCircuitBreakerTransitionsName, 1.0),
 	}
 
 	if config.AddEntryPointsLabels {
@@ -64,6 +95,11 @@ func RegisterDatadog(ctx context.Context, config *types.Datadog) Registry {
 		registry.entryPointReqsTLSCounter = datadogClient.NewCounter(ddEntryPointReqsTLSName, 1.0)
 		registry.entryPointReqDurationHistogram, _ = NewHistogramWithScale(datadogClient.NewHistogram(ddEntryPointReqDurationName, 1.0), time.Second)
 		registry.entryPointOpenConnsGauge = datadogClient.NewGauge(ddEntryPointOpenConnsName)
+		registry.tcpEntryPointConnsCounter = datadogClient.NewCounter(ddTCPEntryPointConnsName, 1.0)
+		registry.tcpEntryPointOpenConnsGauge = datadogClient.NewGauge(ddTCPEntryPointOpenConnsName)
+		registry.tcpEntryPointConnDurationHistogram, _ = NewHistogramWithScale(datadogClient.NewHistogram(ddTCPEntryPointConnDurationName, 1.0), time.Second)
+		registry.tcpEntryPointBytesCounter = datadogClient.NewCounter(ddTCPEntryPointBytesName, 1.0)
+		registry.udpEntryPointSessionsCounter = datadogClient.NewCounter(ddUDPEntryPointSessionsName, 1.0)
 	}
 
 	if config.AddRoutersLabels {
@@ -72,6 +108,11 @@ func RegisterDatadog(ctx context.Context, config *types.Datadog) Registry {
 		registry.routerReqsTLSCounter = datadogClient.NewCounter(ddMetricsRouterReqsTLSName, 1.0)
 		registry.routerReqDurationHistogram, _ = NewHistogramWithScale(datadogClient.NewHistogram(ddMetricsRouterReqsDurationName, 1.0), time.Second)
 		registry.routerOpenConnsGauge = datadogClient.NewGauge(ddRouterOpenConnsName)
+		registry.tcpRouterConnsCounter = datadogClient.NewCounter(ddTCPRouterConnsName, 1.0)
+		registry.tcpRouterOpenConnsGauge = datadogClient.NewGauge(ddTCPRouterOpenConnsName)
+		registry.tcpRouterConnDurationHistogram, _ = NewHistogramWithScale(datadogClient.NewHistogram(ddTCPRouterConnDurationName, 1.0), time.Second)
+		registry.tcpRouterBytesCounter = datadogClient.NewCounter(ddTCPRouterBytesName, 1.0)
+		registry.udpRouterSessionsCounter = datadogClient.NewCounter(ddUDPRouterSessionsName, 1.0)
 	}
 
 	if config.AddServicesLabels {
@@ -82,6 +123,15 @@ func RegisterDatadog(ctx context.Context, config *types.Datadog) Registry {
 		registry.serviceRetriesCounter = datadogClient.NewCounter(ddRetriesTotalName, 1.0)
 		registry.serviceOpenConnsGauge = datadogClient.NewGauge(ddOpenConnsName)
 		registry.serviceServerUpGauge = datadogClient.NewGauge(ddServerUpName)
+		registry.serviceMirrorMismatchesCounter = datadogClient.NewCounter(ddMirrorMismatchesTotalName, 1.0)
+		registry.tcpServiceServerOpenConnsGauge = datadogClient.NewGauge(ddTCPServiceServerOpenConnsName)
+		registry.tcpServiceConnsCounter = datadogClient.NewCounter(ddTCPServiceConnsName, 1.0)
+		registry.tcpServiceOpenConnsGauge = datadogClient.NewGauge(ddTCPServiceOpenConnsName)
+		registry.tcpServiceConnDurationHistogram, _ = NewHistogramWithScale(datadogClient.NewHistogram(ddTCPServiceConnDurationName, 1.0), time.Second)
+		registry.tcpServiceBytesCounter = datadogClient.NewCounter(ddTCPServiceBytesName, 1.0)
+		registry.udpServiceSessionsCounter = datadogClient.NewCounter(ddUDPServiceSessionsName, 1.0)
+		registry.udpServiceDatagramsCounter = datadogClient.NewCounter(ddUDPServiceDatagramsName, 1.0)
+		registry.udpServiceBytesCounter = datadogClient.NewCounter(ddUDPServiceBytesName, 1.0)
 	}
 
 	return registry
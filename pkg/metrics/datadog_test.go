@@ -32,6 +32,9 @@ func TestDatadog(t *testing.T) {
 
 		"traefik.tls.certs.notAfterTimestamp:1.000000|g|#key:value\n",
 
+		"traefik.middleware.cache.request.total:1.000000|c|#middleware:test,status:hit\n",
+		"traefik.middleware.circuitbreaker.transition.total:1.000000|c|#middleware:test,router:test,state:tripped\n",
+
 		"traefik.entrypoint.request.total:1.000000|c|#entrypoint:test\n",
 		"traefik.entrypoint.request.tls.total:1.000000|c|#entrypoint:test,tls_version:foo,tls_cipher:bar\n",
 		"traefik.entrypoint.request.duration:10000.000000|h|#entrypoint:test\n",
@@ -51,6 +54,7 @@ func TestDatadog(t *testing.T) {
 		"traefik.service.retries.total:2.000000|c|#service:test\n",
 		"traefik.service.request.duration:10000.000000|h|#service:test,code:200\n",
 		"traefik.service.server.up:1.000000|g|#service:test,url:http://127.0.0.1,one:two\n",
+		"traefik.service.mirror.mismatches.total:1.000000|c|#service:test,mirror:mirror,reason:status\n",
 	}
 
 	udp.ShouldReceiveAll(t, expected, func() {
@@ -61,6 +65,9 @@ func TestDatadog(t *testing.T) {
 
 		datadogRegistry.TLSCertsNotAfterTimestampGauge().With("key", "value").Set(1)
 
+		datadogRegistry.MiddlewareCacheRequestsCounter().With("middleware", "test", "status", "hit").Add(1)
+		datadogRegistry.MiddlewareCircuitBreakerTransitionsCounter().With("middleware", "test", "router", "test", "state", "tripped").Add(1)
+
 		datadogRegistry.EntryPointReqsCounter().With("entrypoint", "test").Add(1)
 		datadogRegistry.EntryPointReqsTLSCounter().With("entrypoint", "test", "tls_version", "foo", "tls_cipher", "bar").Add(1)
 		datadogRegistry.EntryPointReqDurationHistogram().With("entrypoint", "test").Observe(10000)
@@ -80,5 +87,6 @@ func TestDatadog(t *testing.T) {
 		datadogRegistry.ServiceRetriesCounter().With("service", "test").Add(1)
 		datadogRegistry.ServiceRetriesCounter().With("service", "test").Add(1)
 		datadogRegistry.ServiceServerUpGauge().With("service", "test", "url", "http://127.0.0.1", "one", "two").Set(1)
+		datadogRegistry.ServiceMirrorMismatchesCounter().With("service", "test", "mirror", "mirror", "reason", "status").Add(1)
 	})
 }
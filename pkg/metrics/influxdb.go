@@ -33,6 +33,9 @@ const (
 
 	influxDBTLSCertsNotAfterTimestampName = "traefik.tls.certs.notAfterTimestamp"
 
+	influxDBMiddlewareCacheRequestsName             = "traefik.middleware.cache.requests.total"
+	influxDBMiddlewareCircuitBreakerTransitionsName = "traefik.middleware.circuitbreaker.transitions.total"
+
 	influxDBEntryPointReqsName        = "traefik.entrypoint.requests.total"
 	influxDBEntryPointReqsTLSName     = "traefik.entrypoint.requests.tls.total"
 	influxDBEntryPointReqDurationName = "traefik.entrypoint.request.duration"
@@ -49,6 +52,33 @@ const (
 	influxDBServiceRetriesTotalName = "traefik.service.retries.total"
 	influxDBServiceOpenConnsName    = "traefik.service.connections.open"
 	influxDBServiceServerUpName     = "traefik.service.server.up"
+
+	influxDBServiceMirrorMismatchesTotalName = "traefik.service.mirror.mismatches.total"
+
+	influxDBTCPServiceServerOpenConnsName = "traefik.tcp.service.server.connections.open"
+
+	influxDBTCPEntryPointConnsName        = "traefik.tcp.entrypoint.connections.total"
+	influxDBTCPEntryPointOpenConnsName    = "traefik.tcp.entrypoint.connections.open"
+	influxDBTCPEntryPointConnDurationName = "traefik.tcp.entrypoint.connection.duration"
+	influxDBTCPEntryPointBytesName        = "traefik.tcp.entrypoint.bytes.total"
+
+	influxDBTCPRouterConnsName        = "traefik.tcp.router.connections.total"
+	influxDBTCPRouterOpenConnsName    = "traefik.tcp.router.connections.open"
+	influxDBTCPRouterConnDurationName = "traefik.tcp.router.connection.duration"
+	influxDBTCPRouterBytesName        = "traefik.tcp.router.bytes.total"
+
+	influxDBTCPServiceConnsName        = "traefik.tcp.service.connections.total"
+	influxDBTCPServiceOpenConnsName    = "traefik.tcp.service.connections.open"
+	influxDBTCPServiceConnDurationName = "traefik.tcp.service.connection.duration"
+	influxDBTCPServiceBytesName        = "traefik.tcp.service.bytes.total"
+
+	influxDBUDPEntryPointSessionsName = "traefik.udp.entrypoint.sessions.total"
+
+	influxDBUDPRouterSessionsName = "traefik.udp.router.sessions.total"
+
+	influxDBUDPServiceSessionsName  = "traefik.udp.service.sessions.total"
+	influxDBUDPServiceDatagramsName = "traefik.udp.service.datagrams.total"
+	influxDBUDPServiceBytesName     = "traefik.udp.service.bytes.total"
 )
 
 const (
@@ -66,11 +96,13 @@ func RegisterInfluxDB(ctx context.Context, config *types.InfluxDB) Registry {
 	}
 
 	registry := &standardRegistry{
-		configReloadsCounter:           influxDBClient.NewCounter(influxDBConfigReloadsName),
-		configReloadsFailureCounter:    influxDBClient.NewCounter(influxDBConfigReloadsFailureName),
-		lastConfigReloadSuccessGauge:   influxDBClient.NewGauge(influxDBLastConfigReloadSuccessName),
-		lastConfigReloadFailureGauge:   influxDBClient.NewGauge(influxDBLastConfigReloadFailureName),
-		tlsCertsNotAfterTimestampGauge: influxDBClient.NewGauge(influxDBTLSCertsNotAfterTimestampName),
+		configReloadsCounter:                       influxDBClient.NewCounter(influxDBConfigReloadsName),
+		configReloadsFailureCounter:                influxDBClient.NewCounter(influxDBConfigReloadsFailureName),
+		lastConfigReloadSuccessGauge:               influxDBClient.NewGauge(influxDBLastConfigReloadSuccessName),
+		lastConfigReloadFailureGauge:               influxDBClient.NewGauge(influxDBLastConfigReloadFailureName),
+		tlsCertsNotAfterTimestampGauge:             influxDBClient.NewGauge(influxDBTLSCertsNotAfterTimestampName),
+		middlewareCacheRequestsCounter:             influxDBClient.NewCounter(influxDBMiddlewareCacheRequestsName),
+		middlewareCircuitBreakerTransitionsCounter: influxDBClient.NewCounter(influxDBMiddlewareCircuitBreakerTransitionsName),
 	}
 
 	if config.AddEntryPointsLabels {
@@ -79,6 +111,11 @@ func RegisterInfluxDB(ctx context.Context, config *types.InfluxDB) Registry {
 		registry.entryPointReqsTLSCounter = influxDBClient.NewCounter(influxDBEntryPointReqsTLSName)
 		registry.entryPointReqDurationHistogram, _ = NewHistogramWithScale(influxDBClient.NewHistogram(influxDBEntryPointReqDurationName), time.Second)
 		registry.entryPointOpenConnsGauge = influxDBClient.NewGauge(influxDBEntryPointOpenConnsName)
+		registry.tcpEntryPointConnsCounter = influxDBClient.NewCounter(influxDBTCPEntryPointConnsName)
+		registry.tcpEntryPointOpenConnsGauge = influxDBClient.NewGauge(influxDBTCPEntryPointOpenConnsName)
+		registry.tcpEntryPointConnDurationHistogram, _ = NewHistogramWithScale(influxDBClient.NewHistogram(influxDBTCPEntryPointConnDurationName), time.Second)
+		registry.tcpEntryPointBytesCounter = influxDBClient.NewCounter(influxDBTCPEntryPointBytesName)
+		registry.udpEntryPointSessionsCounter = influxDBClient.NewCounter(influxDBUDPEntryPointSessionsName)
 	}
 
 	if config.AddRoutersLabels {
@@ -87,6 +124,11 @@ func RegisterInfluxDB(ctx context.Context, config *types.InfluxDB) Registry {
 		registry.routerReqsTLSCounter = influxDBClient.NewCounter(influxDBRouterReqsTLSName)
 		registry.routerReqDurationHistogram, _ = NewHistogramWithScale(influxDBClient.NewHistogram(influxDBRouterReqsDurationName), time.Second)
 		registry.routerOpenConnsGauge = influxDBClient.NewGauge(influxDBORouterOpenConnsName)
+		registry.tcpRouterConnsCounter = influxDBClient.NewCounter(influxDBTCPRouterConnsName)
+		registry.tcpRouterOpenConnsGauge = influxDBClient.NewGauge(influxDBTCPRouterOpenConnsName)
+		registry.tcpRouterConnDurationHistogram, _ = NewHistogramWithScale(influxDBClient.NewHistogram(influxDBTCPRouterConnDurationName), time.Second)
+		registry.tcpRouterBytesCounter = influxDBClient.NewCounter(influxDBTCPRouterBytesName)
+		registry.udpRouterSessionsCounter = influxDBClient.NewCounter(influxDBUDPRouterSessionsName)
 	}
 
 	if config.AddServicesLabels {
@@ -97,6 +139,15 @@ func RegisterInfluxDB(ctx context.Context, config *types.InfluxDB) Registry {
 		registry.serviceRetriesCounter = influxDBClient.NewCounter(influxDBServiceRetriesTotalName)
 		registry.serviceOpenConnsGauge = influxDBClient.NewGauge(influxDBServiceOpenConnsName)
 		registry.serviceServerUpGauge = influxDBClient.NewGauge(influxDBServiceServerUpName)
+		registry.serviceMirrorMismatchesCounter = influxDBClient.NewCounter(influxDBServiceMirrorMismatchesTotalName)
+		registry.tcpServiceServerOpenConnsGauge = influxDBClient.NewGauge(influxDBTCPServiceServerOpenConnsName)
+		registry.tcpServiceConnsCounter = influxDBClient.NewCounter(influxDBTCPServiceConnsName)
+		registry.tcpServiceOpenConnsGauge = influxDBClient.NewGauge(influxDBTCPServiceOpenConnsName)
+		registry.tcpServiceConnDurationHistogram, _ = NewHistogramWithScale(influxDBClient.NewHistogram(influxDBTCPServiceConnDurationName), time.Second)
+		registry.tcpServiceBytesCounter = influxDBClient.NewCounter(influxDBTCPServiceBytesName)
+		registry.udpServiceSessionsCounter = influxDBClient.NewCounter(influxDBUDPServiceSessionsName)
+		registry.udpServiceDatagramsCounter = influxDBClient.NewCounter(influxDBUDPServiceDatagramsName)
+		registry.udpServiceBytesCounter = influxDBClient.NewCounter(influxDBUDPServiceBytesName)
 	}
 
 	return registry
@@ -54,6 +54,18 @@ func TestInfluxDB(t *testing.T) {
 
 	assertMessage(t, msgTLS, expectedTLS)
 
+	expectedMiddleware := []string{
+		`(traefik\.middleware\.cache\.requests\.total,middleware=test,status=hit count=1) [\d]{19}`,
+		`(traefik\.middleware\.circuitbreaker\.transitions\.total,middleware=test,router=test,state=tripped count=1) [\d]{19}`,
+	}
+
+	msgMiddleware := udp.ReceiveString(t, func() {
+		influxDBRegistry.MiddlewareCacheRequestsCounter().With("middleware", "test", "status", "hit").Add(1)
+		influxDBRegistry.MiddlewareCircuitBreakerTransitionsCounter().With("middleware", "test", "router", "test", "state", "tripped").Add(1)
+	})
+
+	assertMessage(t, msgMiddleware, expectedMiddleware)
+
 	expectedEntrypoint := []string{
 		`(traefik\.entrypoint\.requests\.total,code=200,entrypoint=test,method=GET count=1) [\d]{19}`,
 		`(traefik\.entrypoint\.requests\.tls\.total,entrypoint=test,tls_cipher=bar,tls_version=foo count=1) [\d]{19}`,
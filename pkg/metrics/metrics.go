@@ -26,6 +26,10 @@ type Registry interface {
 	// TLS
 	TLSCertsNotAfterTimestampGauge() metrics.Gauge
 
+	// middleware metrics
+	MiddlewareCacheRequestsCounter() metrics.Counter
+	MiddlewareCircuitBreakerTransitionsCounter() metrics.Counter
+
 	// entry point metrics
 	EntryPointReqsCounter() metrics.Counter
 	EntryPointReqsTLSCounter() metrics.Counter
@@ -45,6 +49,37 @@ type Registry interface {
 	ServiceOpenConnsGauge() metrics.Gauge
 	ServiceRetriesCounter() metrics.Counter
 	ServiceServerUpGauge() metrics.Gauge
+	ServiceMirrorMismatchesCounter() metrics.Counter
+
+	// TCP entry point metrics
+	TCPEntryPointConnsCounter() metrics.Counter
+	TCPEntryPointOpenConnsGauge() metrics.Gauge
+	TCPEntryPointConnDurationHistogram() ScalableHistogram
+	TCPEntryPointBytesCounter() metrics.Counter
+
+	// TCP router metrics
+	TCPRouterConnsCounter() metrics.Counter
+	TCPRouterOpenConnsGauge() metrics.Gauge
+	TCPRouterConnDurationHistogram() ScalableHistogram
+	TCPRouterBytesCounter() metrics.Counter
+
+	// TCP service metrics
+	TCPServiceConnsCounter() metrics.Counter
+	TCPServiceOpenConnsGauge() metrics.Gauge
+	TCPServiceConnDurationHistogram() ScalableHistogram
+	TCPServiceBytesCounter() metrics.Counter
+	TCPServiceServerOpenConnsGauge() metrics.Gauge
+
+	// UDP entry point metrics
+	UDPEntryPointSessionsCounter() metrics.Counter
+
+	// UDP router metrics
+	UDPRouterSessionsCounter() metrics.Counter
+
+	// UDP service metrics
+	UDPServiceSessionsCounter() metrics.Counter
+	UDPServiceDatagramsCounter() metrics.Counter
+	UDPServiceBytesCounter() metrics.Counter
 }
 
 // NewVoidRegistry is a noop implementation of metrics.Registry.
@@ -62,6 +97,8 @@ func NewMultiRegistry(registries []Registry) Registry {
 	var lastConfigReloadSuccessGauge []metrics.Gauge
 	var lastConfigReloadFailureGauge []metrics.Gauge
 	var tlsCertsNotAfterTimestampGauge []metrics.Gauge
+	var middlewareCacheRequestsCounter []metrics.Counter
+	var middlewareCircuitBreakerTransitionsCounter []metrics.Counter
 	var entryPointReqsCounter []metrics.Counter
 	var entryPointReqsTLSCounter []metrics.Counter
 	var entryPointReqDurationHistogram []ScalableHistogram
@@ -76,6 +113,25 @@ func NewMultiRegistry(registries []Registry) Registry {
 	var serviceOpenConnsGauge []metrics.Gauge
 	var serviceRetriesCounter []metrics.Counter
 	var serviceServerUpGauge []metrics.Gauge
+	var serviceMirrorMismatchesCounter []metrics.Counter
+	var tcpServiceServerOpenConnsGauge []metrics.Gauge
+	var tcpEntryPointConnsCounter []metrics.Counter
+	var tcpEntryPointOpenConnsGauge []metrics.Gauge
+	var tcpEntryPointConnDurationHistogram []ScalableHistogram
+	var tcpEntryPointBytesCounter []metrics.Counter
+	var tcpRouterConnsCounter []metrics.Counter
+	var tcpRouterOpenConnsGauge []metrics.Gauge
+	var tcpRouterConnDurationHistogram []ScalableHistogram
+	var tcpRouterBytesCounter []metrics.Counter
+	var tcpServiceConnsCounter []metrics.Counter
+	var tcpServiceOpenConnsGauge []metrics.Gauge
+	var tcpServiceConnDurationHistogram []ScalableHistogram
+	var tcpServiceBytesCounter []metrics.Counter
+	var udpEntryPointSessionsCounter []metrics.Counter
+	var udpRouterSessionsCounter []metrics.Counter
+	var udpServiceSessionsCounter []metrics.Counter
+	var udpServiceDatagramsCounter []metrics.Counter
+	var udpServiceBytesCounter []metrics.Counter
 
 	for _, r := range registries {
 		if r.ConfigReloadsCounter() != nil {
@@ -93,6 +149,12 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.TLSCertsNotAfterTimestampGauge() != nil {
 			tlsCertsNotAfterTimestampGauge = append(tlsCertsNotAfterTimestampGauge, r.TLSCertsNotAfterTimestampGauge())
 		}
+		if r.MiddlewareCacheRequestsCounter() != nil {
+			middlewareCacheRequestsCounter = append(middlewareCacheRequestsCounter, r.MiddlewareCacheRequestsCounter())
+		}
+		if r.MiddlewareCircuitBreakerTransitionsCounter() != nil {
+			middlewareCircuitBreakerTransitionsCounter = append(middlewareCircuitBreakerTransitionsCounter, r.MiddlewareCircuitBreakerTransitionsCounter())
+		}
 		if r.EntryPointReqsCounter() != nil {
 			entryPointReqsCounter = append(entryPointReqsCounter, r.EntryPointReqsCounter())
 		}
@@ -135,57 +197,159 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.ServiceServerUpGauge() != nil {
 			serviceServerUpGauge = append(serviceServerUpGauge, r.ServiceServerUpGauge())
 		}
+		if r.ServiceMirrorMismatchesCounter() != nil {
+			serviceMirrorMismatchesCounter = append(serviceMirrorMismatchesCounter, r.ServiceMirrorMismatchesCounter())
+		}
+		if r.TCPServiceServerOpenConnsGauge() != nil {
+			tcpServiceServerOpenConnsGauge = append(tcpServiceServerOpenConnsGauge, r.TCPServiceServerOpenConnsGauge())
+		}
+		if r.TCPEntryPointConnsCounter() != nil {
+			tcpEntryPointConnsCounter = append(tcpEntryPointConnsCounter, r.TCPEntryPointConnsCounter())
+		}
+		if r.TCPEntryPointOpenConnsGauge() != nil {
+			tcpEntryPointOpenConnsGauge = append(tcpEntryPointOpenConnsGauge, r.TCPEntryPointOpenConnsGauge())
+		}
+		if r.TCPEntryPointConnDurationHistogram() != nil {
+			tcpEntryPointConnDurationHistogram = append(tcpEntryPointConnDurationHistogram, r.TCPEntryPointConnDurationHistogram())
+		}
+		if r.TCPEntryPointBytesCounter() != nil {
+			tcpEntryPointBytesCounter = append(tcpEntryPointBytesCounter, r.TCPEntryPointBytesCounter())
+		}
+		if r.TCPRouterConnsCounter() != nil {
+			tcpRouterConnsCounter = append(tcpRouterConnsCounter, r.TCPRouterConnsCounter())
+		}
+		if r.TCPRouterOpenConnsGauge() != nil {
+			tcpRouterOpenConnsGauge = append(tcpRouterOpenConnsGauge, r.TCPRouterOpenConnsGauge())
+		}
+		if r.TCPRouterConnDurationHistogram() != nil {
+			tcpRouterConnDurationHistogram = append(tcpRouterConnDurationHistogram, r.TCPRouterConnDurationHistogram())
+		}
+		if r.TCPRouterBytesCounter() != nil {
+			tcpRouterBytesCounter = append(tcpRouterBytesCounter, r.TCPRouterBytesCounter())
+		}
+		if r.TCPServiceConnsCounter() != nil {
+			tcpServiceConnsCounter = append(tcpServiceConnsCounter, r.TCPServiceConnsCounter())
+		}
+		if r.TCPServiceOpenConnsGauge() != nil {
+			tcpServiceOpenConnsGauge = append(tcpServiceOpenConnsGauge, r.TCPServiceOpenConnsGauge())
+		}
+		if r.TCPServiceConnDurationHistogram() != nil {
+			tcpServiceConnDurationHistogram = append(tcpServiceConnDurationHistogram, r.TCPServiceConnDurationHistogram())
+		}
+		if r.TCPServiceBytesCounter() != nil {
+			tcpServiceBytesCounter = append(tcpServiceBytesCounter, r.TCPServiceBytesCounter())
+		}
+		if r.UDPEntryPointSessionsCounter() != nil {
+			udpEntryPointSessionsCounter = append(udpEntryPointSessionsCounter, r.UDPEntryPointSessionsCounter())
+		}
+		if r.UDPRouterSessionsCounter() != nil {
+			udpRouterSessionsCounter = append(udpRouterSessionsCounter, r.UDPRouterSessionsCounter())
+		}
+		if r.UDPServiceSessionsCounter() != nil {
+			udpServiceSessionsCounter = append(udpServiceSessionsCounter, r.UDPServiceSessionsCounter())
+		}
+		if r.UDPServiceDatagramsCounter() != nil {
+			udpServiceDatagramsCounter = append(udpServiceDatagramsCounter, r.UDPServiceDatagramsCounter())
+		}
+		if r.UDPServiceBytesCounter() != nil {
+			udpServiceBytesCounter = append(udpServiceBytesCounter, r.UDPServiceBytesCounter())
+		}
 	}
 
 	return &standardRegistry{
-		epEnabled:                      len(entryPointReqsCounter) > 0 || len(entryPointReqDurationHistogram) > 0 || len(entryPointOpenConnsGauge) > 0,
-		svcEnabled:                     len(serviceReqsCounter) > 0 || len(serviceReqDurationHistogram) > 0 || len(serviceOpenConnsGauge) > 0 || len(serviceRetriesCounter) > 0 || len(serviceServerUpGauge) > 0,
-		routerEnabled:                  len(routerReqsCounter) > 0 || len(routerReqDurationHistogram) > 0 || len(routerOpenConnsGauge) > 0,
-		configReloadsCounter:           multi.NewCounter(configReloadsCounter...),
-		configReloadsFailureCounter:    multi.NewCounter(configReloadsFailureCounter...),
-		lastConfigReloadSuccessGauge:   multi.NewGauge(lastConfigReloadSuccessGauge...),
-		lastConfigReloadFailureGauge:   multi.NewGauge(lastConfigReloadFailureGauge...),
-		tlsCertsNotAfterTimestampGauge: multi.NewGauge(tlsCertsNotAfterTimestampGauge...),
-		entryPointReqsCounter:          multi.NewCounter(entryPointReqsCounter...),
-		entryPointReqsTLSCounter:       multi.NewCounter(entryPointReqsTLSCounter...),
-		entryPointReqDurationHistogram: NewMultiHistogram(entryPointReqDurationHistogram...),
-		entryPointOpenConnsGauge:       multi.NewGauge(entryPointOpenConnsGauge...),
-		routerReqsCounter:              multi.NewCounter(routerReqsCounter...),
-		routerReqsTLSCounter:           multi.NewCounter(routerReqsTLSCounter...),
-		routerReqDurationHistogram:     NewMultiHistogram(routerReqDurationHistogram...),
-		routerOpenConnsGauge:           multi.NewGauge(routerOpenConnsGauge...),
-		serviceReqsCounter:             multi.NewCounter(serviceReqsCounter...),
-		serviceReqsTLSCounter:          multi.NewCounter(serviceReqsTLSCounter...),
-		serviceReqDurationHistogram:    NewMultiHistogram(serviceReqDurationHistogram...),
-		serviceOpenConnsGauge:          multi.NewGauge(serviceOpenConnsGauge...),
-		serviceRetriesCounter:          multi.NewCounter(serviceRetriesCounter...),
-		serviceServerUpGauge:           multi.NewGauge(serviceServerUpGauge...),
+		epEnabled: len(entryPointReqsCounter) > 0 || len(entryPointReqDurationHistogram) > 0 || len(entryPointOpenConnsGauge) > 0 ||
+			len(tcpEntryPointConnsCounter) > 0 || len(tcpEntryPointOpenConnsGauge) > 0 || len(tcpEntryPointConnDurationHistogram) > 0 || len(tcpEntryPointBytesCounter) > 0 || len(udpEntryPointSessionsCounter) > 0,
+		svcEnabled: len(serviceReqsCounter) > 0 || len(serviceReqDurationHistogram) > 0 || len(serviceOpenConnsGauge) > 0 || len(serviceRetriesCounter) > 0 || len(serviceServerUpGauge) > 0 || len(serviceMirrorMismatchesCounter) > 0 || len(tcpServiceServerOpenConnsGauge) > 0 ||
+			len(tcpServiceConnsCounter) > 0 || len(tcpServiceOpenConnsGauge) > 0 || len(tcpServiceConnDurationHistogram) > 0 || len(tcpServiceBytesCounter) > 0 || len(udpServiceSessionsCounter) > 0 || len(udpServiceDatagramsCounter) > 0 || len(udpServiceBytesCounter) > 0,
+		routerEnabled: len(routerReqsCounter) > 0 || len(routerReqDurationHistogram) > 0 || len(routerOpenConnsGauge) > 0 ||
+			len(tcpRouterConnsCounter) > 0 || len(tcpRouterOpenConnsGauge) > 0 || len(tcpRouterConnDurationHistogram) > 0 || len(tcpRouterBytesCounter) > 0 || len(udpRouterSessionsCounter) > 0,
+		configReloadsCounter:                       multi.NewCounter(configReloadsCounter...),
+		configReloadsFailureCounter:                multi.NewCounter(configReloadsFailureCounter...),
+		lastConfigReloadSuccessGauge:               multi.NewGauge(lastConfigReloadSuccessGauge...),
+		lastConfigReloadFailureGauge:               multi.NewGauge(lastConfigReloadFailureGauge...),
+		tlsCertsNotAfterTimestampGauge:             multi.NewGauge(tlsCertsNotAfterTimestampGauge...),
+		middlewareCacheRequestsCounter:             multi.NewCounter(middlewareCacheRequestsCounter...),
+		middlewareCircuitBreakerTransitionsCounter: multi.NewCounter(middlewareCircuitBreakerTransitionsCounter...),
+		entryPointReqsCounter:                      multi.NewCounter(entryPointReqsCounter...),
+		entryPointReqsTLSCounter:                   multi.NewCounter(entryPointReqsTLSCounter...),
+		entryPointReqDurationHistogram:             NewMultiHistogram(entryPointReqDurationHistogram...),
+		entryPointOpenConnsGauge:                   multi.NewGauge(entryPointOpenConnsGauge...),
+		routerReqsCounter:                          multi.NewCounter(routerReqsCounter...),
+		routerReqsTLSCounter:                       multi.NewCounter(routerReqsTLSCounter...),
+		routerReqDurationHistogram:                 NewMultiHistogram(routerReqDurationHistogram...),
+		routerOpenConnsGauge:                       multi.NewGauge(routerOpenConnsGauge...),
+		serviceReqsCounter:                         multi.NewCounter(serviceReqsCounter...),
+		serviceReqsTLSCounter:                      multi.NewCounter(serviceReqsTLSCounter...),
+		serviceReqDurationHistogram:                NewMultiHistogram(serviceReqDurationHistogram...),
+		serviceOpenConnsGauge:                      multi.NewGauge(serviceOpenConnsGauge...),
+		serviceRetriesCounter:                      multi.NewCounter(serviceRetriesCounter...),
+		serviceServerUpGauge:                       multi.NewGauge(serviceServerUpGauge...),
+		serviceMirrorMismatchesCounter:             multi.NewCounter(serviceMirrorMismatchesCounter...),
+		tcpServiceServerOpenConnsGauge:             multi.NewGauge(tcpServiceServerOpenConnsGauge...),
+		tcpEntryPointConnsCounter:                  multi.NewCounter(tcpEntryPointConnsCounter...),
+		tcpEntryPointOpenConnsGauge:                multi.NewGauge(tcpEntryPointOpenConnsGauge...),
+		tcpEntryPointConnDurationHistogram:         NewMultiHistogram(tcpEntryPointConnDurationHistogram...),
+		tcpEntryPointBytesCounter:                  multi.NewCounter(tcpEntryPointBytesCounter...),
+		tcpRouterConnsCounter:                      multi.NewCounter(tcpRouterConnsCounter...),
+		tcpRouterOpenConnsGauge:                    multi.NewGauge(tcpRouterOpenConnsGauge...),
+		tcpRouterConnDurationHistogram:             NewMultiHistogram(tcpRouterConnDurationHistogram...),
+		tcpRouterBytesCounter:                      multi.NewCounter(tcpRouterBytesCounter...),
+		tcpServiceConnsCounter:                     multi.NewCounter(tcpServiceConnsCounter...),
+		tcpServiceOpenConnsGauge:                   multi.NewGauge(tcpServiceOpenConnsGauge...),
+		tcpServiceConnDurationHistogram:            NewMultiHistogram(tcpServiceConnDurationHistogram...),
+		tcpServiceBytesCounter:                     multi.NewCounter(tcpServiceBytesCounter...),
+		udpEntryPointSessionsCounter:               multi.NewCounter(udpEntryPointSessionsCounter...),
+		udpRouterSessionsCounter:                   multi.NewCounter(udpRouterSessionsCounter...),
+		udpServiceSessionsCounter:                  multi.NewCounter(udpServiceSessionsCounter...),
+		udpServiceDatagramsCounter:                 multi.NewCounter(udpServiceDatagramsCounter...),
+		udpServiceBytesCounter:                     multi.NewCounter(udpServiceBytesCounter...),
 	}
 }
 
 type standardRegistry struct {
-	epEnabled                      bool
-	routerEnabled                  bool
-	svcEnabled                     bool
-	configReloadsCounter           metrics.Counter
-	configReloadsFailureCounter    metrics.Counter
-	lastConfigReloadSuccessGauge   metrics.Gauge
-	lastConfigReloadFailureGauge   metrics.Gauge
-	tlsCertsNotAfterTimestampGauge metrics.Gauge
-	entryPointReqsCounter          metrics.Counter
-	entryPointReqsTLSCounter       metrics.Counter
-	entryPointReqDurationHistogram ScalableHistogram
-	entryPointOpenConnsGauge       metrics.Gauge
-	routerReqsCounter              metrics.Counter
-	routerReqsTLSCounter           metrics.Counter
-	routerReqDurationHistogram     ScalableHistogram
-	routerOpenConnsGauge           metrics.Gauge
-	serviceReqsCounter             metrics.Counter
-	serviceReqsTLSCounter          metrics.Counter
-	serviceReqDurationHistogram    ScalableHistogram
-	serviceOpenConnsGauge          metrics.Gauge
-	serviceRetriesCounter          metrics.Counter
-	serviceServerUpGauge           metrics.Gauge
+	epEnabled                                  bool
+	routerEnabled                              bool
+	svcEnabled                                 bool
+	configReloadsCounter                       metrics.Counter
+	configReloadsFailureCounter                metrics.Counter
+	lastConfigReloadSuccessGauge               metrics.Gauge
+	lastConfigReloadFailureGauge               metrics.Gauge
+	tlsCertsNotAfterTimestampGauge             metrics.Gauge
+	middlewareCacheRequestsCounter             metrics.Counter
+	middlewareCircuitBreakerTransitionsCounter metrics.Counter
+	entryPointReqsCounter                      metrics.Counter
+	entryPointReqsTLSCounter                   metrics.Counter
+	entryPointReqDurationHistogram             ScalableHistogram
+	entryPointOpenConnsGauge                   metrics.Gauge
+	routerReqsCounter                          metrics.Counter
+	routerReqsTLSCounter                       metrics.Counter
+	routerReqDurationHistogram                 ScalableHistogram
+	routerOpenConnsGauge                       metrics.Gauge
+	serviceReqsCounter                         metrics.Counter
+	serviceReqsTLSCounter                      metrics.Counter
+	serviceReqDurationHistogram                ScalableHistogram
+	serviceOpenConnsGauge                      metrics.Gauge
+	serviceRetriesCounter                      metrics.Counter
+	serviceServerUpGauge                       metrics.Gauge
+	serviceMirrorMismatchesCounter             metrics.Counter
+	tcpServiceServerOpenConnsGauge             metrics.Gauge
+	tcpEntryPointConnsCounter                  metrics.Counter
+	tcpEntryPointOpenConnsGauge                metrics.Gauge
+	tcpEntryPointConnDurationHistogram         ScalableHistogram
+	tcpEntryPointBytesCounter                  metrics.Counter
+	tcpRouterConnsCounter                      metrics.Counter
+	tcpRouterOpenConnsGauge                    metrics.Gauge
+	tcpRouterConnDurationHistogram             ScalableHistogram
+	tcpRouterBytesCounter                      metrics.Counter
+	tcpServiceConnsCounter                     metrics.Counter
+	tcpServiceOpenConnsGauge                   metrics.Gauge
+	tcpServiceConnDurationHistogram            ScalableHistogram
+	tcpServiceBytesCounter                     metrics.Counter
+	udpEntryPointSessionsCounter               metrics.Counter
+	udpRouterSessionsCounter                   metrics.Counter
+	udpServiceSessionsCounter                  metrics.Counter
+	udpServiceDatagramsCounter                 metrics.Counter
+	udpServiceBytesCounter                     metrics.Counter
 }
 
 func (r *standardRegistry) IsEpEnabled() bool {
@@ -220,6 +384,14 @@ func (r *standardRegistry) TLSCertsNotAfterTimestampGauge() metrics.Gauge {
 	return r.tlsCertsNotAfterTimestampGauge
 }
 
+func (r *standardRegistry) MiddlewareCacheRequestsCounter() metrics.Counter {
+	return r.middlewareCacheRequestsCounter
+}
+
+func (r *standardRegistry) MiddlewareCircuitBreakerTransitionsCounter() metrics.Counter {
+	return r.middlewareCircuitBreakerTransitionsCounter
+}
+
 func (r *standardRegistry) EntryPointReqsCounter() metrics.Counter {
 	return r.entryPointReqsCounter
 }
@@ -276,6 +448,82 @@ func (r *standardRegistry) ServiceServerUpGauge() metrics.Gauge {
 	return r.serviceServerUpGauge
 }
 
+func (r *standardRegistry) ServiceMirrorMismatchesCounter() metrics.Counter {
+	return r.serviceMirrorMismatchesCounter
+}
+
+func (r *standardRegistry) TCPServiceServerOpenConnsGauge() metrics.Gauge {
+	return r.tcpServiceServerOpenConnsGauge
+}
+
+func (r *standardRegistry) TCPEntryPointConnsCounter() metrics.Counter {
+	return r.tcpEntryPointConnsCounter
+}
+
+func (r *standardRegistry) TCPEntryPointOpenConnsGauge() metrics.Gauge {
+	return r.tcpEntryPointOpenConnsGauge
+}
+
+func (r *standardRegistry) TCPEntryPointConnDurationHistogram() ScalableHistogram {
+	return r.tcpEntryPointConnDurationHistogram
+}
+
+func (r *standardRegistry) TCPEntryPointBytesCounter() metrics.Counter {
+	return r.tcpEntryPointBytesCounter
+}
+
+func (r *standardRegistry) TCPRouterConnsCounter() metrics.Counter {
+	return r.tcpRouterConnsCounter
+}
+
+func (r *standardRegistry) TCPRouterOpenConnsGauge() metrics.Gauge {
+	return r.tcpRouterOpenConnsGauge
+}
+
+func (r *standardRegistry) TCPRouterConnDurationHistogram() ScalableHistogram {
+	return r.tcpRouterConnDurationHistogram
+}
+
+func (r *standardRegistry) TCPRouterBytesCounter() metrics.Counter {
+	return r.tcpRouterBytesCounter
+}
+
+func (r *standardRegistry) TCPServiceConnsCounter() metrics.Counter {
+	return r.tcpServiceConnsCounter
+}
+
+func (r *standardRegistry) TCPServiceOpenConnsGauge() metrics.Gauge {
+	return r.tcpServiceOpenConnsGauge
+}
+
+func (r *standardRegistry) TCPServiceConnDurationHistogram() ScalableHistogram {
+	return r.tcpServiceConnDurationHistogram
+}
+
+func (r *standardRegistry) TCPServiceBytesCounter() metrics.Counter {
+	return r.tcpServiceBytesCounter
+}
+
+func (r *standardRegistry) UDPEntryPointSessionsCounter() metrics.Counter {
+	return r.udpEntryPointSessionsCounter
+}
+
+func (r *standardRegistry) UDPRouterSessionsCounter() metrics.Counter {
+	return r.udpRouterSessionsCounter
+}
+
+func (r *standardRegistry) UDPServiceSessionsCounter() metrics.Counter {
+	return r.udpServiceSessionsCounter
+}
+
+func (r *standardRegistry) UDPServiceDatagramsCounter() metrics.Counter {
+	return r.udpServiceDatagramsCounter
+}
+
+func (r *standardRegistry) UDPServiceBytesCounter() metrics.Counter {
+	return r.udpServiceBytesCounter
+}
+
 // ScalableHistogram is a Histogram with a predefined time unit,
 // used when producing observations without explicitly setting the observed value.
 type ScalableHistogram interface {
@@ -33,6 +33,11 @@ const (
 	metricsTLSPrefix          = MetricNamePrefix + "tls_"
 	tlsCertsNotAfterTimestamp = metricsTLSPrefix + "certs_not_after"
 
+	// middleware level.
+	metricMiddlewarePrefix                       = MetricNamePrefix + "middleware_"
+	middlewareCacheRequestsTotalName             = metricMiddlewarePrefix + "cache_requests_total"
+	middlewareCircuitBreakerTransitionsTotalName = metricMiddlewarePrefix + "circuitbreaker_transitions_total"
+
 	// entry point.
 	metricEntryPointPrefix     = MetricNamePrefix + "entrypoint_"
 	entryPointReqsTotalName    = metricEntryPointPrefix + "requests_total"
@@ -55,6 +60,44 @@ const (
 	serviceOpenConnsName    = metricServicePrefix + "open_connections"
 	serviceRetriesTotalName = metricServicePrefix + "retries_total"
 	serviceServerUpName     = metricServicePrefix + "server_up"
+
+	serviceMirrorMismatchesTotalName = metricServicePrefix + "mirror_mismatches_total"
+
+	// TCP entry point level.
+	metricTCPEntryPointPrefix     = MetricNamePrefix + "tcp_entrypoint_"
+	tcpEntryPointConnsName        = metricTCPEntryPointPrefix + "connections_total"
+	tcpEntryPointOpenConnsName    = metricTCPEntryPointPrefix + "open_connections"
+	tcpEntryPointConnDurationName = metricTCPEntryPointPrefix + "connection_duration_seconds"
+	tcpEntryPointBytesName        = metricTCPEntryPointPrefix + "bytes_total"
+
+	// TCP router level.
+	metricTCPRouterPrefix     = MetricNamePrefix + "tcp_router_"
+	tcpRouterConnsName        = metricTCPRouterPrefix + "connections_total"
+	tcpRouterOpenConnsName    = metricTCPRouterPrefix + "open_connections"
+	tcpRouterConnDurationName = metricTCPRouterPrefix + "connection_duration_seconds"
+	tcpRouterBytesName        = metricTCPRouterPrefix + "bytes_total"
+
+	// TCP service level.
+	metricTCPServicePrefix        = MetricNamePrefix + "tcp_service_"
+	tcpServiceServerOpenConnsName = metricTCPServicePrefix + "server_open_connections"
+	tcpServiceConnsName           = metricTCPServicePrefix + "connections_total"
+	tcpServiceOpenConnsName       = metricTCPServicePrefix + "open_connections"
+	tcpServiceConnDurationName    = metricTCPServicePrefix + "connection_duration_seconds"
+	tcpServiceBytesName           = metricTCPServicePrefix + "bytes_total"
+
+	// UDP entry point level.
+	metricUDPEntryPointPrefix = MetricNamePrefix + "udp_entrypoint_"
+	udpEntryPointSessionsName = metricUDPEntryPointPrefix + "sessions_total"
+
+	// UDP router level.
+	metricUDPRouterPrefix = MetricNamePrefix + "udp_router_"
+	udpRouterSessionsName = metricUDPRouterPrefix + "sessions_total"
+
+	// UDP service level.
+	metricUDPServicePrefix  = MetricNamePrefix + "udp_service_"
+	udpServiceSessionsName  = metricUDPServicePrefix + "sessions_total"
+	udpServiceDatagramsName = metricUDPServicePrefix + "datagrams_total"
+	udpServiceBytesName     = metricUDPServicePrefix + "bytes_total"
 )
 
 // promState holds all metric state internally and acts as the only Collector we register for Prometheus.
@@ -134,6 +177,14 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		Name: tlsCertsNotAfterTimestamp,
 		Help: "Certificate expiration timestamp",
 	}, []string{"cn", "serial", "sans"})
+	middlewareCacheRequests := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: middlewareCacheRequestsTotalName,
+		Help: "How many requests were handled by a cache middleware, partitioned by middleware and cache status.",
+	}, []string{"middleware", "status"})
+	middlewareCircuitBreakerTransitions := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: middlewareCircuitBreakerTransitionsTotalName,
+		Help: "How many times a circuit breaker middleware changed state, partitioned by middleware, router and new state.",
+	}, []string{"middleware", "router", "state"})
 
 	promState.describers = []func(chan<- *stdprometheus.Desc){
 		configReloads.cv.Describe,
@@ -141,17 +192,21 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		lastConfigReloadSuccess.gv.Describe,
 		lastConfigReloadFailure.gv.Describe,
 		tlsCertsNotAfterTimesptamp.gv.Describe,
+		middlewareCacheRequests.cv.Describe,
+		middlewareCircuitBreakerTransitions.cv.Describe,
 	}
 
 	reg := &standardRegistry{
-		epEnabled:                      config.AddEntryPointsLabels,
-		routerEnabled:                  config.AddRoutersLabels,
-		svcEnabled:                     config.AddServicesLabels,
-		configReloadsCounter:           configReloads,
-		configReloadsFailureCounter:    configReloadsFailures,
-		lastConfigReloadSuccessGauge:   lastConfigReloadSuccess,
-		lastConfigReloadFailureGauge:   lastConfigReloadFailure,
-		tlsCertsNotAfterTimestampGauge: tlsCertsNotAfterTimesptamp,
+		epEnabled:                                  config.AddEntryPointsLabels,
+		routerEnabled:                              config.AddRoutersLabels,
+		svcEnabled:                                 config.AddServicesLabels,
+		configReloadsCounter:                       configReloads,
+		configReloadsFailureCounter:                configReloadsFailures,
+		lastConfigReloadSuccessGauge:               lastConfigReloadSuccess,
+		lastConfigReloadFailureGauge:               lastConfigReloadFailure,
+		tlsCertsNotAfterTimestampGauge:             tlsCertsNotAfterTimesptamp,
+		middlewareCacheRequestsCounter:             middlewareCacheRequests,
+		middlewareCircuitBreakerTransitionsCounter: middlewareCircuitBreakerTransitions,
 	}
 
 	if config.AddEntryPointsLabels {
@@ -172,18 +227,49 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			Name: entryPointOpenConnsName,
 			Help: "How many open connections exist on an entrypoint, partitioned by method and protocol.",
 		}, []string{"method", "protocol", "entrypoint"})
+		tcpEntryPointConns := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: tcpEntryPointConnsName,
+			Help: "How many TCP connections were handled on an entrypoint.",
+		}, []string{"entrypoint"})
+		tcpEntryPointOpenConns := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+			Name: tcpEntryPointOpenConnsName,
+			Help: "How many TCP connections are currently open on an entrypoint.",
+		}, []string{"entrypoint"})
+		tcpEntryPointConnDuration := newHistogramFrom(promState.collectors, stdprometheus.HistogramOpts{
+			Name:    tcpEntryPointConnDurationName,
+			Help:    "How long the TCP connections handled on an entrypoint lasted.",
+			Buckets: buckets,
+		}, []string{"entrypoint"})
+		tcpEntryPointBytes := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: tcpEntryPointBytesName,
+			Help: "How many bytes were received (in) and sent (out) by the TCP connections of an entrypoint.",
+		}, []string{"direction", "entrypoint"})
+		udpEntryPointSessions := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: udpEntryPointSessionsName,
+			Help: "How many UDP sessions were handled on an entrypoint.",
+		}, []string{"entrypoint"})
 
 		promState.describers = append(promState.describers, []func(chan<- *stdprometheus.Desc){
 			entryPointReqs.cv.Describe,
 			entryPointReqsTLS.cv.Describe,
 			entryPointReqDurations.hv.Describe,
 			entryPointOpenConns.gv.Describe,
+			tcpEntryPointConns.cv.Describe,
+			tcpEntryPointOpenConns.gv.Describe,
+			tcpEntryPointConnDuration.hv.Describe,
+			tcpEntryPointBytes.cv.Describe,
+			udpEntryPointSessions.cv.Describe,
 		}...)
 
 		reg.entryPointReqsCounter = entryPointReqs
 		reg.entryPointReqsTLSCounter = entryPointReqsTLS
 		reg.entryPointReqDurationHistogram, _ = NewHistogramWithScale(entryPointReqDurations, time.Second)
 		reg.entryPointOpenConnsGauge = entryPointOpenConns
+		reg.tcpEntryPointConnsCounter = tcpEntryPointConns
+		reg.tcpEntryPointOpenConnsGauge = tcpEntryPointOpenConns
+		reg.tcpEntryPointConnDurationHistogram, _ = NewHistogramWithScale(tcpEntryPointConnDuration, time.Second)
+		reg.tcpEntryPointBytesCounter = tcpEntryPointBytes
+		reg.udpEntryPointSessionsCounter = udpEntryPointSessions
 	}
 
 	if config.AddRoutersLabels {
@@ -204,17 +290,48 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			Name: routerOpenConnsName,
 			Help: "How many open connections exist on a router, partitioned by service, method, and protocol.",
 		}, []string{"method", "protocol", "router", "service"})
+		tcpRouterConns := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: tcpRouterConnsName,
+			Help: "How many TCP connections were handled on a router, partitioned by service.",
+		}, []string{"router", "service"})
+		tcpRouterOpenConns := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+			Name: tcpRouterOpenConnsName,
+			Help: "How many TCP connections are currently open on a router, partitioned by service.",
+		}, []string{"router", "service"})
+		tcpRouterConnDuration := newHistogramFrom(promState.collectors, stdprometheus.HistogramOpts{
+			Name:    tcpRouterConnDurationName,
+			Help:    "How long the TCP connections handled on a router lasted, partitioned by service.",
+			Buckets: buckets,
+		}, []string{"router", "service"})
+		tcpRouterBytes := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: tcpRouterBytesName,
+			Help: "How many bytes were received (in) and sent (out) by the TCP connections of a router, partitioned by service.",
+		}, []string{"direction", "router", "service"})
+		udpRouterSessions := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: udpRouterSessionsName,
+			Help: "How many UDP sessions were handled on a router, partitioned by service.",
+		}, []string{"router", "service"})
 
 		promState.describers = append(promState.describers, []func(chan<- *stdprometheus.Desc){
 			routerReqs.cv.Describe,
 			routerReqsTLS.cv.Describe,
 			routerReqDurations.hv.Describe,
 			routerOpenConns.gv.Describe,
+			tcpRouterConns.cv.Describe,
+			tcpRouterOpenConns.gv.Describe,
+			tcpRouterConnDuration.hv.Describe,
+			tcpRouterBytes.cv.Describe,
+			udpRouterSessions.cv.Describe,
 		}...)
 		reg.routerReqsCounter = routerReqs
 		reg.routerReqsTLSCounter = routerReqsTLS
 		reg.routerReqDurationHistogram, _ = NewHistogramWithScale(routerReqDurations, time.Second)
 		reg.routerOpenConnsGauge = routerOpenConns
+		reg.tcpRouterConnsCounter = tcpRouterConns
+		reg.tcpRouterOpenConnsGauge = tcpRouterOpenConns
+		reg.tcpRouterConnDurationHistogram, _ = NewHistogramWithScale(tcpRouterConnDuration, time.Second)
+		reg.tcpRouterBytesCounter = tcpRouterBytes
+		reg.udpRouterSessionsCounter = udpRouterSessions
 	}
 
 	if config.AddServicesLabels {
@@ -243,6 +360,43 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			Name: serviceServerUpName,
 			Help: "service server is up, described by gauge value of 0 or 1.",
 		}, []string{"service", "url"})
+		serviceMirrorMismatches := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: serviceMirrorMismatchesTotalName,
+			Help: "How many mirrored responses differed from the responses of a service, partitioned by mirror and reason.",
+		}, []string{"service", "mirror", "reason"})
+		tcpServiceServerOpenConns := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+			Name: tcpServiceServerOpenConnsName,
+			Help: "How many open connections exist on a TCP service server.",
+		}, []string{"service", "address"})
+		tcpServiceConns := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: tcpServiceConnsName,
+			Help: "How many TCP connections were handled on a service.",
+		}, []string{"service"})
+		tcpServiceOpenConns := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+			Name: tcpServiceOpenConnsName,
+			Help: "How many TCP connections are currently open on a service.",
+		}, []string{"service"})
+		tcpServiceConnDuration := newHistogramFrom(promState.collectors, stdprometheus.HistogramOpts{
+			Name:    tcpServiceConnDurationName,
+			Help:    "How long the TCP connections handled on a service lasted.",
+			Buckets: buckets,
+		}, []string{"service"})
+		tcpServiceBytes := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: tcpServiceBytesName,
+			Help: "How many bytes were received (in) and sent (out) by the TCP connections of a service.",
+		}, []string{"direction", "service"})
+		udpServiceSessions := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: udpServiceSessionsName,
+			Help: "How many UDP sessions were handled on a service.",
+		}, []string{"service"})
+		udpServiceDatagrams := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: udpServiceDatagramsName,
+			Help: "How many datagrams were received (in) and sent (out) by the UDP sessions of a service.",
+		}, []string{"direction", "service"})
+		udpServiceBytes := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: udpServiceBytesName,
+			Help: "How many bytes were received (in) and sent (out) by the UDP sessions of a service.",
+		}, []string{"direction", "service"})
 
 		promState.describers = append(promState.describers, []func(chan<- *stdprometheus.Desc){
 			serviceReqs.cv.Describe,
@@ -251,6 +405,15 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			serviceOpenConns.gv.Describe,
 			serviceRetries.cv.Describe,
 			serviceServerUp.gv.Describe,
+			serviceMirrorMismatches.cv.Describe,
+			tcpServiceServerOpenConns.gv.Describe,
+			tcpServiceConns.cv.Describe,
+			tcpServiceOpenConns.gv.Describe,
+			tcpServiceConnDuration.hv.Describe,
+			tcpServiceBytes.cv.Describe,
+			udpServiceSessions.cv.Describe,
+			udpServiceDatagrams.cv.Describe,
+			udpServiceBytes.cv.Describe,
 		}...)
 
 		reg.serviceReqsCounter = serviceReqs
@@ -259,6 +422,15 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		reg.serviceOpenConnsGauge = serviceOpenConns
 		reg.serviceRetriesCounter = serviceRetries
 		reg.serviceServerUpGauge = serviceServerUp
+		reg.serviceMirrorMismatchesCounter = serviceMirrorMismatches
+		reg.tcpServiceServerOpenConnsGauge = tcpServiceServerOpenConns
+		reg.tcpServiceConnsCounter = tcpServiceConns
+		reg.tcpServiceOpenConnsGauge = tcpServiceOpenConns
+		reg.tcpServiceConnDurationHistogram, _ = NewHistogramWithScale(tcpServiceConnDuration, time.Second)
+		reg.tcpServiceBytesCounter = tcpServiceBytes
+		reg.udpServiceSessionsCounter = udpServiceSessions
+		reg.udpServiceDatagramsCounter = udpServiceDatagrams
+		reg.udpServiceBytesCounter = udpServiceBytes
 	}
 
 	return reg
@@ -297,22 +469,57 @@ func OnConfigurationUpdate(conf dynamic.Configuration, entryPoints []string) {
 	}
 
 	for serviceName, service := range conf.HTTP.Services {
-		dynamicConfig.services[serviceName] = make(map[string]bool)
+		servers := dynamicConfig.addService(serviceName)
 		if service.LoadBalancer != nil {
 			for _, server := range service.LoadBalancer.Servers {
-				dynamicConfig.services[serviceName][server.URL] = true
+				servers[server.URL] = true
+			}
+
+			if service.LoadBalancer.DNSDiscovery != nil {
+				dynamicConfig.discoveredServices[serviceName] = true
+			}
+		}
+	}
+
+	if conf.TCP != nil {
+		// The TCP and UDP services can have the same names as the HTTP ones,
+		// and share their metric series, so their servers are merged.
+		for serviceName, service := range conf.TCP.Services {
+			servers := dynamicConfig.addService(serviceName)
+			if service.LoadBalancer != nil {
+				for _, server := range service.LoadBalancer.Servers {
+					servers[server.Address] = true
+				}
+
+				if service.LoadBalancer.DNSDiscovery != nil {
+					dynamicConfig.discoveredServices[serviceName] = true
+				}
 			}
 		}
 	}
 
+	if conf.UDP != nil {
+		for serviceName := range conf.UDP.Services {
+			dynamicConfig.addService(serviceName)
+		}
+	}
+
 	promState.SetDynamicConfig(dynamicConfig)
 }
 
+// OnServersDiscovered receives the servers currently discovered with DNS for a service,
+// so that the metrics of the servers which are not discovered anymore are removed.
+// As the HTTP and TCP services can have the same names, the protocol ("http" or "tcp") tells their servers apart.
+func OnServersDiscovered(protocol, serviceName string, servers []string) {
+	promState.SetDiscoveredServers(protocol, serviceName, servers)
+}
+
 func newPrometheusState() *prometheusState {
 	return &prometheusState{
-		collectors:    make(chan *collector),
-		dynamicConfig: newDynamicConfig(),
-		state:         make(map[string]*collector),
+		collectors:        make(chan *collector),
+		dynamicConfig:     newDynamicConfig(),
+		discoveredServers: make(map[string]map[string]map[string]bool),
+		state:             make(map[string]*collector),
 	}
 }
 
@@ -322,13 +529,37 @@ type prometheusState struct {
 
 	mtx           sync.Mutex
 	dynamicConfig *dynamicConfig
-	state         map[string]*collector
+	// discoveredServers are the servers currently discovered with DNS, by service and by protocol.
+	// They are kept across the configuration updates, as they are only updated by the discoveries.
+	discoveredServers map[string]map[string]map[string]bool
+	state             map[string]*collector
 }
 
 func (ps *prometheusState) SetDynamicConfig(dynamicConfig *dynamicConfig) {
 	ps.mtx.Lock()
 	defer ps.mtx.Unlock()
 	ps.dynamicConfig = dynamicConfig
+
+	for serviceName := range ps.discoveredServers {
+		if !dynamicConfig.discoveredServices[serviceName] {
+			delete(ps.discoveredServers, serviceName)
+		}
+	}
+}
+
+func (ps *prometheusState) SetDiscoveredServers(protocol, serviceName string, servers []string) {
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	discovered := make(map[string]bool, len(servers))
+	for _, server := range servers {
+		discovered[server] = true
+	}
+
+	if ps.discoveredServers[serviceName] == nil {
+		ps.discoveredServers[serviceName] = make(map[string]map[string]bool)
+	}
+	ps.discoveredServers[serviceName][protocol] = discovered
 }
 
 func (ps *prometheusState) ListenValueUpdates() {
@@ -384,7 +615,10 @@ func (ps *prometheusState) isOutdated(collector *collector) bool {
 		if !ps.dynamicConfig.hasService(serviceName) {
 			return true
 		}
-		if url, ok := labels["url"]; ok && !ps.dynamicConfig.hasServerURL(serviceName, url) {
+		if url, ok := labels["url"]; ok && !ps.hasServer(serviceName, url) {
+			return true
+		}
+		if address, ok := labels["address"]; ok && !ps.hasServer(serviceName, address) {
 			return true
 		}
 	}
@@ -392,11 +626,30 @@ func (ps *prometheusState) isOutdated(collector *collector) bool {
 	return false
 }
 
+// hasServer reports whether the server is part of the configuration of the service, or is currently discovered with DNS.
+func (ps *prometheusState) hasServer(serviceName, server string) bool {
+	if ps.dynamicConfig.hasServerURL(serviceName, server) {
+		return true
+	}
+
+	if !ps.dynamicConfig.discoveredServices[serviceName] {
+		return false
+	}
+
+	for _, servers := range ps.discoveredServers[serviceName] {
+		if servers[server] {
+			return true
+		}
+	}
+	return false
+}
+
 func newDynamicConfig() *dynamicConfig {
 	return &dynamicConfig{
-		entryPoints: make(map[string]bool),
-		routers:     make(map[string]bool),
-		services:    make(map[string]map[string]bool),
+		entryPoints:        make(map[string]bool),
+		routers:            make(map[string]bool),
+		services:           make(map[string]map[string]bool),
+		discoveredServices: make(map[string]bool),
 	}
 }
 
@@ -408,6 +661,8 @@ type dynamicConfig struct {
 	entryPoints map[string]bool
 	routers     map[string]bool
 	services    map[string]map[string]bool
+	// discoveredServices are the services whose servers are discovered with DNS, and are not known from the configuration.
+	discoveredServices map[string]bool
 }
 
 func (d *dynamicConfig) hasEntryPoint(entrypointName string) bool {
@@ -415,6 +670,16 @@ func (d *dynamicConfig) hasEntryPoint(entrypointName string) bool {
 	return ok
 }
 
+// addService adds the service if it is not known yet, and returns its set of servers.
+func (d *dynamicConfig) addService(serviceName string) map[string]bool {
+	servers, ok := d.services[serviceName]
+	if !ok {
+		servers = make(map[string]bool)
+		d.services[serviceName] = servers
+	}
+	return servers
+}
+
 func (d *dynamicConfig) hasService(serviceName string) bool {
 	_, ok := d.services[serviceName]
 	return ok
@@ -95,6 +95,7 @@ func (ps *prometheusState) reset() {
 	ps.collectors = make(chan *collector)
 	ps.describers = []func(ch chan<- *prometheus.Desc){}
 	ps.dynamicConfig = newDynamicConfig()
+	ps.discoveredServers = make(map[string]map[string]map[string]bool)
 	ps.state = make(map[string]*collector)
 }
 
@@ -121,6 +122,16 @@ func TestPrometheus(t *testing.T) {
 		With("cn", "value", "serial", "value", "sans", "value").
 		Set(float64(time.Now().Unix()))
 
+	prometheusRegistry.
+		MiddlewareCacheRequestsCounter().
+		With("middleware", "test", "status", "hit").
+		Add(1)
+
+	prometheusRegistry.
+		MiddlewareCircuitBreakerTransitionsCounter().
+		With("middleware", "test", "router", "test", "state", "tripped").
+		Add(1)
+
 	prometheusRegistry.
 		EntryPointReqsCounter().
 		With("code", strconv.Itoa(http.StatusOK), "method", http.MethodGet, "protocol", "http", "entrypoint", "http").
@@ -175,6 +186,86 @@ func TestPrometheus(t *testing.T) {
 		ServiceServerUpGauge().
 		With("service", "service1", "url", "http://127.0.0.10:80").
 		Set(1)
+	prometheusRegistry.
+		ServiceMirrorMismatchesCounter().
+		With("service", "service1", "mirror", "service2", "reason", "status").
+		Add(1)
+
+	prometheusRegistry.
+		TCPEntryPointConnsCounter().
+		With("entrypoint", "tcp").
+		Add(1)
+	prometheusRegistry.
+		TCPEntryPointOpenConnsGauge().
+		With("entrypoint", "tcp").
+		Set(1)
+	prometheusRegistry.
+		TCPEntryPointConnDurationHistogram().
+		With("entrypoint", "tcp").
+		Observe(1)
+	prometheusRegistry.
+		TCPEntryPointBytesCounter().
+		With("entrypoint", "tcp", "direction", "in").
+		Add(1)
+
+	prometheusRegistry.
+		TCPRouterConnsCounter().
+		With("router", "demo", "service", "tcpservice1").
+		Add(1)
+	prometheusRegistry.
+		TCPRouterOpenConnsGauge().
+		With("router", "demo", "service", "tcpservice1").
+		Set(1)
+	prometheusRegistry.
+		TCPRouterConnDurationHistogram().
+		With("router", "demo", "service", "tcpservice1").
+		Observe(1)
+	prometheusRegistry.
+		TCPRouterBytesCounter().
+		With("router", "demo", "service", "tcpservice1", "direction", "out").
+		Add(1)
+
+	prometheusRegistry.
+		TCPServiceConnsCounter().
+		With("service", "tcpservice1").
+		Add(1)
+	prometheusRegistry.
+		TCPServiceOpenConnsGauge().
+		With("service", "tcpservice1").
+		Set(1)
+	prometheusRegistry.
+		TCPServiceConnDurationHistogram().
+		With("service", "tcpservice1").
+		Observe(1)
+	prometheusRegistry.
+		TCPServiceBytesCounter().
+		With("service", "tcpservice1", "direction", "in").
+		Add(1)
+	prometheusRegistry.
+		TCPServiceServerOpenConnsGauge().
+		With("service", "tcpservice1", "address", "127.0.0.10:80").
+		Set(1)
+
+	prometheusRegistry.
+		UDPEntryPointSessionsCounter().
+		With("entrypoint", "udp").
+		Add(1)
+	prometheusRegistry.
+		UDPRouterSessionsCounter().
+		With("router", "demo", "service", "udpservice1").
+		Add(1)
+	prometheusRegistry.
+		UDPServiceSessionsCounter().
+		With("service", "udpservice1").
+		Add(1)
+	prometheusRegistry.
+		UDPServiceDatagramsCounter().
+		With("service", "udpservice1", "direction", "in").
+		Add(1)
+	prometheusRegistry.
+		UDPServiceBytesCounter().
+		With("service", "udpservice1", "direction", "in").
+		Add(1)
 
 	delayForTrackingCompletion()
 
@@ -210,6 +301,23 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildTimestampAssert(t, tlsCertsNotAfterTimestamp),
 		},
+		{
+			name: middlewareCacheRequestsTotalName,
+			labels: map[string]string{
+				"middleware": "test",
+				"status":     "hit",
+			},
+			assert: buildCounterAssert(t, middlewareCacheRequestsTotalName, 1),
+		},
+		{
+			name: middlewareCircuitBreakerTransitionsTotalName,
+			labels: map[string]string{
+				"middleware": "test",
+				"router":     "test",
+				"state":      "tripped",
+			},
+			assert: buildCounterAssert(t, middlewareCircuitBreakerTransitionsTotalName, 1),
+		},
 		{
 			name: entryPointReqsTotalName,
 			labels: map[string]string{
@@ -334,6 +442,152 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildGaugeAssert(t, serviceServerUpName, 1),
 		},
+		{
+			name: serviceMirrorMismatchesTotalName,
+			labels: map[string]string{
+				"service": "service1",
+				"mirror":  "service2",
+				"reason":  "status",
+			},
+			assert: buildCounterAssert(t, serviceMirrorMismatchesTotalName, 1),
+		},
+		{
+			name: tcpEntryPointConnsName,
+			labels: map[string]string{
+				"entrypoint": "tcp",
+			},
+			assert: buildCounterAssert(t, tcpEntryPointConnsName, 1),
+		},
+		{
+			name: tcpEntryPointOpenConnsName,
+			labels: map[string]string{
+				"entrypoint": "tcp",
+			},
+			assert: buildGaugeAssert(t, tcpEntryPointOpenConnsName, 1),
+		},
+		{
+			name: tcpEntryPointConnDurationName,
+			labels: map[string]string{
+				"entrypoint": "tcp",
+			},
+			assert: buildHistogramAssert(t, tcpEntryPointConnDurationName, 1),
+		},
+		{
+			name: tcpEntryPointBytesName,
+			labels: map[string]string{
+				"entrypoint": "tcp",
+				"direction":  "in",
+			},
+			assert: buildCounterAssert(t, tcpEntryPointBytesName, 1),
+		},
+		{
+			name: tcpRouterConnsName,
+			labels: map[string]string{
+				"router":  "demo",
+				"service": "tcpservice1",
+			},
+			assert: buildCounterAssert(t, tcpRouterConnsName, 1),
+		},
+		{
+			name: tcpRouterOpenConnsName,
+			labels: map[string]string{
+				"router":  "demo",
+				"service": "tcpservice1",
+			},
+			assert: buildGaugeAssert(t, tcpRouterOpenConnsName, 1),
+		},
+		{
+			name: tcpRouterConnDurationName,
+			labels: map[string]string{
+				"router":  "demo",
+				"service": "tcpservice1",
+			},
+			assert: buildHistogramAssert(t, tcpRouterConnDurationName, 1),
+		},
+		{
+			name: tcpRouterBytesName,
+			labels: map[string]string{
+				"router":    "demo",
+				"service":   "tcpservice1",
+				"direction": "out",
+			},
+			assert: buildCounterAssert(t, tcpRouterBytesName, 1),
+		},
+		{
+			name: tcpServiceConnsName,
+			labels: map[string]string{
+				"service": "tcpservice1",
+			},
+			assert: buildCounterAssert(t, tcpServiceConnsName, 1),
+		},
+		{
+			name: tcpServiceOpenConnsName,
+			labels: map[string]string{
+				"service": "tcpservice1",
+			},
+			assert: buildGaugeAssert(t, tcpServiceOpenConnsName, 1),
+		},
+		{
+			name: tcpServiceConnDurationName,
+			labels: map[string]string{
+				"service": "tcpservice1",
+			},
+			assert: buildHistogramAssert(t, tcpServiceConnDurationName, 1),
+		},
+		{
+			name: tcpServiceBytesName,
+			labels: map[string]string{
+				"service":   "tcpservice1",
+				"direction": "in",
+			},
+			assert: buildCounterAssert(t, tcpServiceBytesName, 1),
+		},
+		{
+			name: tcpServiceServerOpenConnsName,
+			labels: map[string]string{
+				"service": "tcpservice1",
+				"address": "127.0.0.10:80",
+			},
+			assert: buildGaugeAssert(t, tcpServiceServerOpenConnsName, 1),
+		},
+		{
+			name: udpEntryPointSessionsName,
+			labels: map[string]string{
+				"entrypoint": "udp",
+			},
+			assert: buildCounterAssert(t, udpEntryPointSessionsName, 1),
+		},
+		{
+			name: udpRouterSessionsName,
+			labels: map[string]string{
+				"router":  "demo",
+				"service": "udpservice1",
+			},
+			assert: buildCounterAssert(t, udpRouterSessionsName, 1),
+		},
+		{
+			name: udpServiceSessionsName,
+			labels: map[string]string{
+				"service": "udpservice1",
+			},
+			assert: buildCounterAssert(t, udpServiceSessionsName, 1),
+		},
+		{
+			name: udpServiceDatagramsName,
+			labels: map[string]string{
+				"service":   "udpservice1",
+				"direction": "in",
+			},
+			assert: buildCounterAssert(t, udpServiceDatagramsName, 1),
+		},
+		{
+			name: udpServiceBytesName,
+			labels: map[string]string{
+				"service":   "udpservice1",
+				"direction": "in",
+			},
+			assert: buildCounterAssert(t, udpServiceBytesName, 1),
+		},
 	}
 
 	for _, test := range testCases {
@@ -420,6 +674,104 @@ func TestPrometheusMetricRemoval(t *testing.T) {
 	assertMetricsExist(t, mustScrape(), entryPointReqsTotalName)
 }
 
+func TestPrometheusMetricRemovalSameServiceName(t *testing.T) {
+	promState = newPrometheusState()
+	promRegistry = prometheus.NewRegistry()
+	// Reset state of global promState.
+	defer promState.reset()
+
+	prometheusRegistry := RegisterPrometheus(context.Background(), &types.Prometheus{AddEntryPointsLabels: true, AddServicesLabels: true})
+	defer promRegistry.Unregister(promState)
+
+	conf := dynamic.Configuration{
+		HTTP: th.BuildConfiguration(
+			th.WithLoadBalancerServices(th.WithService("foo@providerName",
+				th.WithServers(th.WithServer("http://localhost:9000"))),
+			),
+		),
+		TCP: &dynamic.TCPConfiguration{
+			Services: map[string]*dynamic.TCPService{
+				"foo@providerName": {
+					LoadBalancer: &dynamic.TCPServersLoadBalancer{
+						Servers: []dynamic.TCPServer{{Address: "127.0.0.1:9001"}},
+					},
+				},
+			},
+		},
+	}
+
+	OnConfigurationUpdate(conf, []string{"entrypoint1"})
+
+	prometheusRegistry.
+		ServiceServerUpGauge().
+		With("service", "foo@providerName", "url", "http://localhost:9000").
+		Set(1)
+	prometheusRegistry.
+		TCPServiceServerOpenConnsGauge().
+		With("service", "foo@providerName", "address", "127.0.0.1:9001").
+		Set(1)
+
+	delayForTrackingCompletion()
+
+	// The servers of both services are part of the active configuration.
+	assertMetricsExist(t, mustScrape(), serviceServerUpName, tcpServiceServerOpenConnsName)
+	assertMetricsExist(t, mustScrape(), serviceServerUpName, tcpServiceServerOpenConnsName)
+}
+
+func TestPrometheusMetricRemovalDiscoveredServers(t *testing.T) {
+	promState = newPrometheusState()
+	promRegistry = prometheus.NewRegistry()
+	// Reset state of global promState.
+	defer promState.reset()
+
+	prometheusRegistry := RegisterPrometheus(context.Background(), &types.Prometheus{AddEntryPointsLabels: true, AddServicesLabels: true})
+	defer promRegistry.Unregister(promState)
+
+	conf := dynamic.Configuration{
+		HTTP: th.BuildConfiguration(
+			th.WithLoadBalancerServices(th.WithService("foo@providerName")),
+			func(cfg *dynamic.HTTPConfiguration) {
+				cfg.Services["foo@providerName"].LoadBalancer.DNSDiscovery = &dynamic.DNSDiscovery{Name: "foo.local", Port: 80}
+			},
+		),
+		TCP: &dynamic.TCPConfiguration{
+			Services: map[string]*dynamic.TCPService{
+				"bar@providerName": {
+					LoadBalancer: &dynamic.TCPServersLoadBalancer{
+						DNSDiscovery: &dynamic.TCPDNSDiscovery{Name: "bar.local", Port: 9001},
+					},
+				},
+			},
+		},
+	}
+
+	OnConfigurationUpdate(conf, []string{"entrypoint1"})
+	OnServersDiscovered("http", "foo@providerName", []string{"http://10.0.0.1:80"})
+	OnServersDiscovered("tcp", "bar@providerName", []string{"10.0.0.2:9001"})
+
+	// The servers discovered with DNS are not part of the configuration, but their metrics are kept.
+	prometheusRegistry.
+		ServiceServerUpGauge().
+		With("service", "foo@providerName", "url", "http://10.0.0.1:80").
+		Set(1)
+	prometheusRegistry.
+		TCPServiceServerOpenConnsGauge().
+		With("service", "bar@providerName", "address", "10.0.0.2:9001").
+		Set(1)
+
+	delayForTrackingCompletion()
+
+	assertMetricsExist(t, mustScrape(), serviceServerUpName, tcpServiceServerOpenConnsName)
+	assertMetricsExist(t, mustScrape(), serviceServerUpName, tcpServiceServerOpenConnsName)
+
+	// The metrics of the servers which are not discovered anymore are removed.
+	OnServersDiscovered("http", "foo@providerName", []string{"http://10.0.0.3:80"})
+	OnServersDiscovered("tcp", "bar@providerName", nil)
+
+	assertMetricsExist(t, mustScrape(), serviceServerUpName, tcpServiceServerOpenConnsName)
+	assertMetricsAbsent(t, mustScrape(), serviceServerUpName, tcpServiceServerOpenConnsName)
+}
+
 func TestPrometheusRemovedMetricsReset(t *testing.T) {
 	// Reset state of global promState.
 	defer promState.reset()
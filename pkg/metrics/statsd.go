@@ -24,6 +24,9 @@ const (
 
 	statsdTLSCertsNotAfterTimestampName = "tls.certs.notAfterTimestamp"
 
+	statsdMiddlewareCacheRequestsName             = "middleware.cache.request.total"
+	statsdMiddlewareCircuitBreakerTransitionsName = "middleware.circuitbreaker.transition.total"
+
 	statsdEntryPointReqsName        = "entrypoint.request.total"
 	statsdEntryPointReqsTLSName     = "entrypoint.request.tls.total"
 	statsdEntryPointReqDurationName = "entrypoint.request.duration"
@@ -40,6 +43,33 @@ const (
 	statsdServiceRetriesTotalName = "service.retries.total"
 	statsdServiceServerUpName     = "service.server.up"
 	statsdServiceOpenConnsName    = "service.connections.open"
+
+	statsdServiceMirrorMismatchesTotalName = "service.mirror.mismatches.total"
+
+	statsdTCPServiceServerOpenConnsName = "tcp.service.server.connections.open"
+
+	statsdTCPEntryPointConnsName        = "tcp.entrypoint.connections.total"
+	statsdTCPEntryPointOpenConnsName    = "tcp.entrypoint.connections.open"
+	statsdTCPEntryPointConnDurationName = "tcp.entrypoint.connection.duration"
+	statsdTCPEntryPointBytesName        = "tcp.entrypoint.bytes.total"
+
+	statsdTCPRouterConnsName        = "tcp.router.connections.total"
+	statsdTCPRouterOpenConnsName    = "tcp.router.connections.open"
+	statsdTCPRouterConnDurationName = "tcp.router.connection.duration"
+	statsdTCPRouterBytesName        = "tcp.router.bytes.total"
+
+	statsdTCPServiceConnsName        = "tcp.service.connections.total"
+	statsdTCPServiceOpenConnsName    = "tcp.service.connections.open"
+	statsdTCPServiceConnDurationName = "tcp.service.connection.duration"
+	statsdTCPServiceBytesName        = "tcp.service.bytes.total"
+
+	statsdUDPEntryPointSessionsName = "udp.entrypoint.sessions.total"
+
+	statsdUDPRouterSessionsName = "udp.router.sessions.total"
+
+	statsdUDPServiceSessionsName  = "udp.service.sessions.total"
+	statsdUDPServiceDatagramsName = "udp.service.datagrams.total"
+	statsdUDPServiceBytesName     = "udp.service.bytes.total"
 )
 
 // RegisterStatsd registers the metrics pusher if this didn't happen yet and creates a statsd Registry instance.
@@ -59,11 +89,13 @@ func RegisterStatsd(ctx context.Context, config *types.Statsd) Registry {
 	}
 
 	registry := &standardRegistry{
-		configReloadsCounter:           statsdClient.NewCounter(statsdConfigReloadsName, 1.0),
-		configReloadsFailureCounter:    statsdClient.NewCounter(statsdConfigReloadsFailureName, 1.0),
-		lastConfigReloadSuccessGauge:   statsdClient.NewGauge(statsdLastConfigReloadSuccessName),
-		lastConfigReloadFailureGauge:   statsdClient.NewGauge(statsdLastConfigReloadFailureName),
-		tlsCertsNotAfterTimestampGauge: statsdClient.NewGauge(statsdTLSCertsNotAfterTimestampName),
+		configReloadsCounter:                       statsdClient.NewCounter(statsdConfigReloadsName, 1.0),
+		configReloadsFailureCounter:                statsdClient.NewCounter(statsdConfigReloadsFailureName, 1.0),
+		lastConfigReloadSuccessGauge:               statsdClient.NewGauge(statsdLastConfigReloadSuccessName),
+		lastConfigReloadFailureGauge:               statsdClient.NewGauge(statsdLastConfigReloadFailureName),
+		tlsCertsNotAfterTimestampGauge:             statsdClient.NewGauge(statsdTLSCertsNotAfterTimestampName),
+		middlewareCacheRequestsCounter:             statsdClient.NewCounter(statsdMiddlewareCacheRequestsName, 1.0),
+		middlewareCircuitBreakerTransitionsCounter: statsdClient.NewCounter(statsdMiddlewareCircuitBreakerTransitionsName, 1.0),
 	}
 
 	if config.AddEntryPointsLabels {
@@ -72,6 +104,11 @@ func RegisterStatsd(ctx context.Context, config *types.Statsd) Registry {
 		registry.entryPointReqsTLSCounter = statsdClient.NewCounter(statsdEntryPointReqsTLSName, 1.0)
 		registry.entryPointReqDurationHistogram, _ = NewHistogramWithScale(statsdClient.NewTiming(statsdEntryPointReqDurationName, 1.0), time.Millisecond)
 		registry.entryPointOpenConnsGauge = statsdClient.NewGauge(statsdEntryPointOpenConnsName)
+		registry.tcpEntryPointConnsCounter = statsdClient.NewCounter(statsdTCPEntryPointConnsName, 1.0)
+		registry.tcpEntryPointOpenConnsGauge = statsdClient.NewGauge(statsdTCPEntryPointOpenConnsName)
+		registry.tcpEntryPointConnDurationHistogram, _ = NewHistogramWithScale(statsdClient.NewTiming(statsdTCPEntryPointConnDurationName, 1.0), time.Millisecond)
+		registry.tcpEntryPointBytesCounter = statsdClient.NewCounter(statsdTCPEntryPointBytesName, 1.0)
+		registry.udpEntryPointSessionsCounter = statsdClient.NewCounter(statsdUDPEntryPointSessionsName, 1.0)
 	}
 
 	if config.AddRoutersLabels {
@@ -80,6 +117,11 @@ func RegisterStatsd(ctx context.Context, config *types.Statsd) Registry {
 		registry.routerReqsTLSCounter = statsdClient.NewCounter(statsdRouterReqsTLSName, 1.0)
 		registry.routerReqDurationHistogram, _ = NewHistogramWithScale(statsdClient.NewTiming(statsdRouterReqsDurationName, 1.0), time.Millisecond)
 		registry.routerOpenConnsGauge = statsdClient.NewGauge(statsdRouterOpenConnsName)
+		registry.tcpRouterConnsCounter = statsdClient.NewCounter(statsdTCPRouterConnsName, 1.0)
+		registry.tcpRouterOpenConnsGauge = statsdClient.NewGauge(statsdTCPRouterOpenConnsName)
+		registry.tcpRouterConnDurationHistogram, _ = NewHistogramWithScale(statsdClient.NewTiming(statsdTCPRouterConnDurationName, 1.0), time.Millisecond)
+		registry.tcpRouterBytesCounter = statsdClient.NewCounter(statsdTCPRouterBytesName, 1.0)
+		registry.udpRouterSessionsCounter = statsdClient.NewCounter(statsdUDPRouterSessionsName, 1.0)
 	}
 
 	if config.AddServicesLabels {
@@ -90,6 +132,15 @@ func RegisterStatsd(ctx context.Context, config *types.Statsd) Registry {
 		registry.serviceRetriesCounter = statsdClient.NewCounter(statsdServiceRetriesTotalName, 1.0)
 		registry.serviceOpenConnsGauge = statsdClient.NewGauge(statsdServiceOpenConnsName)
 		registry.serviceServerUpGauge = statsdClient.NewGauge(statsdServiceServerUpName)
+		registry.serviceMirrorMismatchesCounter = statsdClient.NewCounter(statsdServiceMirrorMismatchesTotalName, 1.0)
+		registry.tcpServiceServerOpenConnsGauge = statsdClient.NewGauge(statsdTCPServiceServerOpenConnsName)
+		registry.tcpServiceConnsCounter = statsdClient.NewCounter(statsdTCPServiceConnsName, 1.0)
+		registry.tcpServiceOpenConnsGauge = statsdClient.NewGauge(statsdTCPServiceOpenConnsName)
+		registry.tcpServiceConnDurationHistogram, _ = NewHistogramWithScale(statsdClient.NewTiming(statsdTCPServiceConnDurationName, 1.0), time.Millisecond)
+		registry.tcpServiceBytesCounter = statsdClient.NewCounter(statsdTCPServiceBytesName, 1.0)
+		registry.udpServiceSessionsCounter = statsdClient.NewCounter(statsdUDPServiceSessionsName, 1.0)
+		registry.udpServiceDatagramsCounter = statsdClient.NewCounter(statsdUDPServiceDatagramsName, 1.0)
+		registry.udpServiceBytesCounter = statsdClient.NewCounter(statsdUDPServiceBytesName, 1.0)
 	}
 
 	return registry
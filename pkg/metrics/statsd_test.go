@@ -59,6 +59,9 @@ func testRegistry(t *testing.T, metricsPrefix string, registry Registry) {
 
 		metricsPrefix + ".tls.certs.notAfterTimestamp:1.000000|g\n",
 
+		metricsPrefix + ".middleware.cache.request.total:1.000000|c\n",
+		metricsPrefix + ".middleware.circuitbreaker.transition.total:1.000000|c\n",
+
 		metricsPrefix + ".entrypoint.request.total:1.000000|c\n",
 		metricsPrefix + ".entrypoint.request.tls.total:1.000000|c\n",
 		metricsPrefix + ".entrypoint.request.duration:10000.000000|ms",
@@ -75,6 +78,7 @@ func testRegistry(t *testing.T, metricsPrefix string, registry Registry) {
 		metricsPrefix + ".service.connections.open:1.000000|g\n",
 		metricsPrefix + ".service.retries.total:2.000000|c\n",
 		metricsPrefix + ".service.server.up:1.000000|g\n",
+		metricsPrefix + ".service.mirror.mismatches.total:1.000000|c\n",
 	}
 
 	udp.ShouldReceiveAll(t, expected, func() {
@@ -85,6 +89,9 @@ func testRegistry(t *testing.T, metricsPrefix string, registry Registry) {
 
 		registry.TLSCertsNotAfterTimestampGauge().With("key", "value").Set(1)
 
+		registry.MiddlewareCacheRequestsCounter().With("middleware", "test", "status", "hit").Add(1)
+		registry.MiddlewareCircuitBreakerTransitionsCounter().With("middleware", "test", "router", "test", "state", "tripped").Add(1)
+
 		registry.EntryPointReqsCounter().With("entrypoint", "test", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet).Add(1)
 		registry.EntryPointReqsTLSCounter().With("entrypoint", "test", "tls_version", "foo", "tls_cipher", "bar").Add(1)
 		registry.EntryPointReqDurationHistogram().With("entrypoint", "test").Observe(10000)
@@ -104,5 +111,6 @@ func testRegistry(t *testing.T, metricsPrefix string, registry Registry) {
 		registry.ServiceRetriesCounter().With("service", "test").Add(1)
 		registry.ServiceRetriesCounter().With("service", "test").Add(1)
 		registry.ServiceServerUpGauge().With("service:test", "url", "http://127.0.0.1").Set(1)
+		registry.ServiceMirrorMismatchesCounter().With("service", "test", "mirror", "mirror", "reason", "status").Add(1)
 	})
 }
@@ -75,6 +75,9 @@ const (
 	TLSVersion = "TLSVersion"
 	// TLSCipher is the cipher used in the request.
 	TLSCipher = "TLSCipher"
+
+	// CacheStatus is the map key used for the status of the request in a cache middleware (e.g. hit or miss).
+	CacheStatus = "CacheStatus"
 )
 
 // These are written out in the default case when no config is provided to specify keys of interest.
@@ -118,6 +121,7 @@ func init() {
 	allCoreKeys[RetryAttempts] = struct{}{}
 	allCoreKeys[TLSVersion] = struct{}{}
 	allCoreKeys[TLSCipher] = struct{}{}
+	allCoreKeys[CacheStatus] = struct{}{}
 }
 
 // CoreLogData holds the fields computed from the request/response.
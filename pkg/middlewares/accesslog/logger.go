@@ -10,6 +10,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -35,6 +36,12 @@ const (
 	JSONFormat string = "json"
 )
 
+// Protocols of the connections logged by the TCP and UDP handlers.
+const (
+	tcpProtocol = "TCP"
+	udpProtocol = "UDP"
+)
+
 type noopCloser struct {
 	*os.File
 }
@@ -50,6 +57,8 @@ func (n noopCloser) Close() error {
 
 type handlerParams struct {
 	logDataTable *LogData
+	// connection is true when the log data is the one of a TCP connection or of a UDP session.
+	connection bool
 }
 
 // Handler will write each request and its response to the access log.
@@ -134,6 +143,10 @@ func NewHandler(config *types.AccessLog) (*Handler, error) {
 		go func() {
 			defer logHandler.wg.Done()
 			for handlerParams := range logHandler.logHandlerChan {
+				if handlerParams.connection {
+					logHandler.logTheConnection(handlerParams.logDataTable)
+					continue
+				}
 				logHandler.logTheRoundTrip(handlerParams.logDataTable)
 			}
 		}()
@@ -343,6 +356,71 @@ func (h *Handler) logTheRoundTrip(logDataTable *LogData) {
 	}
 }
 
+func newConnLogData(remoteAddr net.Addr, protocol string) *LogData {
+	now := time.Now().UTC()
+
+	core := CoreLogData{
+		StartUTC:   now,
+		StartLocal: now.Local(),
+	}
+
+	core[RequestCount] = nextRequestCount()
+	core[RequestProtocol] = protocol
+
+	if remoteAddr != nil {
+		core[ClientAddr] = remoteAddr.String()
+		core[ClientHost], core[ClientPort] = silentSplitHostPort(remoteAddr.String())
+	}
+
+	return &LogData{Core: core}
+}
+
+func (h *Handler) logConnection(logDataTable *LogData) {
+	if h.config.BufferingSize > 0 {
+		h.logHandlerChan <- handlerParams{
+			logDataTable: logDataTable,
+			connection:   true,
+		}
+	} else {
+		h.logTheConnection(logDataTable)
+	}
+}
+
+// logTheConnection writes the access log entry of a TCP connection or UDP session.
+// As there is no status code, only the duration filter can keep such an entry when filters are set.
+func (h *Handler) logTheConnection(logDataTable *LogData) {
+	core := logDataTable.Core
+
+	// n.b. take care to perform time arithmetic using UTC to avoid errors at DST boundaries.
+	totalDuration := time.Now().UTC().Sub(core[StartUTC].(time.Time))
+	core[Duration] = totalDuration
+
+	if !h.keepAccessLog(0, 0, totalDuration) {
+		return
+	}
+
+	core[RequestContentSize] = logDataTable.Request.size
+	core[DownstreamContentSize] = logDataTable.DownstreamResponse.size
+
+	if addr, ok := core[ServiceAddr].(string); ok {
+		protocol, _ := core[RequestProtocol].(string)
+		serviceURL := url.URL{Scheme: strings.ToLower(protocol), Host: addr}
+		core[ServiceURL] = serviceURL.String()
+	}
+
+	fields := logrus.Fields{}
+
+	for k, v := range core {
+		if h.config.Fields.Keep(k) {
+			fields[k] = v
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.logger.WithFields(fields).Println()
+}
+
 func (h *Handler) redactHeaders(headers http.Header, fields logrus.Fields, prefix string) {
 	for k := range headers {
 		v := h.config.Fields.KeepHeader(k)
@@ -0,0 +1,100 @@
+package accesslog
+
+import (
+	"crypto/tls"
+	"net"
+	"sync/atomic"
+
+	"github.com/traefik/traefik/v2/pkg/tcp"
+	traefiktls "github.com/traefik/traefik/v2/pkg/tls"
+)
+
+// NewTCPHandler creates a tcp.Handler writing an access log entry for each connection forwarded to next, once it is over.
+func NewTCPHandler(handler *Handler, next tcp.Handler) tcp.Handler {
+	return tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		handler.ServeTCP(conn, next)
+	})
+}
+
+// GetTCPLogData gets the log data attached to the given TCP connection, if any.
+func GetTCPLogData(conn net.Conn) *LogData {
+	for {
+		switch c := conn.(type) {
+		case *loggedConn:
+			return c.data
+		case interface{ NetConn() net.Conn }:
+			conn = c.NetConn()
+		default:
+			return nil
+		}
+	}
+}
+
+// NewTCPFieldHandler creates a TCP handler adding a new field to the log data of the connections, if any.
+func NewTCPFieldHandler(next tcp.Handler, name, value string) tcp.Handler {
+	return tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		if table := GetTCPLogData(conn); table != nil {
+			table.Core[name] = value
+		}
+
+		next.ServeTCP(conn)
+	})
+}
+
+// ServeTCP forwards the connection to next, and writes its access log entry once it is over.
+func (h *Handler) ServeTCP(conn tcp.WriteCloser, next tcp.Handler) {
+	logDataTable := newConnLogData(conn.RemoteAddr(), tcpProtocol)
+
+	lConn := &loggedConn{WriteCloser: conn, data: logDataTable}
+
+	next.ServeTCP(lConn)
+
+	core := logDataTable.Core
+	switch c := conn.(type) {
+	case *tls.Conn:
+		state := c.ConnectionState()
+		if state.HandshakeComplete {
+			core[RequestHost] = state.ServerName
+			core[TLSVersion] = traefiktls.GetVersion(&state)
+			core[TLSCipher] = traefiktls.GetCipherName(&state)
+		}
+	case *tcp.Conn:
+		if c.ServerName != "" {
+			core[RequestHost] = c.ServerName
+		}
+	}
+
+	logDataTable.Request.size = atomic.LoadInt64(&lConn.bytesIn)
+	logDataTable.DownstreamResponse.size = atomic.LoadInt64(&lConn.bytesOut)
+
+	h.logConnection(logDataTable)
+}
+
+// loggedConn counts the bytes read from and written to the underlying connection,
+// and carries the log data of the connection.
+type loggedConn struct {
+	// bytesIn and bytesOut are accessed atomically,
+	// and are kept first in the struct to guarantee their 64-bit alignment.
+	bytesIn  int64
+	bytesOut int64
+
+	tcp.WriteCloser
+	data *LogData
+}
+
+// NetConn returns the underlying connection.
+func (c *loggedConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
+
+func (c *loggedConn) Read(p []byte) (int, error) {
+	n, err := c.WriteCloser.Read(p)
+	atomic.AddInt64(&c.bytesIn, int64(n))
+	return n, err
+}
+
+func (c *loggedConn) Write(p []byte) (int, error) {
+	n, err := c.WriteCloser.Write(p)
+	atomic.AddInt64(&c.bytesOut, int64(n))
+	return n, err
+}
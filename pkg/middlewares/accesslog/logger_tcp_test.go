@@ -0,0 +1,150 @@
+package accesslog
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+func TestTCPLogger(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		config   *types.AccessLog
+		expected map[string]func(t *testing.T, value interface{})
+	}{
+		{
+			desc: "default config",
+			config: &types.AccessLog{
+				Format: JSONFormat,
+			},
+			expected: map[string]func(t *testing.T, value interface{}){
+				RequestProtocol:       assertString("TCP"),
+				RequestContentSize:    assertFloat64(4),
+				DownstreamContentSize: assertFloat64(4),
+				RouterName:            assertString("router"),
+				ServiceName:           assertString("service"),
+				ServiceAddr:           assertString("10.0.0.1:80"),
+				ServiceURL:            assertString("tcp://10.0.0.1:80"),
+				ClientHost:            assertString("127.0.0.1"),
+				ClientPort:            assertNotEmpty(),
+				ClientAddr:            assertNotEmpty(),
+				RequestCount:          assertFloat64NotZero(),
+				Duration:              assertFloat64NotZero(),
+				"level":               assertString("info"),
+				"msg":                 assertString(""),
+				"time":                assertNotEmpty(),
+				StartLocal:            assertNotEmpty(),
+				StartUTC:              assertNotEmpty(),
+			},
+		},
+		{
+			desc: "drop all fields but kept someone",
+			config: &types.AccessLog{
+				Format: JSONFormat,
+				Fields: &types.AccessLogFields{
+					DefaultMode: "drop",
+					Names: map[string]string{
+						ServiceAddr: "keep",
+					},
+				},
+			},
+			expected: map[string]func(t *testing.T, value interface{}){
+				ServiceAddr: assertString("10.0.0.1:80"),
+				"level":     assertString("info"),
+				"msg":       assertString(""),
+				"time":      assertNotEmpty(),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			logFilePath := filepath.Join(t.TempDir(), logFileNameSuffix)
+			test.config.FilePath = logFilePath
+
+			doTCPLogging(t, test.config)
+
+			logData, err := os.ReadFile(logFilePath)
+			require.NoError(t, err)
+
+			jsonData := make(map[string]interface{})
+			err = json.Unmarshal(logData, &jsonData)
+			require.NoError(t, err)
+
+			assert.Equal(t, len(test.expected), len(jsonData))
+
+			for field, assertion := range test.expected {
+				assertion(t, jsonData[field])
+			}
+		})
+	}
+}
+
+func TestTCPLoggerFilters(t *testing.T) {
+	logFilePath := filepath.Join(t.TempDir(), logFileNameSuffix)
+
+	doTCPLogging(t, &types.AccessLog{
+		FilePath: logFilePath,
+		Format:   CommonFormat,
+		Filters: &types.AccessLogFilters{
+			MinDuration: ptypes.Duration(time.Hour),
+		},
+	})
+
+	logData, err := os.ReadFile(logFilePath)
+	require.NoError(t, err)
+
+	assert.Empty(t, logData)
+}
+
+func doTCPLogging(t *testing.T, config *types.AccessLog) {
+	t.Helper()
+
+	logger, err := NewHandler(config)
+	require.NoError(t, err)
+	defer logger.Close()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	go func() {
+		conn, err := net.Dial("tcp", ln.Addr().String())
+		require.NoError(t, err)
+		defer conn.Close()
+
+		_, err = conn.Write([]byte("ping"))
+		require.NoError(t, err)
+
+		_, err = conn.Read(make([]byte, 4))
+		require.NoError(t, err)
+	}()
+
+	conn, err := ln.Accept()
+	require.NoError(t, err)
+
+	var next tcp.Handler = tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		defer conn.Close()
+
+		_, err := conn.Read(make([]byte, 4))
+		require.NoError(t, err)
+
+		_, err = conn.Write([]byte("pong"))
+		require.NoError(t, err)
+	})
+	next = NewTCPFieldHandler(next, ServiceAddr, "10.0.0.1:80")
+	next = NewTCPFieldHandler(next, ServiceName, "service")
+	next = NewTCPFieldHandler(next, RouterName, "router")
+
+	NewTCPHandler(logger, next).ServeTCP(conn.(*net.TCPConn))
+}
@@ -0,0 +1,46 @@
+package accesslog
+
+import "github.com/traefik/traefik/v2/pkg/udp"
+
+// udpLogDataKey is the key of the log data attached to the UDP sessions.
+type udpLogDataKey struct{}
+
+// NewUDPHandler creates a udp.Handler writing an access log entry for each session forwarded to next, once it is over.
+func NewUDPHandler(handler *Handler, next udp.Handler) udp.Handler {
+	return udp.HandlerFunc(func(conn *udp.Conn) {
+		handler.ServeUDP(conn, next)
+	})
+}
+
+// GetUDPLogData gets the log data attached to the given UDP session, if any.
+func GetUDPLogData(conn *udp.Conn) *LogData {
+	if ld, ok := conn.Value(udpLogDataKey{}).(*LogData); ok {
+		return ld
+	}
+	return nil
+}
+
+// NewUDPFieldHandler creates a UDP handler adding a new field to the log data of the sessions, if any.
+func NewUDPFieldHandler(next udp.Handler, name, value string) udp.Handler {
+	return udp.HandlerFunc(func(conn *udp.Conn) {
+		if table := GetUDPLogData(conn); table != nil {
+			table.Core[name] = value
+		}
+
+		next.ServeUDP(conn)
+	})
+}
+
+// ServeUDP forwards the session to next, and writes its access log entry once it is over.
+func (h *Handler) ServeUDP(conn *udp.Conn, next udp.Handler) {
+	logDataTable := newConnLogData(conn.RemoteAddr(), udpProtocol)
+
+	conn.SetValue(udpLogDataKey{}, logDataTable)
+
+	next.ServeUDP(conn)
+
+	logDataTable.Request.size = conn.BytesRead()
+	logDataTable.DownstreamResponse.size = conn.BytesWritten()
+
+	h.logConnection(logDataTable)
+}
@@ -0,0 +1,143 @@
+package accesslog
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/types"
+	"github.com/traefik/traefik/v2/pkg/udp"
+)
+
+func TestUDPLogger(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		config   *types.AccessLog
+		expected map[string]func(t *testing.T, value interface{})
+	}{
+		{
+			desc: "default config",
+			config: &types.AccessLog{
+				Format: JSONFormat,
+			},
+			expected: map[string]func(t *testing.T, value interface{}){
+				RequestProtocol:       assertString("UDP"),
+				RequestContentSize:    assertFloat64(4),
+				DownstreamContentSize: assertFloat64(4),
+				RouterName:            assertString("router"),
+				ServiceName:           assertString("service"),
+				ServiceAddr:           assertString("10.0.0.1:53"),
+				ServiceURL:            assertString("udp://10.0.0.1:53"),
+				ClientHost:            assertString("127.0.0.1"),
+				ClientPort:            assertNotEmpty(),
+				ClientAddr:            assertNotEmpty(),
+				RequestCount:          assertFloat64NotZero(),
+				Duration:              assertFloat64NotZero(),
+				"level":               assertString("info"),
+				"msg":                 assertString(""),
+				"time":                assertNotEmpty(),
+				StartLocal:            assertNotEmpty(),
+				StartUTC:              assertNotEmpty(),
+			},
+		},
+		{
+			desc: "drop all fields but the service address",
+			config: &types.AccessLog{
+				Format: JSONFormat,
+				Fields: &types.AccessLogFields{
+					DefaultMode: "drop",
+					Names: map[string]string{
+						ServiceAddr: "keep",
+					},
+				},
+			},
+			expected: map[string]func(t *testing.T, value interface{}){
+				ServiceAddr: assertString("10.0.0.1:53"),
+				"level":     assertString("info"),
+				"msg":       assertString(""),
+				"time":      assertNotEmpty(),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			logFilePath := filepath.Join(t.TempDir(), logFileNameSuffix)
+			test.config.FilePath = logFilePath
+
+			doUDPLogging(t, test.config)
+
+			logData, err := os.ReadFile(logFilePath)
+			require.NoError(t, err)
+
+			jsonData := make(map[string]interface{})
+			err = json.Unmarshal(logData, &jsonData)
+			require.NoError(t, err)
+
+			assert.Equal(t, len(test.expected), len(jsonData))
+
+			for field, assertion := range test.expected {
+				assertion(t, jsonData[field])
+			}
+		})
+	}
+}
+
+func doUDPLogging(t *testing.T, config *types.AccessLog) {
+	t.Helper()
+
+	logger, err := NewHandler(config)
+	require.NoError(t, err)
+	defer logger.Close()
+
+	ln, err := udp.Listen("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")}, 3*time.Second)
+	require.NoError(t, err)
+	defer ln.Close()
+
+	// The client errors are asserted in the test goroutine, as require cannot stop the test from another goroutine.
+	clientErr := make(chan error, 1)
+	go func() {
+		clientErr <- pingUDP(ln.Addr().String())
+	}()
+
+	conn, err := ln.Accept()
+	require.NoError(t, err)
+
+	var next udp.Handler = udp.HandlerFunc(func(conn *udp.Conn) {
+		defer conn.Close()
+
+		_, err := conn.Read(make([]byte, 4))
+		require.NoError(t, err)
+
+		_, err = conn.Write([]byte("pong"))
+		require.NoError(t, err)
+	})
+	next = NewUDPFieldHandler(next, ServiceAddr, "10.0.0.1:53")
+	next = NewUDPFieldHandler(next, ServiceName, "service")
+	next = NewUDPFieldHandler(next, RouterName, "router")
+
+	NewUDPHandler(logger, next).ServeUDP(conn)
+
+	require.NoError(t, <-clientErr)
+}
+
+func pingUDP(addr string) error {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err = conn.Write([]byte("ping")); err != nil {
+		return err
+	}
+
+	_, err = conn.Read(make([]byte, 4))
+	return err
+}
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -24,8 +25,14 @@ const (
 	xForwardedURI     = "X-Forwarded-Uri"
 	xForwardedMethod  = "X-Forwarded-Method"
 	forwardedTypeName = "ForwardedAuthType"
+
+	// defaultMaxBodySize is the maximum size of the body forwarded to the authentication server, when not configured.
+	defaultMaxBodySize int64 = 1024 * 1024
 )
 
+// errBodyTooLarge is returned when the request body is larger than the maximum size of the body forwarded to the authentication server.
+var errBodyTooLarge = errors.New("request body too large")
+
 // hopHeaders Hop-by-hop headers to be removed in the authentication request.
 // http://www.w3.org/Protocols/rfc2616/rfc2616-sec13.html
 // Proxy-Authorization header is forwarded to the authentication server (see https://tools.ietf.org/html/rfc7235#section-4.4).
@@ -47,6 +54,10 @@ type forwardAuth struct {
 	client                   http.Client
 	trustForwardHeader       bool
 	authRequestHeaders       []string
+	forwardBody              bool
+	maxBodySize              int64
+	preserveRequestMethod    bool
+	cache                    *authCache
 }
 
 // NewForward creates a forward auth middleware.
@@ -54,12 +65,15 @@ func NewForward(ctx context.Context, next http.Handler, config dynamic.ForwardAu
 	log.FromContext(middlewares.GetLoggerCtx(ctx, name, forwardedTypeName)).Debug("Creating middleware")
 
 	fa := &forwardAuth{
-		address:             config.Address,
-		authResponseHeaders: config.AuthResponseHeaders,
-		next:                next,
-		name:                name,
-		trustForwardHeader:  config.TrustForwardHeader,
-		authRequestHeaders:  config.AuthRequestHeaders,
+		address:               config.Address,
+		authResponseHeaders:   config.AuthResponseHeaders,
+		next:                  next,
+		name:                  name,
+		trustForwardHeader:    config.TrustForwardHeader,
+		authRequestHeaders:    config.AuthRequestHeaders,
+		forwardBody:           config.ForwardBody,
+		maxBodySize:           config.MaxBodySize,
+		preserveRequestMethod: config.PreserveRequestMethod,
 	}
 
 	// Ensure our request client does not follow redirects
@@ -81,6 +95,13 @@ func NewForward(ctx context.Context, next http.Handler, config dynamic.ForwardAu
 		fa.client.Transport = tr
 	}
 
+	if config.MaxBodySize < 0 {
+		return nil, fmt.Errorf("maxBodySize must not be negative: %d", config.MaxBodySize)
+	}
+	if fa.maxBodySize == 0 {
+		fa.maxBodySize = defaultMaxBodySize
+	}
+
 	if config.AuthResponseHeadersRegex != "" {
 		re, err := regexp.Compile(config.AuthResponseHeadersRegex)
 		if err != nil {
@@ -89,6 +110,15 @@ func NewForward(ctx context.Context, next http.Handler, config dynamic.ForwardAu
 		fa.authResponseHeadersRegex = re
 	}
 
+	if config.Cache != nil {
+		// The authentication server responses are cached by the request headers only,
+		// whereas they could depend on the request body.
+		if config.ForwardBody {
+			return nil, errors.New("the cache cannot be used when the request body is forwarded")
+		}
+		fa.cache = newAuthCache(*config.Cache)
+	}
+
 	return fa, nil
 }
 
@@ -99,15 +129,119 @@ func (fa *forwardAuth) GetTracingInformation() (string, ext.SpanKindEnum) {
 func (fa *forwardAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), fa.name, forwardedTypeName))
 
-	forwardReq, err := http.NewRequest(http.MethodGet, fa.address, nil)
+	var cacheKey string
+	var authResp *authResponse
+	if fa.cache != nil {
+		// The forwarded headers are computed as they are sent to the authentication server.
+		forwarded := &http.Request{Header: make(http.Header)}
+		writeHeader(req, forwarded, fa.trustForwardHeader, fa.authRequestHeaders)
+
+		var ok bool
+		if cacheKey, ok = fa.cache.key(req, forwarded.Header); ok {
+			authResp = fa.cache.get(cacheKey)
+		}
+	}
+
+	if authResp != nil {
+		logger.Debugf("Using the cached response of %s", fa.address)
+	} else {
+		var statusCode int
+		authResp, statusCode = fa.callAuthServer(req, logger)
+		if authResp == nil {
+			rw.WriteHeader(statusCode)
+			return
+		}
+
+		// Server errors are transient, and are not cached.
+		if cacheKey != "" && authResp.statusCode < http.StatusInternalServerError {
+			fa.cache.set(cacheKey, authResp)
+		}
+	}
+
+	// Pass the forward response's body and selected headers if it
+	// didn't return a response within the range of [200, 300).
+	if authResp.statusCode < http.StatusOK || authResp.statusCode >= http.StatusMultipleChoices {
+		logger.Debugf("Remote error %s. StatusCode: %d", fa.address, authResp.statusCode)
+
+		utils.CopyHeaders(rw.Header(), authResp.header)
+		utils.RemoveHeaders(rw.Header(), hopHeaders...)
+
+		if authResp.location != "" {
+			// Set the location in our response if one was sent back.
+			rw.Header().Set("Location", authResp.location)
+		}
+
+		tracing.LogResponseCode(tracing.GetSpan(req), authResp.statusCode)
+		rw.WriteHeader(authResp.statusCode)
+
+		if _, err := rw.Write(authResp.body); err != nil {
+			logger.Error(err)
+		}
+		return
+	}
+
+	for _, headerName := range fa.authResponseHeaders {
+		headerKey := http.CanonicalHeaderKey(headerName)
+		req.Header.Del(headerKey)
+		if len(authResp.header[headerKey]) > 0 {
+			req.Header[headerKey] = append([]string(nil), authResp.header[headerKey]...)
+		}
+	}
+
+	if fa.authResponseHeadersRegex != nil {
+		for headerKey := range req.Header {
+			if fa.authResponseHeadersRegex.MatchString(headerKey) {
+				req.Header.Del(headerKey)
+			}
+		}
+
+		for headerKey, headerValues := range authResp.header {
+			if fa.authResponseHeadersRegex.MatchString(headerKey) {
+				req.Header[headerKey] = append([]string(nil), headerValues...)
+			}
+		}
+	}
+
+	req.RequestURI = req.URL.RequestURI()
+	fa.next.ServeHTTP(rw, req)
+}
+
+// callAuthServer calls the authentication server for the request.
+// When the call fails, it returns a nil response and the status code to answer the request with.
+func (fa *forwardAuth) callAuthServer(req *http.Request, logger log.Logger) (*authResponse, int) {
+	method := http.MethodGet
+	if fa.preserveRequestMethod {
+		method = req.Method
+	}
+
+	var body io.Reader
+	if fa.forwardBody {
+		content, err := readBody(req, fa.maxBodySize)
+		if errors.Is(err, errBodyTooLarge) {
+			logger.Debugf("Request body is larger than %d bytes", fa.maxBodySize)
+			return nil, http.StatusRequestEntityTooLarge
+		}
+		if err != nil {
+			logMessage := fmt.Sprintf("Error reading request body. Cause: %s", err)
+			logger.Debug(logMessage)
+			tracing.SetErrorWithEvent(req, logMessage)
+
+			return nil, http.StatusInternalServerError
+		}
+
+		if content != nil {
+			body = bytes.NewReader(content)
+		}
+	}
+
+	forwardReq, err := http.NewRequest(method, fa.address, body)
 	tracing.LogRequest(tracing.GetSpan(req), forwardReq)
 	if err != nil {
 		logMessage := fmt.Sprintf("Error calling %s. Cause %s", fa.address, err)
 		logger.Debug(logMessage)
 		tracing.SetErrorWithEvent(req, logMessage)
 
-		rw.WriteHeader(http.StatusInternalServerError)
-		return
+		return nil, http.StatusInternalServerError
 	}
 
 	// Ensure tracing headers are in the request before we copy the headers to the
@@ -122,29 +256,26 @@ func (fa *forwardAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		logger.Debug(logMessage)
 		tracing.SetErrorWithEvent(req, logMessage)
 
-		rw.WriteHeader(http.StatusInternalServerError)
-		return
+		return nil, http.StatusInternalServerError
 	}
 
-	body, readError := io.ReadAll(forwardResponse.Body)
+	responseBody, readError := io.ReadAll(forwardResponse.Body)
 	if readError != nil {
 		logMessage := fmt.Sprintf("Error reading body %s. Cause: %s", fa.address, readError)
 		logger.Debug(logMessage)
 		tracing.SetErrorWithEvent(req, logMessage)
 
-		rw.WriteHeader(http.StatusInternalServerError)
-		return
+		return nil, http.StatusInternalServerError
 	}
 	defer forwardResponse.Body.Close()
 
-	// Pass the forward response's body and selected headers if it
-	// didn't return a response within the range of [200, 300).
-	if forwardResponse.StatusCode < http.StatusOK || forwardResponse.StatusCode >= http.StatusMultipleChoices {
-		logger.Debugf("Remote error %s. StatusCode: %d", fa.address, forwardResponse.StatusCode)
-
-		utils.CopyHeaders(rw.Header(), forwardResponse.Header)
-		utils.RemoveHeaders(rw.Header(), hopHeaders...)
+	authResp := &authResponse{
+		statusCode: forwardResponse.StatusCode,
+		header:     forwardResponse.Header,
+		body:       responseBody,
+	}
 
+	if forwardResponse.StatusCode < http.StatusOK || forwardResponse.StatusCode >= http.StatusMultipleChoices {
 		// Grab the location header, if any.
 		redirectURL, err := forwardResponse.Location()
 
@@ -154,47 +285,35 @@ func (fa *forwardAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 				logger.Debug(logMessage)
 				tracing.SetErrorWithEvent(req, logMessage)
 
-				rw.WriteHeader(http.StatusInternalServerError)
-				return
+				return nil, http.StatusInternalServerError
 			}
-		} else if redirectURL.String() != "" {
-			// Set the location in our response if one was sent back.
-			rw.Header().Set("Location", redirectURL.String())
+		} else {
+			authResp.location = redirectURL.String()
 		}
+	}
 
-		tracing.LogResponseCode(tracing.GetSpan(req), forwardResponse.StatusCode)
-		rw.WriteHeader(forwardResponse.StatusCode)
+	return authResp, 0
+}
 
-		if _, err = rw.Write(body); err != nil {
-			logger.Error(err)
-		}
-		return
+// readBody reads the request body, and restores it for the next handlers.
+// It returns errBodyTooLarge if the body is larger than maxSize.
+func readBody(req *http.Request, maxSize int64) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
 	}
 
-	for _, headerName := range fa.authResponseHeaders {
-		headerKey := http.CanonicalHeaderKey(headerName)
-		req.Header.Del(headerKey)
-		if len(forwardResponse.Header[headerKey]) > 0 {
-			req.Header[headerKey] = append([]string(nil), forwardResponse.Header[headerKey]...)
-		}
+	content, err := io.ReadAll(io.LimitReader(req.Body, maxSize+1))
+	if err != nil {
+		return nil, err
 	}
 
-	if fa.authResponseHeadersRegex != nil {
-		for headerKey := range req.Header {
-			if fa.authResponseHeadersRegex.MatchString(headerKey) {
-				req.Header.Del(headerKey)
-			}
-		}
-
-		for headerKey, headerValues := range forwardResponse.Header {
-			if fa.authResponseHeadersRegex.MatchString(headerKey) {
-				req.Header[headerKey] = append([]string(nil), headerValues...)
-			}
-		}
+	if int64(len(content)) > maxSize {
+		return nil, errBodyTooLarge
 	}
 
-	req.RequestURI = req.URL.RequestURI()
-	fa.next.ServeHTTP(rw, req)
+	req.Body = io.NopCloser(bytes.NewReader(content))
+
+	return content, nil
 }
 
 func writeHeader(req, forwardReq *http.Request, trustForwardHeader bool, allowedHeaders []string) {
@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/vulcand/oxy/forward"
+)
+
+// forwardedKeyHeaders are the headers describing the original request to the authentication server,
+// by which the responses are cached in addition to the configured headers.
+var forwardedKeyHeaders = []string{xForwardedMethod, forward.XForwardedProto, forward.XForwardedHost, xForwardedURI}
+
+// authResponse is the response of the authentication server, as used by the forward auth middleware.
+type authResponse struct {
+	statusCode int
+	header     http.Header
+	body       []byte
+	location   string
+}
+
+type authCacheEntry struct {
+	key      string
+	response *authResponse
+	expiry   time.Time
+}
+
+// authCache is a least recently used cache of the authentication server responses,
+// keyed by the values of the configured request headers and of the forwarded headers sent to the authentication server.
+type authCache struct {
+	headers    []string
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+func newAuthCache(config dynamic.ForwardAuthCache) *authCache {
+	headers := config.Headers
+	if len(headers) == 0 {
+		headers = []string{authorizationHeader}
+	}
+
+	return &authCache{
+		headers:    headers,
+		ttl:        time.Duration(config.TTL),
+		maxEntries: config.MaxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// key returns the cache key of the request, given the headers forwarded to the authentication server,
+// and false if the request has none of the configured headers.
+func (c *authCache) key(req *http.Request, forwarded http.Header) (string, bool) {
+	hash := sha256.New()
+
+	var found bool
+	for _, name := range c.headers {
+		values := req.Header.Values(name)
+		if len(values) > 0 {
+			found = true
+		}
+
+		hash.Write([]byte(http.CanonicalHeaderKey(name)))
+		for _, value := range values {
+			hash.Write([]byte{0})
+			hash.Write([]byte(value))
+		}
+		hash.Write([]byte{0, 0})
+	}
+
+	if !found {
+		return "", false
+	}
+
+	for _, name := range forwardedKeyHeaders {
+		hash.Write([]byte(name))
+		hash.Write([]byte{0})
+		hash.Write([]byte(forwarded.Get(name)))
+		hash.Write([]byte{0, 0})
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), true
+}
+
+func (c *authCache) get(key string) *authResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elt, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+
+	entry := elt.Value.(*authCacheEntry)
+	if time.Now().After(entry.expiry) {
+		c.remove(elt)
+		return nil
+	}
+
+	c.order.MoveToFront(elt)
+	return entry.response
+}
+
+func (c *authCache) set(key string, response *authResponse) {
+	if c.ttl <= 0 || c.maxEntries <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elt, ok := c.entries[key]; ok {
+		c.remove(elt)
+	}
+
+	c.entries[key] = c.order.PushFront(&authCacheEntry{
+		key:      key,
+		response: response,
+		expiry:   time.Now().Add(c.ttl),
+	})
+
+	for c.order.Len() > c.maxEntries {
+		c.remove(c.order.Back())
+	}
+}
+
+func (c *authCache) remove(elt *list.Element) {
+	c.order.Remove(elt)
+	delete(c.entries, elt.Value.(*authCacheEntry).key)
+}
@@ -6,12 +6,16 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/mocktracer"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	tracingMiddleware "github.com/traefik/traefik/v2/pkg/middlewares/tracing"
 	"github.com/traefik/traefik/v2/pkg/testhelpers"
@@ -450,6 +454,199 @@ func Test_writeHeader(t *testing.T) {
 	}
 }
 
+func TestForwardAuthCache(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.Header.Get("Authorization") != "Bearer valid" {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		w.Header().Set("X-Auth-User", "user@example.com")
+	}))
+	t.Cleanup(server.Close)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.Header.Get("X-Auth-User"))
+	})
+
+	cache := &dynamic.ForwardAuthCache{}
+	cache.SetDefaults()
+
+	middleware, err := NewForward(context.Background(), next, dynamic.ForwardAuth{
+		Address:             server.URL,
+		AuthResponseHeaders: []string{"X-Auth-User"},
+		Cache:               cache,
+	}, "authTest")
+	require.NoError(t, err)
+
+	serveTarget := func(method, target, authorization string) (int, string) {
+		req := httptest.NewRequest(method, target, nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+
+		recorder := httptest.NewRecorder()
+		middleware.ServeHTTP(recorder, req)
+
+		return recorder.Code, recorder.Body.String()
+	}
+
+	serve := func(authorization string) (int, string) {
+		return serveTarget(http.MethodGet, "http://foo.bar", authorization)
+	}
+
+	for i := 0; i < 2; i++ {
+		code, body := serve("Bearer valid")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "user@example.com", body)
+
+		code, body = serve("Bearer invalid")
+		assert.Equal(t, http.StatusForbidden, code)
+		assert.Equal(t, "Forbidden\n", body)
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	// The requests without the cache key headers are not cached.
+	for i := 0; i < 2; i++ {
+		code, _ := serve("")
+		assert.Equal(t, http.StatusForbidden, code)
+	}
+	assert.Equal(t, int32(4), atomic.LoadInt32(&calls))
+
+	// The responses are also cached by the method, host and URI forwarded to the authentication server.
+	for _, target := range []string{"http://foo.bar/admin", "http://foo.bar/?user=admin", "http://other.bar"} {
+		code, _ := serveTarget(http.MethodGet, target, "Bearer valid")
+		assert.Equal(t, http.StatusOK, code)
+	}
+	code, _ := serveTarget(http.MethodDelete, "http://foo.bar", "Bearer valid")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, int32(8), atomic.LoadInt32(&calls))
+}
+
+func Test_authCache(t *testing.T) {
+	cache := newAuthCache(dynamic.ForwardAuthCache{TTL: ptypes.Duration(time.Hour), MaxEntries: 2})
+
+	cache.set("a", &authResponse{statusCode: http.StatusOK})
+	cache.set("b", &authResponse{statusCode: http.StatusOK})
+	assert.NotNil(t, cache.get("a"))
+
+	// The least recently used entry is evicted.
+	cache.set("c", &authResponse{statusCode: http.StatusOK})
+	assert.NotNil(t, cache.get("a"))
+	assert.Nil(t, cache.get("b"))
+	assert.NotNil(t, cache.get("c"))
+
+	cache.entries["a"].Value.(*authCacheEntry).expiry = time.Now().Add(-time.Second)
+	assert.Nil(t, cache.get("a"))
+	assert.Equal(t, 1, cache.order.Len())
+}
+
+func TestForwardAuthForwardBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		if r.Method != http.MethodPost || string(body) != "payload" {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		fmt.Fprint(w, string(body))
+	})
+
+	testCases := []struct {
+		desc           string
+		config         dynamic.ForwardAuth
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			desc:           "body not forwarded",
+			config:         dynamic.ForwardAuth{PreserveRequestMethod: true},
+			body:           "payload",
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   "Forbidden\n",
+		},
+		{
+			desc:           "method not preserved",
+			config:         dynamic.ForwardAuth{ForwardBody: true},
+			body:           "payload",
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   "Forbidden\n",
+		},
+		{
+			desc:           "body forwarded",
+			config:         dynamic.ForwardAuth{ForwardBody: true, PreserveRequestMethod: true},
+			body:           "payload",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "payload",
+		},
+		{
+			desc:           "body within the maximum size",
+			config:         dynamic.ForwardAuth{ForwardBody: true, MaxBodySize: 7, PreserveRequestMethod: true},
+			body:           "payload",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "payload",
+		},
+		{
+			desc:           "body larger than the maximum size",
+			config:         dynamic.ForwardAuth{ForwardBody: true, MaxBodySize: 6, PreserveRequestMethod: true},
+			body:           "payload",
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			desc:           "body larger than the default maximum size",
+			config:         dynamic.ForwardAuth{ForwardBody: true, PreserveRequestMethod: true},
+			body:           strings.Repeat("a", int(defaultMaxBodySize)+1),
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			test.config.Address = server.URL
+			middleware, err := NewForward(context.Background(), next, test.config, "authTest")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "http://foo.bar", strings.NewReader(test.body))
+
+			recorder := httptest.NewRecorder()
+			middleware.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestForwardAuthNegativeMaxBodySize(t *testing.T) {
+	_, err := NewForward(context.Background(), http.NotFoundHandler(), dynamic.ForwardAuth{
+		Address:     "http://auth.example.com",
+		ForwardBody: true,
+		MaxBodySize: -1,
+	}, "authTest")
+	assert.Error(t, err)
+}
+
+func TestForwardAuthCacheWithBody(t *testing.T) {
+	_, err := NewForward(context.Background(), http.NotFoundHandler(), dynamic.ForwardAuth{
+		Address:     "http://auth.example.com",
+		ForwardBody: true,
+		Cache:       &dynamic.ForwardAuthCache{},
+	}, "authTest")
+	assert.Error(t, err)
+}
+
 func TestForwardAuthUsesTracing(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Header.Get("Mockpfx-Ids-Traceid") == "" {
@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"gopkg.in/square/go-jose.v2"
+)
+
+// jwksMinRefreshDelay is the minimum delay between two fetches of a key set,
+// so that tokens signed with unknown keys do not trigger a fetch each.
+const jwksMinRefreshDelay = 10 * time.Second
+
+// keySet is a JSON Web Key Set fetched from a URL.
+// It is fetched again once its refresh interval has elapsed, or when a token is signed with a key it does not hold.
+type keySet struct {
+	url             string
+	client          *http.Client
+	refreshInterval time.Duration
+	minRefreshDelay time.Duration
+
+	mu          sync.Mutex
+	keys        *jose.JSONWebKeySet
+	fetchedAt   time.Time
+	lastAttempt time.Time
+	ongoing     *keySetFetch // nil when no fetch is ongoing
+}
+
+// keySetFetch is a fetch of a key set, shared by the requests waiting for it.
+type keySetFetch struct {
+	done chan struct{}
+	err  error
+}
+
+func newKeySet(config dynamic.JWKS) (*keySet, error) {
+	if config.URL == "" {
+		return nil, errors.New("a JWKS URL is required")
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	if config.TLS != nil {
+		tlsConfig, err := config.TLS.CreateTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		tr := http.DefaultTransport.(*http.Transport).Clone()
+		tr.TLSClientConfig = tlsConfig
+		client.Transport = tr
+	}
+
+	return &keySet{
+		url:             config.URL,
+		client:          client,
+		refreshInterval: time.Duration(config.RefreshInterval),
+		minRefreshDelay: jwksMinRefreshDelay,
+	}, nil
+}
+
+// get returns the keys of the set with the given key ID, or all its keys if the key ID is empty.
+// The fetches of the key set are done outside of the lock, and the cached keys are served while a fetch is ongoing,
+// unless they cannot verify the token.
+func (s *keySet) get(ctx context.Context, kid string) ([]jose.JSONWebKey, error) {
+	s.mu.Lock()
+
+	if s.ongoing == nil && s.needsRefresh(time.Now(), kid) {
+		s.ongoing = &keySetFetch{done: make(chan struct{})}
+
+		// The key set is fetched independently of the request, so that a canceled request does not fail the fetch.
+		go s.refresh(log.FromContext(ctx), s.ongoing)
+	}
+
+	ongoing := s.ongoing
+	wait := ongoing != nil && (s.keys == nil || kid != "" && len(s.keys.Key(kid)) == 0)
+
+	s.mu.Unlock()
+
+	var fetchErr error
+	if wait {
+		select {
+		case <-ongoing.done:
+			fetchErr = ongoing.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.keys == nil {
+		if fetchErr != nil {
+			return nil, fetchErr
+		}
+		return nil, fmt.Errorf("the JWKS from %s is not available", s.url)
+	}
+
+	if kid == "" {
+		return s.keys.Keys, nil
+	}
+	return s.keys.Key(kid), nil
+}
+
+// refresh fetches the key set, and keeps the previous keys if the fetch fails.
+func (s *keySet) refresh(logger log.Logger, ongoing *keySetFetch) {
+	keys, err := s.fetch()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.lastAttempt = now
+
+	switch {
+	case err != nil && s.keys != nil:
+		logger.Errorf("Unable to refresh the JWKS from %s, using the previous keys: %v", s.url, err)
+	case err == nil:
+		s.keys = keys
+		s.fetchedAt = now
+	}
+
+	ongoing.err = err
+	s.ongoing = nil
+	close(ongoing.done)
+}
+
+func (s *keySet) needsRefresh(now time.Time, kid string) bool {
+	if !s.lastAttempt.IsZero() && now.Sub(s.lastAttempt) < s.minRefreshDelay {
+		return false
+	}
+
+	if s.keys == nil || now.Sub(s.fetchedAt) >= s.refreshInterval {
+		return true
+	}
+
+	return kid != "" && len(s.keys.Key(kid)) == 0
+}
+
+func (s *keySet) fetch() (*jose.JSONWebKeySet, error) {
+	resp, err := s.client.Get(s.url)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d fetching the JWKS from %s", resp.StatusCode, s.url)
+	}
+
+	var keys jose.JSONWebKeySet
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&keys); err != nil {
+		return nil, fmt.Errorf("unable to decode the JWKS from %s: %w", s.url, err)
+	}
+
+	return &keys, nil
+}
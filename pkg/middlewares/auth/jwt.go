@@ -0,0 +1,322 @@
+package auth
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+const (
+	jwtTypeName = "JWT"
+)
+
+// errInsufficientClaims is returned when a valid token does not have the required claims.
+var errInsufficientClaims = errors.New("insufficient claims")
+
+type jwtAuth struct {
+	next          http.Handler
+	name          string
+	validator     *tokenValidator
+	claims        map[string]string
+	forwardClaims map[string]string
+	removeHeader  bool
+}
+
+// tokenValidator verifies the signature of the tokens, with static keys or with the keys of a key set,
+// and checks their registered claims.
+type tokenValidator struct {
+	keys      []interface{}
+	keySet    *keySet
+	issuers   []string
+	audiences []string
+	clockSkew time.Duration
+}
+
+// NewJWT creates a JWT authentication middleware.
+func NewJWT(ctx context.Context, next http.Handler, config dynamic.JWT, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, jwtTypeName)).Debug("Creating middleware")
+
+	if len(config.Keys) == 0 && config.JWKS == nil {
+		return nil, errors.New("at least one key or a JWKS is required")
+	}
+
+	validator := &tokenValidator{
+		issuers:   config.Issuers,
+		audiences: config.Audiences,
+		clockSkew: time.Duration(config.ClockSkew),
+	}
+
+	for _, key := range config.Keys {
+		content, err := key.Read()
+		if err != nil {
+			return nil, fmt.Errorf("unable to read key: %w", err)
+		}
+
+		parsed, err := parseJWTKey(content)
+		if err != nil {
+			return nil, err
+		}
+		validator.keys = append(validator.keys, parsed)
+	}
+
+	if config.JWKS != nil {
+		var err error
+		validator.keySet, err = newKeySet(*config.JWKS)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &jwtAuth{
+		next:          next,
+		name:          name,
+		validator:     validator,
+		claims:        config.Claims,
+		forwardClaims: config.ForwardClaims,
+		removeHeader:  config.RemoveHeader,
+	}, nil
+}
+
+func (j *jwtAuth) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return j.name, tracing.SpanKindNoneEnum
+}
+
+func (j *jwtAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), j.name, jwtTypeName))
+
+	token, ok := bearerToken(req)
+	if !ok {
+		logger.Debug("Authentication failed: no bearer token")
+		tracing.SetErrorWithEvent(req, "Authentication failed")
+
+		rw.Header().Set("WWW-Authenticate", fmt.Sprintf("Bearer realm=%q", defaultRealm))
+		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	claims, err := j.validate(req.Context(), token)
+	if errors.Is(err, errInsufficientClaims) {
+		logger.Debugf("Authorization failed: %v", err)
+		tracing.SetErrorWithEvent(req, "Authorization failed")
+
+		rw.Header().Set("WWW-Authenticate", fmt.Sprintf("Bearer realm=%q, error=%q", defaultRealm, "insufficient_scope"))
+		http.Error(rw, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		logger.Debugf("Authentication failed: %v", err)
+		tracing.SetErrorWithEvent(req, "Authentication failed")
+
+		rw.Header().Set("WWW-Authenticate", fmt.Sprintf("Bearer realm=%q, error=%q", defaultRealm, "invalid_token"))
+		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	logger.Debug("Authentication succeeded")
+
+	if logData := accesslog.GetLogData(req); logData != nil {
+		if sub, ok := claims["sub"].(string); ok {
+			logData.Core[accesslog.ClientUsername] = sub
+		}
+	}
+
+	for header, claim := range j.forwardClaims {
+		req.Header.Del(header)
+		if value, ok := claims[claim]; ok {
+			req.Header.Set(header, claimString(value))
+		}
+	}
+
+	if j.removeHeader {
+		logger.Debug("Removing authorization header")
+		req.Header.Del(authorizationHeader)
+	}
+
+	j.next.ServeHTTP(rw, req)
+}
+
+// validate verifies the token and checks its claims, which it returns.
+func (j *jwtAuth) validate(ctx context.Context, token string) (map[string]interface{}, error) {
+	_, claims, err := j.validator.validate(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, expected := range j.claims {
+		if !claimMatches(claims[name], expected) {
+			return nil, fmt.Errorf("%w: claim %q does not match", errInsufficientClaims, name)
+		}
+	}
+
+	return claims, nil
+}
+
+// validate verifies the signature of the token and checks its registered claims.
+// It returns the registered claims, and all the claims of the token.
+func (v *tokenValidator) validate(ctx context.Context, token string) (*jwt.Claims, map[string]interface{}, error) {
+	parsed, err := jwt.ParseSigned(token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var kid string
+	for _, header := range parsed.Headers {
+		if header.KeyID != "" {
+			kid = header.KeyID
+			break
+		}
+	}
+
+	keys := v.keys
+	if v.keySet != nil {
+		jwks, err := v.keySet.get(ctx, kid)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		keys = append(make([]interface{}, 0, len(v.keys)+len(jwks)), v.keys...)
+		for _, key := range jwks {
+			if key.Use == "" || key.Use == "sig" {
+				keys = append(keys, key.Key)
+			}
+		}
+	}
+
+	var registered jwt.Claims
+	var claims map[string]interface{}
+	if err = verifyClaims(parsed, keys, &registered, &claims); err != nil {
+		return nil, nil, err
+	}
+
+	if err = registered.ValidateWithLeeway(jwt.Expected{Time: time.Now()}, v.clockSkew); err != nil {
+		return nil, nil, err
+	}
+
+	if len(v.issuers) > 0 && !contains(v.issuers, registered.Issuer) {
+		return nil, nil, fmt.Errorf("unexpected issuer %q", registered.Issuer)
+	}
+
+	if len(v.audiences) > 0 && !containsAny(registered.Audience, v.audiences) {
+		return nil, nil, fmt.Errorf("unexpected audience %q", registered.Audience)
+	}
+
+	return &registered, claims, nil
+}
+
+// verifyClaims verifies the signature of the token with each key, until one succeeds, and decodes its claims.
+func verifyClaims(token *jwt.JSONWebToken, keys []interface{}, dest ...interface{}) error {
+	if len(keys) == 0 {
+		return errors.New("no key to verify the token signature with")
+	}
+
+	var err error
+	for _, key := range keys {
+		if err = token.Claims(key, dest...); err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
+
+// parseJWTKey parses a PEM-encoded public key or certificate.
+// A key which is not PEM-encoded is a secret for the HMAC algorithms.
+func parseJWTKey(content []byte) (interface{}, error) {
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return content, nil
+	}
+
+	switch block.Type {
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return cert.PublicKey, nil
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+}
+
+func bearerToken(req *http.Request) (string, bool) {
+	authorization := req.Header.Get(authorizationHeader)
+
+	const prefix = "Bearer "
+	if len(authorization) <= len(prefix) || !strings.EqualFold(authorization[:len(prefix)], prefix) {
+		return "", false
+	}
+
+	return strings.TrimSpace(authorization[len(prefix):]), true
+}
+
+// claimMatches tells whether the claim has the expected value, or contains it if the claim is an array.
+func claimMatches(claim interface{}, expected string) bool {
+	if values, ok := claim.([]interface{}); ok {
+		for _, value := range values {
+			if claimMatches(value, expected) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return claim != nil && claimString(claim) == expected
+}
+
+// claimString returns the claim as a header value:
+// strings as is, arrays as comma-separated lists, and other values as JSON.
+func claimString(claim interface{}) string {
+	switch value := claim.(type) {
+	case string:
+		return value
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, v := range value {
+			values = append(values, claimString(v))
+		}
+		return strings.Join(values, ",")
+	default:
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprint(value)
+		}
+		return string(encoded)
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAny(audience jwt.Audience, values []string) bool {
+	for _, value := range values {
+		if audience.Contains(value) {
+			return true
+		}
+	}
+	return false
+}
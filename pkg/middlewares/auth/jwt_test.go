@@ -0,0 +1,346 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/tls"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+func signToken(t *testing.T, alg jose.SignatureAlgorithm, key interface{}, kid string, claims map[string]interface{}) string {
+	t.Helper()
+
+	opts := &jose.SignerOptions{}
+	if kid != "" {
+		opts = opts.WithHeader(jose.HeaderKey("kid"), kid)
+	}
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: alg, Key: key}, opts)
+	require.NoError(t, err)
+
+	token, err := jwt.Signed(signer).Claims(claims).CompactSerialize()
+	require.NoError(t, err)
+
+	return token
+}
+
+func TestJWT_static(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	require.NoError(t, err)
+	publicKey := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	now := time.Now()
+
+	testCases := []struct {
+		desc            string
+		config          dynamic.JWT
+		token           string
+		expectedStatus  int
+		expectedHeaders map[string]string
+	}{
+		{
+			desc:           "no token",
+			config:         dynamic.JWT{Keys: []tls.FileOrContent{"secret"}},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc:           "malformed token",
+			config:         dynamic.JWT{Keys: []tls.FileOrContent{"secret"}},
+			token:          "foo.bar.baz",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc:   "HMAC signature",
+			config: dynamic.JWT{Keys: []tls.FileOrContent{"secret"}},
+			token: signToken(t, jose.HS256, []byte("secret"), "", map[string]interface{}{
+				"sub": "foo",
+			}),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:   "invalid HMAC signature",
+			config: dynamic.JWT{Keys: []tls.FileOrContent{"secret"}},
+			token: signToken(t, jose.HS256, []byte("other"), "", map[string]interface{}{
+				"sub": "foo",
+			}),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc:   "ECDSA signature with a second key",
+			config: dynamic.JWT{Keys: []tls.FileOrContent{"secret", tls.FileOrContent(publicKey)}},
+			token: signToken(t, jose.ES256, privateKey, "", map[string]interface{}{
+				"sub": "foo",
+			}),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:   "public key used as HMAC secret",
+			config: dynamic.JWT{Keys: []tls.FileOrContent{tls.FileOrContent(publicKey)}},
+			token: signToken(t, jose.HS256, publicKey, "", map[string]interface{}{
+				"sub": "foo",
+			}),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc:   "expired token",
+			config: dynamic.JWT{Keys: []tls.FileOrContent{"secret"}},
+			token: signToken(t, jose.HS256, []byte("secret"), "", map[string]interface{}{
+				"exp": now.Add(-time.Minute).Unix(),
+			}),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc: "expired token within the clock skew",
+			config: dynamic.JWT{
+				Keys:      []tls.FileOrContent{"secret"},
+				ClockSkew: ptypes.Duration(2 * time.Minute),
+			},
+			token: signToken(t, jose.HS256, []byte("secret"), "", map[string]interface{}{
+				"exp": now.Add(-time.Minute).Unix(),
+			}),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:   "token not valid yet",
+			config: dynamic.JWT{Keys: []tls.FileOrContent{"secret"}},
+			token: signToken(t, jose.HS256, []byte("secret"), "", map[string]interface{}{
+				"nbf": now.Add(time.Minute).Unix(),
+			}),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc: "accepted issuer and audience",
+			config: dynamic.JWT{
+				Keys:      []tls.FileOrContent{"secret"},
+				Issuers:   []string{"https://foo.com", "https://bar.com"},
+				Audiences: []string{"api"},
+			},
+			token: signToken(t, jose.HS256, []byte("secret"), "", map[string]interface{}{
+				"iss": "https://bar.com",
+				"aud": []string{"web", "api"},
+			}),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc: "unexpected issuer",
+			config: dynamic.JWT{
+				Keys:    []tls.FileOrContent{"secret"},
+				Issuers: []string{"https://foo.com"},
+			},
+			token: signToken(t, jose.HS256, []byte("secret"), "", map[string]interface{}{
+				"iss": "https://bar.com",
+			}),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc: "unexpected audience",
+			config: dynamic.JWT{
+				Keys:      []tls.FileOrContent{"secret"},
+				Audiences: []string{"api"},
+			},
+			token: signToken(t, jose.HS256, []byte("secret"), "", map[string]interface{}{
+				"aud": "web",
+			}),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			desc: "required claims",
+			config: dynamic.JWT{
+				Keys:   []tls.FileOrContent{"secret"},
+				Claims: map[string]string{"groups": "admin", "email_verified": "true"},
+			},
+			token: signToken(t, jose.HS256, []byte("secret"), "", map[string]interface{}{
+				"groups":         []string{"dev", "admin"},
+				"email_verified": true,
+			}),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc: "missing required claim",
+			config: dynamic.JWT{
+				Keys:   []tls.FileOrContent{"secret"},
+				Claims: map[string]string{"groups": "admin"},
+			},
+			token: signToken(t, jose.HS256, []byte("secret"), "", map[string]interface{}{
+				"groups": []string{"dev"},
+			}),
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc: "forwarded claims",
+			config: dynamic.JWT{
+				Keys:          []tls.FileOrContent{"secret"},
+				ForwardClaims: map[string]string{"X-User": "sub", "X-Groups": "groups", "X-Age": "age", "X-Missing": "missing"},
+				RemoveHeader:  true,
+			},
+			token: signToken(t, jose.HS256, []byte("secret"), "", map[string]interface{}{
+				"sub":    "foo",
+				"groups": []string{"dev", "admin"},
+				"age":    42,
+			}),
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"X-User":        "foo",
+				"X-Groups":      "dev,admin",
+				"X-Age":         "42",
+				"X-Missing":     "",
+				"Authorization": "",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var forwarded http.Header
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				forwarded = req.Header
+			})
+
+			handler, err := NewJWT(context.Background(), next, test.config, "jwt")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://foo.bar", nil)
+			req.Header.Set("X-Missing", "spoofed")
+			if test.token != "" {
+				req.Header.Set("Authorization", "Bearer "+test.token)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+
+			if test.expectedStatus != http.StatusOK {
+				assert.NotEmpty(t, recorder.Header().Get("WWW-Authenticate"))
+				assert.Nil(t, forwarded)
+				return
+			}
+
+			for name, value := range test.expectedHeaders {
+				assert.Equal(t, value, forwarded.Get(name), name)
+			}
+		})
+	}
+}
+
+func TestJWT_noKey(t *testing.T) {
+	_, err := NewJWT(context.Background(), http.NotFoundHandler(), dynamic.JWT{}, "jwt")
+	assert.Error(t, err)
+}
+
+func TestJWT_JWKS(t *testing.T) {
+	first, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	second, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	keys := []jose.JSONWebKey{{Key: &first.PublicKey, KeyID: "first", Algorithm: string(jose.ES256), Use: "sig"}}
+
+	var fetches int32
+	jwksServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		_ = json.NewEncoder(rw).Encode(jose.JSONWebKeySet{Keys: keys})
+	}))
+	defer jwksServer.Close()
+
+	config := dynamic.JWT{JWKS: &dynamic.JWKS{URL: jwksServer.URL}}
+	config.JWKS.SetDefaults()
+
+	handler, err := NewJWT(context.Background(), http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}), config, "jwt")
+	require.NoError(t, err)
+
+	serve := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "http://foo.bar", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+
+		return recorder.Code
+	}
+
+	claims := map[string]interface{}{"sub": "foo"}
+
+	assert.Equal(t, http.StatusOK, serve(signToken(t, jose.ES256, first, "first", claims)))
+	assert.Equal(t, http.StatusOK, serve(signToken(t, jose.ES256, first, "", claims)))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+
+	// The key set has been rotated, but it was fetched too recently to be fetched again.
+	keys = []jose.JSONWebKey{{Key: &second.PublicKey, KeyID: "second", Algorithm: string(jose.ES256), Use: "sig"}}
+
+	assert.Equal(t, http.StatusUnauthorized, serve(signToken(t, jose.ES256, second, "second", claims)))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+
+	// The unknown key ID triggers a new fetch once the minimum delay has elapsed.
+	handler.(*jwtAuth).validator.keySet.minRefreshDelay = 0
+
+	assert.Equal(t, http.StatusOK, serve(signToken(t, jose.ES256, second, "second", claims)))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&fetches))
+
+	assert.Equal(t, http.StatusUnauthorized, serve(signToken(t, jose.ES256, first, "first", claims)))
+}
+
+func TestKeySet_slowRefresh(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	keys := []jose.JSONWebKey{{Key: &key.PublicKey, KeyID: "first", Algorithm: string(jose.ES256), Use: "sig"}}
+
+	var fetches int32
+	release := make(chan struct{})
+	jwksServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&fetches, 1) > 1 {
+			<-release
+		}
+		_ = json.NewEncoder(rw).Encode(jose.JSONWebKeySet{Keys: keys})
+	}))
+	defer jwksServer.Close()
+	defer close(release)
+
+	set, err := newKeySet(dynamic.JWKS{URL: jwksServer.URL})
+	require.NoError(t, err)
+	set.minRefreshDelay = 0
+
+	found, err := set.get(context.Background(), "first")
+	require.NoError(t, err)
+	assert.Len(t, found, 1)
+
+	// The refresh interval is elapsed, but the cached keys are served while the refresh is blocked.
+	for i := 0; i < 3; i++ {
+		found, err = set.get(context.Background(), "first")
+		require.NoError(t, err)
+		assert.Len(t, found, 1)
+	}
+
+	// The requests for an unknown key wait for the ongoing refresh, until they are canceled.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = set.get(ctx, "second")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&fetches))
+}
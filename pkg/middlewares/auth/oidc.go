@@ -0,0 +1,642 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+const (
+	oidcTypeName = "OIDC"
+
+	// oidcStateMaxAge is the time the users have to authenticate with the provider.
+	oidcStateMaxAge = 10 * time.Minute
+	// oidcDefaultSessionLifetime is the lifetime of a refreshed session, when the provider tells neither the expiry of the ID token, nor of the access token.
+	oidcDefaultSessionLifetime = time.Hour
+)
+
+// oidcProviderMetadata is the configuration of an OpenID provider, as discovered from its issuer URL.
+type oidcProviderMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type oidcTokenResponse struct {
+	IDToken      string `json:"id_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+type oidcAuth struct {
+	next   http.Handler
+	name   string
+	config dynamic.OIDC
+	cookie dynamic.OIDCCookie
+	codec  *cookieCodec
+	client *http.Client
+
+	// keptClaims are the claims of the ID token stored in the session.
+	keptClaims []string
+
+	mu          sync.Mutex
+	metadata    *oidcProviderMetadata
+	validator   *tokenValidator
+	discovering *oidcDiscovery // nil when no discovery is ongoing
+}
+
+// oidcDiscovery is a discovery of the provider configuration, shared by the requests waiting for it.
+type oidcDiscovery struct {
+	done chan struct{}
+	err  error
+}
+
+// NewOIDC creates an OpenID Connect authentication middleware.
+func NewOIDC(ctx context.Context, next http.Handler, config dynamic.OIDC, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, oidcTypeName)).Debug("Creating middleware")
+
+	if config.Issuer == "" || config.ClientID == "" {
+		return nil, errors.New("an issuer and a client ID are required")
+	}
+
+	codec, err := newCookieCodec(config.SessionSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	defaults := dynamic.OIDC{}
+	defaults.SetDefaults()
+	if len(config.Scopes) == 0 {
+		config.Scopes = defaults.Scopes
+	}
+	if config.CallbackPath == "" {
+		config.CallbackPath = defaults.CallbackPath
+	}
+	if config.GroupsClaim == "" {
+		config.GroupsClaim = defaults.GroupsClaim
+	}
+
+	cookie := dynamic.OIDCCookie{}
+	cookie.SetDefaults()
+	if config.Cookie != nil {
+		if config.Cookie.Name != "" {
+			cookie.Name = config.Cookie.Name
+		}
+		if config.Cookie.Path != "" {
+			cookie.Path = config.Cookie.Path
+		}
+		cookie.Domain = config.Cookie.Domain
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	if config.TLS != nil {
+		tlsConfig, err := config.TLS.CreateTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		tr := http.DefaultTransport.(*http.Transport).Clone()
+		tr.TLSClientConfig = tlsConfig
+		client.Transport = tr
+	}
+
+	keptClaims := []string{"sub", "email", "email_verified", config.GroupsClaim}
+	for _, claim := range config.ForwardClaims {
+		keptClaims = append(keptClaims, claim)
+	}
+
+	return &oidcAuth{
+		next:       next,
+		name:       name,
+		config:     config,
+		cookie:     cookie,
+		codec:      codec,
+		client:     client,
+		keptClaims: keptClaims,
+	}, nil
+}
+
+func (o *oidcAuth) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return o.name, tracing.SpanKindNoneEnum
+}
+
+func (o *oidcAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), o.name, oidcTypeName))
+
+	switch {
+	case req.URL.Path == o.config.CallbackPath:
+		o.callback(rw, req)
+		return
+	case o.config.LogoutPath != "" && req.URL.Path == o.config.LogoutPath:
+		logger.Debug("Ending session")
+		o.setCookie(rw, req, o.cookie.Name, "", -1)
+		http.Redirect(rw, req, "/", http.StatusFound)
+		return
+	}
+
+	session := o.loadSession(rw, req)
+	if session == nil {
+		o.authenticate(rw, req)
+		return
+	}
+
+	if !o.allowed(session.Claims) {
+		logger.Debug("Authorization failed")
+		tracing.SetErrorWithEvent(req, "Authorization failed")
+
+		http.Error(rw, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	logger.Debug("Authentication succeeded")
+
+	if logData := accesslog.GetLogData(req); logData != nil {
+		if sub, ok := session.Claims["sub"].(string); ok {
+			logData.Core[accesslog.ClientUsername] = sub
+		}
+	}
+
+	for header, claim := range o.config.ForwardClaims {
+		req.Header.Del(header)
+		if value, ok := session.Claims[claim]; ok {
+			req.Header.Set(header, claimString(value))
+		}
+	}
+
+	removeCookie(req, o.cookie.Name)
+
+	o.next.ServeHTTP(rw, req)
+}
+
+// authenticate redirects the user to the authorization endpoint of the provider.
+func (o *oidcAuth) authenticate(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), o.name, oidcTypeName))
+
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		logger.Debug("Authentication failed: no session")
+		tracing.SetErrorWithEvent(req, "Authentication failed")
+
+		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	metadata, _, err := o.discover(req.Context())
+	if err != nil {
+		logger.Errorf("Unable to discover the OpenID provider configuration: %v", err)
+		tracing.SetErrorWithEvent(req, "Unable to discover the OpenID provider configuration")
+
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	state := oidcState{
+		RedirectPath: req.URL.RequestURI(),
+		Expiry:       time.Now().Add(oidcStateMaxAge),
+	}
+	for _, value := range []*string{&state.State, &state.Nonce, &state.CodeVerifier} {
+		if *value, err = randomString(); err != nil {
+			logger.Errorf("Unable to generate the authentication state: %v", err)
+			http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	authURL, err := url.Parse(metadata.AuthorizationEndpoint)
+	if err != nil {
+		logger.Errorf("Invalid authorization endpoint: %v", err)
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	challenge := sha256.Sum256([]byte(state.CodeVerifier))
+
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", o.config.ClientID)
+	query.Set("redirect_uri", o.redirectURI(req))
+	query.Set("scope", strings.Join(o.config.Scopes, " "))
+	query.Set("state", state.State)
+	query.Set("nonce", state.Nonce)
+	query.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+
+	encoded, err := o.codec.encode(o.stateCookieName(), state)
+	if err != nil {
+		logger.Errorf("Unable to encode the authentication state: %v", err)
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	logger.Debug("Redirecting to the OpenID provider")
+	o.setCookie(rw, req, o.stateCookieName(), encoded, int(oidcStateMaxAge.Seconds()))
+	http.Redirect(rw, req, authURL.String(), http.StatusFound)
+}
+
+// callback handles the user sent back by the provider, and starts their session.
+func (o *oidcAuth) callback(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), o.name, oidcTypeName))
+
+	fail := func(status int, format string, args ...interface{}) {
+		logger.Debugf("Authentication failed: "+format, args...)
+		tracing.SetErrorWithEvent(req, "Authentication failed")
+
+		http.Error(rw, http.StatusText(status), status)
+	}
+
+	var state oidcState
+	cookie, err := req.Cookie(o.stateCookieName())
+	if err != nil {
+		fail(http.StatusBadRequest, "no authentication in progress")
+		return
+	}
+
+	if err = o.codec.decode(o.stateCookieName(), cookie.Value, &state); err != nil {
+		fail(http.StatusBadRequest, "invalid state cookie: %v", err)
+		return
+	}
+
+	o.setCookie(rw, req, o.stateCookieName(), "", -1)
+
+	query := req.URL.Query()
+	if query.Get("state") != state.State || time.Now().After(state.Expiry) {
+		fail(http.StatusBadRequest, "invalid or expired state")
+		return
+	}
+
+	if errCode := query.Get("error"); errCode != "" {
+		fail(http.StatusUnauthorized, "the provider returned the error %q: %s", errCode, query.Get("error_description"))
+		return
+	}
+
+	metadata, validator, err := o.discover(req.Context())
+	if err != nil {
+		logger.Errorf("Unable to discover the OpenID provider configuration: %v", err)
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	tokens, err := o.exchange(metadata, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {query.Get("code")},
+		"redirect_uri":  {o.redirectURI(req)},
+		"code_verifier": {state.CodeVerifier},
+	})
+	if err != nil {
+		fail(http.StatusUnauthorized, "unable to exchange the authorization code: %v", err)
+		return
+	}
+
+	registered, claims, err := validator.validate(req.Context(), tokens.IDToken)
+	if err != nil {
+		fail(http.StatusUnauthorized, "invalid ID token: %v", err)
+		return
+	}
+
+	if nonce, _ := claims["nonce"].(string); nonce != state.Nonce {
+		fail(http.StatusUnauthorized, "invalid ID token nonce")
+		return
+	}
+
+	session := &oidcSession{
+		Claims:       o.keepClaims(claims),
+		RefreshToken: tokens.RefreshToken,
+		Expiry:       sessionExpiry(registered.Expiry, tokens.ExpiresIn),
+	}
+
+	if err = o.setSession(rw, req, session); err != nil {
+		logger.Errorf("Unable to encode the session: %v", err)
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	redirectPath := state.RedirectPath
+	if !strings.HasPrefix(redirectPath, "/") || strings.HasPrefix(redirectPath, "//") {
+		redirectPath = "/"
+	}
+
+	logger.Debug("Authentication succeeded, starting session")
+	http.Redirect(rw, req, redirectPath, http.StatusFound)
+}
+
+// loadSession returns the session of the request, refreshing it if it is expired.
+// It returns nil when the request does not have a valid session.
+func (o *oidcAuth) loadSession(rw http.ResponseWriter, req *http.Request) *oidcSession {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), o.name, oidcTypeName))
+
+	cookie, err := req.Cookie(o.cookie.Name)
+	if err != nil {
+		return nil
+	}
+
+	var session oidcSession
+	if err = o.codec.decode(o.cookie.Name, cookie.Value, &session); err != nil {
+		logger.Debugf("Invalid session cookie: %v", err)
+		return nil
+	}
+
+	if time.Now().Before(session.Expiry) {
+		return &session
+	}
+
+	if session.RefreshToken == "" {
+		return nil
+	}
+
+	metadata, validator, err := o.discover(req.Context())
+	if err != nil {
+		logger.Errorf("Unable to discover the OpenID provider configuration: %v", err)
+		return nil
+	}
+
+	tokens, err := o.exchange(metadata, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {session.RefreshToken},
+	})
+	if err != nil {
+		logger.Debugf("Unable to refresh the session: %v", err)
+		return nil
+	}
+
+	refreshed := &oidcSession{
+		Claims:       session.Claims,
+		RefreshToken: session.RefreshToken,
+		Expiry:       sessionExpiry(nil, tokens.ExpiresIn),
+	}
+
+	if tokens.RefreshToken != "" {
+		refreshed.RefreshToken = tokens.RefreshToken
+	}
+
+	if tokens.IDToken != "" {
+		registered, claims, err := validator.validate(req.Context(), tokens.IDToken)
+		if err != nil {
+			logger.Debugf("Invalid refreshed ID token: %v", err)
+			return nil
+		}
+
+		refreshed.Claims = o.keepClaims(claims)
+		refreshed.Expiry = sessionExpiry(registered.Expiry, tokens.ExpiresIn)
+	}
+
+	if err = o.setSession(rw, req, refreshed); err != nil {
+		logger.Errorf("Unable to encode the session: %v", err)
+		return nil
+	}
+
+	logger.Debug("Session refreshed")
+
+	return refreshed
+}
+
+// discover returns the configuration of the provider, discovered from its issuer URL on the first successful call.
+// The requests arriving during a discovery wait for it, rather than holding the lock or discovering again.
+func (o *oidcAuth) discover(ctx context.Context) (*oidcProviderMetadata, *tokenValidator, error) {
+	o.mu.Lock()
+
+	if o.metadata != nil {
+		defer o.mu.Unlock()
+		return o.metadata, o.validator, nil
+	}
+
+	discovering := o.discovering
+	if discovering == nil {
+		discovering = &oidcDiscovery{done: make(chan struct{})}
+		o.discovering = discovering
+
+		// The configuration is discovered independently of the request, so that a canceled request does not fail the discovery.
+		go o.runDiscovery(discovering)
+	}
+
+	o.mu.Unlock()
+
+	select {
+	case <-discovering.done:
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+
+	if discovering.err != nil {
+		return nil, nil, discovering.err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.metadata, o.validator, nil
+}
+
+func (o *oidcAuth) runDiscovery(discovering *oidcDiscovery) {
+	metadata, validator, err := o.fetchDiscovery()
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if err == nil {
+		o.metadata = metadata
+		o.validator = validator
+	}
+
+	discovering.err = err
+	o.discovering = nil
+	close(discovering.done)
+}
+
+func (o *oidcAuth) fetchDiscovery() (*oidcProviderMetadata, *tokenValidator, error) {
+	issuer := strings.TrimSuffix(o.config.Issuer, "/")
+
+	resp, err := o.client.Get(issuer + "/.well-known/openid-configuration")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	var metadata oidcProviderMetadata
+	if err = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&metadata); err != nil {
+		return nil, nil, err
+	}
+
+	if strings.TrimSuffix(metadata.Issuer, "/") != issuer {
+		return nil, nil, fmt.Errorf("the discovered issuer %q does not match %q", metadata.Issuer, o.config.Issuer)
+	}
+
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, nil, errors.New("the authorization, token, and JWKS endpoints are required")
+	}
+
+	jwks := dynamic.JWKS{URL: metadata.JWKSURI, TLS: o.config.TLS}
+	jwks.SetDefaults()
+
+	keys, err := newKeySet(jwks)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	validator := &tokenValidator{
+		keySet:    keys,
+		issuers:   []string{metadata.Issuer},
+		audiences: []string{o.config.ClientID},
+	}
+
+	return &metadata, validator, nil
+}
+
+// exchange requests tokens from the token endpoint of the provider.
+func (o *oidcAuth) exchange(metadata *oidcProviderMetadata, form url.Values) (*oidcTokenResponse, error) {
+	req, err := http.NewRequest(http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(o.config.ClientID), url.QueryEscape(o.config.ClientSecret))
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, body)
+	}
+
+	var tokens oidcTokenResponse
+	if err = json.Unmarshal(body, &tokens); err != nil {
+		return nil, err
+	}
+
+	return &tokens, nil
+}
+
+// allowed tells whether the user with the given claims belongs to one of the allowed groups, or has one of the allowed emails.
+func (o *oidcAuth) allowed(claims map[string]interface{}) bool {
+	if len(o.config.AllowedGroups) == 0 && len(o.config.AllowedEmails) == 0 {
+		return true
+	}
+
+	for _, group := range o.config.AllowedGroups {
+		if claimMatches(claims[o.config.GroupsClaim], group) {
+			return true
+		}
+	}
+
+	email, _ := claims["email"].(string)
+	if email == "" || claims["email_verified"] == false {
+		return false
+	}
+
+	for _, allowed := range o.config.AllowedEmails {
+		if strings.HasPrefix(allowed, "@") && strings.HasSuffix(strings.ToLower(email), strings.ToLower(allowed)) {
+			return true
+		}
+		if strings.EqualFold(email, allowed) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// keepClaims returns the claims of the ID token stored in the session, which has to fit in a cookie.
+func (o *oidcAuth) keepClaims(claims map[string]interface{}) map[string]interface{} {
+	kept := make(map[string]interface{})
+	for _, name := range o.keptClaims {
+		if value, ok := claims[name]; ok {
+			kept[name] = value
+		}
+	}
+	return kept
+}
+
+func (o *oidcAuth) setSession(rw http.ResponseWriter, req *http.Request, session *oidcSession) error {
+	encoded, err := o.codec.encode(o.cookie.Name, session)
+	if err != nil {
+		return err
+	}
+
+	o.setCookie(rw, req, o.cookie.Name, encoded, 0)
+	return nil
+}
+
+func (o *oidcAuth) setCookie(rw http.ResponseWriter, req *http.Request, name, value string, maxAge int) {
+	http.SetCookie(rw, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     o.cookie.Path,
+		Domain:   o.cookie.Domain,
+		MaxAge:   maxAge,
+		Secure:   requestScheme(req) == "https",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func (o *oidcAuth) stateCookieName() string {
+	return o.cookie.Name + "_state"
+}
+
+func (o *oidcAuth) redirectURI(req *http.Request) string {
+	return requestScheme(req) + "://" + req.Host + o.config.CallbackPath
+}
+
+func requestScheme(req *http.Request) string {
+	if proto := req.Header.Get("X-Forwarded-Proto"); proto != "" {
+		return proto
+	}
+	if req.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// sessionExpiry returns the expiry of the ID token, or of the access token if the former is unknown.
+func sessionExpiry(idTokenExpiry *jwt.NumericDate, expiresIn int64) time.Time {
+	if idTokenExpiry != nil {
+		return idTokenExpiry.Time()
+	}
+	if expiresIn > 0 {
+		return time.Now().Add(time.Duration(expiresIn) * time.Second)
+	}
+	return time.Now().Add(oidcDefaultSessionLifetime)
+}
+
+// removeCookie removes the cookie with the given name from the request, so that it is not forwarded to the service.
+func removeCookie(req *http.Request, name string) {
+	cookies := req.Cookies()
+
+	req.Header.Del("Cookie")
+	for _, cookie := range cookies {
+		if cookie.Name != name {
+			req.AddCookie(cookie)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// oidcSession is the session of an authenticated user, stored in the session cookie.
+type oidcSession struct {
+	Claims       map[string]interface{} `json:"claims"`
+	RefreshToken string                 `json:"refreshToken,omitempty"`
+	Expiry       time.Time              `json:"expiry"`
+}
+
+// oidcState is the state of an authentication in progress, stored in the state cookie until the provider sends the user back.
+type oidcState struct {
+	State        string    `json:"state"`
+	Nonce        string    `json:"nonce"`
+	CodeVerifier string    `json:"codeVerifier"`
+	RedirectPath string    `json:"redirectPath"`
+	Expiry       time.Time `json:"expiry"`
+}
+
+// cookieCodec encrypts and authenticates the values stored in cookies, with AES-GCM.
+type cookieCodec struct {
+	aead cipher.AEAD
+}
+
+func newCookieCodec(secret string) (*cookieCodec, error) {
+	if secret == "" {
+		return nil, errors.New("a session secret is required")
+	}
+
+	key := sha256.Sum256([]byte(secret))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cookieCodec{aead: aead}, nil
+}
+
+// encode encrypts the value for the cookie with the given name.
+// The value is bound to the name, so that it cannot be used as the value of another cookie.
+func (c *cookieCodec) encode(name string, value interface{}) (string, error) {
+	plaintext, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(c.aead.Seal(nonce, nonce, plaintext, []byte(name))), nil
+}
+
+// decode decrypts the value of the cookie with the given name.
+func (c *cookieCodec) decode(name, encoded string, value interface{}) error {
+	sealed, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return err
+	}
+
+	if len(sealed) < c.aead.NonceSize() {
+		return errors.New("invalid cookie value")
+	}
+
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, []byte(name))
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(plaintext, value)
+}
+
+// randomString returns a random URL-safe string, for the state, nonce, and PKCE code verifier.
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,340 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"gopkg.in/square/go-jose.v2"
+)
+
+// mockProvider is an OpenID provider, issuing tokens for the authorization code "code" and the refresh token "refresh".
+type mockProvider struct {
+	*httptest.Server
+
+	t      *testing.T
+	key    *ecdsa.PrivateKey
+	claims map[string]interface{}
+
+	mu        sync.Mutex
+	nonce     string
+	challenge string
+}
+
+func newMockProvider(t *testing.T, claims map[string]interface{}) *mockProvider {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	provider := &mockProvider{t: t, key: key, claims: claims}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(rw http.ResponseWriter, req *http.Request) {
+		_ = json.NewEncoder(rw).Encode(oidcProviderMetadata{
+			Issuer:                provider.URL,
+			AuthorizationEndpoint: provider.URL + "/authorize",
+			TokenEndpoint:         provider.URL + "/token",
+			JWKSURI:               provider.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(rw http.ResponseWriter, req *http.Request) {
+		_ = json.NewEncoder(rw).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{Key: &key.PublicKey, KeyID: "key", Use: "sig"}}})
+	})
+	mux.HandleFunc("/token", provider.token)
+
+	provider.Server = httptest.NewServer(mux)
+	t.Cleanup(provider.Close)
+
+	return provider
+}
+
+func (p *mockProvider) token(rw http.ResponseWriter, req *http.Request) {
+	clientID, clientSecret, ok := req.BasicAuth()
+	if !ok || clientID != "client" || clientSecret != "secret" {
+		http.Error(rw, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+		return
+	}
+
+	claims := map[string]interface{}{
+		"iss": p.URL,
+		"aud": "client",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	for name, value := range p.claims {
+		claims[name] = value
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch req.FormValue("grant_type") {
+	case "authorization_code":
+		verifier := sha256.Sum256([]byte(req.FormValue("code_verifier")))
+		if req.FormValue("code") != "code" || base64.RawURLEncoding.EncodeToString(verifier[:]) != p.challenge {
+			http.Error(rw, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		claims["nonce"] = p.nonce
+
+	case "refresh_token":
+		if req.FormValue("refresh_token") != "refresh" {
+			http.Error(rw, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		claims["email"] = "refreshed@example.com"
+
+	default:
+		http.Error(rw, `{"error":"unsupported_grant_type"}`, http.StatusBadRequest)
+		return
+	}
+
+	_ = json.NewEncoder(rw).Encode(oidcTokenResponse{
+		IDToken:      signToken(p.t, jose.ES256, p.key, "key", claims),
+		RefreshToken: "refresh",
+		ExpiresIn:    300,
+	})
+}
+
+// authorize simulates the authentication of the user, following the given redirection to the authorization endpoint.
+// It returns the URL of the callback.
+func (p *mockProvider) authorize(t *testing.T, location string) string {
+	t.Helper()
+
+	authURL, err := url.Parse(location)
+	require.NoError(t, err)
+	assert.Equal(t, p.URL+"/authorize", authURL.Scheme+"://"+authURL.Host+authURL.Path)
+
+	query := authURL.Query()
+	assert.Equal(t, "code", query.Get("response_type"))
+	assert.Equal(t, "client", query.Get("client_id"))
+	assert.Equal(t, "openid profile email", query.Get("scope"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+
+	p.mu.Lock()
+	p.nonce = query.Get("nonce")
+	p.challenge = query.Get("code_challenge")
+	p.mu.Unlock()
+
+	return query.Get("redirect_uri") + "?" + url.Values{"code": {"code"}, "state": {query.Get("state")}}.Encode()
+}
+
+func newOIDCConfig(issuer string) dynamic.OIDC {
+	config := dynamic.OIDC{
+		Issuer:        issuer,
+		ClientID:      "client",
+		ClientSecret:  "secret",
+		SessionSecret: "session secret",
+		LogoutPath:    "/logout",
+		ForwardClaims: map[string]string{"X-Email": "email"},
+	}
+	config.SetDefaults()
+	return config
+}
+
+func serveOIDC(handler http.Handler, method, target string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	return recorder
+}
+
+func getCookie(t *testing.T, recorder *httptest.ResponseRecorder, name string) *http.Cookie {
+	t.Helper()
+
+	for _, cookie := range recorder.Result().Cookies() {
+		if cookie.Name == name {
+			return cookie
+		}
+	}
+
+	require.Failf(t, "cookie not found", "%s", name)
+	return nil
+}
+
+func TestOIDC_flow(t *testing.T) {
+	provider := newMockProvider(t, map[string]interface{}{"sub": "foo", "email": "foo@example.com", "groups": []string{"dev"}})
+
+	var forwarded *http.Request
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		forwarded = req
+	})
+
+	handler, err := NewOIDC(context.Background(), next, newOIDCConfig(provider.URL), "oidc")
+	require.NoError(t, err)
+
+	// An unauthenticated browser is redirected to the provider.
+	recorder := serveOIDC(handler, http.MethodGet, "http://app.example.com/dashboard?tab=1")
+	require.Equal(t, http.StatusFound, recorder.Code)
+	stateCookie := getCookie(t, recorder, "traefik_oidc_state")
+
+	callback := provider.authorize(t, recorder.Header().Get("Location"))
+	assert.Contains(t, callback, "http://app.example.com/oidc/callback?")
+
+	// The callback starts the session, and redirects to the original URL.
+	recorder = serveOIDC(handler, http.MethodGet, callback, stateCookie)
+	require.Equal(t, http.StatusFound, recorder.Code, recorder.Body.String())
+	assert.Equal(t, "/dashboard?tab=1", recorder.Header().Get("Location"))
+	assert.Equal(t, -1, getCookie(t, recorder, "traefik_oidc_state").MaxAge)
+	sessionCookie := getCookie(t, recorder, "traefik_oidc")
+	assert.True(t, sessionCookie.HttpOnly)
+
+	// The session grants access to the service, without forwarding the session cookie.
+	recorder = serveOIDC(handler, http.MethodGet, "http://app.example.com/dashboard", sessionCookie, &http.Cookie{Name: "other", Value: "value"})
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.NotNil(t, forwarded)
+	assert.Equal(t, "foo@example.com", forwarded.Header.Get("X-Email"))
+	assert.Equal(t, "other=value", forwarded.Header.Get("Cookie"))
+
+	// The callback cannot be replayed.
+	recorder = serveOIDC(handler, http.MethodGet, callback)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	// The logout ends the session.
+	recorder = serveOIDC(handler, http.MethodGet, "http://app.example.com/logout", sessionCookie)
+	assert.Equal(t, http.StatusFound, recorder.Code)
+	assert.Equal(t, -1, getCookie(t, recorder, "traefik_oidc").MaxAge)
+}
+
+func TestOIDC_unauthenticated(t *testing.T) {
+	provider := newMockProvider(t, map[string]interface{}{"sub": "foo"})
+
+	handler, err := NewOIDC(context.Background(), http.NotFoundHandler(), newOIDCConfig(provider.URL), "oidc")
+	require.NoError(t, err)
+
+	recorder := serveOIDC(handler, http.MethodPost, "http://app.example.com/api")
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	recorder = serveOIDC(handler, http.MethodGet, "http://app.example.com/api", &http.Cookie{Name: "traefik_oidc", Value: "forged"})
+	assert.Equal(t, http.StatusFound, recorder.Code)
+
+	// The state of the callback must match the state cookie.
+	stateCookie := getCookie(t, recorder, "traefik_oidc_state")
+	provider.authorize(t, recorder.Header().Get("Location"))
+
+	recorder = serveOIDC(handler, http.MethodGet, "http://app.example.com/oidc/callback?code=code&state=forged", stateCookie)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestOIDC_refresh(t *testing.T) {
+	provider := newMockProvider(t, map[string]interface{}{"sub": "foo", "email": "foo@example.com"})
+
+	var forwarded *http.Request
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		forwarded = req
+	})
+
+	handler, err := NewOIDC(context.Background(), next, newOIDCConfig(provider.URL), "oidc")
+	require.NoError(t, err)
+
+	oa := handler.(*oidcAuth)
+
+	expired, err := oa.codec.encode("traefik_oidc", oidcSession{
+		Claims:       map[string]interface{}{"sub": "foo", "email": "foo@example.com"},
+		RefreshToken: "refresh",
+		Expiry:       time.Now().Add(-time.Minute),
+	})
+	require.NoError(t, err)
+
+	recorder := serveOIDC(handler, http.MethodGet, "http://app.example.com/", &http.Cookie{Name: "traefik_oidc", Value: expired})
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "refreshed@example.com", forwarded.Header.Get("X-Email"))
+
+	var session oidcSession
+	require.NoError(t, oa.codec.decode("traefik_oidc", getCookie(t, recorder, "traefik_oidc").Value, &session))
+	assert.True(t, session.Expiry.After(time.Now()))
+
+	// An expired session which cannot be refreshed requires a new authentication.
+	revoked, err := oa.codec.encode("traefik_oidc", oidcSession{
+		Claims:       map[string]interface{}{"sub": "foo"},
+		RefreshToken: "revoked",
+		Expiry:       time.Now().Add(-time.Minute),
+	})
+	require.NoError(t, err)
+
+	recorder = serveOIDC(handler, http.MethodGet, "http://app.example.com/", &http.Cookie{Name: "traefik_oidc", Value: revoked})
+	assert.Equal(t, http.StatusFound, recorder.Code)
+}
+
+func TestOIDC_allowed(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		allowedGroups []string
+		allowedEmails []string
+		claims        map[string]interface{}
+		expected      bool
+	}{
+		{
+			desc:     "no restriction",
+			claims:   map[string]interface{}{"sub": "foo"},
+			expected: true,
+		},
+		{
+			desc:          "allowed group",
+			allowedGroups: []string{"admin"},
+			claims:        map[string]interface{}{"groups": []interface{}{"dev", "admin"}},
+			expected:      true,
+		},
+		{
+			desc:          "other groups",
+			allowedGroups: []string{"admin"},
+			claims:        map[string]interface{}{"groups": []interface{}{"dev"}},
+		},
+		{
+			desc:          "allowed email",
+			allowedEmails: []string{"Foo@example.com"},
+			claims:        map[string]interface{}{"email": "foo@example.com"},
+			expected:      true,
+		},
+		{
+			desc:          "allowed domain",
+			allowedEmails: []string{"@example.com"},
+			claims:        map[string]interface{}{"email": "foo@example.com", "email_verified": true},
+			expected:      true,
+		},
+		{
+			desc:          "unverified email",
+			allowedEmails: []string{"@example.com"},
+			claims:        map[string]interface{}{"email": "foo@example.com", "email_verified": false},
+		},
+		{
+			desc:          "other domain",
+			allowedEmails: []string{"@example.com"},
+			claims:        map[string]interface{}{"email": "foo@notexample.com.evil.org"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			config := newOIDCConfig("https://issuer.example.com")
+			config.AllowedGroups = test.allowedGroups
+			config.AllowedEmails = test.allowedEmails
+
+			handler, err := NewOIDC(context.Background(), http.NotFoundHandler(), config, "oidc")
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, handler.(*oidcAuth).allowed(test.claims))
+		})
+	}
+}
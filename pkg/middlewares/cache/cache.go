@@ -0,0 +1,556 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	typeName = "Cache"
+)
+
+const (
+	defaultMaxSize      = 64 * 1024 * 1024
+	defaultMaxEntrySize = 1024 * 1024
+	defaultDiskMaxSize  = 1024 * 1024 * 1024
+)
+
+// Statuses of the requests handled by the cache, reported in the access logs and the metrics.
+const (
+	statusHit         = "hit"
+	statusMiss        = "miss"
+	statusStale       = "stale"
+	statusRevalidated = "revalidated"
+	statusBypass      = "bypass"
+)
+
+// cache is a middleware caching the responses of the next handler, following the RFC 7234 semantics of a shared cache.
+type cache struct {
+	name         string
+	next         http.Handler
+	store        *Store
+	maxEntrySize int64
+	defaultTTL   time.Duration
+	keyHeaders   []string
+	keyCookies   []string
+	requests     gokitmetrics.Counter
+
+	now func() time.Time
+
+	revalidatingMu sync.Mutex
+	revalidating   map[string]struct{}
+}
+
+// New creates a cache middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.Cache, stores *Stores, name string) (http.Handler, error) {
+	log.FromContext(log.With(ctx, log.Str(log.MiddlewareName, name), log.Str(log.MiddlewareType, typeName))).Debug("Creating middleware")
+
+	if stores == nil {
+		return nil, errors.New("cache stores are not available")
+	}
+
+	if config.MaxSize <= 0 {
+		config.MaxSize = defaultMaxSize
+	}
+	if config.MaxEntrySize <= 0 {
+		config.MaxEntrySize = defaultMaxEntrySize
+	}
+	if config.Disk != nil && config.Disk.MaxSize <= 0 {
+		config.Disk.MaxSize = defaultDiskMaxSize
+	}
+
+	store, err := stores.get(name, config)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &cache{
+		name:         name,
+		next:         next,
+		store:        store,
+		maxEntrySize: config.MaxEntrySize,
+		defaultTTL:   time.Duration(config.DefaultTTL),
+		now:          time.Now,
+		revalidating: make(map[string]struct{}),
+	}
+
+	if config.Key != nil {
+		c.keyHeaders = config.Key.Headers
+		c.keyCookies = config.Key.Cookies
+	}
+
+	if stores.metricsRegistry != nil {
+		c.requests = stores.metricsRegistry.MiddlewareCacheRequestsCounter()
+	}
+
+	return c, nil
+}
+
+func (c *cache) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return c.name, tracing.SpanKindNoneEnum
+}
+
+func (c *cache) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		c.serveUnsafe(rw, req)
+		return
+	}
+
+	reqCC := requestCacheControl(req)
+
+	if reqCC.has("no-store") || req.Header.Get("Range") != "" || req.Header.Get("Upgrade") != "" ||
+		req.Header.Get("If-Match") != "" || req.Header.Get("If-Unmodified-Since") != "" {
+		c.report(req, statusBypass)
+		c.next.ServeHTTP(rw, req)
+		return
+	}
+
+	key, reqURL := c.key(req)
+	now := c.now()
+
+	stored := c.lookup(key, req)
+	if stored == nil {
+		if reqCC.has("only-if-cached") {
+			c.report(req, statusMiss)
+			rw.WriteHeader(http.StatusGatewayTimeout)
+			return
+		}
+
+		c.report(req, c.fetch(rw, req, reqCC, key, reqURL, nil))
+		return
+	}
+
+	age := stored.age(now)
+	lifetime := freshnessLifetime(stored.StatusCode, stored.Header, c.defaultTTL)
+	if maxAge, ok := reqCC.duration("max-age"); ok && maxAge < lifetime {
+		lifetime = maxAge
+	}
+	if minFresh, ok := reqCC.duration("min-fresh"); ok {
+		lifetime -= minFresh
+	}
+
+	if !reqCC.has("no-cache") && age < lifetime {
+		c.report(req, statusHit)
+		c.serveEntry(rw, req, stored, now)
+		return
+	}
+
+	staleness := age - lifetime
+	respCC := parseCacheControl(stored.Header.Values("Cache-Control"))
+	canServeStale := !reqCC.has("no-cache") && !mustRevalidate(stored.Header)
+
+	if canServeStale && acceptsStale(reqCC, staleness) {
+		c.report(req, statusStale)
+		c.serveEntry(rw, req, stored, now)
+		return
+	}
+
+	if reqCC.has("only-if-cached") {
+		c.report(req, statusMiss)
+		rw.WriteHeader(http.StatusGatewayTimeout)
+		return
+	}
+
+	if swr, ok := respCC.duration("stale-while-revalidate"); canServeStale && ok && staleness <= swr {
+		c.report(req, statusStale)
+		c.serveEntry(rw, req, stored, now)
+		c.revalidateInBackground(req, reqCC, key, reqURL, stored)
+		return
+	}
+
+	c.report(req, c.fetch(rw, req, reqCC, key, reqURL, stored))
+}
+
+// serveUnsafe forwards a request with an unsafe method, and invalidates the responses stored for its URL (RFC 7234 section 4.4).
+func (c *cache) serveUnsafe(rw http.ResponseWriter, req *http.Request) {
+	c.report(req, statusBypass)
+
+	recorder := &statusRecorder{ResponseWriter: rw, statusCode: http.StatusOK}
+	c.next.ServeHTTP(recorder, req)
+
+	if recorder.statusCode >= http.StatusBadRequest {
+		return
+	}
+
+	_, reqURL := c.key(req)
+	c.store.Purge(reqURL, "")
+
+	for _, name := range []string{"Location", "Content-Location"} {
+		location, err := url.Parse(recorder.Header().Get(name))
+		if err != nil || location.String() == "" {
+			continue
+		}
+
+		base, err := url.Parse(reqURL)
+		if err != nil {
+			continue
+		}
+
+		// Only the URLs of the same host are invalidated, to prevent denial of service attacks.
+		if resolved := base.ResolveReference(location); resolved.Host == base.Host {
+			c.store.Purge(resolved.Scheme+"://"+resolved.Host+resolved.RequestURI(), "")
+		}
+	}
+}
+
+// fetch forwards the request, to revalidate the stored entry if any, and stores the response if possible.
+// It returns the cache status of the request.
+func (c *cache) fetch(rw http.ResponseWriter, req *http.Request, reqCC cacheControl, key, reqURL string, stored *entry) string {
+	outReq := req.Clone(req.Context())
+
+	// The conditional headers of the client are evaluated against the cached response, not forwarded.
+	outReq.Header.Del("If-None-Match")
+	outReq.Header.Del("If-Modified-Since")
+	if stored != nil {
+		if etag := stored.Header.Get("ETag"); etag != "" {
+			outReq.Header.Set("If-None-Match", etag)
+		}
+		if lastModified := stored.Header.Get("Last-Modified"); lastModified != "" {
+			outReq.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	requestTime := c.now()
+
+	recorder := &responseRecorder{
+		rw:          rw,
+		header:      http.Header{},
+		maxBodySize: c.maxEntrySize,
+	}
+	recorder.decide = func(statusCode int, header http.Header) bool {
+		switch {
+		case stored != nil && statusCode == http.StatusNotModified:
+			return false
+		case stored != nil && statusCode >= http.StatusInternalServerError && c.canServeStaleOnError(stored, reqCC):
+			return false
+		case statusCode == http.StatusOK && notModified(req, header):
+			return false
+		default:
+			return true
+		}
+	}
+
+	c.next.ServeHTTP(recorder, outReq)
+	recorder.finish()
+
+	responseTime := c.now()
+
+	switch {
+	case stored != nil && recorder.statusCode == http.StatusNotModified:
+		revalidated := stored.revalidated(req, recorder.header, requestTime, responseTime)
+		c.store.set(key, reqURL, req, revalidated)
+		c.serveEntry(rw, req, revalidated, responseTime)
+
+		return statusRevalidated
+
+	case !recorder.passThrough && recorder.statusCode >= http.StatusInternalServerError:
+		c.serveEntry(rw, req, stored, responseTime)
+
+		return statusStale
+
+	default:
+		if !recorder.tooLarge && storable(req, reqCC, recorder.statusCode, recorder.header, c.defaultTTL) {
+			c.store.set(key, reqURL, req, newEntry(req, recorder.statusCode, recorder.header, recorder.body.Bytes(), requestTime, responseTime))
+		}
+
+		if !recorder.passThrough {
+			// The response satisfies the conditional headers of the client.
+			writeNotModified(rw, recorder.header, 0)
+		}
+
+		return statusMiss
+	}
+}
+
+// revalidateInBackground revalidates the stored entry without holding the client,
+// at most once at a time for a given key.
+func (c *cache) revalidateInBackground(req *http.Request, reqCC cacheControl, key, reqURL string, stored *entry) {
+	c.revalidatingMu.Lock()
+	if _, ok := c.revalidating[key]; ok {
+		c.revalidatingMu.Unlock()
+		return
+	}
+	c.revalidating[key] = struct{}{}
+	c.revalidatingMu.Unlock()
+
+	outReq := req.Clone(detachedContext{Context: req.Context()})
+	outReq.Method = http.MethodGet
+	outReq.Header.Del("If-None-Match")
+	outReq.Header.Del("If-Modified-Since")
+
+	safe.Go(func() {
+		defer func() {
+			c.revalidatingMu.Lock()
+			delete(c.revalidating, key)
+			c.revalidatingMu.Unlock()
+		}()
+
+		c.fetch(&discardResponseWriter{header: http.Header{}}, outReq, reqCC, key, reqURL, stored)
+	})
+}
+
+// acceptsStale tells whether the client accepts a response which is stale for the given duration, with the max-stale directive.
+func acceptsStale(reqCC cacheControl, staleness time.Duration) bool {
+	if !reqCC.has("max-stale") {
+		return false
+	}
+
+	// Without value, max-stale accepts a response of any staleness.
+	if reqCC["max-stale"] == "" {
+		return true
+	}
+
+	maxStale, ok := reqCC.duration("max-stale")
+	return ok && staleness <= maxStale
+}
+
+func (c *cache) canServeStaleOnError(stored *entry, reqCC cacheControl) bool {
+	if mustRevalidate(stored.Header) {
+		return false
+	}
+
+	staleness := stored.age(c.now()) - freshnessLifetime(stored.StatusCode, stored.Header, c.defaultTTL)
+
+	respCC := parseCacheControl(stored.Header.Values("Cache-Control"))
+	if sie, ok := respCC.duration("stale-if-error"); ok && staleness <= sie {
+		return true
+	}
+	if sie, ok := reqCC.duration("stale-if-error"); ok && staleness <= sie {
+		return true
+	}
+	return false
+}
+
+// lookup returns the stored entry matching the request, if any.
+func (c *cache) lookup(key string, req *http.Request) *entry {
+	for _, e := range c.store.get(key) {
+		if e.matches(req) {
+			return e
+		}
+	}
+	return nil
+}
+
+// key returns the cache key of a request, and the URL it is made of.
+func (c *cache) key(req *http.Request) (string, string) {
+	scheme := "http"
+	if req.TLS != nil {
+		scheme = "https"
+	}
+
+	reqURL := scheme + "://" + req.Host + req.URL.RequestURI()
+
+	if len(c.keyHeaders) == 0 && len(c.keyCookies) == 0 {
+		return reqURL, reqURL
+	}
+
+	key := strings.Builder{}
+	key.WriteString(reqURL)
+
+	for _, name := range c.keyHeaders {
+		key.WriteString("\nheader:" + http.CanonicalHeaderKey(name) + "=" + normalizeHeader(req.Header.Values(name)))
+	}
+
+	for _, name := range c.keyCookies {
+		var value string
+		if cookie, err := req.Cookie(name); err == nil {
+			value = cookie.Value
+		}
+		key.WriteString("\ncookie:" + name + "=" + value)
+	}
+
+	return key.String(), reqURL
+}
+
+// serveEntry writes a stored response, or a 304 Not Modified if it satisfies the conditional headers of the request.
+func (c *cache) serveEntry(rw http.ResponseWriter, req *http.Request, e *entry, now time.Time) {
+	age := e.age(now)
+
+	if e.StatusCode == http.StatusOK && notModified(req, e.Header) {
+		writeNotModified(rw, e.Header, age)
+		return
+	}
+
+	header := rw.Header()
+	for name, values := range e.Header {
+		header[name] = values
+	}
+	header.Set("Age", strconv.FormatInt(int64(age/time.Second), 10))
+
+	rw.WriteHeader(e.StatusCode)
+
+	if req.Method != http.MethodHead {
+		_, _ = rw.Write(e.Body)
+	}
+}
+
+func (c *cache) report(req *http.Request, status string) {
+	if logData := accesslog.GetLogData(req); logData != nil {
+		logData.Core[accesslog.CacheStatus] = status
+	}
+
+	if c.requests != nil {
+		c.requests.With("middleware", c.name, "status", status).Add(1)
+	}
+}
+
+// notModifiedHeaders are the headers sent in a 304 Not Modified response (RFC 7232 section 4.1).
+var notModifiedHeaders = []string{"Cache-Control", "Content-Location", "Date", "ETag", "Expires", "Last-Modified", "Vary"}
+
+func writeNotModified(rw http.ResponseWriter, header http.Header, age time.Duration) {
+	for _, name := range notModifiedHeaders {
+		if values := header.Values(name); len(values) > 0 {
+			rw.Header()[http.CanonicalHeaderKey(name)] = values
+		}
+	}
+	if age > 0 {
+		rw.Header().Set("Age", strconv.FormatInt(int64(age/time.Second), 10))
+	}
+
+	rw.WriteHeader(http.StatusNotModified)
+}
+
+// responseRecorder records the response of the next handler, up to a maximum body size,
+// while writing it to the client unless decide returns false for its status code and headers.
+type responseRecorder struct {
+	rw     http.ResponseWriter
+	header http.Header
+	decide func(statusCode int, header http.Header) bool
+
+	statusCode  int
+	wroteHeader bool
+	passThrough bool
+
+	body        bytes.Buffer
+	maxBodySize int64
+	tooLarge    bool
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) WriteHeader(statusCode int) {
+	if r.wroteHeader {
+		return
+	}
+
+	// 1xx informational responses are not recorded.
+	if statusCode >= http.StatusContinue && statusCode < http.StatusOK {
+		return
+	}
+
+	r.wroteHeader = true
+	r.statusCode = statusCode
+	r.passThrough = r.decide(statusCode, r.header)
+
+	if r.passThrough {
+		for name, values := range r.header {
+			r.rw.Header()[name] = values
+		}
+		r.rw.WriteHeader(statusCode)
+	}
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	if !r.tooLarge {
+		if int64(r.body.Len()+len(p)) > r.maxBodySize {
+			r.tooLarge = true
+			r.body = bytes.Buffer{}
+		} else {
+			r.body.Write(p)
+		}
+	}
+
+	if !r.passThrough {
+		return len(p), nil
+	}
+	return r.rw.Write(p)
+}
+
+func (r *responseRecorder) Flush() {
+	if r.passThrough {
+		if flusher, ok := r.rw.(http.Flusher); ok {
+			flusher.Flush()
+		}
+	}
+}
+
+// finish completes the response of a next handler which wrote nothing.
+func (r *responseRecorder) finish() {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+}
+
+// statusRecorder records the status code of a response.
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(statusCode int) {
+	if !r.wroteHeader && statusCode >= http.StatusOK {
+		r.statusCode = statusCode
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// discardResponseWriter is the response writer of the background revalidations.
+type discardResponseWriter struct {
+	header http.Header
+}
+
+func (w *discardResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *discardResponseWriter) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (w *discardResponseWriter) WriteHeader(int) {}
+
+// detachedContext keeps the values of a request context, without being canceled with the request.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
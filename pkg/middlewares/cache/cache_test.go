@@ -0,0 +1,549 @@
+package cache
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
+)
+
+// fakeClock is a clock which only moves forward when told to.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func newTestCache(t *testing.T, config dynamic.Cache, next http.Handler) (*cache, *fakeClock) {
+	t.Helper()
+
+	handler, err := New(context.Background(), next, config, NewStores(nil, ""), "cache")
+	require.NoError(t, err)
+
+	clock := &fakeClock{now: time.Date(2021, time.May, 1, 12, 0, 0, 0, time.UTC)}
+
+	c := handler.(*cache)
+	c.now = clock.Now
+
+	return c, clock
+}
+
+func serve(c *cache, req *http.Request) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	c.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestCache_freshness(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		header         http.Header
+		elapsed        time.Duration
+		requestHeader  http.Header
+		expectedCalls  int32
+		expectedStatus string
+	}{
+		{
+			desc:           "fresh with max-age",
+			header:         http.Header{"Cache-Control": {"max-age=60"}},
+			elapsed:        30 * time.Second,
+			expectedCalls:  1,
+			expectedStatus: statusHit,
+		},
+		{
+			desc:           "stale with max-age",
+			header:         http.Header{"Cache-Control": {"max-age=60"}},
+			elapsed:        90 * time.Second,
+			expectedCalls:  2,
+			expectedStatus: statusMiss,
+		},
+		{
+			desc:           "s-maxage overrides max-age",
+			header:         http.Header{"Cache-Control": {"max-age=60, s-maxage=120"}},
+			elapsed:        90 * time.Second,
+			expectedCalls:  1,
+			expectedStatus: statusHit,
+		},
+		{
+			desc: "fresh with Expires",
+			header: http.Header{
+				"Date":    {"Sat, 01 May 2021 12:00:00 GMT"},
+				"Expires": {"Sat, 01 May 2021 12:01:00 GMT"},
+			},
+			elapsed:        30 * time.Second,
+			expectedCalls:  1,
+			expectedStatus: statusHit,
+		},
+		{
+			desc:           "invalid Expires",
+			header:         http.Header{"Expires": {"0"}},
+			elapsed:        0,
+			expectedCalls:  2,
+			expectedStatus: statusMiss,
+		},
+		{
+			desc:           "no-store",
+			header:         http.Header{"Cache-Control": {"no-store, max-age=60"}},
+			expectedCalls:  2,
+			expectedStatus: statusMiss,
+		},
+		{
+			desc:           "private",
+			header:         http.Header{"Cache-Control": {"private, max-age=60"}},
+			expectedCalls:  2,
+			expectedStatus: statusMiss,
+		},
+		{
+			desc:           "Set-Cookie",
+			header:         http.Header{"Cache-Control": {"max-age=60"}, "Set-Cookie": {"session=foo"}},
+			expectedCalls:  2,
+			expectedStatus: statusMiss,
+		},
+		{
+			desc:           "request with max-age=0",
+			header:         http.Header{"Cache-Control": {"max-age=60"}},
+			elapsed:        10 * time.Second,
+			requestHeader:  http.Header{"Cache-Control": {"max-age=0"}},
+			expectedCalls:  2,
+			expectedStatus: statusMiss,
+		},
+		{
+			desc:           "request with Pragma no-cache",
+			header:         http.Header{"Cache-Control": {"max-age=60"}},
+			requestHeader:  http.Header{"Pragma": {"no-cache"}},
+			expectedCalls:  2,
+			expectedStatus: statusMiss,
+		},
+		{
+			desc:           "request with max-stale",
+			header:         http.Header{"Cache-Control": {"max-age=60"}},
+			elapsed:        90 * time.Second,
+			requestHeader:  http.Header{"Cache-Control": {"max-stale=60"}},
+			expectedCalls:  1,
+			expectedStatus: statusStale,
+		},
+		{
+			desc:           "request with max-stale on must-revalidate",
+			header:         http.Header{"Cache-Control": {"max-age=60, must-revalidate"}},
+			elapsed:        90 * time.Second,
+			requestHeader:  http.Header{"Cache-Control": {"max-stale"}},
+			expectedCalls:  2,
+			expectedStatus: statusMiss,
+		},
+		{
+			desc:           "authorized request",
+			header:         http.Header{"Cache-Control": {"max-age=60"}},
+			requestHeader:  http.Header{"Authorization": {"Basic Zm9vOmJhcg=="}},
+			expectedCalls:  2,
+			expectedStatus: statusMiss,
+		},
+		{
+			desc:           "authorized request with public response",
+			header:         http.Header{"Cache-Control": {"public, max-age=60"}},
+			requestHeader:  http.Header{"Authorization": {"Basic Zm9vOmJhcg=="}},
+			expectedCalls:  1,
+			expectedStatus: statusHit,
+		},
+		{
+			desc:           "range request",
+			header:         http.Header{"Cache-Control": {"max-age=60"}},
+			requestHeader:  http.Header{"Range": {"bytes=0-1"}},
+			expectedCalls:  2,
+			expectedStatus: statusBypass,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var calls int32
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				for name, values := range test.header {
+					rw.Header()[name] = values
+				}
+				_, _ = rw.Write([]byte("catalogue"))
+			})
+
+			c, clock := newTestCache(t, dynamic.Cache{}, next)
+
+			req := httptest.NewRequest(http.MethodGet, "http://foo.bar/products", nil)
+			for name, values := range test.requestHeader {
+				req.Header[name] = values
+			}
+
+			recorder := serve(c, req)
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, "catalogue", recorder.Body.String())
+
+			clock.Add(test.elapsed)
+
+			logData := &accesslog.LogData{Core: accesslog.CoreLogData{}}
+			req = req.WithContext(context.WithValue(req.Context(), accesslog.DataTableKey, logData))
+
+			recorder = serve(c, req)
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, "catalogue", recorder.Body.String())
+
+			assert.Equal(t, test.expectedCalls, atomic.LoadInt32(&calls))
+			assert.Equal(t, test.expectedStatus, logData.Core[accesslog.CacheStatus])
+		})
+	}
+}
+
+func TestCache_age(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Cache-Control", "max-age=60")
+		rw.Header().Set("Age", "10")
+		_, _ = rw.Write([]byte("catalogue"))
+	})
+
+	c, clock := newTestCache(t, dynamic.Cache{}, next)
+
+	serve(c, httptest.NewRequest(http.MethodGet, "http://foo.bar/products", nil))
+	clock.Add(20 * time.Second)
+
+	recorder := serve(c, httptest.NewRequest(http.MethodGet, "http://foo.bar/products", nil))
+	assert.Equal(t, "30", recorder.Header().Get("Age"))
+
+	// The response was already 10 seconds old when it was received, so it is stale,
+	// and the new response is forwarded with its own age.
+	clock.Add(40 * time.Second)
+
+	recorder = serve(c, httptest.NewRequest(http.MethodGet, "http://foo.bar/products", nil))
+	assert.Equal(t, "10", recorder.Header().Get("Age"))
+}
+
+func TestCache_revalidation(t *testing.T) {
+	var calls int32
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		rw.Header().Set("Cache-Control", "max-age=60")
+		rw.Header().Set("ETag", `"v1"`)
+		if req.Header.Get("If-None-Match") == `"v1"` {
+			rw.Header().Set("X-Revalidated", "true")
+			rw.WriteHeader(http.StatusNotModified)
+			return
+		}
+		_, _ = rw.Write([]byte("catalogue"))
+	})
+
+	c, clock := newTestCache(t, dynamic.Cache{}, next)
+
+	serve(c, httptest.NewRequest(http.MethodGet, "http://foo.bar/products", nil))
+	clock.Add(90 * time.Second)
+
+	logData := &accesslog.LogData{Core: accesslog.CoreLogData{}}
+	req := httptest.NewRequest(http.MethodGet, "http://foo.bar/products", nil)
+	req = req.WithContext(context.WithValue(req.Context(), accesslog.DataTableKey, logData))
+
+	recorder := serve(c, req)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "catalogue", recorder.Body.String())
+	assert.Equal(t, "true", recorder.Header().Get("X-Revalidated"))
+	assert.Equal(t, statusRevalidated, logData.Core[accesslog.CacheStatus])
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	// The revalidated response is fresh again.
+	recorder = serve(c, httptest.NewRequest(http.MethodGet, "http://foo.bar/products", nil))
+	assert.Equal(t, "catalogue", recorder.Body.String())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestCache_conditionalRequest(t *testing.T) {
+	var calls int32
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		// The conditional headers of the client are not forwarded.
+		assert.Empty(t, req.Header.Get("If-None-Match"))
+
+		rw.Header().Set("Cache-Control", "max-age=60")
+		rw.Header().Set("ETag", `"v1"`)
+		_, _ = rw.Write([]byte("catalogue"))
+	})
+
+	c, _ := newTestCache(t, dynamic.Cache{}, next)
+
+	req := httptest.NewRequest(http.MethodGet, "http://foo.bar/products", nil)
+	req.Header.Set("If-None-Match", `"v1"`)
+
+	recorder := serve(c, req)
+	assert.Equal(t, http.StatusNotModified, recorder.Code)
+	assert.Empty(t, recorder.Body.String())
+
+	recorder = serve(c, req)
+	assert.Equal(t, http.StatusNotModified, recorder.Code)
+	assert.Equal(t, `"v1"`, recorder.Header().Get("ETag"))
+
+	recorder = serve(c, httptest.NewRequest(http.MethodGet, "http://foo.bar/products", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "catalogue", recorder.Body.String())
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestCache_staleWhileRevalidate(t *testing.T) {
+	revalidated := make(chan struct{})
+
+	var calls int32
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		call := atomic.AddInt32(&calls, 1)
+
+		rw.Header().Set("Cache-Control", "max-age=60, stale-while-revalidate=60")
+		if call == 1 {
+			_, _ = rw.Write([]byte("v1"))
+			return
+		}
+
+		_, _ = rw.Write([]byte("v2"))
+		close(revalidated)
+	})
+
+	c, clock := newTestCache(t, dynamic.Cache{}, next)
+
+	serve(c, httptest.NewRequest(http.MethodGet, "http://foo.bar/products", nil))
+	clock.Add(90 * time.Second)
+
+	// The stale response is served while it is revalidated.
+	recorder := serve(c, httptest.NewRequest(http.MethodGet, "http://foo.bar/products", nil))
+	assert.Equal(t, "v1", recorder.Body.String())
+
+	select {
+	case <-revalidated:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the response has not been revalidated")
+	}
+
+	assert.Eventually(t, func() bool {
+		return serve(c, httptest.NewRequest(http.MethodGet, "http://foo.bar/products", nil)).Body.String() == "v2"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestCache_staleIfError(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		cacheControl string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			desc:         "stale-if-error",
+			cacheControl: "max-age=60, stale-if-error=60",
+			expectedCode: http.StatusOK,
+			expectedBody: "catalogue",
+		},
+		{
+			desc:         "stale-if-error expired",
+			cacheControl: "max-age=10, stale-if-error=10",
+			expectedCode: http.StatusBadGateway,
+			expectedBody: "error",
+		},
+		{
+			desc:         "stale-if-error with must-revalidate",
+			cacheControl: "max-age=60, stale-if-error=60, must-revalidate",
+			expectedCode: http.StatusBadGateway,
+			expectedBody: "error",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var calls int32
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if atomic.AddInt32(&calls, 1) > 1 {
+					rw.WriteHeader(http.StatusBadGateway)
+					_, _ = rw.Write([]byte("error"))
+					return
+				}
+
+				rw.Header().Set("Cache-Control", test.cacheControl)
+				_, _ = rw.Write([]byte("catalogue"))
+			})
+
+			c, clock := newTestCache(t, dynamic.Cache{}, next)
+
+			serve(c, httptest.NewRequest(http.MethodGet, "http://foo.bar/products", nil))
+			clock.Add(90 * time.Second)
+
+			recorder := serve(c, httptest.NewRequest(http.MethodGet, "http://foo.bar/products", nil))
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestCache_vary(t *testing.T) {
+	var calls int32
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		rw.Header().Set("Cache-Control", "max-age=60")
+		rw.Header().Set("Vary", "Accept-Language")
+		_, _ = rw.Write([]byte(req.Header.Get("Accept-Language")))
+	})
+
+	c, _ := newTestCache(t, dynamic.Cache{}, next)
+
+	for _, language := range []string{"en", "fr", "en", "fr"} {
+		req := httptest.NewRequest(http.MethodGet, "http://foo.bar/products", nil)
+		req.Header.Set("Accept-Language", language)
+
+		recorder := serve(c, req)
+		assert.Equal(t, language, recorder.Body.String())
+	}
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestCache_key(t *testing.T) {
+	var calls int32
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		rw.Header().Set("Cache-Control", "max-age=60")
+		cookie, err := req.Cookie("currency")
+		require.NoError(t, err)
+		_, _ = rw.Write([]byte(req.Header.Get("X-Tenant") + cookie.Value))
+	})
+
+	c, _ := newTestCache(t, dynamic.Cache{Key: &dynamic.CacheKey{Headers: []string{"X-Tenant"}, Cookies: []string{"currency"}}}, next)
+
+	requests := []struct {
+		tenant   string
+		currency string
+	}{
+		{tenant: "a", currency: "EUR"},
+		{tenant: "b", currency: "EUR"},
+		{tenant: "a", currency: "USD"},
+		{tenant: "a", currency: "EUR"},
+	}
+
+	for _, r := range requests {
+		req := httptest.NewRequest(http.MethodGet, "http://foo.bar/products", nil)
+		req.Header.Set("X-Tenant", r.tenant)
+		req.AddCookie(&http.Cookie{Name: "currency", Value: r.currency})
+
+		recorder := serve(c, req)
+		assert.Equal(t, r.tenant+r.currency, recorder.Body.String())
+	}
+
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestCache_head(t *testing.T) {
+	var calls int32
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		rw.Header().Set("Cache-Control", "max-age=60")
+		_, _ = rw.Write([]byte("catalogue"))
+	})
+
+	c, _ := newTestCache(t, dynamic.Cache{}, next)
+
+	serve(c, httptest.NewRequest(http.MethodGet, "http://foo.bar/products", nil))
+
+	recorder := serve(c, httptest.NewRequest(http.MethodHead, "http://foo.bar/products", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Empty(t, recorder.Body.String())
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestCache_unsafeMethodInvalidation(t *testing.T) {
+	var calls int32
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		if req.Method == http.MethodPost {
+			rw.Header().Set("Location", "/products/42")
+			rw.WriteHeader(http.StatusCreated)
+			return
+		}
+
+		rw.Header().Set("Cache-Control", "max-age=60")
+		_, _ = rw.Write([]byte("catalogue"))
+	})
+
+	c, _ := newTestCache(t, dynamic.Cache{}, next)
+
+	serve(c, httptest.NewRequest(http.MethodGet, "http://foo.bar/products", nil))
+	serve(c, httptest.NewRequest(http.MethodGet, "http://foo.bar/products/42", nil))
+	serve(c, httptest.NewRequest(http.MethodGet, "http://foo.bar/categories", nil))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	recorder := serve(c, httptest.NewRequest(http.MethodPost, "http://foo.bar/products", nil))
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+
+	serve(c, httptest.NewRequest(http.MethodGet, "http://foo.bar/products", nil))
+	serve(c, httptest.NewRequest(http.MethodGet, "http://foo.bar/products/42", nil))
+	serve(c, httptest.NewRequest(http.MethodGet, "http://foo.bar/categories", nil))
+	assert.Equal(t, int32(6), atomic.LoadInt32(&calls))
+}
+
+func TestCache_maxEntrySize(t *testing.T) {
+	var calls int32
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		rw.Header().Set("Cache-Control", "max-age=60")
+		_, _ = rw.Write([]byte("catalogue"))
+		_, _ = rw.Write([]byte(" of products"))
+	})
+
+	c, _ := newTestCache(t, dynamic.Cache{MaxEntrySize: 10}, next)
+
+	for i := 0; i < 2; i++ {
+		recorder := serve(c, httptest.NewRequest(http.MethodGet, "http://foo.bar/products", nil))
+		body, err := io.ReadAll(recorder.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "catalogue of products", string(body))
+	}
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestCache_onlyIfCached(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		t.Error("the request should not be forwarded")
+	})
+
+	c, _ := newTestCache(t, dynamic.Cache{}, next)
+
+	req := httptest.NewRequest(http.MethodGet, "http://foo.bar/products", nil)
+	req.Header.Set("Cache-Control", "only-if-cached")
+
+	recorder := serve(c, req)
+	assert.Equal(t, http.StatusGatewayTimeout, recorder.Code)
+}
@@ -0,0 +1,217 @@
+package cache
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cacheableByDefault are the status codes which can be cached with a heuristic freshness lifetime (RFC 7231 section 6.1).
+var cacheableByDefault = map[int]struct{}{
+	http.StatusOK:                   {},
+	http.StatusNonAuthoritativeInfo: {},
+	http.StatusNoContent:            {},
+	http.StatusMultipleChoices:      {},
+	http.StatusMovedPermanently:     {},
+	http.StatusNotFound:             {},
+	http.StatusMethodNotAllowed:     {},
+	http.StatusGone:                 {},
+	http.StatusRequestURITooLong:    {},
+	http.StatusNotImplemented:       {},
+}
+
+// cacheControl holds the directives of Cache-Control headers, with their arguments.
+type cacheControl map[string]string
+
+func parseCacheControl(values []string) cacheControl {
+	cc := cacheControl{}
+	for _, value := range values {
+		for _, directive := range strings.Split(value, ",") {
+			directive = strings.TrimSpace(directive)
+			if directive == "" {
+				continue
+			}
+
+			name, arg := directive, ""
+			if i := strings.Index(directive, "="); i >= 0 {
+				name, arg = directive[:i], strings.Trim(strings.TrimSpace(directive[i+1:]), `"`)
+			}
+			cc[strings.ToLower(strings.TrimSpace(name))] = arg
+		}
+	}
+	return cc
+}
+
+func (cc cacheControl) has(directive string) bool {
+	_, ok := cc[directive]
+	return ok
+}
+
+// duration returns the delta-seconds argument of the given directive, and whether it is valid.
+func (cc cacheControl) duration(directive string) (time.Duration, bool) {
+	arg, ok := cc[directive]
+	if !ok {
+		return 0, false
+	}
+
+	seconds, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+// requestCacheControl returns the cache directives of a request,
+// with the HTTP/1.0 Pragma: no-cache header standing for Cache-Control: no-cache.
+func requestCacheControl(req *http.Request) cacheControl {
+	cc := parseCacheControl(req.Header.Values("Cache-Control"))
+	if len(req.Header.Values("Cache-Control")) == 0 && strings.EqualFold(strings.TrimSpace(req.Header.Get("Pragma")), "no-cache") {
+		cc["no-cache"] = ""
+	}
+	return cc
+}
+
+// storable tells whether a response to the given request can be stored (RFC 7234 section 3).
+func storable(req *http.Request, reqCC cacheControl, statusCode int, header http.Header, defaultTTL time.Duration) bool {
+	if req.Method != http.MethodGet || reqCC.has("no-store") {
+		return false
+	}
+
+	if statusCode == http.StatusPartialContent || statusCode == http.StatusNotModified || statusCode < http.StatusOK {
+		return false
+	}
+
+	cc := parseCacheControl(header.Values("Cache-Control"))
+	if cc.has("no-store") || cc.has("private") {
+		return false
+	}
+
+	if header.Get("Vary") == "*" || len(header.Values("Set-Cookie")) > 0 {
+		return false
+	}
+
+	// A shared cache must not store the responses to authorized requests, unless it is explicitly allowed to (RFC 7234 section 3.2).
+	if req.Header.Get("Authorization") != "" && !cc.has("public") && !cc.has("must-revalidate") && !cc.has("s-maxage") {
+		return false
+	}
+
+	if _, ok := cc.duration("s-maxage"); ok {
+		return true
+	}
+	if _, ok := cc.duration("max-age"); ok {
+		return true
+	}
+	if header.Get("Expires") != "" || cc.has("public") {
+		return true
+	}
+
+	if _, ok := cacheableByDefault[statusCode]; !ok {
+		return false
+	}
+
+	return defaultTTL > 0 || header.Get("Last-Modified") != "" || header.Get("ETag") != ""
+}
+
+// freshnessLifetime returns the freshness lifetime of a response (RFC 7234 section 4.2.1).
+func freshnessLifetime(statusCode int, header http.Header, defaultTTL time.Duration) time.Duration {
+	cc := parseCacheControl(header.Values("Cache-Control"))
+	if cc.has("no-cache") {
+		return 0
+	}
+
+	if lifetime, ok := cc.duration("s-maxage"); ok {
+		return lifetime
+	}
+	if lifetime, ok := cc.duration("max-age"); ok {
+		return lifetime
+	}
+
+	date := parseHTTPTime(header.Get("Date"))
+
+	if expiresValue := header.Get("Expires"); expiresValue != "" {
+		expires := parseHTTPTime(expiresValue)
+		if expires.IsZero() || date.IsZero() || !expires.After(date) {
+			return 0
+		}
+		return expires.Sub(date)
+	}
+
+	if _, ok := cacheableByDefault[statusCode]; !ok {
+		return 0
+	}
+
+	// Heuristic freshness, a tenth of the time elapsed since the last modification (RFC 7234 section 4.2.2).
+	if lastModified := parseHTTPTime(header.Get("Last-Modified")); !lastModified.IsZero() && !date.IsZero() && date.After(lastModified) {
+		return date.Sub(lastModified) / 10
+	}
+
+	return defaultTTL
+}
+
+// mustRevalidate tells whether a stale response must not be served without being revalidated first.
+func mustRevalidate(header http.Header) bool {
+	cc := parseCacheControl(header.Values("Cache-Control"))
+	return cc.has("must-revalidate") || cc.has("proxy-revalidate") || cc.has("s-maxage") || cc.has("no-cache")
+}
+
+// initialAge returns the corrected initial age of a response (RFC 7234 section 4.2.3).
+func initialAge(header http.Header, requestTime, responseTime time.Time) time.Duration {
+	var apparentAge time.Duration
+	if date := parseHTTPTime(header.Get("Date")); !date.IsZero() && responseTime.After(date) {
+		apparentAge = responseTime.Sub(date)
+	}
+
+	var ageValue time.Duration
+	if seconds, err := strconv.ParseInt(header.Get("Age"), 10, 64); err == nil && seconds > 0 {
+		ageValue = time.Duration(seconds) * time.Second
+	}
+
+	correctedAge := ageValue + responseTime.Sub(requestTime)
+	if apparentAge > correctedAge {
+		return apparentAge
+	}
+	return correctedAge
+}
+
+// notModified tells whether the conditional headers of a request are satisfied by a response with the given headers,
+// so that a 304 Not Modified can be sent instead of the response (RFC 7232 section 6).
+func notModified(req *http.Request, header http.Header) bool {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false
+	}
+
+	if ifNoneMatch := req.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		etag := header.Get("ETag")
+		if etag == "" {
+			return false
+		}
+
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	ifModifiedSince := parseHTTPTime(req.Header.Get("If-Modified-Since"))
+	lastModified := parseHTTPTime(header.Get("Last-Modified"))
+	if ifModifiedSince.IsZero() || lastModified.IsZero() {
+		return false
+	}
+	return !lastModified.After(ifModifiedSince)
+}
+
+func parseHTTPTime(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+
+	t, err := http.ParseTime(value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,440 @@
+package cache
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// entry is a stored response.
+// Entries are never modified once stored, a revalidated response is stored as a new entry.
+type entry struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+
+	// VaryHeaders are the values of the request headers the response varies on.
+	VaryHeaders http.Header
+
+	ResponseTime time.Time
+	// InitialAge is the age the response had when it was received.
+	InitialAge time.Duration
+}
+
+func (e *entry) age(now time.Time) time.Duration {
+	return e.InitialAge + now.Sub(e.ResponseTime)
+}
+
+// matches tells whether the entry can be served for the given request, according to the headers the response varies on.
+func (e *entry) matches(req *http.Request) bool {
+	for _, name := range e.Header.Values("Vary") {
+		for _, field := range strings.Split(name, ",") {
+			field = http.CanonicalHeaderKey(strings.TrimSpace(field))
+			if field == "" {
+				continue
+			}
+
+			if normalizeHeader(req.Header.Values(field)) != normalizeHeader(e.VaryHeaders.Values(field)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (e *entry) size() int64 {
+	size := int64(len(e.Body))
+	for name, values := range e.Header {
+		size += int64(len(name))
+		for _, value := range values {
+			size += int64(len(value))
+		}
+	}
+	for name, values := range e.VaryHeaders {
+		size += int64(len(name))
+		for _, value := range values {
+			size += int64(len(value))
+		}
+	}
+	return size
+}
+
+func newEntry(req *http.Request, statusCode int, header http.Header, body []byte, requestTime, responseTime time.Time) *entry {
+	header = header.Clone()
+	if header.Get("Date") == "" {
+		header.Set("Date", responseTime.UTC().Format(http.TimeFormat))
+	}
+
+	varyHeaders := http.Header{}
+	for _, name := range header.Values("Vary") {
+		for _, field := range strings.Split(name, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			for _, value := range req.Header.Values(field) {
+				varyHeaders.Add(field, value)
+			}
+		}
+	}
+
+	return &entry{
+		StatusCode:   statusCode,
+		Header:       header,
+		Body:         body,
+		VaryHeaders:  varyHeaders,
+		ResponseTime: responseTime,
+		InitialAge:   initialAge(header, requestTime, responseTime),
+	}
+}
+
+// revalidated returns a copy of the entry, updated with the headers of a 304 Not Modified response (RFC 7234 section 4.3.4).
+func (e *entry) revalidated(req *http.Request, header http.Header, requestTime, responseTime time.Time) *entry {
+	merged := e.Header.Clone()
+	merged.Del("Date")
+	merged.Del("Age")
+	for name, values := range header {
+		// The 304 response does not describe the stored body.
+		if name == "Content-Length" {
+			continue
+		}
+		merged[name] = values
+	}
+
+	return newEntry(req, e.StatusCode, merged, e.Body, requestTime, responseTime)
+}
+
+func normalizeHeader(values []string) string {
+	var fields []string
+	for _, value := range values {
+		for _, field := range strings.Split(value, ",") {
+			fields = append(fields, strings.TrimSpace(field))
+		}
+	}
+	return strings.Join(fields, ",")
+}
+
+// item holds the entries stored for a cache key, one per variant of the response.
+type item struct {
+	Key     string
+	URL     string
+	Entries []*entry
+
+	size int64
+}
+
+func (i *item) computeSize() {
+	i.size = int64(len(i.Key) + len(i.URL))
+	for _, e := range i.Entries {
+		i.size += e.size()
+	}
+}
+
+// Store holds the responses cached by a cache middleware, in memory,
+// and on disk once they are evicted from memory if a disk tier is configured.
+type Store struct {
+	mu      sync.Mutex
+	maxSize int64
+	size    int64
+	lru     *list.List
+	items   map[string]*list.Element
+	disk    *diskTier
+}
+
+func newStore(maxSize int64, disk *diskTier) *Store {
+	return &Store{
+		maxSize: maxSize,
+		lru:     list.New(),
+		items:   make(map[string]*list.Element),
+		disk:    disk,
+	}
+}
+
+// get returns the entries stored for the given key.
+func (s *Store) get(key string) []*entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elt, ok := s.items[key]; ok {
+		s.lru.MoveToFront(elt)
+		return elt.Value.(*item).Entries
+	}
+
+	if s.disk == nil {
+		return nil
+	}
+
+	it := s.disk.take(key)
+	if it == nil {
+		return nil
+	}
+
+	it.computeSize()
+	s.add(it)
+
+	return it.Entries
+}
+
+// set stores an entry for the given key, replacing the variant which would have been served for the request.
+func (s *Store) set(key, url string, req *http.Request, e *entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := []*entry{e}
+	if elt, ok := s.items[key]; ok {
+		for _, stored := range elt.Value.(*item).Entries {
+			if !stored.matches(req) {
+				entries = append(entries, stored)
+			}
+		}
+		s.remove(elt)
+	} else if s.disk != nil {
+		s.disk.delete(key)
+	}
+
+	it := &item{Key: key, URL: url, Entries: entries}
+	it.computeSize()
+	if it.size > s.maxSize {
+		return
+	}
+
+	s.add(it)
+}
+
+// Purge removes the responses stored for the given URL, or for the URLs starting with the given prefix,
+// and returns the number of removed cache keys.
+// All the responses are removed when both are empty.
+func (s *Store) Purge(url, prefix string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	match := func(candidate string) bool {
+		switch {
+		case url != "":
+			return candidate == url
+		case prefix != "":
+			return strings.HasPrefix(candidate, prefix)
+		default:
+			return true
+		}
+	}
+
+	var purged int
+	for _, elt := range s.items {
+		if match(elt.Value.(*item).URL) {
+			s.remove(elt)
+			purged++
+		}
+	}
+
+	if s.disk != nil {
+		purged += s.disk.purge(match)
+	}
+
+	return purged
+}
+
+func (s *Store) add(it *item) {
+	s.items[it.Key] = s.lru.PushFront(it)
+	s.size += it.size
+
+	for s.size > s.maxSize {
+		elt := s.lru.Back()
+		if elt == nil {
+			return
+		}
+
+		evicted := elt.Value.(*item)
+		s.remove(elt)
+
+		if s.disk != nil {
+			s.disk.put(evicted)
+		}
+	}
+}
+
+func (s *Store) remove(elt *list.Element) {
+	it := elt.Value.(*item)
+	s.lru.Remove(elt)
+	delete(s.items, it.Key)
+	s.size -= it.size
+}
+
+func (s *Store) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lru.Init()
+	s.items = make(map[string]*list.Element)
+	s.size = 0
+
+	if s.disk != nil {
+		s.disk.close()
+	}
+}
+
+const entryFileExt = ".entry"
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+// diskTier holds the items evicted from memory, one file per item.
+// Its index is not persisted, so the files of a previous run are removed when it is created.
+type diskTier struct {
+	dir     string
+	maxSize int64
+	size    int64
+	lru     *list.List
+	items   map[string]*list.Element
+}
+
+type diskItem struct {
+	key  string
+	url  string
+	file string
+	size int64
+}
+
+func newDiskTier(path, name string, maxSize int64) (*diskTier, error) {
+	dir := filepath.Join(path, unsafeFileChars.ReplaceAllString(name, "_"))
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("unable to create cache directory: %w", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"+entryFileExt))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		if err := os.Remove(file); err != nil {
+			return nil, fmt.Errorf("unable to remove stale cache file: %w", err)
+		}
+	}
+
+	return &diskTier{
+		dir:     dir,
+		maxSize: maxSize,
+		lru:     list.New(),
+		items:   make(map[string]*list.Element),
+	}, nil
+}
+
+func (d *diskTier) put(it *item) {
+	if it.size > d.maxSize {
+		return
+	}
+
+	sum := sha256.Sum256([]byte(it.Key))
+	file := filepath.Join(d.dir, hex.EncodeToString(sum[:])+entryFileExt)
+
+	if err := writeItem(file, it); err != nil {
+		log.WithoutContext().Errorf("Unable to write cache file: %v", err)
+		return
+	}
+
+	d.delete(it.Key)
+	d.items[it.Key] = d.lru.PushFront(&diskItem{key: it.Key, url: it.URL, file: file, size: it.size})
+	d.size += it.size
+
+	for d.size > d.maxSize {
+		d.remove(d.lru.Back())
+	}
+}
+
+// take removes the item stored for the given key from the disk and returns it.
+func (d *diskTier) take(key string) *item {
+	elt, ok := d.items[key]
+	if !ok {
+		return nil
+	}
+
+	file := elt.Value.(*diskItem).file
+	it, err := readItem(file)
+	d.remove(elt)
+	if err != nil {
+		log.WithoutContext().Errorf("Unable to read cache file: %v", err)
+		return nil
+	}
+
+	return it
+}
+
+func (d *diskTier) delete(key string) {
+	if elt, ok := d.items[key]; ok {
+		d.remove(elt)
+	}
+}
+
+func (d *diskTier) purge(match func(url string) bool) int {
+	var purged int
+	for _, elt := range d.items {
+		if match(elt.Value.(*diskItem).url) {
+			d.remove(elt)
+			purged++
+		}
+	}
+	return purged
+}
+
+func (d *diskTier) remove(elt *list.Element) {
+	it := elt.Value.(*diskItem)
+	d.lru.Remove(elt)
+	delete(d.items, it.key)
+	d.size -= it.size
+
+	if err := os.Remove(it.file); err != nil && !os.IsNotExist(err) {
+		log.WithoutContext().Errorf("Unable to remove cache file: %v", err)
+	}
+}
+
+func (d *diskTier) close() {
+	for _, elt := range d.items {
+		d.remove(elt)
+	}
+}
+
+func writeItem(file string, it *item) error {
+	tmp := file + ".tmp"
+
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if err = gob.NewEncoder(f).Encode(it); err != nil {
+		_ = f.Close()
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	if err = f.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	return os.Rename(tmp, file)
+}
+
+func readItem(file string) (*item, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var it item
+	if err := gob.NewDecoder(f).Decode(&it); err != nil {
+		return nil, err
+	}
+	return &it, nil
+}
@@ -0,0 +1,173 @@
+package cache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+)
+
+func newTestEntry(body string) *entry {
+	return &entry{
+		StatusCode:   http.StatusOK,
+		Header:       http.Header{},
+		Body:         []byte(body),
+		VaryHeaders:  http.Header{},
+		ResponseTime: time.Now(),
+	}
+}
+
+func TestStore_eviction(t *testing.T) {
+	store := newStore(100, nil)
+	req := httptest.NewRequest(http.MethodGet, "http://foo.bar", nil)
+
+	store.set("a", "a", req, newTestEntry(strings.Repeat("a", 40)))
+	store.set("b", "b", req, newTestEntry(strings.Repeat("b", 40)))
+
+	// a is now the most recently used.
+	require.Len(t, store.get("a"), 1)
+
+	store.set("c", "c", req, newTestEntry(strings.Repeat("c", 40)))
+
+	assert.Len(t, store.get("a"), 1)
+	assert.Empty(t, store.get("b"))
+	assert.Len(t, store.get("c"), 1)
+	assert.LessOrEqual(t, store.size, int64(100))
+}
+
+func TestStore_disk(t *testing.T) {
+	dir := t.TempDir()
+
+	// The files of a previous run are removed.
+	staleFile := filepath.Join(dir, "cache", "stale"+entryFileExt)
+	require.NoError(t, os.MkdirAll(filepath.Dir(staleFile), 0o700))
+	require.NoError(t, os.WriteFile(staleFile, []byte("stale"), 0o600))
+
+	disk, err := newDiskTier(dir, "cache", 1000)
+	require.NoError(t, err)
+	assert.NoFileExists(t, staleFile)
+
+	store := newStore(100, disk)
+	req := httptest.NewRequest(http.MethodGet, "http://foo.bar", nil)
+
+	store.set("a", "http://foo.bar/a", req, newTestEntry(strings.Repeat("a", 60)))
+	store.set("b", "http://foo.bar/b", req, newTestEntry(strings.Repeat("b", 60)))
+
+	// a is evicted from memory to the disk.
+	assert.NotContains(t, store.items, "a")
+	assert.Contains(t, disk.items, "a")
+
+	entries := store.get("a")
+	require.Len(t, entries, 1)
+	assert.Equal(t, strings.Repeat("a", 60), string(entries[0].Body))
+
+	// a is back in memory, and b is on the disk.
+	assert.Contains(t, store.items, "a")
+	assert.NotContains(t, disk.items, "a")
+	assert.Contains(t, disk.items, "b")
+
+	assert.Equal(t, 1, store.Purge("http://foo.bar/b", ""))
+	assert.Empty(t, store.get("b"))
+
+	files, err := filepath.Glob(filepath.Join(dir, "cache", "*"+entryFileExt))
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+func TestStore_Purge(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		url            string
+		prefix         string
+		expectedPurged int
+		expectedKeys   []string
+	}{
+		{
+			desc:           "by URL",
+			url:            "http://foo.bar/products",
+			expectedPurged: 2,
+			expectedKeys:   []string{"http://foo.bar/products/42", "http://foo.bar/categories"},
+		},
+		{
+			desc:           "by prefix",
+			prefix:         "http://foo.bar/products",
+			expectedPurged: 3,
+			expectedKeys:   []string{"http://foo.bar/categories"},
+		},
+		{
+			desc:           "all",
+			expectedPurged: 4,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			store := newStore(1000, nil)
+			req := httptest.NewRequest(http.MethodGet, "http://foo.bar", nil)
+
+			store.set("http://foo.bar/products", "http://foo.bar/products", req, newTestEntry("products"))
+			store.set("http://foo.bar/products\nheader:X-Tenant=a", "http://foo.bar/products", req, newTestEntry("products"))
+			store.set("http://foo.bar/products/42", "http://foo.bar/products/42", req, newTestEntry("product"))
+			store.set("http://foo.bar/categories", "http://foo.bar/categories", req, newTestEntry("categories"))
+
+			assert.Equal(t, test.expectedPurged, store.Purge(test.url, test.prefix))
+
+			var keys []string
+			for key := range store.items {
+				keys = append(keys, key)
+			}
+			assert.ElementsMatch(t, test.expectedKeys, keys)
+		})
+	}
+}
+
+func TestStores(t *testing.T) {
+	stores := NewStores(nil, "")
+
+	store, err := stores.get("cache@file", dynamic.Cache{MaxSize: 100})
+	require.NoError(t, err)
+
+	// The store is kept as long as the configuration does not change.
+	same, err := stores.get("cache@file", dynamic.Cache{MaxSize: 100})
+	require.NoError(t, err)
+	assert.Same(t, store, same)
+
+	other, err := stores.get("cache@file", dynamic.Cache{MaxSize: 200})
+	require.NoError(t, err)
+	assert.NotSame(t, store, other)
+
+	_, ok := stores.Purge("cache@file", "", "")
+	assert.True(t, ok)
+
+	stores.Retain(map[string]*runtime.MiddlewareInfo{
+		"cache@file": {Middleware: &dynamic.Middleware{AddPrefix: &dynamic.AddPrefix{}}},
+	})
+
+	_, ok = stores.Purge("cache@file", "", "")
+	assert.False(t, ok)
+}
+
+func TestStores_disk(t *testing.T) {
+	config := dynamic.Cache{MaxSize: 100, Disk: &dynamic.CacheDisk{MaxSize: 1000}}
+
+	// The disk tier cannot be enabled without the directory of the static configuration.
+	_, err := NewStores(nil, "").get("cache@file", config)
+	assert.Error(t, err)
+
+	dir := t.TempDir()
+	store, err := NewStores(nil, dir).get("cache@file", config)
+	require.NoError(t, err)
+	require.NotNil(t, store.disk)
+	assert.Equal(t, filepath.Join(dir, "cache_file"), store.disk.dir)
+}
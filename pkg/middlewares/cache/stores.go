@@ -0,0 +1,101 @@
+package cache
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+)
+
+// Stores holds the stores of the cache middlewares.
+// It outlives the middlewares, so that the cached responses are kept across configuration changes,
+// as long as the configuration of a middleware does not change.
+type Stores struct {
+	metricsRegistry metrics.Registry
+	diskPath        string
+
+	mu     sync.Mutex
+	stores map[string]*configuredStore
+}
+
+type configuredStore struct {
+	config dynamic.Cache
+	store  *Store
+}
+
+// NewStores creates new Stores.
+// The disk tiers of the stores are kept in the given directory, which comes from the static configuration,
+// and they cannot be enabled when it is empty.
+func NewStores(metricsRegistry metrics.Registry, diskPath string) *Stores {
+	return &Stores{
+		metricsRegistry: metricsRegistry,
+		diskPath:        diskPath,
+		stores:          make(map[string]*configuredStore),
+	}
+}
+
+// get returns the store of the given middleware, creating a new one if the middleware is new or if its configuration changed.
+func (s *Stores) get(name string, config dynamic.Cache) (*Store, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if current, ok := s.stores[name]; ok {
+		if reflect.DeepEqual(current.config, config) {
+			return current.store, nil
+		}
+
+		current.store.close()
+		delete(s.stores, name)
+	}
+
+	var disk *diskTier
+	if config.Disk != nil {
+		if s.diskPath == "" {
+			return nil, errors.New("the disk tier requires the cache disk path of the static configuration")
+		}
+
+		var err error
+		disk, err = newDiskTier(s.diskPath, name, config.Disk.MaxSize)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	store := newStore(config.MaxSize, disk)
+	s.stores[name] = &configuredStore{config: config, store: store}
+
+	return store, nil
+}
+
+// Retain removes the stores of the middlewares which are not cache middlewares of the given configuration anymore.
+func (s *Stores) Retain(middlewares map[string]*runtime.MiddlewareInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for name, current := range s.stores {
+		if middleware, ok := middlewares[name]; ok && middleware.Middleware != nil && middleware.Cache != nil {
+			continue
+		}
+
+		current.store.close()
+		delete(s.stores, name)
+	}
+}
+
+// Purge removes the responses cached by the given middleware for the given URL, or for the URLs starting with the given prefix,
+// or all its cached responses when both are empty.
+// It returns the number of removed cache keys, and false if the middleware does not have a store.
+func (s *Stores) Purge(name, url, prefix string) (int, bool) {
+	s.mu.Lock()
+	current, ok := s.stores[name]
+	s.mu.Unlock()
+
+	if !ok {
+		return 0, false
+	}
+
+	return current.store.Purge(url, prefix), true
+}
@@ -2,53 +2,157 @@ package circuitbreaker
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"sync"
+	"time"
 
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/opentracing/opentracing-go/ext"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
 	"github.com/traefik/traefik/v2/pkg/middlewares"
 	"github.com/traefik/traefik/v2/pkg/tracing"
-	"github.com/vulcand/oxy/cbreaker"
+	"github.com/vulcand/oxy/memmetrics"
+	"github.com/vulcand/oxy/utils"
 )
 
 const (
 	typeName = "CircuitBreaker"
 )
 
+const (
+	defaultFallbackDuration = 10 * time.Second
+	defaultRecoveryDuration = 10 * time.Second
+	defaultProbeRatio       = 0.5
+
+	// checkPeriod is the minimum period between two evaluations of the circuit breaker expression.
+	checkPeriod = 100 * time.Millisecond
+)
+
+// States of the circuit breaker, reported in the metrics and the runtime status of the middleware.
+const (
+	stateStandby    = "standby"
+	stateTripped    = "tripped"
+	stateRecovering = "recovering"
+)
+
+type serviceBuilder interface {
+	BuildHTTP(ctx context.Context, serviceName string) (http.Handler, error)
+}
+
+// circuitBreaker is a middleware serving the requests with a fallback handler while the expression matches.
+// Once tripped, it stays open for the fallback duration, then lets a linearly growing ratio of the requests through,
+// up to the probe ratio at the end of the recovery duration, after which it closes again.
 type circuitBreaker struct {
-	circuitBreaker *cbreaker.CircuitBreaker
-	name           string
+	name       string
+	routerName string
+	next       http.Handler
+	fallback   http.Handler
+	logger     log.Logger
+
+	condition        hpredicate
+	metrics          *memmetrics.RTMetrics
+	fallbackDuration time.Duration
+	recoveryDuration time.Duration
+	probeRatio       float64
+
+	transitions gokitmetrics.Counter
+	info        *runtime.MiddlewareInfo
+
+	now func() time.Time
+
+	mu        sync.RWMutex
+	state     string
+	until     time.Time
+	lastCheck time.Time
+	// start, allowed and denied keep track of the requests let through while recovering.
+	start   time.Time
+	allowed int
+	denied  int
 }
 
 // New creates a new circuit breaker middleware.
-func New(ctx context.Context, next http.Handler, confCircuitBreaker dynamic.CircuitBreaker, name string) (http.Handler, error) {
-	expression := confCircuitBreaker.Expression
+// The state transitions are reported in the given metrics registry and middleware info, when not nil,
+// for the router the middleware is built for.
+func New(ctx context.Context, next http.Handler, config dynamic.CircuitBreaker, serviceBuilder serviceBuilder, metricsRegistry metrics.Registry, info *runtime.MiddlewareInfo, name, routerName string) (http.Handler, error) {
+	expression := config.Expression
 
 	logger := log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName))
 	logger.Debug("Creating middleware")
-	logger.Debug("Setting up with expression: %s", expression)
+	logger.Debugf("Setting up with expression: %s", expression)
 
-	oxyCircuitBreaker, err := cbreaker.New(next, expression, createCircuitBreakerOptions(expression))
+	condition, err := parseExpression(expression)
 	if err != nil {
 		return nil, err
 	}
-	return &circuitBreaker{
-		circuitBreaker: oxyCircuitBreaker,
-		name:           name,
-	}, nil
-}
 
-// NewCircuitBreakerOptions returns a new CircuitBreakerOption.
-func createCircuitBreakerOptions(expression string) cbreaker.CircuitBreakerOption {
-	return cbreaker.Fallback(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
-		tracing.SetErrorWithEvent(req, "blocked by circuit-breaker (%q)", expression)
-		rw.WriteHeader(http.StatusServiceUnavailable)
+	rtMetrics, err := memmetrics.NewRTMetrics()
+	if err != nil {
+		return nil, err
+	}
+
+	if config.FallbackDuration < 0 || config.RecoveryDuration < 0 {
+		return nil, errors.New("fallbackDuration and recoveryDuration must be positive")
+	}
 
-		if _, err := rw.Write([]byte(http.StatusText(http.StatusServiceUnavailable))); err != nil {
-			log.FromContext(req.Context()).Error(err)
+	if config.ProbeRatio < 0 || config.ProbeRatio > 1 {
+		return nil, fmt.Errorf("probeRatio must be between 0 and 1: %v", config.ProbeRatio)
+	}
+
+	c := &circuitBreaker{
+		name:             name,
+		routerName:       routerName,
+		next:             next,
+		logger:           logger,
+		condition:        condition,
+		metrics:          rtMetrics,
+		fallbackDuration: time.Duration(config.FallbackDuration),
+		recoveryDuration: time.Duration(config.RecoveryDuration),
+		probeRatio:       config.ProbeRatio,
+		info:             info,
+		now:              time.Now,
+		state:            stateStandby,
+	}
+
+	if c.fallbackDuration == 0 {
+		c.fallbackDuration = defaultFallbackDuration
+	}
+	if c.recoveryDuration == 0 {
+		c.recoveryDuration = defaultRecoveryDuration
+	}
+	if c.probeRatio == 0 {
+		c.probeRatio = defaultProbeRatio
+	}
+
+	if config.FallbackService != "" {
+		c.fallback, err = serviceBuilder.BuildHTTP(ctx, config.FallbackService)
+		if err != nil {
+			return nil, err
 		}
-	}))
+	} else {
+		c.fallback = http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			tracing.SetErrorWithEvent(req, "blocked by circuit-breaker (%q)", expression)
+			rw.WriteHeader(http.StatusServiceUnavailable)
+
+			if _, err := rw.Write([]byte(http.StatusText(http.StatusServiceUnavailable))); err != nil {
+				log.FromContext(req.Context()).Error(err)
+			}
+		})
+	}
+
+	if metricsRegistry != nil {
+		c.transitions = metricsRegistry.MiddlewareCircuitBreakerTransitionsCounter()
+	}
+
+	if c.info != nil {
+		c.info.UpdateCircuitBreakerState(c.routerName, stateStandby)
+	}
+
+	return c, nil
 }
 
 func (c *circuitBreaker) GetTracingInformation() (string, ext.SpanKindEnum) {
@@ -56,5 +160,117 @@ func (c *circuitBreaker) GetTracingInformation() (string, ext.SpanKindEnum) {
 }
 
 func (c *circuitBreaker) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
-	c.circuitBreaker.ServeHTTP(rw, req)
+	if c.activateFallback() {
+		c.fallback.ServeHTTP(rw, req)
+		return
+	}
+
+	start := c.now()
+	p := utils.NewProxyWriter(rw)
+
+	c.next.ServeHTTP(p, req)
+
+	c.metrics.Record(p.StatusCode(), c.now().Sub(start))
+
+	// The expression is actually evaluated at most once per check period.
+	c.checkAndSet()
+}
+
+// activateFallback updates the state of the circuit breaker, and tells whether the request should be served by the fallback.
+func (c *circuitBreaker) activateFallback() bool {
+	// Quick check with a read lock, optimized for the standby state.
+	c.mu.RLock()
+	standby := c.state == stateStandby
+	c.mu.RUnlock()
+	if standby {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+
+	switch c.state {
+	case stateTripped:
+		if now.Before(c.until) {
+			return true
+		}
+
+		c.setState(stateRecovering, now.Add(c.recoveryDuration))
+		c.start = now
+		c.allowed = 0
+		c.denied = 0
+
+		fallthrough
+	case stateRecovering:
+		if now.After(c.until) {
+			c.setState(stateStandby, now)
+			return false
+		}
+
+		return !c.allowRequest(now)
+	}
+
+	return false
+}
+
+// allowRequest tells whether a request is let through while recovering,
+// the ratio of allowed requests growing linearly from 0 to the probe ratio.
+func (c *circuitBreaker) allowRequest(now time.Time) bool {
+	target := c.probeRatio * float64(now.Sub(c.start)) / float64(c.recoveryDuration)
+
+	// Whether the target ratio would still be satisfied if this request is allowed.
+	if float64(c.allowed+1)/float64(c.allowed+c.denied+1) < target {
+		c.allowed++
+		return true
+	}
+
+	c.denied++
+	return false
+}
+
+// checkAndSet trips the circuit breaker when the expression matches.
+func (c *circuitBreaker) checkAndSet() {
+	c.mu.RLock()
+	timeToCheck := c.now().After(c.lastCheck)
+	c.mu.RUnlock()
+	if !timeToCheck {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+
+	// Another request could have checked the expression in the meantime.
+	if !now.After(c.lastCheck) {
+		return
+	}
+	c.lastCheck = now.Add(checkPeriod)
+
+	if c.state == stateTripped || !c.condition(c) {
+		return
+	}
+
+	c.setState(stateTripped, now.Add(c.fallbackDuration))
+	c.metrics.Reset()
+}
+
+// setState sets the state of the circuit breaker until the given time, and reports the transition.
+// It is the responsibility of the caller to hold the lock.
+func (c *circuitBreaker) setState(state string, until time.Time) {
+	c.logger.Debugf("Circuit breaker state changed from %s to %s", c.state, state)
+
+	c.state = state
+	c.until = until
+
+	if c.transitions != nil {
+		c.transitions.With("middleware", c.name, "router", c.routerName, "state", state).Add(1)
+	}
+
+	if c.info != nil {
+		c.info.UpdateCircuitBreakerState(c.routerName, state)
+	}
 }
@@ -0,0 +1,269 @@
+package circuitbreaker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/testhelpers"
+)
+
+type fakeServiceBuilder map[string]http.Handler
+
+func (f fakeServiceBuilder) BuildHTTP(_ context.Context, serviceName string) (http.Handler, error) {
+	handler, ok := f[serviceName]
+	if !ok {
+		return nil, fmt.Errorf("service %q not found", serviceName)
+	}
+	return handler, nil
+}
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		config    dynamic.CircuitBreaker
+		expectErr bool
+	}{
+		{
+			desc:   "defaults",
+			config: dynamic.CircuitBreaker{Expression: "NetworkErrorRatio() > 0.5"},
+		},
+		{
+			desc: "all options",
+			config: dynamic.CircuitBreaker{
+				Expression:       "LatencyAtQuantileMS(50.0) > 100 || ResponseCodeRatio(500, 600, 0, 600) > 0.25",
+				FallbackDuration: ptypes.Duration(time.Second),
+				RecoveryDuration: ptypes.Duration(time.Minute),
+				ProbeRatio:       1,
+				FallbackService:  "fallback",
+			},
+		},
+		{
+			desc:      "invalid expression",
+			config:    dynamic.CircuitBreaker{Expression: "NetworkErrorRatio() > "},
+			expectErr: true,
+		},
+		{
+			desc:      "unknown function",
+			config:    dynamic.CircuitBreaker{Expression: "Foo() > 0.5"},
+			expectErr: true,
+		},
+		{
+			desc:      "int compared to a float",
+			config:    dynamic.CircuitBreaker{Expression: "LatencyAtQuantileMS(50.0) > 0.5"},
+			expectErr: true,
+		},
+		{
+			desc: "negative fallback duration",
+			config: dynamic.CircuitBreaker{
+				Expression:       "NetworkErrorRatio() > 0.5",
+				FallbackDuration: ptypes.Duration(-time.Second),
+			},
+			expectErr: true,
+		},
+		{
+			desc: "negative recovery duration",
+			config: dynamic.CircuitBreaker{
+				Expression:       "NetworkErrorRatio() > 0.5",
+				RecoveryDuration: ptypes.Duration(-time.Second),
+			},
+			expectErr: true,
+		},
+		{
+			desc: "probe ratio greater than 1",
+			config: dynamic.CircuitBreaker{
+				Expression: "NetworkErrorRatio() > 0.5",
+				ProbeRatio: 1.5,
+			},
+			expectErr: true,
+		},
+		{
+			desc: "negative probe ratio",
+			config: dynamic.CircuitBreaker{
+				Expression: "NetworkErrorRatio() > 0.5",
+				ProbeRatio: -0.5,
+			},
+			expectErr: true,
+		},
+		{
+			desc: "unknown fallback service",
+			config: dynamic.CircuitBreaker{
+				Expression:      "NetworkErrorRatio() > 0.5",
+				FallbackService: "unknown",
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+			builder := fakeServiceBuilder{"fallback": next}
+
+			_, err := New(context.Background(), next, test.config, builder, nil, nil, "cb", "router")
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestCircuitBreaker_transitions(t *testing.T) {
+	failing := true
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if failing {
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = rw.Write([]byte("next"))
+	})
+
+	fallback := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = rw.Write([]byte("fallback"))
+	})
+
+	config := dynamic.CircuitBreaker{
+		Expression:       "ResponseCodeRatio(500, 600, 0, 600) > 0.5",
+		FallbackDuration: ptypes.Duration(10 * time.Second),
+		RecoveryDuration: ptypes.Duration(10 * time.Second),
+		ProbeRatio:       1,
+		FallbackService:  "fallback",
+	}
+
+	info := &runtime.MiddlewareInfo{}
+
+	handler, err := New(context.Background(), next, config, fakeServiceBuilder{"fallback": fallback}, nil, info, "cb", "router")
+	require.NoError(t, err)
+
+	assert.Equal(t, stateStandby, info.GetCircuitBreakerStates()["router"])
+
+	counter := &testhelpers.CollectingCounter{}
+	start := time.Now()
+	now := start
+
+	cb := handler.(*circuitBreaker)
+	cb.transitions = counter
+	cb.now = func() time.Time { return now }
+
+	serve := func() string {
+		rw := httptest.NewRecorder()
+		cb.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/", nil))
+		return rw.Body.String()
+	}
+
+	// The failing request trips the circuit breaker.
+	assert.Equal(t, "", serve())
+	assert.Equal(t, stateTripped, info.GetCircuitBreakerStates()["router"])
+	assert.Equal(t, []string{"middleware", "cb", "router", "router", "state", stateTripped}, counter.LastLabelValues)
+
+	now = start.Add(5 * time.Second)
+	failing = false
+	assert.Equal(t, "fallback", serve())
+	assert.Equal(t, stateTripped, info.GetCircuitBreakerStates()["router"])
+
+	// At the beginning of the recovery, no request is let through.
+	now = start.Add(10 * time.Second)
+	assert.Equal(t, "fallback", serve())
+	assert.Equal(t, stateRecovering, info.GetCircuitBreakerStates()["router"])
+	assert.Equal(t, []string{"middleware", "cb", "router", "router", "state", stateRecovering}, counter.LastLabelValues)
+
+	// Halfway through the recovery, about half of the requests are let through.
+	now = start.Add(15 * time.Second)
+	var passed int
+	for i := 0; i < 10; i++ {
+		if serve() == "next" {
+			passed++
+		}
+	}
+	assert.Equal(t, 5, passed)
+	assert.Equal(t, stateRecovering, info.GetCircuitBreakerStates()["router"])
+
+	// Once recovered, all the requests are let through.
+	now = start.Add(21 * time.Second)
+	assert.Equal(t, "next", serve())
+	assert.Equal(t, stateStandby, info.GetCircuitBreakerStates()["router"])
+	assert.Equal(t, []string{"middleware", "cb", "router", "router", "state", stateStandby}, counter.LastLabelValues)
+	assert.Equal(t, float64(3), counter.CounterValue)
+}
+
+func TestCircuitBreaker_tripWhileRecovering(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusBadGateway)
+	})
+
+	config := dynamic.CircuitBreaker{
+		Expression: "NetworkErrorRatio() > 0.5",
+		ProbeRatio: 1,
+	}
+
+	info := &runtime.MiddlewareInfo{}
+
+	handler, err := New(context.Background(), next, config, nil, nil, info, "cb", "router")
+	require.NoError(t, err)
+
+	start := time.Now()
+	now := start
+
+	cb := handler.(*circuitBreaker)
+	cb.now = func() time.Time { return now }
+
+	serve := func() int {
+		rw := httptest.NewRecorder()
+		cb.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/", nil))
+		return rw.Code
+	}
+
+	assert.Equal(t, http.StatusBadGateway, serve())
+	assert.Equal(t, stateTripped, info.GetCircuitBreakerStates()["router"])
+
+	// Without fallback service, the rejected requests get a 503.
+	now = start.Add(defaultFallbackDuration / 2)
+	assert.Equal(t, http.StatusServiceUnavailable, serve())
+
+	now = start.Add(defaultFallbackDuration)
+	assert.Equal(t, http.StatusServiceUnavailable, serve())
+	assert.Equal(t, stateRecovering, info.GetCircuitBreakerStates()["router"])
+
+	// The first request let through while recovering trips the circuit breaker again.
+	now = start.Add(defaultFallbackDuration + defaultRecoveryDuration/2)
+	codes := map[int]int{}
+	for i := 0; i < 4; i++ {
+		codes[serve()]++
+	}
+	assert.Equal(t, map[int]int{http.StatusBadGateway: 1, http.StatusServiceUnavailable: 3}, codes)
+	assert.Equal(t, stateTripped, info.GetCircuitBreakerStates()["router"])
+}
+
+func TestCircuitBreaker_perRouter(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusBadGateway)
+	})
+
+	config := dynamic.CircuitBreaker{Expression: "NetworkErrorRatio() > 0.5"}
+
+	// The routers using the middleware share its info, but each gets its own circuit breaker.
+	info := &runtime.MiddlewareInfo{}
+
+	tripped, err := New(context.Background(), next, config, nil, nil, info, "cb", "router1")
+	require.NoError(t, err)
+
+	_, err = New(context.Background(), next, config, nil, nil, info, "cb", "router2")
+	require.NoError(t, err)
+
+	tripped.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, map[string]string{"router1": stateTripped, "router2": stateStandby}, info.GetCircuitBreakerStates())
+}
@@ -0,0 +1,181 @@
+package circuitbreaker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/vulcand/predicate"
+)
+
+// hpredicate tells whether the circuit breaker condition matches.
+type hpredicate func(*circuitBreaker) bool
+
+type (
+	toInt     func(c *circuitBreaker) int
+	toFloat64 func(c *circuitBreaker) float64
+)
+
+// parseExpression parses the circuit breaker expression into a predicate,
+// supporting the same functions and operators as the oxy circuit breaker.
+func parseExpression(in string) (hpredicate, error) {
+	p, err := predicate.NewParser(predicate.Def{
+		Operators: predicate.Operators{
+			AND: and,
+			OR:  or,
+			EQ:  eq,
+			NEQ: neq,
+			LT:  lt,
+			LE:  le,
+			GT:  gt,
+			GE:  ge,
+		},
+		Functions: map[string]interface{}{
+			"LatencyAtQuantileMS": latencyAtQuantile,
+			"NetworkErrorRatio":   networkErrorRatio,
+			"ResponseCodeRatio":   responseCodeRatio,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := p.Parse(in)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, ok := out.(hpredicate)
+	if !ok {
+		return nil, fmt.Errorf("expected predicate, got %T", out)
+	}
+	return pr, nil
+}
+
+func latencyAtQuantile(quantile float64) toInt {
+	return func(c *circuitBreaker) int {
+		h, err := c.metrics.LatencyHistogram()
+		if err != nil {
+			c.logger.Errorf("Failed to get latency histogram: %v", err)
+			return 0
+		}
+		return int(h.LatencyAtQuantile(quantile) / time.Millisecond)
+	}
+}
+
+func networkErrorRatio() toFloat64 {
+	return func(c *circuitBreaker) float64 {
+		return c.metrics.NetworkErrorRatio()
+	}
+}
+
+func responseCodeRatio(startA, endA, startB, endB int) toFloat64 {
+	return func(c *circuitBreaker) float64 {
+		return c.metrics.ResponseCodeRatio(startA, endA, startB, endB)
+	}
+}
+
+func or(fns ...hpredicate) hpredicate {
+	return func(c *circuitBreaker) bool {
+		for _, fn := range fns {
+			if fn(c) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func and(fns ...hpredicate) hpredicate {
+	return func(c *circuitBreaker) bool {
+		for _, fn := range fns {
+			if !fn(c) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func not(p hpredicate) hpredicate {
+	return func(c *circuitBreaker) bool {
+		return !p(c)
+	}
+}
+
+func eq(m interface{}, value interface{}) (hpredicate, error) {
+	switch mapper := m.(type) {
+	case toInt:
+		return compareInt(mapper, value, func(a, b int) bool { return a == b })
+	case toFloat64:
+		return compareFloat64(mapper, value, func(a, b float64) bool { return a == b })
+	}
+	return nil, fmt.Errorf("eq: unsupported argument: %T", m)
+}
+
+func neq(m interface{}, value interface{}) (hpredicate, error) {
+	p, err := eq(m, value)
+	if err != nil {
+		return nil, err
+	}
+	return not(p), nil
+}
+
+func lt(m interface{}, value interface{}) (hpredicate, error) {
+	switch mapper := m.(type) {
+	case toInt:
+		return compareInt(mapper, value, func(a, b int) bool { return a < b })
+	case toFloat64:
+		return compareFloat64(mapper, value, func(a, b float64) bool { return a < b })
+	}
+	return nil, fmt.Errorf("lt: unsupported argument: %T", m)
+}
+
+func le(m interface{}, value interface{}) (hpredicate, error) {
+	switch mapper := m.(type) {
+	case toInt:
+		return compareInt(mapper, value, func(a, b int) bool { return a <= b })
+	case toFloat64:
+		return compareFloat64(mapper, value, func(a, b float64) bool { return a <= b })
+	}
+	return nil, fmt.Errorf("le: unsupported argument: %T", m)
+}
+
+func gt(m interface{}, value interface{}) (hpredicate, error) {
+	switch mapper := m.(type) {
+	case toInt:
+		return compareInt(mapper, value, func(a, b int) bool { return a > b })
+	case toFloat64:
+		return compareFloat64(mapper, value, func(a, b float64) bool { return a > b })
+	}
+	return nil, fmt.Errorf("gt: unsupported argument: %T", m)
+}
+
+func ge(m interface{}, value interface{}) (hpredicate, error) {
+	switch mapper := m.(type) {
+	case toInt:
+		return compareInt(mapper, value, func(a, b int) bool { return a >= b })
+	case toFloat64:
+		return compareFloat64(mapper, value, func(a, b float64) bool { return a >= b })
+	}
+	return nil, fmt.Errorf("ge: unsupported argument: %T", m)
+}
+
+func compareInt(m toInt, val interface{}, cmp func(a, b int) bool) (hpredicate, error) {
+	value, ok := val.(int)
+	if !ok {
+		return nil, fmt.Errorf("expected int, got %T", val)
+	}
+	return func(c *circuitBreaker) bool {
+		return cmp(m(c), value)
+	}, nil
+}
+
+func compareFloat64(m toFloat64, val interface{}, cmp func(a, b float64) bool) (hpredicate, error) {
+	value, ok := val.(float64)
+	if !ok {
+		return nil, fmt.Errorf("expected float64, got %T", val)
+	}
+	return func(c *circuitBreaker) bool {
+		return cmp(m(c), value)
+	}, nil
+}
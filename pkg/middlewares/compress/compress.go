@@ -1,13 +1,18 @@
 package compress
 
 import (
-	"compress/gzip"
+	"bufio"
 	"context"
+	"errors"
+	"fmt"
 	"mime"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/opentracing/opentracing-go/ext"
-	"github.com/traefik/gziphandler"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/middlewares"
@@ -18,17 +23,29 @@ const (
 	typeName = "Compress"
 )
 
+// defaultMinSize is the default minimum size of the compressed response bodies.
+// 1400 bytes is about the payload of a single packet, there is nothing to gain in compressing smaller bodies.
+const defaultMinSize = 1400
+
 // Compress is a middleware that allows to compress the response.
 type compress struct {
-	next     http.Handler
-	name     string
-	excludes []string
+	next      http.Handler
+	name      string
+	excludes  []string
+	includes  []string
+	minSize   int
+	encodings []string
+	pools     map[string]*sync.Pool
 }
 
 // New creates a new compress middleware.
 func New(ctx context.Context, next http.Handler, conf dynamic.Compress, name string) (http.Handler, error) {
 	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
 
+	if len(conf.ExcludedContentTypes) > 0 && len(conf.IncludedContentTypes) > 0 {
+		return nil, errors.New("excludedContentTypes and includedContentTypes options are mutually exclusive")
+	}
+
 	excludes := []string{"application/grpc"}
 	for _, v := range conf.ExcludedContentTypes {
 		mediaType, _, err := mime.ParseMediaType(v)
@@ -39,7 +56,61 @@ func New(ctx context.Context, next http.Handler, conf dynamic.Compress, name str
 		excludes = append(excludes, mediaType)
 	}
 
-	return &compress{next: next, name: name, excludes: excludes}, nil
+	var includes []string
+	for _, v := range conf.IncludedContentTypes {
+		mediaType, _, err := mime.ParseMediaType(v)
+		if err != nil {
+			return nil, err
+		}
+
+		includes = append(includes, mediaType)
+	}
+
+	if conf.MinResponseBodyBytes < 0 {
+		return nil, fmt.Errorf("minResponseBodyBytes must be positive: %d", conf.MinResponseBodyBytes)
+	}
+
+	minSize := conf.MinResponseBodyBytes
+	if minSize == 0 {
+		minSize = defaultMinSize
+	}
+
+	encodings := defaultEncodings
+	if len(conf.Encodings) > 0 {
+		encodings = nil
+		for _, encoding := range conf.Encodings {
+			encodings = append(encodings, strings.ToLower(strings.TrimSpace(encoding)))
+		}
+	}
+
+	levels := map[string]int{
+		gzipName:   conf.GzipLevel,
+		brotliName: conf.BrotliLevel,
+		zstdName:   conf.ZstdLevel,
+	}
+
+	pools := make(map[string]*sync.Pool)
+	for _, encoding := range encodings {
+		if _, ok := pools[encoding]; ok {
+			continue
+		}
+
+		pool, err := newEncoderPool(encoding, levels[encoding])
+		if err != nil {
+			return nil, err
+		}
+		pools[encoding] = pool
+	}
+
+	return &compress{
+		next:      next,
+		name:      name,
+		excludes:  excludes,
+		includes:  includes,
+		minSize:   minSize,
+		encodings: encodings,
+		pools:     pools,
+	}, nil
 }
 
 func (c *compress) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
@@ -50,9 +121,23 @@ func (c *compress) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 
 	if contains(c.excludes, mediaType) {
 		c.next.ServeHTTP(rw, req)
-	} else {
-		ctx := middlewares.GetLoggerCtx(req.Context(), c.name, typeName)
-		c.gzipHandler(ctx).ServeHTTP(rw, req)
+		return
+	}
+
+	rw.Header().Add("Vary", "Accept-Encoding")
+
+	encoding := negotiateEncoding(req.Header.Values("Accept-Encoding"), c.encodings)
+	if encoding == "" {
+		c.next.ServeHTTP(rw, req)
+		return
+	}
+
+	writer := &responseWriter{rw: rw, middleware: c, encoding: encoding}
+	c.next.ServeHTTP(writer, req)
+
+	if err := writer.close(); err != nil {
+		logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), c.name, typeName))
+		logger.Debugf("Error compressing response: %v", err)
 	}
 }
 
@@ -60,16 +145,228 @@ func (c *compress) GetTracingInformation() (string, ext.SpanKindEnum) {
 	return c.name, tracing.SpanKindNoneEnum
 }
 
-func (c *compress) gzipHandler(ctx context.Context) http.Handler {
-	wrapper, err := gziphandler.GzipHandlerWithOpts(
-		gziphandler.ContentTypeExceptions(c.excludes),
-		gziphandler.CompressionLevel(gzip.DefaultCompression),
-		gziphandler.MinSize(gziphandler.DefaultMinSize))
+// compressible tells whether a response with the given content type is to be compressed.
+func (c *compress) compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
 	if err != nil {
-		log.FromContext(ctx).Error(err)
+		// The responses with an invalid content type are only compressed when no content types are included.
+		return len(c.includes) == 0
+	}
+
+	if len(c.includes) > 0 {
+		return contains(c.includes, mediaType)
+	}
+	return !contains(c.excludes, mediaType)
+}
+
+// responseWriter compresses the response body with the negotiated encoding.
+// It holds back the headers and the beginning of the body until it knows whether the response is to be compressed,
+// that is until the body reaches the minimum size, or the response is complete or flushed.
+type responseWriter struct {
+	rw         http.ResponseWriter
+	middleware *compress
+	encoding   string
+
+	statusCode  int
+	wroteHeader bool
+	buf         []byte
+
+	// Whether it is decided to compress or not the response.
+	decided bool
+	encoder encoder
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.rw.Header()
+}
+
+func (w *responseWriter) WriteHeader(statusCode int) {
+	if w.wroteHeader {
+		return
+	}
+
+	// 1xx informational responses are sent right away.
+	if statusCode >= http.StatusContinue && statusCode < http.StatusOK && statusCode != http.StatusSwitchingProtocols {
+		w.rw.WriteHeader(statusCode)
+		return
+	}
+
+	w.wroteHeader = true
+	w.statusCode = statusCode
+
+	if !w.candidate() {
+		w.sendPlain()
+	}
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.decided {
+		if w.encoder != nil {
+			return w.encoder.Write(p)
+		}
+		return w.rw.Write(p)
+	}
+
+	w.buf = append(w.buf, p...)
+	if len(w.buf) < w.middleware.minSize {
+		return len(p), nil
+	}
+
+	if err := w.decide(); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush sends the response to the client, compressed or not depending on the size of the body written so far.
+func (w *responseWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if !w.decided {
+		if err := w.decide(); err != nil {
+			return
+		}
+	}
+
+	if w.encoder != nil {
+		if err := w.encoder.Flush(); err != nil {
+			return
+		}
+	}
+
+	if flusher, ok := w.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack hijacks the connection.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.rw.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T is not a http.Hijacker", w.rw)
+	}
+	return hijacker.Hijack()
+}
+
+// candidate tells whether the response could be compressed, given its status code and headers.
+func (w *responseWriter) candidate() bool {
+	switch w.statusCode {
+	case http.StatusSwitchingProtocols, http.StatusNoContent, http.StatusPartialContent, http.StatusNotModified:
+		return false
+	}
+
+	header := w.rw.Header()
+
+	// Already encoded responses are never compressed again.
+	if encoding := header.Get("Content-Encoding"); encoding != "" && encoding != identityName {
+		return false
+	}
+
+	if header.Get("Content-Range") != "" {
+		return false
+	}
+
+	if contentLength, err := strconv.Atoi(header.Get("Content-Length")); err == nil && contentLength < w.middleware.minSize {
+		return false
+	}
+
+	if contentType := header.Get("Content-Type"); contentType != "" && !w.middleware.compressible(contentType) {
+		return false
+	}
+
+	return true
+}
+
+// decide compresses the response if the body held back is large enough and its content type is compressible,
+// and sends the headers and the body held back.
+func (w *responseWriter) decide() error {
+	if len(w.buf) < w.middleware.minSize {
+		return w.sendPlain()
+	}
+
+	header := w.rw.Header()
+
+	// Detects the content type as the http.ResponseWriter would, unless it is explicitly suppressed.
+	if _, ok := header["Content-Type"]; !ok {
+		header.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+
+	if !w.middleware.compressible(header.Get("Content-Type")) {
+		return w.sendPlain()
+	}
+
+	return w.sendCompressed()
+}
+
+// sendPlain sends the response uncompressed.
+func (w *responseWriter) sendPlain() error {
+	w.decided = true
+
+	w.rw.WriteHeader(w.statusCode)
+
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	_, err := w.rw.Write(w.buf)
+	w.buf = nil
+
+	return err
+}
+
+// sendCompressed sends the response compressed with the negotiated encoding.
+func (w *responseWriter) sendCompressed() error {
+	w.decided = true
+
+	header := w.rw.Header()
+	header.Set("Content-Encoding", w.encoding)
+
+	// The size of the compressed body is unknown, and ranges refer to the uncompressed body.
+	header.Del("Content-Length")
+	header.Del("Accept-Ranges")
+	if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		header.Set("ETag", "W/"+etag)
 	}
 
-	return wrapper(c.next)
+	w.rw.WriteHeader(w.statusCode)
+
+	w.encoder = w.middleware.pools[w.encoding].Get().(encoder)
+	w.encoder.Reset(w.rw)
+
+	_, err := w.encoder.Write(w.buf)
+	w.buf = nil
+
+	return err
+}
+
+// close sends the end of the response, and releases the encoder.
+func (w *responseWriter) close() error {
+	if !w.wroteHeader {
+		return nil
+	}
+
+	if !w.decided {
+		return w.sendPlain()
+	}
+
+	if w.encoder == nil {
+		return nil
+	}
+
+	err := w.encoder.Close()
+
+	// The encoder is reset so that it does not retain the response writer while in the pool.
+	w.encoder.Reset(nil)
+	w.middleware.pools[w.encoding].Put(w.encoder)
+	w.encoder = nil
+
+	return err
 }
 
 func contains(values []string, val string) bool {
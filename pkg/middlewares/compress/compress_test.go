@@ -1,15 +1,19 @@
 package compress
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"github.com/traefik/gziphandler"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/testhelpers"
 )
@@ -26,13 +30,14 @@ func TestShouldCompressWhenNoContentEncodingHeader(t *testing.T) {
 	req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
 	req.Header.Add(acceptEncodingHeader, gzipValue)
 
-	baseBody := generateBytes(gziphandler.DefaultMinSize)
+	baseBody := generateBytes(defaultMinSize)
 
 	next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
 		_, err := rw.Write(baseBody)
 		assert.NoError(t, err)
 	})
-	handler := &compress{next: next}
+	handler, err := New(context.Background(), next, dynamic.Compress{}, "test")
+	require.NoError(t, err)
 
 	rw := httptest.NewRecorder()
 	handler.ServeHTTP(rw, req)
@@ -49,7 +54,7 @@ func TestShouldNotCompressWhenContentEncodingHeader(t *testing.T) {
 	req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
 	req.Header.Add(acceptEncodingHeader, gzipValue)
 
-	fakeCompressedBody := generateBytes(gziphandler.DefaultMinSize)
+	fakeCompressedBody := generateBytes(defaultMinSize)
 	next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
 		rw.Header().Add(contentEncodingHeader, gzipValue)
 		rw.Header().Add(varyHeader, acceptEncodingHeader)
@@ -58,7 +63,8 @@ func TestShouldNotCompressWhenContentEncodingHeader(t *testing.T) {
 			http.Error(rw, err.Error(), http.StatusInternalServerError)
 		}
 	})
-	handler := &compress{next: next}
+	handler, err := New(context.Background(), next, dynamic.Compress{}, "test")
+	require.NoError(t, err)
 
 	rw := httptest.NewRecorder()
 	handler.ServeHTTP(rw, req)
@@ -72,14 +78,15 @@ func TestShouldNotCompressWhenContentEncodingHeader(t *testing.T) {
 func TestShouldNotCompressWhenNoAcceptEncodingHeader(t *testing.T) {
 	req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
 
-	fakeBody := generateBytes(gziphandler.DefaultMinSize)
+	fakeBody := generateBytes(defaultMinSize)
 	next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
 		_, err := rw.Write(fakeBody)
 		if err != nil {
 			http.Error(rw, err.Error(), http.StatusInternalServerError)
 		}
 	})
-	handler := &compress{next: next}
+	handler, err := New(context.Background(), next, dynamic.Compress{}, "test")
+	require.NoError(t, err)
 
 	rw := httptest.NewRecorder()
 	handler.ServeHTTP(rw, req)
@@ -89,7 +96,7 @@ func TestShouldNotCompressWhenNoAcceptEncodingHeader(t *testing.T) {
 }
 
 func TestShouldNotCompressWhenSpecificContentType(t *testing.T) {
-	baseBody := generateBytes(gziphandler.DefaultMinSize)
+	baseBody := generateBytes(defaultMinSize)
 
 	testCases := []struct {
 		desc            string
@@ -190,8 +197,10 @@ func TestIntegrationShouldNotCompress(t *testing.T) {
 
 	for _, test := range testCases {
 		t.Run(test.name, func(t *testing.T) {
-			compress := &compress{next: test.handler}
-			ts := httptest.NewServer(compress)
+			handler, err := New(context.Background(), test.handler, dynamic.Compress{}, "test")
+			require.NoError(t, err)
+
+			ts := httptest.NewServer(handler)
 			defer ts.Close()
 
 			req := testhelpers.MustNewRequest(http.MethodGet, ts.URL, nil)
@@ -223,7 +232,8 @@ func TestShouldWriteHeaderWhenFlush(t *testing.T) {
 			http.Error(rw, err.Error(), http.StatusInternalServerError)
 		}
 	})
-	handler := &compress{next: next}
+	handler, err := New(context.Background(), next, dynamic.Compress{}, "test")
+	require.NoError(t, err)
 	ts := httptest.NewServer(handler)
 	defer ts.Close()
 
@@ -272,8 +282,10 @@ func TestIntegrationShouldCompress(t *testing.T) {
 
 	for _, test := range testCases {
 		t.Run(test.name, func(t *testing.T) {
-			compress := &compress{next: test.handler}
-			ts := httptest.NewServer(compress)
+			handler, err := New(context.Background(), test.handler, dynamic.Compress{}, "test")
+			require.NoError(t, err)
+
+			ts := httptest.NewServer(handler)
 			defer ts.Close()
 
 			req := testhelpers.MustNewRequest(http.MethodGet, ts.URL, nil)
@@ -296,6 +308,290 @@ func TestIntegrationShouldCompress(t *testing.T) {
 	}
 }
 
+func TestShouldCompressWithNegotiatedEncoding(t *testing.T) {
+	baseBody := bytes.Repeat([]byte("<p>foo</p>"), 1000)
+
+	testCases := []struct {
+		desc             string
+		conf             dynamic.Compress
+		acceptEncoding   string
+		expectedEncoding string
+	}{
+		{
+			desc:             "zstd",
+			acceptEncoding:   "gzip, br, zstd",
+			expectedEncoding: "zstd",
+		},
+		{
+			desc:             "brotli",
+			acceptEncoding:   "gzip, br",
+			expectedEncoding: "br",
+		},
+		{
+			desc:             "gzip",
+			acceptEncoding:   "gzip;q=1, br;q=0.5",
+			expectedEncoding: "gzip",
+		},
+		{
+			desc: "configured encodings and levels",
+			conf: dynamic.Compress{
+				Encodings:   []string{"br", "gzip"},
+				GzipLevel:   9,
+				BrotliLevel: 11,
+				ZstdLevel:   22,
+			},
+			acceptEncoding:   "gzip, br, zstd",
+			expectedEncoding: "br",
+		},
+		{
+			desc:           "no supported encoding",
+			acceptEncoding: "deflate",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				rw.Header().Set(contentTypeHeader, "text/html")
+				rw.Header().Set("Content-Length", strconv.Itoa(len(baseBody)))
+				rw.Header().Set("ETag", `"foo"`)
+
+				_, err := rw.Write(baseBody)
+				assert.NoError(t, err)
+			})
+
+			handler, err := New(context.Background(), next, test.conf, "test")
+			require.NoError(t, err)
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+			req.Header.Set(acceptEncodingHeader, test.acceptEncoding)
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			assert.Equal(t, test.expectedEncoding, rw.Header().Get(contentEncodingHeader))
+			assert.Equal(t, acceptEncodingHeader, rw.Header().Get(varyHeader))
+
+			if test.expectedEncoding == "" {
+				assert.Equal(t, strconv.Itoa(len(baseBody)), rw.Header().Get("Content-Length"))
+				assert.Equal(t, `"foo"`, rw.Header().Get("ETag"))
+				assert.Equal(t, baseBody, rw.Body.Bytes())
+				return
+			}
+
+			assert.Empty(t, rw.Header().Get("Content-Length"))
+			assert.Equal(t, `W/"foo"`, rw.Header().Get("ETag"))
+			assert.Less(t, rw.Body.Len(), len(baseBody))
+			assert.Equal(t, baseBody, decode(t, test.expectedEncoding, rw.Body.Bytes()))
+		})
+	}
+}
+
+func TestShouldCompressDependingOnSize(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		conf          dynamic.Compress
+		bodySize      int
+		contentLength bool
+		expected      bool
+	}{
+		{
+			desc:     "body smaller than the default minimum size",
+			bodySize: defaultMinSize - 1,
+		},
+		{
+			desc:     "body as large as the default minimum size",
+			bodySize: defaultMinSize,
+			expected: true,
+		},
+		{
+			desc:          "Content-Length smaller than the minimum size",
+			bodySize:      defaultMinSize - 1,
+			contentLength: true,
+		},
+		{
+			desc:     "body smaller than the configured minimum size",
+			conf:     dynamic.Compress{MinResponseBodyBytes: 4096},
+			bodySize: 4095,
+		},
+		{
+			desc:     "body larger than the configured minimum size",
+			conf:     dynamic.Compress{MinResponseBodyBytes: 10},
+			bodySize: 100,
+			expected: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			baseBody := generateBytes(test.bodySize)
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				if test.contentLength {
+					rw.Header().Set("Content-Length", strconv.Itoa(len(baseBody)))
+				}
+
+				// Writes the body byte by byte, so that the decision is taken on the body written so far.
+				for _, b := range baseBody {
+					_, err := rw.Write([]byte{b})
+					assert.NoError(t, err)
+				}
+			})
+
+			handler, err := New(context.Background(), next, test.conf, "test")
+			require.NoError(t, err)
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+			req.Header.Set(acceptEncodingHeader, gzipValue)
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			if !test.expected {
+				assert.Empty(t, rw.Header().Get(contentEncodingHeader))
+				assert.Equal(t, baseBody, rw.Body.Bytes())
+				return
+			}
+
+			assert.Equal(t, gzipValue, rw.Header().Get(contentEncodingHeader))
+			assert.Equal(t, baseBody, decode(t, gzipValue, rw.Body.Bytes()))
+		})
+	}
+}
+
+func TestShouldCompressIncludedContentTypes(t *testing.T) {
+	baseBody := generateBytes(defaultMinSize)
+
+	testCases := []struct {
+		desc        string
+		contentType string
+		expected    bool
+	}{
+		{
+			desc:        "included content type",
+			contentType: "application/json; charset=utf-8",
+			expected:    true,
+		},
+		{
+			desc:        "not included content type",
+			contentType: "text/html",
+		},
+		{
+			desc:        "detected content type",
+			contentType: "",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				if test.contentType != "" {
+					rw.Header().Set(contentTypeHeader, test.contentType)
+				}
+
+				_, err := rw.Write(baseBody)
+				assert.NoError(t, err)
+			})
+
+			handler, err := New(context.Background(), next, dynamic.Compress{IncludedContentTypes: []string{"application/json"}}, "test")
+			require.NoError(t, err)
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+			req.Header.Set(acceptEncodingHeader, gzipValue)
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			if !test.expected {
+				assert.Empty(t, rw.Header().Get(contentEncodingHeader))
+				assert.Equal(t, baseBody, rw.Body.Bytes())
+				return
+			}
+
+			assert.Equal(t, gzipValue, rw.Header().Get(contentEncodingHeader))
+			assert.Equal(t, baseBody, decode(t, gzipValue, rw.Body.Bytes()))
+		})
+	}
+}
+
+func TestNew_invalidConfiguration(t *testing.T) {
+	testCases := []struct {
+		desc string
+		conf dynamic.Compress
+	}{
+		{
+			desc: "included and excluded content types",
+			conf: dynamic.Compress{
+				ExcludedContentTypes: []string{"text/event-stream"},
+				IncludedContentTypes: []string{"text/html"},
+			},
+		},
+		{
+			desc: "negative minimum size",
+			conf: dynamic.Compress{MinResponseBodyBytes: -1},
+		},
+		{
+			desc: "unsupported encoding",
+			conf: dynamic.Compress{Encodings: []string{"deflate"}},
+		},
+		{
+			desc: "invalid gzip level",
+			conf: dynamic.Compress{GzipLevel: 10},
+		},
+		{
+			desc: "invalid brotli level",
+			conf: dynamic.Compress{BrotliLevel: 12},
+		},
+		{
+			desc: "invalid zstd level",
+			conf: dynamic.Compress{ZstdLevel: 23},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(context.Background(), http.NotFoundHandler(), test.conf, "test")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func decode(t *testing.T, encoding string, body []byte) []byte {
+	t.Helper()
+
+	var reader io.Reader
+	switch encoding {
+	case "gzip":
+		gr, err := gzip.NewReader(bytes.NewReader(body))
+		require.NoError(t, err)
+		reader = gr
+	case "br":
+		reader = brotli.NewReader(bytes.NewReader(body))
+	case "zstd":
+		zr, err := zstd.NewReader(bytes.NewReader(body))
+		require.NoError(t, err)
+		defer zr.Close()
+		reader = zr
+	}
+
+	decoded, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	return decoded
+}
+
 func generateBytes(length int) []byte {
 	var value []byte
 	for i := 0; i < length; i++ {
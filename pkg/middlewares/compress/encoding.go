@@ -0,0 +1,196 @@
+package compress
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	gzipName     = "gzip"
+	brotliName   = "br"
+	zstdName     = "zstd"
+	identityName = "identity"
+)
+
+// defaultEncodings are the supported encodings, in the default server preference order.
+var defaultEncodings = []string{zstdName, brotliName, gzipName}
+
+const (
+	defaultGzipLevel   = 6
+	defaultBrotliLevel = 6
+	defaultZstdLevel   = 3
+)
+
+// encoder is a writer compressing the data written to it with a given encoding.
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// newEncoderPool creates a pool of the encoders of the given encoding and compression level.
+func newEncoderPool(encoding string, level int) (*sync.Pool, error) {
+	var newEncoder func() (encoder, error)
+
+	switch encoding {
+	case gzipName:
+		if level == 0 {
+			level = defaultGzipLevel
+		}
+		if level < gzip.BestSpeed || level > gzip.BestCompression {
+			return nil, fmt.Errorf("invalid gzip compression level %d, must be between %d and %d", level, gzip.BestSpeed, gzip.BestCompression)
+		}
+		newEncoder = func() (encoder, error) {
+			return gzip.NewWriterLevel(nil, level)
+		}
+
+	case brotliName:
+		if level == 0 {
+			level = defaultBrotliLevel
+		}
+		if level < 1 || level > brotli.BestCompression {
+			return nil, fmt.Errorf("invalid brotli compression level %d, must be between 1 and %d", level, brotli.BestCompression)
+		}
+		newEncoder = func() (encoder, error) {
+			return brotli.NewWriterLevel(nil, level), nil
+		}
+
+	case zstdName:
+		if level == 0 {
+			level = defaultZstdLevel
+		}
+		if level < 1 || level > 22 {
+			return nil, fmt.Errorf("invalid zstd compression level %d, must be between 1 and 22", level)
+		}
+		newEncoder = func() (encoder, error) {
+			// A single goroutine per encoder, as each one only compresses one response at a time.
+			return zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)), zstd.WithEncoderConcurrency(1))
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported encoding %q", encoding)
+	}
+
+	// Creates a first encoder to check the options.
+	enc, err := newEncoder()
+	if err != nil {
+		return nil, err
+	}
+
+	pool := &sync.Pool{
+		New: func() interface{} {
+			enc, _ := newEncoder()
+			return enc
+		},
+	}
+	pool.Put(enc)
+
+	return pool, nil
+}
+
+// parseAcceptEncoding returns the quality values of the content codings of the Accept-Encoding header values.
+// The codings with an invalid quality value are ignored.
+func parseAcceptEncoding(values []string) map[string]float64 {
+	codings := make(map[string]float64)
+
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			params := strings.Split(part, ";")
+
+			coding := strings.ToLower(strings.TrimSpace(params[0]))
+			if coding == "" {
+				continue
+			}
+
+			qValue, ok := parseQValue(params[1:])
+			if !ok {
+				continue
+			}
+
+			codings[coding] = qValue
+		}
+	}
+
+	// x-gzip is an alias of gzip.
+	if qValue, ok := codings["x-gzip"]; ok {
+		if _, exists := codings[gzipName]; !exists {
+			codings[gzipName] = qValue
+		}
+	}
+
+	return codings
+}
+
+// parseQValue returns the quality value of the parameters of a content coding, which defaults to 1.
+func parseQValue(params []string) (float64, bool) {
+	for _, param := range params {
+		nameValue := strings.SplitN(param, "=", 2)
+		if len(nameValue) != 2 || !strings.EqualFold(strings.TrimSpace(nameValue[0]), "q") {
+			continue
+		}
+
+		qValue, err := strconv.ParseFloat(strings.TrimSpace(nameValue[1]), 64)
+		if err != nil || qValue < 0 || qValue > 1 {
+			return 0, false
+		}
+		return qValue, true
+	}
+
+	return 1, true
+}
+
+// AcceptsEncoding tells whether the given Accept-Encoding header values accept the given encoding,
+// explicitly or through the wildcard, with a non-zero quality value.
+func AcceptsEncoding(acceptEncoding []string, encoding string) bool {
+	codings := parseAcceptEncoding(acceptEncoding)
+
+	qValue, ok := codings[strings.ToLower(encoding)]
+	if !ok {
+		qValue = codings["*"]
+	}
+
+	return qValue > 0
+}
+
+// negotiateEncoding returns the encoding to use for a request with the given Accept-Encoding header values,
+// or an empty string when the response is not to be compressed.
+// The encoding with the highest quality value is chosen, and the server preference order breaks the ties.
+func negotiateEncoding(acceptEncoding []string, encodings []string) string {
+	codings := parseAcceptEncoding(acceptEncoding)
+	if len(codings) == 0 {
+		return ""
+	}
+
+	wildcard, hasWildcard := codings["*"]
+
+	var best string
+	var bestQValue float64
+	for _, encoding := range encodings {
+		qValue, ok := codings[encoding]
+		if !ok {
+			if !hasWildcard {
+				continue
+			}
+			qValue = wildcard
+		}
+
+		if qValue > bestQValue {
+			best = encoding
+			bestQValue = qValue
+		}
+	}
+
+	// The response is not compressed when the client explicitly prefers the identity encoding.
+	if qValue, ok := codings[identityName]; ok && qValue > bestQValue {
+		return ""
+	}
+
+	return best
+}
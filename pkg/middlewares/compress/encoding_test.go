@@ -0,0 +1,157 @@
+package compress
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		acceptEncoding []string
+		encodings      []string
+		expected       string
+	}{
+		{
+			desc:      "no Accept-Encoding header",
+			encodings: defaultEncodings,
+			expected:  "",
+		},
+		{
+			desc:           "single encoding",
+			acceptEncoding: []string{"gzip"},
+			encodings:      defaultEncodings,
+			expected:       gzipName,
+		},
+		{
+			desc:           "server preference order",
+			acceptEncoding: []string{"gzip, deflate, br, zstd"},
+			encodings:      defaultEncodings,
+			expected:       zstdName,
+		},
+		{
+			desc:           "configured server preference order",
+			acceptEncoding: []string{"gzip, deflate, br, zstd"},
+			encodings:      []string{gzipName, brotliName},
+			expected:       gzipName,
+		},
+		{
+			desc:           "q-values",
+			acceptEncoding: []string{"zstd;q=0.5, br;q=0.8, gzip;q=0.9"},
+			encodings:      defaultEncodings,
+			expected:       gzipName,
+		},
+		{
+			desc:           "several header values",
+			acceptEncoding: []string{"zstd;q=0.5", "br ; q=1.0"},
+			encodings:      defaultEncodings,
+			expected:       brotliName,
+		},
+		{
+			desc:           "not acceptable encoding",
+			acceptEncoding: []string{"br;q=0, gzip"},
+			encodings:      []string{brotliName, gzipName},
+			expected:       gzipName,
+		},
+		{
+			desc:           "unsupported encoding",
+			acceptEncoding: []string{"deflate"},
+			encodings:      defaultEncodings,
+			expected:       "",
+		},
+		{
+			desc:           "wildcard",
+			acceptEncoding: []string{"*"},
+			encodings:      defaultEncodings,
+			expected:       zstdName,
+		},
+		{
+			desc:           "wildcard with excluded encoding",
+			acceptEncoding: []string{"zstd;q=0, *;q=0.5"},
+			encodings:      defaultEncodings,
+			expected:       brotliName,
+		},
+		{
+			desc:           "identity preferred",
+			acceptEncoding: []string{"identity, gzip;q=0.5"},
+			encodings:      defaultEncodings,
+			expected:       "",
+		},
+		{
+			desc:           "x-gzip alias",
+			acceptEncoding: []string{"x-gzip"},
+			encodings:      defaultEncodings,
+			expected:       gzipName,
+		},
+		{
+			desc:           "case insensitive",
+			acceptEncoding: []string{"GZIP;Q=0.5"},
+			encodings:      defaultEncodings,
+			expected:       gzipName,
+		},
+		{
+			desc:           "invalid q-value",
+			acceptEncoding: []string{"br;q=foo, gzip;q=2, zstd;q=0.1"},
+			encodings:      defaultEncodings,
+			expected:       zstdName,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, negotiateEncoding(test.acceptEncoding, test.encodings))
+		})
+	}
+}
+
+func TestAcceptsEncoding(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		acceptEncoding []string
+		expected       bool
+	}{
+		{
+			desc:           "accepted",
+			acceptEncoding: []string{"br, gzip;q=0.5"},
+			expected:       true,
+		},
+		{
+			desc:           "alias",
+			acceptEncoding: []string{"x-gzip"},
+			expected:       true,
+		},
+		{
+			desc:           "refused with a zero q-value",
+			acceptEncoding: []string{"gzip;q=0, br"},
+			expected:       false,
+		},
+		{
+			desc:           "accepted by the wildcard",
+			acceptEncoding: []string{"br", "*;q=0.1"},
+			expected:       true,
+		},
+		{
+			desc:           "refused despite the wildcard",
+			acceptEncoding: []string{"gzip;q=0, *"},
+			expected:       false,
+		},
+		{
+			desc:           "not listed",
+			acceptEncoding: []string{"br, zstd"},
+			expected:       false,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, AcceptsEncoding(test.acceptEncoding, gzipName))
+		})
+	}
+}
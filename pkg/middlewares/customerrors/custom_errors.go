@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -43,6 +44,7 @@ type customErrors struct {
 	backendHandler http.Handler
 	httpCodeRanges types.HTTPCodeRanges
 	backendQuery   string
+	pages          Pages
 }
 
 // New creates a new custom error pages middleware.
@@ -54,18 +56,32 @@ func New(ctx context.Context, next http.Handler, config dynamic.ErrorPage, servi
 		return nil, err
 	}
 
-	backend, err := serviceBuilder.BuildHTTP(ctx, config.Service)
-	if err != nil {
-		return nil, err
-	}
-
-	return &customErrors{
+	c := &customErrors{
 		name:           name,
 		next:           next,
-		backendHandler: backend,
 		httpCodeRanges: httpCodeRanges,
 		backendQuery:   config.Query,
-	}, nil
+	}
+
+	if len(config.Pages) > 0 {
+		if config.Service != "" {
+			return nil, errors.New("error pages cannot be served both from a service and from pages")
+		}
+
+		c.pages, err = NewPages(name, config.Pages)
+		if err != nil {
+			return nil, err
+		}
+
+		return c, nil
+	}
+
+	c.backendHandler, err = serviceBuilder.BuildHTTP(ctx, config.Service)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
 }
 
 func (c *customErrors) GetTracingInformation() (string, ext.SpanKindEnum) {
@@ -76,7 +92,7 @@ func (c *customErrors) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	ctx := middlewares.GetLoggerCtx(req.Context(), c.name, typeName)
 	logger := log.FromContext(ctx)
 
-	if c.backendHandler == nil {
+	if c.backendHandler == nil && len(c.pages) == 0 {
 		logger.Error("Error pages: no backend handler.")
 		tracing.SetErrorWithEvent(req, "Error pages: no backend handler.")
 		c.next.ServeHTTP(rw, req)
@@ -98,6 +114,13 @@ func (c *customErrors) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 
 		logger.Debugf("Caught HTTP Status Code %d, returning error page", code)
 
+		if len(c.pages) > 0 {
+			if err := c.pages.Serve(rw, req, code); err != nil {
+				logger.Errorf("Error serving error page: %v", err)
+			}
+			return
+		}
+
 		var query string
 		if len(c.backendQuery) > 0 {
 			query = "/" + strings.TrimPrefix(c.backendQuery, "/")
@@ -0,0 +1,239 @@
+package customerrors
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+const defaultPageContentType = "text/html; charset=utf-8"
+
+// pageTemplate is the common interface of the text and HTML templates.
+type pageTemplate interface {
+	Execute(w io.Writer, data interface{}) error
+}
+
+// pageData is the data the error page templates are rendered with.
+type pageData struct {
+	StatusCode int
+	StatusText string
+	RequestID  string
+	Host       string
+}
+
+// page is an error page served without a service.
+type page struct {
+	contentType string
+	mediaType   string
+	template    pageTemplate
+}
+
+var templateFuncs = map[string]interface{}{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+func newPage(config dynamic.ErrorPageContent, allowFile bool) (*page, error) {
+	if config.Body != "" && config.File != "" {
+		return nil, errors.New("an error page cannot have both a body and a file")
+	}
+	if config.Body == "" && config.File == "" {
+		return nil, errors.New("an error page requires a body or a file")
+	}
+
+	body := config.Body
+	if config.File != "" {
+		if !allowFile {
+			return nil, errors.New("error page files are only allowed with the file provider")
+		}
+
+		content, err := os.ReadFile(config.File)
+		if err != nil {
+			return nil, fmt.Errorf("error reading error page file %s: %w", config.File, err)
+		}
+		body = string(content)
+	}
+
+	contentType := config.ContentType
+	if contentType == "" {
+		contentType = defaultPageContentType
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, fmt.Errorf("invalid error page content type %s: %w", contentType, err)
+	}
+
+	p := &page{contentType: contentType, mediaType: mediaType}
+
+	// The HTML templates escape the data, which comes from the request.
+	if mediaType == "text/html" {
+		p.template, err = htmltemplate.New("page").Funcs(templateFuncs).Parse(body)
+	} else {
+		p.template, err = template.New("page").Funcs(templateFuncs).Parse(body)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing error page template: %w", err)
+	}
+
+	return p, nil
+}
+
+// render renders the page for the request, with the given status code.
+func (p *page) render(req *http.Request, code int) ([]byte, error) {
+	data := pageData{
+		StatusCode: code,
+		StatusText: http.StatusText(code),
+		RequestID:  req.Header.Get("X-Request-Id"),
+		Host:       req.Host,
+	}
+
+	var body bytes.Buffer
+	if err := p.template.Execute(&body, data); err != nil {
+		return nil, err
+	}
+
+	return body.Bytes(), nil
+}
+
+// Pages are error pages served without a service.
+type Pages []*page
+
+// NewPages creates the error pages of the given middleware from their configuration.
+// The pages can only be read from a file when the middleware comes from the file provider,
+// as the other providers, like the container labels, could otherwise make Traefik serve any file it can read.
+func NewPages(middlewareName string, configs []dynamic.ErrorPageContent) (Pages, error) {
+	allowFile := fromFileProvider(middlewareName)
+
+	var pages Pages
+	for _, config := range configs {
+		p, err := newPage(config, allowFile)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, p)
+	}
+	return pages, nil
+}
+
+// fromFileProvider tells whether the given qualified middleware name is the one of a middleware of the file provider.
+func fromFileProvider(middlewareName string) bool {
+	i := strings.LastIndex(middlewareName, "@")
+	return i >= 0 && middlewareName[i+1:] == "file"
+}
+
+// Serve serves the page matching the Accept header of the request, with the given status code.
+// The status text is served when the page cannot be rendered.
+func (p Pages) Serve(rw http.ResponseWriter, req *http.Request, code int) error {
+	selected := selectPage(p, req.Header.Get("Accept"))
+
+	body, err := selected.render(req, code)
+	if err != nil {
+		rw.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		rw.WriteHeader(code)
+		_, _ = fmt.Fprint(rw, http.StatusText(code))
+		return fmt.Errorf("error rendering error page: %w", err)
+	}
+
+	rw.Header().Set("Content-Type", selected.contentType)
+	rw.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	rw.WriteHeader(code)
+
+	if req.Method == http.MethodHead {
+		return nil
+	}
+
+	_, err = rw.Write(body)
+	return err
+}
+
+// selectPage returns the page with the content type preferred by the Accept header,
+// the order of the pages breaking the ties. The first page is returned when none is acceptable.
+func selectPage(pages []*page, accept string) *page {
+	ranges := parseAccept(accept)
+	if len(ranges) == 0 {
+		return pages[0]
+	}
+
+	var best *page
+	var bestQValue float64
+	for _, p := range pages {
+		if qValue := acceptQValue(ranges, p.mediaType); qValue > bestQValue {
+			best = p
+			bestQValue = qValue
+		}
+	}
+
+	if best == nil {
+		return pages[0]
+	}
+	return best
+}
+
+type mediaRange struct {
+	mediaType string
+	qValue    float64
+}
+
+// parseAccept returns the media ranges of the Accept header, ignoring the invalid ones.
+func parseAccept(accept string) []mediaRange {
+	var ranges []mediaRange
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		qValue := 1.0
+		if q, ok := params["q"]; ok {
+			qValue, err = strconv.ParseFloat(q, 64)
+			if err != nil || qValue < 0 || qValue > 1 {
+				continue
+			}
+		}
+
+		ranges = append(ranges, mediaRange{mediaType: mediaType, qValue: qValue})
+	}
+
+	return ranges
+}
+
+// acceptQValue returns the quality value of the most specific media range matching the media type.
+func acceptQValue(ranges []mediaRange, mediaType string) float64 {
+	mainType := strings.SplitN(mediaType, "/", 2)[0]
+
+	qValue, specificity := 0.0, -1
+	for _, r := range ranges {
+		var s int
+		switch r.mediaType {
+		case mediaType:
+			s = 2
+		case mainType + "/*":
+			s = 1
+		case "*/*":
+			s = 0
+		default:
+			continue
+		}
+
+		if s > specificity {
+			qValue, specificity = r.qValue, s
+		}
+	}
+
+	return qValue
+}
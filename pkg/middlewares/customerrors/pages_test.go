@@ -0,0 +1,236 @@
+package customerrors
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestHandler_pages(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "error.json")
+	err := os.WriteFile(file, []byte(`{"status":{{ .StatusCode }},"host":{{ json .Host }},"requestId":{{ json .RequestID }}}`), 0o644)
+	require.NoError(t, err)
+
+	config := dynamic.ErrorPage{
+		Status: []string{"502-504"},
+		Pages: []dynamic.ErrorPageContent{
+			{Body: `<h1>{{ .StatusCode }} {{ .StatusText }}</h1><p>{{ .Host }}</p>`},
+			{ContentType: "application/json", File: file},
+		},
+	}
+
+	testCases := []struct {
+		desc                string
+		backendCode         int
+		host                string
+		accept              string
+		expectedCode        int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			desc:                "no error",
+			backendCode:         http.StatusOK,
+			host:                "foo.bar",
+			expectedCode:        http.StatusOK,
+			expectedContentType: "",
+			expectedBody:        "OK",
+		},
+		{
+			desc:                "not caught error",
+			backendCode:         http.StatusInternalServerError,
+			host:                "foo.bar",
+			expectedCode:        http.StatusInternalServerError,
+			expectedContentType: "",
+			expectedBody:        "Internal Server Error",
+		},
+		{
+			desc:                "HTML page by default",
+			backendCode:         http.StatusBadGateway,
+			host:                "foo.bar",
+			expectedCode:        http.StatusBadGateway,
+			expectedContentType: "text/html; charset=utf-8",
+			expectedBody:        `<h1>502 Bad Gateway</h1><p>foo.bar</p>`,
+		},
+		{
+			desc:                "HTML page is escaped",
+			backendCode:         http.StatusServiceUnavailable,
+			host:                "<script>",
+			accept:              "text/html,application/xhtml+xml,*/*;q=0.8",
+			expectedCode:        http.StatusServiceUnavailable,
+			expectedContentType: "text/html; charset=utf-8",
+			expectedBody:        `<h1>503 Service Unavailable</h1><p>&lt;script&gt;</p>`,
+		},
+		{
+			desc:                "JSON page",
+			backendCode:         http.StatusGatewayTimeout,
+			host:                "foo.bar",
+			accept:              "application/json",
+			expectedCode:        http.StatusGatewayTimeout,
+			expectedContentType: "application/json",
+			expectedBody:        `{"status":504,"host":"foo.bar","requestId":"123"}`,
+		},
+		{
+			desc:                "not acceptable content type",
+			backendCode:         http.StatusBadGateway,
+			host:                "foo.bar",
+			accept:              "image/png",
+			expectedCode:        http.StatusBadGateway,
+			expectedContentType: "text/html; charset=utf-8",
+			expectedBody:        `<h1>502 Bad Gateway</h1><p>foo.bar</p>`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Content-Type", "text/plain")
+				rw.WriteHeader(test.backendCode)
+				_, _ = rw.Write([]byte(http.StatusText(test.backendCode)))
+			})
+
+			errorPageHandler, err := New(context.Background(), handler, config, nil, "test@file")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://"+test.host, nil)
+			req.Header.Set("X-Request-Id", "123")
+			if test.accept != "" {
+				req.Header.Set("Accept", test.accept)
+			}
+
+			recorder := httptest.NewRecorder()
+			errorPageHandler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+			if test.expectedContentType != "" {
+				assert.Equal(t, test.expectedContentType, recorder.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestSelectPage(t *testing.T) {
+	html, err := newPage(dynamic.ErrorPageContent{ContentType: "text/html", Body: "html"}, false)
+	require.NoError(t, err)
+	json, err := newPage(dynamic.ErrorPageContent{ContentType: "application/json", Body: "json"}, false)
+	require.NoError(t, err)
+
+	pages := []*page{html, json}
+
+	testCases := []struct {
+		desc     string
+		accept   string
+		expected *page
+	}{
+		{
+			desc:     "no Accept header",
+			expected: html,
+		},
+		{
+			desc:     "exact media type",
+			accept:   "application/json",
+			expected: json,
+		},
+		{
+			desc:     "q-values",
+			accept:   "text/html;q=0.5, application/json;q=0.9",
+			expected: json,
+		},
+		{
+			desc:     "media type range",
+			accept:   "application/*",
+			expected: json,
+		},
+		{
+			desc:     "most specific media range",
+			accept:   "text/html;q=0, */*",
+			expected: json,
+		},
+		{
+			desc:     "pages order breaks the ties",
+			accept:   "*/*",
+			expected: html,
+		},
+		{
+			desc:     "no acceptable page",
+			accept:   "image/png",
+			expected: html,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Same(t, test.expected, selectPage(pages, test.accept))
+		})
+	}
+}
+
+func TestNew_invalidPages(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config dynamic.ErrorPage
+	}{
+		{
+			desc: "service and pages",
+			config: dynamic.ErrorPage{
+				Service: "error",
+				Pages:   []dynamic.ErrorPageContent{{Body: "error"}},
+			},
+		},
+		{
+			desc:   "no body nor file",
+			config: dynamic.ErrorPage{Pages: []dynamic.ErrorPageContent{{ContentType: "text/html"}}},
+		},
+		{
+			desc:   "body and file",
+			config: dynamic.ErrorPage{Pages: []dynamic.ErrorPageContent{{Body: "error", File: "error.html"}}},
+		},
+		{
+			desc:   "missing file",
+			config: dynamic.ErrorPage{Pages: []dynamic.ErrorPageContent{{File: "/does/not/exist.html"}}},
+		},
+		{
+			desc:   "invalid template",
+			config: dynamic.ErrorPage{Pages: []dynamic.ErrorPageContent{{Body: "{{ .StatusCode "}}},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(context.Background(), http.NotFoundHandler(), test.config, &mockServiceBuilder{}, "test@file")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNewPages_file(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "error.html")
+	err := os.WriteFile(file, []byte("error"), 0o644)
+	require.NoError(t, err)
+
+	configs := []dynamic.ErrorPageContent{{File: file}}
+
+	_, err = NewPages("test@file", configs)
+	require.NoError(t, err)
+
+	// The other providers, like the container labels, cannot read the files of the host.
+	_, err = NewPages("test@docker", configs)
+	assert.EqualError(t, err, "error page files are only allowed with the file provider")
+}
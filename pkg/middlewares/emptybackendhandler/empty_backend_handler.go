@@ -1,6 +1,7 @@
 package emptybackendhandler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/traefik/traefik/v2/pkg/healthcheck"
@@ -31,3 +32,13 @@ func (e *emptyBackend) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		e.next.ServeHTTP(rw, req)
 	}
 }
+
+// RegisterStatusUpdater registers a function called when the status of the Backend changes.
+func (e *emptyBackend) RegisterStatusUpdater(fn func(up bool)) error {
+	statusUpdater, ok := e.next.(healthcheck.StatusUpdater)
+	if !ok {
+		return errors.New("the load balancer does not support status updates")
+	}
+
+	return statusUpdater.RegisterStatusUpdater(fn)
+}
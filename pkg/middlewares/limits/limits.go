@@ -0,0 +1,242 @@
+package limits
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	typeName = "Limits"
+)
+
+// errBodyTooLarge is returned when reading a request body exceeding the limit.
+var errBodyTooLarge = errors.New("request body too large")
+
+// limits is a middleware rejecting the requests exceeding the configured limits,
+// without buffering their bodies.
+type limits struct {
+	next   http.Handler
+	name   string
+	config dynamic.Limits
+}
+
+// New creates a limits middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.Limits, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if config.MaxRequestBodyBytes < 0 || config.MaxHeaderCount < 0 || config.MaxHeaderBytes < 0 || config.MaxURLLength < 0 {
+		return nil, errors.New("limits must be positive")
+	}
+
+	return &limits{
+		next:   next,
+		name:   name,
+		config: config,
+	}, nil
+}
+
+// Merge returns the limits set by the given configurations, the ones set by the last configurations overriding the previous ones.
+func Merge(configs ...dynamic.Limits) dynamic.Limits {
+	var merged dynamic.Limits
+	for _, config := range configs {
+		if config.MaxRequestBodyBytes != 0 {
+			merged.MaxRequestBodyBytes = config.MaxRequestBodyBytes
+		}
+		if config.MaxHeaderCount != 0 {
+			merged.MaxHeaderCount = config.MaxHeaderCount
+		}
+		if config.MaxHeaderBytes != 0 {
+			merged.MaxHeaderBytes = config.MaxHeaderBytes
+		}
+		if config.MaxURLLength != 0 {
+			merged.MaxURLLength = config.MaxURLLength
+		}
+	}
+	return merged
+}
+
+func (l *limits) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return l.name, tracing.SpanKindNoneEnum
+}
+
+func (l *limits) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), l.name, typeName))
+
+	// The length of the path and query, whether the request target is in the origin or the absolute form.
+	if l.config.MaxURLLength > 0 && len(req.URL.RequestURI()) > l.config.MaxURLLength {
+		logger.Debugf("Rejecting request: URL longer than %d bytes", l.config.MaxURLLength)
+		http.Error(rw, http.StatusText(http.StatusRequestURITooLong), http.StatusRequestURITooLong)
+		return
+	}
+
+	if l.config.MaxHeaderCount > 0 || l.config.MaxHeaderBytes > 0 {
+		count, size := headerSize(req.Header)
+		if l.config.MaxHeaderCount > 0 && count > l.config.MaxHeaderCount {
+			logger.Debugf("Rejecting request: more than %d header fields", l.config.MaxHeaderCount)
+			http.Error(rw, http.StatusText(http.StatusRequestHeaderFieldsTooLarge), http.StatusRequestHeaderFieldsTooLarge)
+			return
+		}
+		if l.config.MaxHeaderBytes > 0 && size > l.config.MaxHeaderBytes {
+			logger.Debugf("Rejecting request: header fields larger than %d bytes", l.config.MaxHeaderBytes)
+			http.Error(rw, http.StatusText(http.StatusRequestHeaderFieldsTooLarge), http.StatusRequestHeaderFieldsTooLarge)
+			return
+		}
+	}
+
+	if l.config.MaxRequestBodyBytes <= 0 || req.Body == nil || req.Body == http.NoBody {
+		l.next.ServeHTTP(rw, req)
+		return
+	}
+
+	if req.ContentLength > l.config.MaxRequestBodyBytes {
+		logger.Debugf("Rejecting request: Content-Length %d larger than %d bytes", req.ContentLength, l.config.MaxRequestBodyBytes)
+		http.Error(rw, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	// The server already ensures that the body is not larger than its Content-Length.
+	if req.ContentLength >= 0 {
+		l.next.ServeHTTP(rw, req)
+		return
+	}
+
+	body := &limitedBody{body: req.Body, remaining: l.config.MaxRequestBodyBytes}
+	req.Body = body
+
+	l.next.ServeHTTP(&responseWriter{rw: rw, body: body}, req)
+
+	if body.isExceeded() {
+		logger.Debugf("Rejected request: body larger than %d bytes", l.config.MaxRequestBodyBytes)
+	}
+}
+
+// headerSize returns the number of header fields, and their size as sent by the client, that is with the ": " and CRLF separators.
+func headerSize(header http.Header) (int, int) {
+	var count, size int
+	for name, values := range header {
+		for _, value := range values {
+			count++
+			size += len(name) + len(value) + 4
+		}
+	}
+	return count, size
+}
+
+// limitedBody is a request body returning an error once more than the remaining bytes are read.
+type limitedBody struct {
+	body      io.ReadCloser
+	remaining int64
+
+	// exceeded is accessed atomically, as the body can be read concurrently with the response being written, e.g. by the proxy.
+	exceeded int32
+}
+
+func (b *limitedBody) isExceeded() bool {
+	return atomic.LoadInt32(&b.exceeded) == 1
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.isExceeded() {
+		return 0, errBodyTooLarge
+	}
+
+	// Reads one more byte than remaining, to detect a body exceeding the limit.
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+
+	n, err := b.body.Read(p)
+	if int64(n) <= b.remaining {
+		b.remaining -= int64(n)
+		return n, err
+	}
+
+	atomic.StoreInt32(&b.exceeded, 1)
+	n = int(b.remaining)
+	b.remaining = 0
+	return n, errBodyTooLarge
+}
+
+func (b *limitedBody) Close() error {
+	return b.body.Close()
+}
+
+// responseWriter sends a 413 response instead of the response of the next handler,
+// when the request body exceeds the limit before the response is sent,
+// as the next handler then fails to read the body, and usually answers with an error.
+type responseWriter struct {
+	rw   http.ResponseWriter
+	body *limitedBody
+
+	wroteHeader bool
+	rejected    bool
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.rw.Header()
+}
+
+func (w *responseWriter) WriteHeader(statusCode int) {
+	if w.wroteHeader {
+		return
+	}
+
+	// 1xx informational responses are sent right away.
+	if statusCode >= http.StatusContinue && statusCode < http.StatusOK {
+		w.rw.WriteHeader(statusCode)
+		return
+	}
+
+	w.wroteHeader = true
+
+	if !w.body.isExceeded() {
+		w.rw.WriteHeader(statusCode)
+		return
+	}
+
+	w.rejected = true
+
+	header := w.rw.Header()
+	for name := range header {
+		header.Del(name)
+	}
+	header.Set("Content-Type", "text/plain; charset=utf-8")
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("Connection", "close")
+	w.rw.WriteHeader(http.StatusRequestEntityTooLarge)
+	_, _ = fmt.Fprintln(w.rw, http.StatusText(http.StatusRequestEntityTooLarge))
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	// The response of the next handler is discarded once rejected.
+	if w.rejected {
+		return len(p), nil
+	}
+
+	return w.rw.Write(p)
+}
+
+// Flush sends any buffered data to the client.
+func (w *responseWriter) Flush() {
+	if w.rejected {
+		return
+	}
+
+	if flusher, ok := w.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
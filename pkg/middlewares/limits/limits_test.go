@@ -0,0 +1,162 @@
+package limits
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestNew(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	_, err := New(context.Background(), next, dynamic.Limits{MaxRequestBodyBytes: 10, MaxURLLength: 10}, "traefikTest")
+	require.NoError(t, err)
+
+	_, err = New(context.Background(), next, dynamic.Limits{MaxHeaderCount: -1}, "traefikTest")
+	assert.Error(t, err)
+}
+
+func TestLimits_ServeHTTP(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		config             dynamic.Limits
+		url                string
+		headers            map[string]string
+		body               string
+		chunked            bool
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			desc:               "no limits",
+			config:             dynamic.Limits{},
+			url:                "http://foo.bar/foo?bar=baz",
+			body:               "body",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "body",
+		},
+		{
+			desc:               "URL too long",
+			config:             dynamic.Limits{MaxURLLength: 10},
+			url:                "http://foo.bar/foo?bar=baz",
+			expectedStatusCode: http.StatusRequestURITooLong,
+		},
+		{
+			desc:               "URL within limit",
+			config:             dynamic.Limits{MaxURLLength: 12},
+			url:                "http://foo.bar/foo?bar=baz",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc:               "too many header fields",
+			config:             dynamic.Limits{MaxHeaderCount: 1},
+			url:                "http://foo.bar/",
+			headers:            map[string]string{"X-Foo": "foo", "X-Bar": "bar"},
+			expectedStatusCode: http.StatusRequestHeaderFieldsTooLarge,
+		},
+		{
+			desc:               "header fields too large",
+			config:             dynamic.Limits{MaxHeaderBytes: 20},
+			url:                "http://foo.bar/",
+			headers:            map[string]string{"X-Foo": strings.Repeat("a", 20)},
+			expectedStatusCode: http.StatusRequestHeaderFieldsTooLarge,
+		},
+		{
+			desc:               "header fields within limits",
+			config:             dynamic.Limits{MaxHeaderCount: 2, MaxHeaderBytes: 24},
+			url:                "http://foo.bar/",
+			headers:            map[string]string{"X-Foo": "foo", "X-Bar": "bar"},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc:               "Content-Length too large",
+			config:             dynamic.Limits{MaxRequestBodyBytes: 3},
+			url:                "http://foo.bar/",
+			body:               "body",
+			expectedStatusCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			desc:               "Content-Length within limit",
+			config:             dynamic.Limits{MaxRequestBodyBytes: 4},
+			url:                "http://foo.bar/",
+			body:               "body",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "body",
+		},
+		{
+			desc:               "streamed body too large",
+			config:             dynamic.Limits{MaxRequestBodyBytes: 3},
+			url:                "http://foo.bar/",
+			body:               "body",
+			chunked:            true,
+			expectedStatusCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			desc:               "streamed body within limit",
+			config:             dynamic.Limits{MaxRequestBodyBytes: 4},
+			url:                "http://foo.bar/",
+			body:               "body",
+			chunked:            true,
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "body",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				body, err := io.ReadAll(req.Body)
+				if err != nil {
+					http.Error(rw, err.Error(), http.StatusBadGateway)
+					return
+				}
+				_, _ = rw.Write(body)
+			})
+
+			handler, err := New(context.Background(), next, test.config, "traefikTest")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, test.url, strings.NewReader(test.body))
+			for name, value := range test.headers {
+				req.Header.Set(name, value)
+			}
+			if test.chunked {
+				req.ContentLength = -1
+				req.Body = io.NopCloser(strings.NewReader(test.body))
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatusCode, recorder.Code)
+			if test.expectedStatusCode == http.StatusOK {
+				assert.Equal(t, test.expectedBody, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	merged := Merge(
+		dynamic.Limits{MaxRequestBodyBytes: 10, MaxHeaderCount: 20, MaxURLLength: 30},
+		dynamic.Limits{MaxRequestBodyBytes: 100, MaxHeaderBytes: 200},
+	)
+
+	expected := dynamic.Limits{
+		MaxRequestBodyBytes: 100,
+		MaxHeaderCount:      20,
+		MaxHeaderBytes:      200,
+		MaxURLLength:        30,
+	}
+	assert.Equal(t, expected, merged)
+}
@@ -0,0 +1,140 @@
+package maintenance
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/ip"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/middlewares/customerrors"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	typeName = "Maintenance"
+)
+
+// maintenance is a middleware serving a 503 page while the maintenance mode is enabled,
+// except for the allowed IPs and the requests with the bypass header.
+type maintenance struct {
+	next              http.Handler
+	name              string
+	enabled           bool
+	states            *States
+	retryAfter        string
+	checker           *ip.Checker
+	strategy          ip.Strategy
+	bypassHeaderName  string
+	bypassHeaderValue string
+	pages             customerrors.Pages
+}
+
+// New creates a maintenance middleware.
+// The enabled state of the middleware is the one set in the given states, if any, or the configured one.
+func New(ctx context.Context, next http.Handler, config dynamic.Maintenance, states *States, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if (config.BypassHeaderName == "") != (config.BypassHeaderValue == "") {
+		return nil, errors.New("bypassHeaderName and bypassHeaderValue must be set together")
+	}
+
+	m := &maintenance{
+		next:              next,
+		name:              name,
+		enabled:           config.Enabled,
+		states:            states,
+		bypassHeaderName:  config.BypassHeaderName,
+		bypassHeaderValue: config.BypassHeaderValue,
+	}
+
+	if config.RetryAfter < 0 {
+		return nil, fmt.Errorf("retryAfter must be positive: %s", time.Duration(config.RetryAfter))
+	}
+	if config.RetryAfter > 0 {
+		m.retryAfter = strconv.FormatInt(int64(time.Duration(config.RetryAfter)/time.Second), 10)
+	}
+
+	if len(config.SourceRange) > 0 {
+		var err error
+		m.checker, err = ip.NewChecker(config.SourceRange)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse CIDR allowlist %s: %w", config.SourceRange, err)
+		}
+
+		m.strategy, err = config.IPStrategy.Get()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(config.Pages) > 0 {
+		var err error
+		m.pages, err = customerrors.NewPages(name, config.Pages)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+func (m *maintenance) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return m.name, tracing.SpanKindNoneEnum
+}
+
+func (m *maintenance) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if !m.isEnabled() || m.bypass(req) {
+		m.next.ServeHTTP(rw, req)
+		return
+	}
+
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), m.name, typeName))
+	logger.Debug("Maintenance mode enabled, rejecting request")
+
+	if m.retryAfter != "" {
+		rw.Header().Set("Retry-After", m.retryAfter)
+	}
+
+	if len(m.pages) > 0 {
+		if err := m.pages.Serve(rw, req, http.StatusServiceUnavailable); err != nil {
+			logger.Errorf("Error serving maintenance page: %v", err)
+		}
+		return
+	}
+
+	http.Error(rw, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+}
+
+// isEnabled returns the enabled state set through the API, if any, or the configured one.
+func (m *maintenance) isEnabled() bool {
+	if m.states != nil {
+		if enabled, ok := m.states.Get(m.name); ok {
+			return enabled
+		}
+	}
+	return m.enabled
+}
+
+// bypass tells whether the request is let through despite the maintenance mode.
+func (m *maintenance) bypass(req *http.Request) bool {
+	if m.bypassHeaderName != "" {
+		value := req.Header.Get(m.bypassHeaderName)
+		if value != "" && subtle.ConstantTimeCompare([]byte(value), []byte(m.bypassHeaderValue)) == 1 {
+			return true
+		}
+	}
+
+	if m.checker != nil {
+		return m.checker.IsAuthorized(m.strategy.GetIP(req)) == nil
+	}
+
+	return false
+}
@@ -0,0 +1,203 @@
+package maintenance
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestNew(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "maintenance.html")
+	err := os.WriteFile(file, []byte("maintenance"), 0o644)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc          string
+		config        dynamic.Maintenance
+		expectedError bool
+	}{
+		{
+			desc:   "empty",
+			config: dynamic.Maintenance{},
+		},
+		{
+			desc: "invalid source range",
+			config: dynamic.Maintenance{
+				SourceRange: []string{"foo"},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "bypass header name without value",
+			config: dynamic.Maintenance{
+				BypassHeaderName: "X-Bypass",
+			},
+			expectedError: true,
+		},
+		{
+			desc: "negative retry after",
+			config: dynamic.Maintenance{
+				RetryAfter: ptypes.Duration(-time.Second),
+			},
+			expectedError: true,
+		},
+		{
+			desc: "page file outside of the file provider",
+			config: dynamic.Maintenance{
+				Pages: []dynamic.ErrorPageContent{{File: file}},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "invalid page",
+			config: dynamic.Maintenance{
+				Pages: []dynamic.ErrorPageContent{{Body: "{{ .Foo"}},
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+			_, err := New(context.Background(), next, test.config, nil, "traefikTest")
+
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMaintenance_ServeHTTP(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		config             dynamic.Maintenance
+		states             map[string]bool
+		remoteAddr         string
+		headers            map[string]string
+		expectedStatusCode int
+		expectedRetryAfter string
+		expectedBody       string
+	}{
+		{
+			desc:               "disabled",
+			config:             dynamic.Maintenance{},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "next",
+		},
+		{
+			desc:               "enabled",
+			config:             dynamic.Maintenance{Enabled: true},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedBody:       "Service Unavailable\n",
+		},
+		{
+			desc:               "enabled with retry after",
+			config:             dynamic.Maintenance{Enabled: true, RetryAfter: ptypes.Duration(5 * time.Minute)},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedRetryAfter: "300",
+			expectedBody:       "Service Unavailable\n",
+		},
+		{
+			desc: "enabled with page",
+			config: dynamic.Maintenance{
+				Enabled: true,
+				Pages:   []dynamic.ErrorPageContent{{ContentType: "text/plain", Body: "{{ .StatusCode }} maintenance"}},
+			},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedBody:       "503 maintenance",
+		},
+		{
+			desc:               "enabled through the API",
+			config:             dynamic.Maintenance{},
+			states:             map[string]bool{"traefikTest": true},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedBody:       "Service Unavailable\n",
+		},
+		{
+			desc:               "disabled through the API",
+			config:             dynamic.Maintenance{Enabled: true},
+			states:             map[string]bool{"traefikTest": false},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "next",
+		},
+		{
+			desc:               "allowed IP",
+			config:             dynamic.Maintenance{Enabled: true, SourceRange: []string{"10.0.0.0/8"}},
+			remoteAddr:         "10.1.2.3:1234",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "next",
+		},
+		{
+			desc:               "not allowed IP",
+			config:             dynamic.Maintenance{Enabled: true, SourceRange: []string{"10.0.0.0/8"}},
+			remoteAddr:         "192.168.1.1:1234",
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedBody:       "Service Unavailable\n",
+		},
+		{
+			desc:               "bypass header",
+			config:             dynamic.Maintenance{Enabled: true, BypassHeaderName: "X-Bypass", BypassHeaderValue: "secret"},
+			headers:            map[string]string{"X-Bypass": "secret"},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "next",
+		},
+		{
+			desc:               "wrong bypass header",
+			config:             dynamic.Maintenance{Enabled: true, BypassHeaderName: "X-Bypass", BypassHeaderValue: "secret"},
+			headers:            map[string]string{"X-Bypass": "foo"},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedBody:       "Service Unavailable\n",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			states, err := NewStates("")
+			require.NoError(t, err)
+			for name, enabled := range test.states {
+				require.NoError(t, states.Set(name, enabled))
+			}
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				_, _ = rw.Write([]byte("next"))
+			})
+
+			handler, err := New(context.Background(), next, test.config, states, "traefikTest")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://foo.bar/", nil)
+			if test.remoteAddr != "" {
+				req.RemoteAddr = test.remoteAddr
+			}
+			for name, value := range test.headers {
+				req.Header.Set(name, value)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatusCode, recorder.Code)
+			assert.Equal(t, test.expectedRetryAfter, recorder.Header().Get("Retry-After"))
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package maintenance
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// States holds the enabled states of the maintenance middlewares toggled through the API.
+// It outlives the middlewares, and persists the states to a file, if any, so that they are kept across restarts.
+type States struct {
+	path string
+
+	mu     sync.RWMutex
+	states map[string]bool
+}
+
+// NewStates creates new States persisted to the given file, or only kept in memory if the path is empty.
+// The states are loaded from the file when it exists.
+func NewStates(path string) (*States, error) {
+	s := &States{path: path, states: make(map[string]bool)}
+	if path == "" {
+		return s, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return s, nil
+		}
+		return s, fmt.Errorf("error reading maintenance states: %w", err)
+	}
+
+	if len(content) == 0 {
+		return s, nil
+	}
+
+	if err := json.Unmarshal(content, &s.states); err != nil {
+		return s, fmt.Errorf("error decoding maintenance states %s: %w", path, err)
+	}
+
+	return s, nil
+}
+
+// Get returns the enabled state of the given middleware, and false if it has not been toggled.
+func (s *States) Get(name string) (bool, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	enabled, ok := s.states[name]
+	return enabled, ok
+}
+
+// Set sets the enabled state of the given middleware, and persists the states.
+// The state is left unchanged if it cannot be persisted.
+func (s *States) Set(name string, enabled bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, ok := s.states[name]
+
+	s.states[name] = enabled
+	if err := s.save(); err != nil {
+		s.restore(name, previous, ok)
+		return err
+	}
+
+	return nil
+}
+
+// Reset removes the enabled state of the given middleware, so that the configured one applies again, and persists the states.
+// The state is left unchanged if it cannot be persisted.
+func (s *States) Reset(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, ok := s.states[name]
+
+	delete(s.states, name)
+	if err := s.save(); err != nil {
+		s.restore(name, previous, ok)
+		return err
+	}
+
+	return nil
+}
+
+func (s *States) restore(name string, previous, ok bool) {
+	if ok {
+		s.states[name] = previous
+	} else {
+		delete(s.states, name)
+	}
+}
+
+// save writes the states to the file, through a temporary file so that the file is never partially written.
+func (s *States) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	content, err := json.MarshalIndent(s.states, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return fmt.Errorf("error saving maintenance states: %w", err)
+	}
+
+	_, err = tmp.Write(content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), s.path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("error saving maintenance states: %w", err)
+	}
+
+	return nil
+}
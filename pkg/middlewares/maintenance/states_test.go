@@ -0,0 +1,56 @@
+package maintenance
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStates_persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "maintenance.json")
+
+	states, err := NewStates(path)
+	require.NoError(t, err)
+
+	_, ok := states.Get("foo")
+	assert.False(t, ok)
+
+	require.NoError(t, states.Set("foo", true))
+	require.NoError(t, states.Set("bar", false))
+	require.NoError(t, states.Reset("bar"))
+
+	reloaded, err := NewStates(path)
+	require.NoError(t, err)
+
+	enabled, ok := reloaded.Get("foo")
+	assert.True(t, ok)
+	assert.True(t, enabled)
+
+	_, ok = reloaded.Get("bar")
+	assert.False(t, ok)
+}
+
+func TestStates_invalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "maintenance.json")
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+
+	states, err := NewStates(path)
+	assert.Error(t, err)
+	require.NotNil(t, states)
+
+	_, ok := states.Get("foo")
+	assert.False(t, ok)
+}
+
+func TestStates_saveError(t *testing.T) {
+	states, err := NewStates(filepath.Join(t.TempDir(), "missing", "maintenance.json"))
+	require.NoError(t, err)
+
+	assert.Error(t, states.Set("foo", true))
+
+	_, ok := states.Get("foo")
+	assert.False(t, ok)
+}
@@ -0,0 +1,130 @@
+package proxyprotocol
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pires/go-proxyproto"
+	"github.com/pires/go-proxyproto/tlvparse"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+// HeaderPrefix is the prefix of the request headers holding the TLVs of the PROXY protocol header.
+const HeaderPrefix = "X-Proxy-Protocol-"
+
+const (
+	headerALPN           = HeaderPrefix + "Alpn"
+	headerAuthority      = HeaderPrefix + "Authority"
+	headerUniqueID       = HeaderPrefix + "Unique-Id"
+	headerSSLVersion     = HeaderPrefix + "Ssl-Version"
+	headerSSLClientCN    = HeaderPrefix + "Ssl-Client-Cn"
+	headerSSLVerified    = HeaderPrefix + "Ssl-Verified"
+	headerNetNS          = HeaderPrefix + "Netns"
+	headerAWSVPCEID      = HeaderPrefix + "Aws-Vpce-Id"
+	headerAzureLinkID    = HeaderPrefix + "Azure-Link-Id"
+	headerTLVTypedPrefix = HeaderPrefix + "Tlv-"
+)
+
+type tlvsKey struct{}
+
+// ConnContext returns a copy of ctx holding the TLVs of the PROXY protocol header which started the connection, if any.
+// It is meant to be used as the ConnContext of an http.Server.
+func ConnContext(ctx context.Context, conn net.Conn) context.Context {
+	addr, ok := conn.RemoteAddr().(*tcp.ProxyProtocolAddr)
+	if !ok {
+		return ctx
+	}
+
+	return context.WithValue(ctx, tlvsKey{}, addr.TLVs)
+}
+
+// TLVHeaders is an HTTP handler wrapper which sets the TLVs of the PROXY protocol header,
+// received at the start of the connection, as request headers.
+// As the headers are trusted, it first removes all the existing values for those headers.
+type TLVHeaders struct {
+	next http.Handler
+}
+
+// NewTLVHeaders creates a new TLVHeaders.
+func NewTLVHeaders(next http.Handler) *TLVHeaders {
+	return &TLVHeaders{next: next}
+}
+
+func (t *TLVHeaders) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	for name := range req.Header {
+		if strings.HasPrefix(name, HeaderPrefix) {
+			req.Header.Del(name)
+		}
+	}
+
+	if tlvs, ok := req.Context().Value(tlvsKey{}).([]proxyproto.TLV); ok {
+		setTLVHeaders(req.Header, tlvs)
+	}
+
+	t.next.ServeHTTP(rw, req)
+}
+
+func setTLVHeaders(header http.Header, tlvs []proxyproto.TLV) {
+	for _, tlv := range tlvs {
+		switch {
+		case tlv.Type == proxyproto.PP2_TYPE_NOOP, tlv.Type == proxyproto.PP2_TYPE_CRC32C:
+			continue
+
+		case tlv.Type == proxyproto.PP2_TYPE_ALPN:
+			header.Set(headerALPN, string(tlv.Value))
+
+		case tlv.Type == proxyproto.PP2_TYPE_AUTHORITY:
+			header.Set(headerAuthority, string(tlv.Value))
+
+		case tlv.Type == proxyproto.PP2_TYPE_UNIQUE_ID:
+			header.Set(headerUniqueID, hex.EncodeToString(tlv.Value))
+
+		case tlv.Type == proxyproto.PP2_TYPE_NETNS:
+			header.Set(headerNetNS, string(tlv.Value))
+
+		case tlvparse.IsSSL(tlv):
+			ssl, err := tlvparse.SSL(tlv)
+			if err != nil {
+				setTypedHeader(header, tlv)
+				continue
+			}
+
+			header.Set(headerSSLVerified, strconv.FormatBool(ssl.ClientSSL() && ssl.Verified()))
+			if version, ok := ssl.SSLVersion(); ok {
+				header.Set(headerSSLVersion, version)
+			}
+			if cn, ok := ssl.ClientCN(); ok {
+				header.Set(headerSSLClientCN, cn)
+			}
+
+		case tlvparse.IsAWSVPCEndpointID(tlv):
+			id, err := tlvparse.AWSVPCEndpointID(tlv)
+			if err != nil {
+				setTypedHeader(header, tlv)
+				continue
+			}
+			header.Set(headerAWSVPCEID, id)
+
+		case tlv.Type == tlvparse.PP2_TYPE_AZURE:
+			linkID, ok := tlvparse.FindAzurePrivateEndpointLinkID([]proxyproto.TLV{tlv})
+			if !ok {
+				setTypedHeader(header, tlv)
+				continue
+			}
+			header.Set(headerAzureLinkID, strconv.FormatUint(uint64(linkID), 10))
+
+		default:
+			setTypedHeader(header, tlv)
+		}
+	}
+}
+
+// setTypedHeader sets the hex encoded value of the TLV in a header named after its type.
+func setTypedHeader(header http.Header, tlv proxyproto.TLV) {
+	header.Add(fmt.Sprintf("%s%02X", headerTLVTypedPrefix, byte(tlv.Type)), hex.EncodeToString(tlv.Value))
+}
@@ -0,0 +1,136 @@
+package proxyprotocol
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pires/go-proxyproto"
+	"github.com/pires/go-proxyproto/tlvparse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+func TestTLVHeaders(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		tlvs            []proxyproto.TLV
+		incomingHeaders map[string]string
+		expectedHeaders map[string]string
+	}{
+		{
+			desc: "no TLVs",
+			incomingHeaders: map[string]string{
+				headerAuthority: "spoofed.com",
+			},
+			expectedHeaders: map[string]string{
+				headerAuthority: "",
+			},
+		},
+		{
+			desc: "standard TLVs",
+			tlvs: []proxyproto.TLV{
+				{Type: proxyproto.PP2_TYPE_ALPN, Value: []byte("h2")},
+				{Type: proxyproto.PP2_TYPE_AUTHORITY, Value: []byte("foo.bar")},
+				{Type: proxyproto.PP2_TYPE_UNIQUE_ID, Value: []byte{0xca, 0xfe}},
+				{Type: proxyproto.PP2_TYPE_NOOP, Value: []byte{0x00}},
+			},
+			incomingHeaders: map[string]string{
+				headerAuthority:   "spoofed.com",
+				headerSSLClientCN: "spoofed",
+			},
+			expectedHeaders: map[string]string{
+				headerALPN:        "h2",
+				headerAuthority:   "foo.bar",
+				headerUniqueID:    "cafe",
+				headerSSLClientCN: "",
+			},
+		},
+		{
+			desc: "AWS VPC endpoint ID",
+			tlvs: []proxyproto.TLV{
+				{Type: tlvparse.PP2_TYPE_AWS, Value: append([]byte{tlvparse.PP2_SUBTYPE_AWS_VPCE_ID}, []byte("vpce-123")...)},
+			},
+			expectedHeaders: map[string]string{
+				headerAWSVPCEID: "vpce-123",
+			},
+		},
+		{
+			desc: "SSL",
+			tlvs: []proxyproto.TLV{sslTLV(t)},
+			expectedHeaders: map[string]string{
+				headerSSLVerified: "true",
+				headerSSLVersion:  "TLSv1.3",
+				headerSSLClientCN: "client",
+			},
+		},
+		{
+			desc: "custom TLV",
+			tlvs: []proxyproto.TLV{
+				{Type: tcp.PP2TypeRouterName, Value: []byte("foo")},
+			},
+			expectedHeaders: map[string]string{
+				headerTLVTypedPrefix + "E0": "666f6f",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+			for k, v := range test.incomingHeaders {
+				req.Header.Set(k, v)
+			}
+
+			conn := &fakeConn{addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 8000}}
+			if test.tlvs != nil {
+				conn.addr = &tcp.ProxyProtocolAddr{Addr: conn.addr, TLVs: test.tlvs}
+			}
+			req = req.WithContext(ConnContext(context.Background(), conn))
+
+			var headers http.Header
+			handler := NewTLVHeaders(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				headers = req.Header
+			}))
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			for k, v := range test.expectedHeaders {
+				assert.Equal(t, v, headers.Get(k), k)
+			}
+		})
+	}
+}
+
+func sslTLV(t *testing.T) proxyproto.TLV {
+	t.Helper()
+
+	ssl := tlvparse.PP2SSL{
+		Client: tlvparse.PP2_BITFIELD_CLIENT_SSL | tlvparse.PP2_BITFIELD_CLIENT_CERT_CONN,
+		Verify: 0,
+		TLV: []proxyproto.TLV{
+			{Type: proxyproto.PP2_SUBTYPE_SSL_VERSION, Value: []byte("TLSv1.3")},
+			{Type: proxyproto.PP2_SUBTYPE_SSL_CN, Value: []byte("client")},
+		},
+	}
+
+	tlv, err := ssl.Marshal()
+	require.NoError(t, err)
+
+	return tlv
+}
+
+type fakeConn struct {
+	net.Conn
+	addr net.Addr
+}
+
+func (c *fakeConn) RemoteAddr() net.Addr {
+	return c.addr
+}
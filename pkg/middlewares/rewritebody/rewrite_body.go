@@ -0,0 +1,388 @@
+package rewritebody
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/middlewares/compress"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	typeName = "RewriteBody"
+)
+
+// maxBufferedSize is the size of the rewritten response bodies which are sent with a Content-Length header.
+// The larger bodies are streamed.
+const maxBufferedSize = 64 * 1024
+
+// rewriteBody is a middleware rewriting the request and response bodies.
+type rewriteBody struct {
+	next             http.Handler
+	name             string
+	replacers        []*replacer
+	requestReplacers []*replacer
+	contentTypes     []string
+}
+
+// New creates a body rewriting middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.RewriteBody, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if len(config.Rewrites) == 0 && len(config.RequestRewrites) == 0 {
+		return nil, fmt.Errorf("at least one rewrite is required")
+	}
+
+	r := &rewriteBody{
+		next:         next,
+		name:         name,
+		contentTypes: []string{"text/html"},
+	}
+
+	for _, rule := range config.Rewrites {
+		rep, err := newReplacer(rule)
+		if err != nil {
+			return nil, err
+		}
+		r.replacers = append(r.replacers, rep)
+	}
+
+	for _, rule := range config.RequestRewrites {
+		rep, err := newReplacer(rule)
+		if err != nil {
+			return nil, err
+		}
+		r.requestReplacers = append(r.requestReplacers, rep)
+	}
+
+	if len(config.ContentTypes) > 0 {
+		r.contentTypes = nil
+		for _, contentType := range config.ContentTypes {
+			mediaType, _, err := mime.ParseMediaType(contentType)
+			if err != nil {
+				return nil, err
+			}
+			r.contentTypes = append(r.contentTypes, mediaType)
+		}
+	}
+
+	return r, nil
+}
+
+func (r *rewriteBody) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return r.name, tracing.SpanKindNoneEnum
+}
+
+func (r *rewriteBody) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), r.name, typeName))
+
+	if len(r.requestReplacers) > 0 && r.rewritable(req.Header) && req.Body != nil && req.Body != http.NoBody {
+		r.rewriteRequest(req)
+	}
+
+	if len(r.replacers) == 0 {
+		r.next.ServeHTTP(rw, req)
+		return
+	}
+
+	// Only the gzip-encoded responses can be rewritten.
+	if acceptEncoding := req.Header.Values("Accept-Encoding"); len(acceptEncoding) > 0 {
+		if compress.AcceptsEncoding(acceptEncoding, "gzip") {
+			req.Header.Set("Accept-Encoding", "gzip")
+		} else {
+			req.Header.Del("Accept-Encoding")
+		}
+	}
+
+	writer := &responseWriter{rw: rw, middleware: r, head: req.Method == http.MethodHead}
+	// The next handler can panic, e.g. with http.ErrAbortHandler when the body of the service cannot be read.
+	defer writer.release()
+
+	r.next.ServeHTTP(writer, req)
+
+	if err := writer.close(); err != nil {
+		logger.Debugf("Error rewriting response body: %v", err)
+	}
+}
+
+// rewritable tells whether the body with the given headers is to be rewritten.
+func (r *rewriteBody) rewritable(header http.Header) bool {
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+
+	for _, contentType := range r.contentTypes {
+		if contentType == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+// rewriteRequest rewrites the request body while it is read, so its size is unknown.
+func (r *rewriteBody) rewriteRequest(req *http.Request) {
+	if encoding := req.Header.Get("Content-Encoding"); encoding != "" && encoding != "identity" {
+		return
+	}
+
+	body := &requestBody{src: req.Body, chunk: make([]byte, 32*1024)}
+	body.pipeline = newPipeline(r.requestReplacers, &body.buf)
+
+	req.Body = body
+	req.ContentLength = -1
+	req.Header.Del("Content-Length")
+}
+
+// requestBody rewrites the body read from src,
+// so that only the end of the body held back by the rewriters is kept in memory.
+type requestBody struct {
+	src      io.ReadCloser
+	pipeline pipeline
+	chunk    []byte
+	buf      bytes.Buffer // the rewritten body not read yet.
+	err      error
+}
+
+func (b *requestBody) Read(p []byte) (int, error) {
+	for b.buf.Len() == 0 {
+		if b.err != nil {
+			return 0, b.err
+		}
+		b.fill()
+	}
+
+	return b.buf.Read(p)
+}
+
+// fill rewrites the next chunk of the body, or the end of the body held back by the rewriters once src is read.
+func (b *requestBody) fill() {
+	n, err := b.src.Read(b.chunk)
+	if n > 0 {
+		if _, werr := b.pipeline.Write(b.chunk[:n]); werr != nil {
+			b.err = werr
+			return
+		}
+	}
+
+	switch {
+	case errors.Is(err, io.EOF):
+		b.err = io.EOF
+		if ferr := b.pipeline.flush(); ferr != nil {
+			b.err = ferr
+		}
+	case err != nil:
+		b.err = err
+	}
+}
+
+func (b *requestBody) Close() error {
+	return b.src.Close()
+}
+
+// responseWriter rewrites the body of the responses with a rewritable content type.
+type responseWriter struct {
+	rw          http.ResponseWriter
+	middleware  *rewriteBody
+	head        bool
+	wroteHeader bool
+
+	// The chain the body is written to, when the response is rewritten.
+	body     io.Writer
+	decoder  *gzipDecoder
+	pipeline pipeline
+	encoder  *gzip.Writer
+	sink     *sink
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.rw.Header()
+}
+
+func (w *responseWriter) WriteHeader(statusCode int) {
+	if w.wroteHeader {
+		return
+	}
+
+	// 1xx informational responses are not rewritten.
+	if statusCode >= http.StatusContinue && statusCode < http.StatusOK {
+		w.rw.WriteHeader(statusCode)
+		return
+	}
+
+	w.wroteHeader = true
+
+	header := w.rw.Header()
+	if statusCode == http.StatusNoContent || statusCode == http.StatusNotModified || statusCode == http.StatusPartialContent ||
+		!w.middleware.rewritable(header) {
+		w.rw.WriteHeader(statusCode)
+		return
+	}
+
+	encoding := header.Get("Content-Encoding")
+	if encoding != "" && encoding != "identity" && encoding != "gzip" {
+		w.rw.WriteHeader(statusCode)
+		return
+	}
+
+	// The size of the rewritten body is unknown.
+	header.Del("Content-Length")
+	if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		header.Set("ETag", "W/"+etag)
+	}
+
+	if w.head {
+		w.rw.WriteHeader(statusCode)
+		return
+	}
+
+	w.sink = &sink{rw: w.rw, statusCode: statusCode}
+
+	var next io.Writer = w.sink
+	if encoding == "gzip" {
+		w.encoder = gzip.NewWriter(w.sink)
+		next = w.encoder
+	}
+
+	w.pipeline = newPipeline(w.middleware.replacers, next)
+	w.body = w.pipeline
+
+	if encoding == "gzip" {
+		w.decoder = newGzipDecoder(w.pipeline)
+		w.body = w.decoder
+	}
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.body == nil {
+		return w.rw.Write(p)
+	}
+	return w.body.Write(p)
+}
+
+// Flush sends the rewritten body to the client, except the end of the body held back in case a match spans several writes.
+func (w *responseWriter) Flush() {
+	// The decompressed body is rewritten concurrently.
+	if w.decoder != nil {
+		return
+	}
+
+	if w.sink != nil {
+		_ = w.sink.stream()
+	}
+
+	if flusher, ok := w.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack hijacks the connection.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.rw.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T is not a http.Hijacker", w.rw)
+	}
+	return hijacker.Hijack()
+}
+
+// release stops the decompression of the body, if any, when the response is aborted before being closed.
+func (w *responseWriter) release() {
+	if w.decoder != nil {
+		w.decoder.abort()
+	}
+}
+
+// close writes the end of the rewritten body.
+func (w *responseWriter) close() error {
+	if w.body == nil {
+		return nil
+	}
+
+	if w.decoder != nil {
+		if err := w.decoder.close(); err != nil {
+			return err
+		}
+	}
+
+	if err := w.pipeline.flush(); err != nil {
+		return err
+	}
+
+	if w.encoder != nil {
+		if err := w.encoder.Close(); err != nil {
+			return err
+		}
+	}
+
+	return w.sink.close()
+}
+
+// sink writes the rewritten body to the response writer.
+// It holds back the headers and the beginning of the body, up to maxBufferedSize,
+// so that the responses with a small rewritten body are sent with a Content-Length header.
+type sink struct {
+	rw         http.ResponseWriter
+	statusCode int
+	buf        []byte
+	streaming  bool
+}
+
+func (s *sink) Write(p []byte) (int, error) {
+	if s.streaming {
+		return s.rw.Write(p)
+	}
+
+	s.buf = append(s.buf, p...)
+	if len(s.buf) > maxBufferedSize {
+		if err := s.stream(); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// stream sends the headers and the body held back, without a Content-Length header.
+func (s *sink) stream() error {
+	if s.streaming {
+		return nil
+	}
+	s.streaming = true
+
+	s.rw.WriteHeader(s.statusCode)
+	_, err := s.rw.Write(s.buf)
+	s.buf = nil
+
+	return err
+}
+
+func (s *sink) close() error {
+	if s.streaming {
+		return nil
+	}
+	s.streaming = true
+
+	s.rw.Header().Set("Content-Length", strconv.Itoa(len(s.buf)))
+	s.rw.WriteHeader(s.statusCode)
+	_, err := s.rw.Write(s.buf)
+
+	return err
+}
@@ -0,0 +1,299 @@
+package rewritebody
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestRewriteBody(t *testing.T) {
+	config := dynamic.RewriteBody{
+		Rewrites: []dynamic.RewriteBodyRule{
+			{Regex: `href="/`, Replacement: `href="/app/`},
+			{Literal: "</body>", Replacement: "<script></script></body>"},
+		},
+	}
+
+	largeBody := strings.Repeat(`<a href="/foo">`, 10000)
+
+	testCases := []struct {
+		desc                  string
+		config                dynamic.RewriteBody
+		method                string
+		statusCode            int
+		contentType           string
+		contentEncoding       string
+		body                  string
+		expectedBody          string
+		expectedContentLength string
+	}{
+		{
+			desc:                  "HTML response",
+			config:                config,
+			contentType:           "text/html; charset=utf-8",
+			body:                  `<body><a href="/foo"></a></body>`,
+			expectedBody:          `<body><a href="/app/foo"></a><script></script></body>`,
+			expectedContentLength: "53",
+		},
+		{
+			desc:            "gzip-encoded response",
+			config:          config,
+			contentType:     "text/html",
+			contentEncoding: "gzip",
+			body:            `<body><a href="/foo"></a></body>`,
+			expectedBody:    `<body><a href="/app/foo"></a><script></script></body>`,
+		},
+		{
+			desc:                  "large response",
+			config:                config,
+			contentType:           "text/html",
+			body:                  largeBody,
+			expectedBody:          strings.ReplaceAll(largeBody, `href="/`, `href="/app/`),
+			expectedContentLength: "",
+		},
+		{
+			desc:                  "not rewritten content type",
+			config:                config,
+			contentType:           "application/json",
+			body:                  `{"href":"/foo"}`,
+			expectedBody:          `{"href":"/foo"}`,
+			expectedContentLength: "15",
+		},
+		{
+			desc: "configured content type",
+			config: dynamic.RewriteBody{
+				Rewrites:     []dynamic.RewriteBodyRule{{Literal: `"/foo"`, Replacement: `"/app/foo"`}},
+				ContentTypes: []string{"application/json"},
+			},
+			contentType:           "application/json",
+			body:                  `{"href":"/foo"}`,
+			expectedBody:          `{"href":"/app/foo"}`,
+			expectedContentLength: "19",
+		},
+		{
+			desc:                  "not modified response",
+			config:                config,
+			statusCode:            http.StatusNotModified,
+			contentType:           "text/html",
+			expectedContentLength: "0",
+		},
+		{
+			desc:                  "HEAD request",
+			config:                config,
+			method:                http.MethodHead,
+			contentType:           "text/html",
+			body:                  `<body></body>`,
+			expectedContentLength: "",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				body := []byte(test.body)
+				if test.contentEncoding == "gzip" {
+					assert.Equal(t, "gzip", req.Header.Get("Accept-Encoding"))
+
+					var buf bytes.Buffer
+					gw := gzip.NewWriter(&buf)
+					_, err := gw.Write(body)
+					require.NoError(t, err)
+					require.NoError(t, gw.Close())
+					body = buf.Bytes()
+
+					rw.Header().Set("Content-Encoding", "gzip")
+				}
+
+				rw.Header().Set("Content-Type", test.contentType)
+				rw.Header().Set("Content-Length", strconv.Itoa(len(body)))
+
+				statusCode := test.statusCode
+				if statusCode == 0 {
+					statusCode = http.StatusOK
+				}
+				rw.WriteHeader(statusCode)
+
+				if req.Method == http.MethodHead || statusCode == http.StatusNotModified {
+					return
+				}
+
+				// Writes the body in chunks, as a proxied response would be.
+				for len(body) > 0 {
+					n := 1000
+					if n > len(body) {
+						n = len(body)
+					}
+					_, _ = rw.Write(body[:n])
+					body = body[n:]
+				}
+			})
+
+			handler, err := New(context.Background(), next, test.config, "rewriteBody")
+			require.NoError(t, err)
+
+			method := test.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			req := httptest.NewRequest(method, "http://foo.bar", nil)
+			req.Header.Set("Accept-Encoding", "gzip, br")
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			body := recorder.Body.Bytes()
+			if test.contentEncoding == "gzip" {
+				assert.Equal(t, "gzip", recorder.Header().Get("Content-Encoding"))
+				assert.Equal(t, strconv.Itoa(len(body)), recorder.Header().Get("Content-Length"))
+
+				gr, err := gzip.NewReader(bytes.NewReader(body))
+				require.NoError(t, err)
+				body, err = io.ReadAll(gr)
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, test.expectedBody, string(body))
+			if test.contentEncoding == "" {
+				assert.Equal(t, test.expectedContentLength, recorder.Header().Get("Content-Length"))
+			}
+		})
+	}
+}
+
+func TestRewriteBody_request(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+
+		// The rewritten body is streamed.
+		assert.Equal(t, int64(-1), req.ContentLength)
+		assert.Empty(t, req.Header.Get("Content-Length"))
+		_, _ = rw.Write(body)
+	})
+
+	handler, err := New(context.Background(), next, dynamic.RewriteBody{
+		RequestRewrites: []dynamic.RewriteBodyRule{{Literal: "https://example.com/app/", Replacement: "http://internal/"}},
+		ContentTypes:    []string{"application/x-www-form-urlencoded"},
+	}, "rewriteBody")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "http://foo.bar", strings.NewReader("url=https://example.com/app/foo"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	assert.Equal(t, "url=http://internal/foo", recorder.Body.String())
+}
+
+func TestRewriteBody_largeRequest(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = io.Copy(rw, req.Body)
+	})
+
+	handler, err := New(context.Background(), next, dynamic.RewriteBody{
+		RequestRewrites: []dynamic.RewriteBodyRule{{Literal: "https://example.com/app/", Replacement: "http://internal/"}},
+		ContentTypes:    []string{"text/plain"},
+	}, "rewriteBody")
+	require.NoError(t, err)
+
+	body := strings.Repeat("url=https://example.com/app/foo\n", 100000)
+
+	req := httptest.NewRequest(http.MethodPost, "http://foo.bar", strings.NewReader(body))
+	req.Header.Set("Content-Type", "text/plain")
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	assert.Equal(t, strings.Repeat("url=http://internal/foo\n", 100000), recorder.Body.String())
+}
+
+func TestRewriteBody_acceptEncoding(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		acceptEncoding string
+		expected       string
+	}{
+		{
+			desc:           "gzip accepted",
+			acceptEncoding: "br, gzip;q=0.8",
+			expected:       "gzip",
+		},
+		{
+			desc:           "gzip refused",
+			acceptEncoding: "gzip;q=0, br",
+		},
+		{
+			desc:           "gzip not listed",
+			acceptEncoding: "br",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				assert.Equal(t, test.expected, req.Header.Get("Accept-Encoding"))
+			})
+
+			handler, err := New(context.Background(), next, dynamic.RewriteBody{
+				Rewrites: []dynamic.RewriteBodyRule{{Literal: "foo", Replacement: "bar"}},
+			}, "rewriteBody")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://foo.bar", nil)
+			req.Header.Set("Accept-Encoding", test.acceptEncoding)
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+		})
+	}
+}
+
+func TestRewriteBody_abort(t *testing.T) {
+	var writer *responseWriter
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		writer = rw.(*responseWriter)
+
+		rw.Header().Set("Content-Type", "text/html")
+		rw.Header().Set("Content-Encoding", "gzip")
+		rw.WriteHeader(http.StatusOK)
+
+		// The beginning of a gzip-encoded body, before the body of the service cannot be read anymore.
+		_, _ = rw.Write([]byte{0x1f, 0x8b})
+		panic(http.ErrAbortHandler)
+	})
+
+	handler, err := New(context.Background(), next, dynamic.RewriteBody{
+		Rewrites: []dynamic.RewriteBodyRule{{Literal: "foo", Replacement: "bar"}},
+	}, "rewriteBody")
+	require.NoError(t, err)
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://foo.bar", nil))
+	})
+
+	// The decompression of the body is stopped.
+	require.NotNil(t, writer.decoder)
+	assert.True(t, writer.decoder.stopped)
+}
+
+func TestRewriteBody_noRewrite(t *testing.T) {
+	_, err := New(context.Background(), http.NotFoundHandler(), dynamic.RewriteBody{}, "rewriteBody")
+	assert.Error(t, err)
+}
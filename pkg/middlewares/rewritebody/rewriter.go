@@ -0,0 +1,216 @@
+package rewritebody
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+// maxMatchSize is the maximum size of a match.
+// The rewriters hold back this much of the body, in case a match spans several writes.
+const maxMatchSize = 4 * 1024
+
+// replacer replaces the matches of a regular expression or of a literal string.
+type replacer struct {
+	regex       *regexp.Regexp
+	literal     []byte
+	replacement []byte
+}
+
+func newReplacer(rule dynamic.RewriteBodyRule) (*replacer, error) {
+	switch {
+	case rule.Regex != "" && rule.Literal != "":
+		return nil, errors.New("a rewrite cannot have both a regex and a literal")
+
+	case rule.Regex != "":
+		re, err := regexp.Compile(rule.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("error compiling regular expression %s: %w", rule.Regex, err)
+		}
+		return &replacer{regex: re, replacement: []byte(rule.Replacement)}, nil
+
+	case rule.Literal != "":
+		if len(rule.Literal) > maxMatchSize {
+			return nil, fmt.Errorf("the literal of a rewrite cannot be longer than %d bytes", maxMatchSize)
+		}
+		return &replacer{literal: []byte(rule.Literal), replacement: []byte(rule.Replacement)}, nil
+
+	default:
+		return nil, errors.New("a rewrite requires a regex or a literal")
+	}
+}
+
+// matches returns the start and end indexes of the successive matches in b,
+// followed by the indexes of the submatches of the regular expression.
+func (r *replacer) matches(b []byte) [][]int {
+	if r.regex != nil {
+		return r.regex.FindAllSubmatchIndex(b, -1)
+	}
+
+	var locs [][]int
+	for pos := 0; ; {
+		i := bytes.Index(b[pos:], r.literal)
+		if i < 0 {
+			return locs
+		}
+
+		locs = append(locs, []int{pos + i, pos + i + len(r.literal)})
+		pos += i + len(r.literal)
+	}
+}
+
+// expand appends the replacement of the match of b at loc to dst.
+func (r *replacer) expand(dst, b []byte, loc []int) []byte {
+	if r.regex != nil {
+		return r.regex.Expand(dst, r.replacement, b, loc)
+	}
+	return append(dst, r.replacement...)
+}
+
+// rewriter replaces the matches of its replacer in the body written to it, and writes the result to the next writer.
+type rewriter struct {
+	replacer *replacer
+	next     io.Writer
+	buf      []byte
+	out      []byte
+}
+
+func (w *rewriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+
+	if len(w.buf) < 2*maxMatchSize {
+		return len(p), nil
+	}
+
+	if err := w.rewrite(len(w.buf) - maxMatchSize); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// flush rewrites and writes all the buffered body.
+func (w *rewriter) flush() error {
+	return w.rewrite(len(w.buf))
+}
+
+// rewrite rewrites and writes the buffered body up to limit, or up to the start of a match crossing limit,
+// and keeps the rest of the body for the next writes.
+func (w *rewriter) rewrite(limit int) error {
+	out := w.out[:0]
+	final := limit == len(w.buf)
+
+	var pos int
+	for _, loc := range w.replacer.matches(w.buf) {
+		// Unless all the body is rewritten, a match ending at the limit could go on with the next writes.
+		if loc[1] > limit || (loc[1] == limit && !final) {
+			if loc[0] < limit {
+				limit = loc[0]
+			}
+			break
+		}
+
+		out = append(out, w.buf[pos:loc[0]]...)
+		out = w.replacer.expand(out, w.buf, loc)
+		pos = loc[1]
+	}
+
+	out = append(out, w.buf[pos:limit]...)
+	w.buf = append(w.buf[:0], w.buf[limit:]...)
+	w.out = out
+
+	if len(out) == 0 {
+		return nil
+	}
+
+	_, err := w.next.Write(out)
+	return err
+}
+
+// pipeline is a chain of rewriters, each one writing to the next one.
+type pipeline []*rewriter
+
+func newPipeline(replacers []*replacer, w io.Writer) pipeline {
+	p := make(pipeline, len(replacers))
+	for i := len(replacers) - 1; i >= 0; i-- {
+		p[i] = &rewriter{replacer: replacers[i], next: w}
+		w = p[i]
+	}
+	return p
+}
+
+func (p pipeline) Write(b []byte) (int, error) {
+	return p[0].Write(b)
+}
+
+// flush flushes the rewriters in order, so that each one receives all the body before being flushed.
+func (p pipeline) flush() error {
+	for _, w := range p {
+		if err := w.flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// errAborted is the error of the decompression of a body which is not written completely.
+var errAborted = errors.New("body aborted")
+
+// gzipDecoder decompresses the gzip-encoded body written to it, and writes the result to the next writer.
+type gzipDecoder struct {
+	pw   *io.PipeWriter
+	done chan error
+
+	stopped bool
+	err     error
+}
+
+func newGzipDecoder(next io.Writer) *gzipDecoder {
+	pr, pw := io.Pipe()
+	d := &gzipDecoder{pw: pw, done: make(chan error, 1)}
+
+	go func() {
+		gr, err := gzip.NewReader(pr)
+		switch {
+		case errors.Is(err, io.EOF):
+			// The body is empty.
+			err = nil
+		case err == nil:
+			_, err = io.Copy(next, gr)
+		}
+
+		_ = pr.CloseWithError(err)
+		d.done <- err
+	}()
+
+	return d
+}
+
+func (d *gzipDecoder) Write(p []byte) (int, error) {
+	return d.pw.Write(p)
+}
+
+// close waits for all the body to be decompressed.
+func (d *gzipDecoder) close() error {
+	_ = d.pw.Close()
+	return d.wait()
+}
+
+// abort stops the decompression, when the body is not written completely, and waits for it to stop.
+// It does nothing once the decompression is over.
+func (d *gzipDecoder) abort() {
+	_ = d.pw.CloseWithError(errAborted)
+	_ = d.wait()
+}
+
+func (d *gzipDecoder) wait() error {
+	if !d.stopped {
+		d.err = <-d.done
+		d.stopped = true
+	}
+	return d.err
+}
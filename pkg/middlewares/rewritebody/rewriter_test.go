@@ -0,0 +1,122 @@
+package rewritebody
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestPipeline(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rules    []dynamic.RewriteBodyRule
+		body     string
+		expected string
+	}{
+		{
+			desc:     "literal",
+			rules:    []dynamic.RewriteBodyRule{{Literal: "</body>", Replacement: "<script></script></body>"}},
+			body:     "<html><body>foo</body></html>",
+			expected: "<html><body>foo<script></script></body></html>",
+		},
+		{
+			desc:     "regex with submatches",
+			rules:    []dynamic.RewriteBodyRule{{Regex: `(href|src)="/`, Replacement: `$1="/app/`}},
+			body:     `<a href="/foo"><img src="/bar.png"><a href="https://example.com/">`,
+			expected: `<a href="/app/foo"><img src="/app/bar.png"><a href="https://example.com/">`,
+		},
+		{
+			desc: "rewrites in order",
+			rules: []dynamic.RewriteBodyRule{
+				{Literal: "foo", Replacement: "bar"},
+				{Literal: "bar", Replacement: "baz"},
+			},
+			body:     "foo bar",
+			expected: "baz baz",
+		},
+		{
+			desc:     "matches spanning the held back body",
+			rules:    []dynamic.RewriteBodyRule{{Literal: "http://internal", Replacement: "https://example.com"}},
+			body:     strings.Repeat("<a href=\"http://internal/\">", 1000),
+			expected: strings.Repeat("<a href=\"https://example.com/\">", 1000),
+		},
+		{
+			desc:     "no match",
+			rules:    []dynamic.RewriteBodyRule{{Regex: "foo+", Replacement: "bar"}},
+			body:     strings.Repeat("fo", 10000),
+			expected: strings.Repeat("fo", 10000),
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var replacers []*replacer
+			for _, rule := range test.rules {
+				rep, err := newReplacer(rule)
+				require.NoError(t, err)
+				replacers = append(replacers, rep)
+			}
+
+			// Writes the body in chunks of various sizes, so that the matches span several writes.
+			for _, size := range []int{1, 7, 1000, len(test.body)} {
+				var out bytes.Buffer
+				p := newPipeline(replacers, &out)
+
+				for body := test.body; len(body) > 0; {
+					n := size
+					if n > len(body) {
+						n = len(body)
+					}
+
+					_, err := p.Write([]byte(body[:n]))
+					require.NoError(t, err)
+					body = body[n:]
+				}
+				require.NoError(t, p.flush())
+
+				assert.Equal(t, test.expected, out.String(), "chunk size %d", size)
+			}
+		})
+	}
+}
+
+func TestNewReplacer(t *testing.T) {
+	testCases := []struct {
+		desc string
+		rule dynamic.RewriteBodyRule
+	}{
+		{
+			desc: "no regex nor literal",
+			rule: dynamic.RewriteBodyRule{Replacement: "foo"},
+		},
+		{
+			desc: "both regex and literal",
+			rule: dynamic.RewriteBodyRule{Regex: "foo", Literal: "foo"},
+		},
+		{
+			desc: "invalid regex",
+			rule: dynamic.RewriteBodyRule{Regex: "(foo"},
+		},
+		{
+			desc: "literal too long",
+			rule: dynamic.RewriteBodyRule{Literal: strings.Repeat("a", maxMatchSize+1)},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := newReplacer(test.rule)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -209,6 +209,18 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 			continue
 		}
 
+		oidc, err := createOIDCMiddleware(client, middleware.Namespace, middleware.Spec.OIDC)
+		if err != nil {
+			log.FromContext(ctxMid).Errorf("Error while reading OIDC middleware: %v", err)
+			continue
+		}
+
+		jwt, err := createJWTMiddleware(client, middleware.Namespace, middleware.Spec.JWT)
+		if err != nil {
+			log.FromContext(ctxMid).Errorf("Error while reading JWT middleware: %v", err)
+			continue
+		}
+
 		errorPage, errorPageService, err := p.createErrorPageMiddleware(client, middleware.Namespace, middleware.Spec.Errors)
 		if err != nil {
 			log.FromContext(ctxMid).Errorf("Error while reading error page middleware: %v", err)
@@ -245,6 +257,7 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 			StripPrefixRegex:  middleware.Spec.StripPrefixRegex,
 			ReplacePath:       middleware.Spec.ReplacePath,
 			ReplacePathRegex:  middleware.Spec.ReplacePathRegex,
+			RewriteBody:       middleware.Spec.RewriteBody,
 			Chain:             createChainMiddleware(ctxMid, middleware.Namespace, middleware.Spec.Chain),
 			IPWhiteList:       middleware.Spec.IPWhiteList,
 			Headers:           middleware.Spec.Headers,
@@ -255,9 +268,14 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 			BasicAuth:         basicAuth,
 			DigestAuth:        digestAuth,
 			ForwardAuth:       forwardAuth,
+			OIDC:              oidc,
 			InFlightReq:       middleware.Spec.InFlightReq,
+			JWT:               jwt,
 			Buffering:         middleware.Spec.Buffering,
+			Cache:             middleware.Spec.Cache,
 			CircuitBreaker:    middleware.Spec.CircuitBreaker,
+			Limits:            middleware.Spec.Limits,
+			Maintenance:       createMaintenanceMiddleware(middleware.Spec.Maintenance),
 			Compress:          middleware.Spec.Compress,
 			PassTLSClientCert: middleware.Spec.PassTLSClientCert,
 			Retry:             retry,
@@ -440,6 +458,12 @@ func (p *Provider) createErrorPageMiddleware(client Client, namespace string, er
 	errorPageMiddleware := &dynamic.ErrorPage{
 		Status: errorPage.Status,
 		Query:  errorPage.Query,
+		Pages:  createErrorPageContents(errorPage.Pages),
+	}
+
+	// The pages are served without a service.
+	if len(errorPage.Pages) > 0 && errorPage.Service.Name == "" {
+		return errorPageMiddleware, nil, nil
 	}
 
 	balancerServerHTTP, err := configBuilder{client, p.AllowCrossNamespace}.buildServersLB(namespace, errorPage.Service.LoadBalancerSpec)
@@ -450,6 +474,38 @@ func (p *Provider) createErrorPageMiddleware(client Client, namespace string, er
 	return errorPageMiddleware, balancerServerHTTP, nil
 }
 
+func createMaintenanceMiddleware(maintenance *v1alpha1.Maintenance) *dynamic.Maintenance {
+	if maintenance == nil {
+		return nil
+	}
+
+	return &dynamic.Maintenance{
+		Enabled:           maintenance.Enabled,
+		RetryAfter:        maintenance.RetryAfter,
+		SourceRange:       maintenance.SourceRange,
+		IPStrategy:        maintenance.IPStrategy,
+		BypassHeaderName:  maintenance.BypassHeaderName,
+		BypassHeaderValue: maintenance.BypassHeaderValue,
+		Pages:             createErrorPageContents(maintenance.Pages),
+	}
+}
+
+func createErrorPageContents(pages []v1alpha1.ErrorPageContent) []dynamic.ErrorPageContent {
+	if len(pages) == 0 {
+		return nil
+	}
+
+	contents := make([]dynamic.ErrorPageContent, 0, len(pages))
+	for _, page := range pages {
+		contents = append(contents, dynamic.ErrorPageContent{
+			ContentType: page.ContentType,
+			Body:        page.Body,
+		})
+	}
+
+	return contents
+}
+
 func createForwardAuthMiddleware(k8sClient Client, namespace string, auth *v1alpha1.ForwardAuth) (*dynamic.ForwardAuth, error) {
 	if auth == nil {
 		return nil, nil
@@ -464,35 +520,208 @@ func createForwardAuthMiddleware(k8sClient Client, namespace string, auth *v1alp
 		AuthResponseHeaders:      auth.AuthResponseHeaders,
 		AuthResponseHeadersRegex: auth.AuthResponseHeadersRegex,
 		AuthRequestHeaders:       auth.AuthRequestHeaders,
+		ForwardBody:              auth.ForwardBody,
+		MaxBodySize:              auth.MaxBodySize,
+		PreserveRequestMethod:    auth.PreserveRequestMethod,
+	}
+
+	if auth.Cache != nil {
+		cache, err := createForwardAuthCache(auth.Cache)
+		if err != nil {
+			return nil, err
+		}
+		forwardAuth.Cache = cache
 	}
 
 	if auth.TLS == nil {
 		return forwardAuth, nil
 	}
 
-	forwardAuth.TLS = &dynamic.ClientTLS{
-		CAOptional:         auth.TLS.CAOptional,
-		InsecureSkipVerify: auth.TLS.InsecureSkipVerify,
+	clientTLS, err := createClientTLS(k8sClient, namespace, auth.TLS)
+	if err != nil {
+		return nil, err
+	}
+	forwardAuth.TLS = clientTLS
+
+	return forwardAuth, nil
+}
+
+func createForwardAuthCache(cache *v1alpha1.ForwardAuthCache) (*dynamic.ForwardAuthCache, error) {
+	result := &dynamic.ForwardAuthCache{Headers: cache.Headers}
+	result.SetDefaults()
+
+	if cache.TTL != nil {
+		if err := result.TTL.Set(cache.TTL.String()); err != nil {
+			return nil, fmt.Errorf("invalid forward auth cache TTL: %w", err)
+		}
 	}
 
-	if len(auth.TLS.CASecret) > 0 {
-		caSecret, err := loadCASecret(namespace, auth.TLS.CASecret, k8sClient)
+	if cache.MaxEntries != nil {
+		result.MaxEntries = *cache.MaxEntries
+	}
+
+	return result, nil
+}
+
+func createClientTLS(k8sClient Client, namespace string, clientTLS *v1alpha1.ClientTLS) (*dynamic.ClientTLS, error) {
+	result := &dynamic.ClientTLS{
+		CAOptional:         clientTLS.CAOptional,
+		InsecureSkipVerify: clientTLS.InsecureSkipVerify,
+	}
+
+	if len(clientTLS.CASecret) > 0 {
+		caSecret, err := loadCASecret(namespace, clientTLS.CASecret, k8sClient)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load auth ca secret: %w", err)
 		}
-		forwardAuth.TLS.CA = caSecret
+		result.CA = caSecret
 	}
 
-	if len(auth.TLS.CertSecret) > 0 {
-		authSecretCert, authSecretKey, err := loadAuthTLSSecret(namespace, auth.TLS.CertSecret, k8sClient)
+	if len(clientTLS.CertSecret) > 0 {
+		authSecretCert, authSecretKey, err := loadAuthTLSSecret(namespace, clientTLS.CertSecret, k8sClient)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load auth secret: %w", err)
 		}
-		forwardAuth.TLS.Cert = authSecretCert
-		forwardAuth.TLS.Key = authSecretKey
+		result.Cert = authSecretCert
+		result.Key = authSecretKey
 	}
 
-	return forwardAuth, nil
+	return result, nil
+}
+
+func createOIDCMiddleware(k8sClient Client, namespace string, oidc *v1alpha1.OIDC) (*dynamic.OIDC, error) {
+	if oidc == nil {
+		return nil, nil
+	}
+
+	result := &dynamic.OIDC{}
+	result.SetDefaults()
+
+	result.Issuer = oidc.Issuer
+	result.ClientID = oidc.ClientID
+	result.LogoutPath = oidc.LogoutPath
+	result.Cookie = oidc.Cookie
+	result.AllowedGroups = oidc.AllowedGroups
+	result.AllowedEmails = oidc.AllowedEmails
+	result.ForwardClaims = oidc.ForwardClaims
+
+	if len(oidc.Scopes) > 0 {
+		result.Scopes = oidc.Scopes
+	}
+	if oidc.CallbackPath != "" {
+		result.CallbackPath = oidc.CallbackPath
+	}
+	if oidc.GroupsClaim != "" {
+		result.GroupsClaim = oidc.GroupsClaim
+	}
+
+	if oidc.Secret == "" {
+		return nil, fmt.Errorf("OIDC secret must be set")
+	}
+
+	secret, ok, err := k8sClient.GetSecret(namespace, oidc.Secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch secret '%s/%s': %w", namespace, oidc.Secret, err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("secret '%s/%s' not found", namespace, oidc.Secret)
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("data for secret '%s/%s' must not be nil", namespace, oidc.Secret)
+	}
+
+	result.ClientSecret = string(secret.Data["clientSecret"])
+	result.SessionSecret = string(secret.Data["sessionSecret"])
+	if result.SessionSecret == "" {
+		return nil, fmt.Errorf("secret '%s/%s' must have a sessionSecret entry", namespace, oidc.Secret)
+	}
+
+	if oidc.TLS != nil {
+		clientTLS, err := createClientTLS(k8sClient, namespace, oidc.TLS)
+		if err != nil {
+			return nil, err
+		}
+		result.TLS = clientTLS
+	}
+
+	return result, nil
+}
+
+func createJWTMiddleware(k8sClient Client, namespace string, jwt *v1alpha1.JWT) (*dynamic.JWT, error) {
+	if jwt == nil {
+		return nil, nil
+	}
+
+	result := &dynamic.JWT{
+		Issuers:       jwt.Issuers,
+		Audiences:     jwt.Audiences,
+		Claims:        jwt.Claims,
+		ForwardClaims: jwt.ForwardClaims,
+		RemoveHeader:  jwt.RemoveHeader,
+	}
+
+	if jwt.ClockSkew != nil {
+		if err := result.ClockSkew.Set(jwt.ClockSkew.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(jwt.KeysSecret) > 0 {
+		keys, err := loadJWTKeys(namespace, jwt.KeysSecret, k8sClient)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load JWT keys: %w", err)
+		}
+		result.Keys = keys
+	}
+
+	if jwt.JWKS == nil {
+		return result, nil
+	}
+
+	result.JWKS = &dynamic.JWKS{URL: jwt.JWKS.URL}
+	result.JWKS.SetDefaults()
+
+	if jwt.JWKS.RefreshInterval != nil {
+		if err := result.JWKS.RefreshInterval.Set(jwt.JWKS.RefreshInterval.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	if jwt.JWKS.TLS != nil {
+		clientTLS, err := createClientTLS(k8sClient, namespace, jwt.JWKS.TLS)
+		if err != nil {
+			return nil, err
+		}
+		result.JWKS.TLS = clientTLS
+	}
+
+	return result, nil
+}
+
+func loadJWTKeys(namespace, secretName string, k8sClient Client) ([]tls.FileOrContent, error) {
+	secret, ok, err := k8sClient.GetSecret(namespace, secretName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch secret '%s/%s': %w", namespace, secretName, err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("secret '%s/%s' not found", namespace, secretName)
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("data for secret '%s/%s' must not be nil", namespace, secretName)
+	}
+
+	var names []string
+	for name := range secret.Data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var keys []tls.FileOrContent
+	for _, name := range names {
+		keys = append(keys, tls.FileOrContent(secret.Data[name]))
+	}
+
+	return keys, nil
 }
 
 func loadCASecret(namespace, secretName string, k8sClient Client) (string, error) {
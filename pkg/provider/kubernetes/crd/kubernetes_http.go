@@ -195,6 +195,7 @@ func (c configBuilder) buildTraefikService(ctx context.Context, tService *v1alph
 // It adds it to the given conf map.
 func (c configBuilder) buildServicesLB(ctx context.Context, namespace string, tService v1alpha1.ServiceSpec, id string, conf map[string]*dynamic.Service) error {
 	var wrrServices []dynamic.WRRService
+	fullNames := make(map[string]string)
 
 	for _, service := range tService.Weighted.Services {
 		fullName, k8sService, err := c.nameAndService(ctx, namespace, service.LoadBalancerSpec)
@@ -215,12 +216,29 @@ func (c configBuilder) buildServicesLB(ctx context.Context, namespace string, tS
 			Name:   fullName,
 			Weight: weight,
 		})
+
+		if _, ok := fullNames[service.Name]; !ok {
+			fullNames[service.Name] = fullName
+		}
+	}
+
+	var overrides []dynamic.WRROverride
+	for _, override := range tService.Weighted.Overrides {
+		fullName, ok := fullNames[override.Service]
+		if !ok {
+			return fmt.Errorf("override service %s is not one of the weighted services", override.Service)
+		}
+
+		override.Service = fullName
+		overrides = append(overrides, override)
 	}
 
 	conf[id] = &dynamic.Service{
 		Weighted: &dynamic.WeightedRoundRobin{
-			Services: wrrServices,
-			Sticky:   tService.Weighted.Sticky,
+			Services:   wrrServices,
+			Sticky:     tService.Weighted.Sticky,
+			StickyUser: tService.Weighted.StickyUser,
+			Overrides:  overrides,
 		},
 	}
 	return nil
@@ -250,8 +268,10 @@ func (c configBuilder) buildMirroring(ctx context.Context, tService *v1alpha1.Tr
 		}
 
 		mirrorServices = append(mirrorServices, dynamic.MirrorService{
-			Name:    mirroredName,
-			Percent: mirror.Percent,
+			Name:       mirroredName,
+			Percent:    mirror.Percent,
+			Rule:       mirror.Match,
+			HashHeader: mirror.HashHeader,
 		})
 	}
 
@@ -260,6 +280,7 @@ func (c configBuilder) buildMirroring(ctx context.Context, tService *v1alpha1.Tr
 			Service:     fullNameMain,
 			Mirrors:     mirrorServices,
 			MaxBodySize: tService.Spec.Mirroring.MaxBodySize,
+			Compare:     tService.Spec.Mirroring.Compare,
 		},
 	}
 
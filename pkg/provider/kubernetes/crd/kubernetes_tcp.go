@@ -153,6 +153,10 @@ func (p *Provider) createLoadBalancerServerTCP(client Client, parentNamespace st
 		if service.ProxyProtocol.Version != 0 {
 			tcpService.LoadBalancer.ProxyProtocol.Version = service.ProxyProtocol.Version
 		}
+
+		if service.ProxyProtocol.TLVs != nil {
+			tcpService.LoadBalancer.ProxyProtocol.TLVs = service.ProxyProtocol.TLVs.DeepCopy()
+		}
 	}
 
 	if service.TerminationDelay != nil {
@@ -1,6 +1,7 @@
 package v1alpha1
 
 import (
+	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	apiextensionv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -28,6 +29,7 @@ type MiddlewareSpec struct {
 	StripPrefixRegex  *dynamic.StripPrefixRegex      `json:"stripPrefixRegex,omitempty"`
 	ReplacePath       *dynamic.ReplacePath           `json:"replacePath,omitempty"`
 	ReplacePathRegex  *dynamic.ReplacePathRegex      `json:"replacePathRegex,omitempty"`
+	RewriteBody       *dynamic.RewriteBody           `json:"rewriteBody,omitempty"`
 	Chain             *Chain                         `json:"chain,omitempty"`
 	IPWhiteList       *dynamic.IPWhiteList           `json:"ipWhiteList,omitempty"`
 	Headers           *dynamic.Headers               `json:"headers,omitempty"`
@@ -38,9 +40,14 @@ type MiddlewareSpec struct {
 	BasicAuth         *BasicAuth                     `json:"basicAuth,omitempty"`
 	DigestAuth        *DigestAuth                    `json:"digestAuth,omitempty"`
 	ForwardAuth       *ForwardAuth                   `json:"forwardAuth,omitempty"`
+	OIDC              *OIDC                          `json:"oidc,omitempty"`
 	InFlightReq       *dynamic.InFlightReq           `json:"inFlightReq,omitempty"`
+	JWT               *JWT                           `json:"jwt,omitempty"`
 	Buffering         *dynamic.Buffering             `json:"buffering,omitempty"`
+	Cache             *dynamic.Cache                 `json:"cache,omitempty"`
 	CircuitBreaker    *dynamic.CircuitBreaker        `json:"circuitBreaker,omitempty"`
+	Limits            *dynamic.Limits                `json:"limits,omitempty"`
+	Maintenance       *Maintenance                   `json:"maintenance,omitempty"`
 	Compress          *dynamic.Compress              `json:"compress,omitempty"`
 	PassTLSClientCert *dynamic.PassTLSClientCert     `json:"passTLSClientCert,omitempty"`
 	Retry             *Retry                         `json:"retry,omitempty"`
@@ -52,9 +59,19 @@ type MiddlewareSpec struct {
 
 // ErrorPage holds the custom error page configuration.
 type ErrorPage struct {
-	Status  []string `json:"status,omitempty"`
-	Service Service  `json:"service,omitempty"`
-	Query   string   `json:"query,omitempty"`
+	Status  []string           `json:"status,omitempty"`
+	Service Service            `json:"service,omitempty"`
+	Query   string             `json:"query,omitempty"`
+	Pages   []ErrorPageContent `json:"pages,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ErrorPageContent holds an error page served without a service, from an inline template.
+// Unlike the other providers, reading the template from a file is not supported.
+type ErrorPageContent struct {
+	ContentType string `json:"contentType,omitempty"`
+	Body        string `json:"body,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -88,12 +105,84 @@ type DigestAuth struct {
 
 // ForwardAuth holds the http forward authentication configuration.
 type ForwardAuth struct {
-	Address                  string     `json:"address,omitempty"`
-	TrustForwardHeader       bool       `json:"trustForwardHeader,omitempty"`
-	AuthResponseHeaders      []string   `json:"authResponseHeaders,omitempty"`
-	AuthResponseHeadersRegex string     `json:"authResponseHeadersRegex,omitempty"`
-	AuthRequestHeaders       []string   `json:"authRequestHeaders,omitempty"`
-	TLS                      *ClientTLS `json:"tls,omitempty"`
+	Address                  string            `json:"address,omitempty"`
+	TrustForwardHeader       bool              `json:"trustForwardHeader,omitempty"`
+	AuthResponseHeaders      []string          `json:"authResponseHeaders,omitempty"`
+	AuthResponseHeadersRegex string            `json:"authResponseHeadersRegex,omitempty"`
+	AuthRequestHeaders       []string          `json:"authRequestHeaders,omitempty"`
+	TLS                      *ClientTLS        `json:"tls,omitempty"`
+	ForwardBody              bool              `json:"forwardBody,omitempty"`
+	MaxBodySize              int64             `json:"maxBodySize,omitempty"`
+	PreserveRequestMethod    bool              `json:"preserveRequestMethod,omitempty"`
+	Cache                    *ForwardAuthCache `json:"cache,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ForwardAuthCache holds the configuration of the cache of the authentication server responses.
+type ForwardAuthCache struct {
+	Headers    []string            `json:"headers,omitempty"`
+	TTL        *intstr.IntOrString `json:"ttl,omitempty"`
+	MaxEntries *int                `json:"maxEntries,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// JWT holds the JWT authentication configuration.
+type JWT struct {
+	// KeysSecret is the name of the secret holding the keys the token signatures are verified with, one key per data entry.
+	KeysSecret    string              `json:"keysSecret,omitempty"`
+	JWKS          *JWKS               `json:"jwks,omitempty"`
+	Issuers       []string            `json:"issuers,omitempty"`
+	Audiences     []string            `json:"audiences,omitempty"`
+	ClockSkew     *intstr.IntOrString `json:"clockSkew,omitempty"`
+	Claims        map[string]string   `json:"claims,omitempty"`
+	ForwardClaims map[string]string   `json:"forwardClaims,omitempty"`
+	RemoveHeader  bool                `json:"removeHeader,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// JWKS holds the configuration of a JSON Web Key Set the JWT signatures are verified with.
+type JWKS struct {
+	URL             string              `json:"url,omitempty"`
+	RefreshInterval *intstr.IntOrString `json:"refreshInterval,omitempty"`
+	TLS             *ClientTLS          `json:"tls,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// OIDC holds the OpenID Connect authentication configuration.
+type OIDC struct {
+	Issuer   string   `json:"issuer,omitempty"`
+	ClientID string   `json:"clientID,omitempty"`
+	Scopes   []string `json:"scopes,omitempty"`
+	// Secret is the name of the secret holding the client secret, in its clientSecret entry,
+	// and the secret the session cookies are encrypted with, in its sessionSecret entry.
+	Secret        string              `json:"secret,omitempty"`
+	CallbackPath  string              `json:"callbackPath,omitempty"`
+	LogoutPath    string              `json:"logoutPath,omitempty"`
+	Cookie        *dynamic.OIDCCookie `json:"cookie,omitempty"`
+	AllowedGroups []string            `json:"allowedGroups,omitempty"`
+	AllowedEmails []string            `json:"allowedEmails,omitempty"`
+	GroupsClaim   string              `json:"groupsClaim,omitempty"`
+	ForwardClaims map[string]string   `json:"forwardClaims,omitempty"`
+	TLS           *ClientTLS          `json:"tls,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// Maintenance holds the maintenance mode configuration.
+// The enabled state toggled through the API takes precedence over the configured one.
+type Maintenance struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// RetryAfter is the delay advertised in the Retry-After header of the maintenance responses, none if it is 0.
+	RetryAfter        ptypes.Duration     `json:"retryAfter,omitempty"`
+	SourceRange       []string            `json:"sourceRange,omitempty"`
+	IPStrategy        *dynamic.IPStrategy `json:"ipStrategy,omitempty"`
+	BypassHeaderName  string              `json:"bypassHeaderName,omitempty"`
+	BypassHeaderValue string              `json:"bypassHeaderValue,omitempty"`
+	Pages             []ErrorPageContent  `json:"pages,omitempty"`
 }
 
 // ClientTLS holds TLS specific configurations as client.
@@ -48,6 +48,8 @@ type Mirroring struct {
 
 	MaxBodySize *int64          `json:"maxBodySize,omitempty"`
 	Mirrors     []MirrorService `json:"mirrors,omitempty"`
+	// Compare enables the comparison of the responses of the mirrors with the response of the service.
+	Compare *dynamic.MirroringCompare `json:"compare,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -57,12 +59,20 @@ type MirrorService struct {
 	LoadBalancerSpec `json:",inline"`
 
 	Percent int `json:"percent,omitempty"`
+	// Match restricts the mirroring to the requests matching it, with the same syntax as the routes of the IngressRoutes.
+	Match string `json:"match,omitempty"`
+	// HashHeader is the name of the header whose value selects the mirrored requests.
+	HashHeader string `json:"hashHeader,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
 
 // WeightedRoundRobin defines a load-balancer of services.
 type WeightedRoundRobin struct {
-	Services []Service       `json:"services,omitempty"`
-	Sticky   *dynamic.Sticky `json:"sticky,omitempty"`
+	Services   []Service           `json:"services,omitempty"`
+	Sticky     *dynamic.Sticky     `json:"sticky,omitempty"`
+	StickyUser *dynamic.StickyUser `json:"stickyUser,omitempty"`
+	// Overrides forward the requests with a given header or cookie to one of the services.
+	// The service of an override is the name of one of the services.
+	Overrides []dynamic.WRROverride `json:"overrides,omitempty"`
 }
@@ -70,6 +70,32 @@ func (r *Router) AddRoute(rule string, priority int, handler http.Handler) error
 	return nil
 }
 
+// Matcher matches requests with a rule.
+type Matcher struct {
+	router *Router
+}
+
+// NewMatcher returns a new Matcher for the given rule.
+func NewMatcher(rule string) (*Matcher, error) {
+	router, err := NewRouter()
+	if err != nil {
+		return nil, err
+	}
+
+	err = router.AddRoute(rule, 0, http.NotFoundHandler())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Matcher{router: router}, nil
+}
+
+// Match reports whether the request matches the rule.
+func (m *Matcher) Match(req *http.Request) bool {
+	var match mux.RouteMatch
+	return m.router.Match(req, &match)
+}
+
 type tree struct {
 	matcher   string
 	value     []string
@@ -696,6 +696,80 @@ func TestHostRegexp(t *testing.T) {
 	}
 }
 
+func TestMatcher(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		rule          string
+		method        string
+		url           string
+		headers       map[string]string
+		expected      bool
+		expectedError bool
+	}{
+		{
+			desc:          "invalid rule",
+			rule:          "Foo(`bar`)",
+			expectedError: true,
+		},
+		{
+			desc:     "matching method and path",
+			rule:     "Method(`POST`) && Path(`/orders`)",
+			method:   http.MethodPost,
+			url:      "http://localhost/orders",
+			expected: true,
+		},
+		{
+			desc:   "method mismatch",
+			rule:   "Method(`POST`) && Path(`/orders`)",
+			method: http.MethodGet,
+			url:    "http://localhost/orders",
+		},
+		{
+			desc:     "matching header",
+			rule:     "Headers(`X-User`, `123`)",
+			method:   http.MethodGet,
+			url:      "http://localhost/",
+			headers:  map[string]string{"X-User": "123"},
+			expected: true,
+		},
+		{
+			desc:    "header mismatch",
+			rule:    "Headers(`X-User`, `123`)",
+			method:  http.MethodGet,
+			url:     "http://localhost/",
+			headers: map[string]string{"X-User": "456"},
+		},
+		{
+			desc:     "or",
+			rule:     "PathPrefix(`/foo`) || PathPrefix(`/bar`)",
+			method:   http.MethodGet,
+			url:      "http://localhost/bar/baz",
+			expected: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			matcher, err := NewMatcher(test.rule)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			req := testhelpers.MustNewRequest(test.method, test.url, nil)
+			for key, value := range test.headers {
+				req.Header.Set(key, value)
+			}
+
+			assert.Equal(t, test.expected, matcher.Match(req))
+		})
+	}
+}
+
 func TestParseDomains(t *testing.T) {
 	testCases := []struct {
 		description   string
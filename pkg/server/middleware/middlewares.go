@@ -8,10 +8,13 @@ import (
 	"strings"
 
 	"github.com/containous/alice"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/metrics"
 	"github.com/traefik/traefik/v2/pkg/middlewares/addprefix"
 	"github.com/traefik/traefik/v2/pkg/middlewares/auth"
 	"github.com/traefik/traefik/v2/pkg/middlewares/buffering"
+	"github.com/traefik/traefik/v2/pkg/middlewares/cache"
 	"github.com/traefik/traefik/v2/pkg/middlewares/chain"
 	"github.com/traefik/traefik/v2/pkg/middlewares/circuitbreaker"
 	"github.com/traefik/traefik/v2/pkg/middlewares/compress"
@@ -19,12 +22,15 @@ import (
 	"github.com/traefik/traefik/v2/pkg/middlewares/headers"
 	"github.com/traefik/traefik/v2/pkg/middlewares/inflightreq"
 	"github.com/traefik/traefik/v2/pkg/middlewares/ipwhitelist"
+	"github.com/traefik/traefik/v2/pkg/middlewares/limits"
+	"github.com/traefik/traefik/v2/pkg/middlewares/maintenance"
 	"github.com/traefik/traefik/v2/pkg/middlewares/passtlsclientcert"
 	"github.com/traefik/traefik/v2/pkg/middlewares/ratelimiter"
 	"github.com/traefik/traefik/v2/pkg/middlewares/redirect"
 	"github.com/traefik/traefik/v2/pkg/middlewares/replacepath"
 	"github.com/traefik/traefik/v2/pkg/middlewares/replacepathregex"
 	"github.com/traefik/traefik/v2/pkg/middlewares/retry"
+	"github.com/traefik/traefik/v2/pkg/middlewares/rewritebody"
 	"github.com/traefik/traefik/v2/pkg/middlewares/stripprefix"
 	"github.com/traefik/traefik/v2/pkg/middlewares/stripprefixregex"
 	"github.com/traefik/traefik/v2/pkg/middlewares/tracing"
@@ -35,13 +41,25 @@ type middlewareStackType int
 
 const (
 	middlewareStackKey middlewareStackType = iota
+	chainLimitsKey
+	routerNameKey
 )
 
+// WithRouterName returns a context carrying the name of the router the middlewares are built for,
+// so that the middlewares keeping a state, like the circuit breaker, can report it for each router.
+func WithRouterName(ctx context.Context, routerName string) context.Context {
+	return context.WithValue(ctx, routerNameKey, routerName)
+}
+
 // Builder the middleware builder.
 type Builder struct {
 	configs        map[string]*runtime.MiddlewareInfo
 	pluginBuilder  PluginsBuilder
 	serviceBuilder serviceBuilder
+	cacheStores    *cache.Stores
+
+	maintenanceStates *maintenance.States
+	metricsRegistry   metrics.Registry
 }
 
 type serviceBuilder interface {
@@ -49,12 +67,14 @@ type serviceBuilder interface {
 }
 
 // NewBuilder creates a new Builder.
-func NewBuilder(configs map[string]*runtime.MiddlewareInfo, serviceBuilder serviceBuilder, pluginBuilder PluginsBuilder) *Builder {
-	return &Builder{configs: configs, serviceBuilder: serviceBuilder, pluginBuilder: pluginBuilder}
+func NewBuilder(configs map[string]*runtime.MiddlewareInfo, serviceBuilder serviceBuilder, pluginBuilder PluginsBuilder, cacheStores *cache.Stores, maintenanceStates *maintenance.States, metricsRegistry metrics.Registry) *Builder {
+	return &Builder{configs: configs, serviceBuilder: serviceBuilder, pluginBuilder: pluginBuilder, cacheStores: cacheStores, maintenanceStates: maintenanceStates, metricsRegistry: metricsRegistry}
 }
 
 // BuildChain creates a middleware chain.
 func (b *Builder) BuildChain(ctx context.Context, middlewares []string) *alice.Chain {
+	ctx = b.withChainLimits(ctx, middlewares)
+
 	chain := alice.New()
 	for _, name := range middlewares {
 		middlewareName := provider.GetQualifiedName(ctx, name)
@@ -89,6 +109,49 @@ func (b *Builder) BuildChain(ctx context.Context, middlewares []string) *alice.C
 	return &chain
 }
 
+// withChainLimits adds in the context the limits of the limits middlewares of the chain, including the ones of the nested chains,
+// so that the limits of the last ones, e.g. set on the router, override the ones of the previous ones, e.g. set on the entry point.
+// The limits are computed once by the outermost chain, and are kept when building the nested chains.
+func (b *Builder) withChainLimits(ctx context.Context, middlewares []string) context.Context {
+	if _, ok := ctx.Value(chainLimitsKey).(dynamic.Limits); ok {
+		return ctx
+	}
+
+	configs := b.collectLimits(ctx, middlewares, make(map[string]struct{}))
+	if len(configs) == 0 {
+		return ctx
+	}
+
+	return context.WithValue(ctx, chainLimitsKey, limits.Merge(configs...))
+}
+
+func (b *Builder) collectLimits(ctx context.Context, middlewares []string, visited map[string]struct{}) []dynamic.Limits {
+	var configs []dynamic.Limits
+	for _, name := range middlewares {
+		middlewareName := provider.GetQualifiedName(ctx, name)
+
+		// The recursive chains are reported when building the chain.
+		if _, ok := visited[middlewareName]; ok {
+			continue
+		}
+		visited[middlewareName] = struct{}{}
+
+		midInf, ok := b.configs[middlewareName]
+		if !ok || midInf.Middleware == nil {
+			continue
+		}
+
+		switch {
+		case midInf.Limits != nil:
+			configs = append(configs, *midInf.Limits)
+		case midInf.Chain != nil:
+			configs = append(configs, b.collectLimits(provider.AddInContext(ctx, middlewareName), midInf.Chain.Middlewares, visited)...)
+		}
+	}
+
+	return configs
+}
+
 func checkRecursion(ctx context.Context, middlewareName string) (context.Context, error) {
 	currentStack, ok := ctx.Value(middlewareStackKey).([]string)
 	if !ok {
@@ -137,6 +200,16 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// Cache
+	if config.Cache != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return cache.New(ctx, next, *config.Cache, b.cacheStores, middlewareName)
+		}
+	}
+
 	// Chain
 	if config.Chain != nil {
 		if middleware != nil {
@@ -158,8 +231,9 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		if middleware != nil {
 			return nil, badConf
 		}
+		routerName, _ := ctx.Value(routerNameKey).(string)
 		middleware = func(next http.Handler) (http.Handler, error) {
-			return circuitbreaker.New(ctx, next, *config.CircuitBreaker, middlewareName)
+			return circuitbreaker.New(ctx, next, *config.CircuitBreaker, b.serviceBuilder, b.metricsRegistry, config, middlewareName, routerName)
 		}
 	}
 
@@ -248,6 +322,51 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// JWT
+	if config.JWT != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return auth.NewJWT(ctx, next, *config.JWT, middlewareName)
+		}
+	}
+
+	// Limits
+	if config.Limits != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+
+		limitsConfig := *config.Limits
+		if chainLimits, ok := ctx.Value(chainLimitsKey).(dynamic.Limits); ok {
+			limitsConfig = chainLimits
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return limits.New(ctx, next, limitsConfig, middlewareName)
+		}
+	}
+
+	// Maintenance
+	if config.Maintenance != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return maintenance.New(ctx, next, *config.Maintenance, b.maintenanceStates, middlewareName)
+		}
+	}
+
+	// OIDC
+	if config.OIDC != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return auth.NewOIDC(ctx, next, *config.OIDC, middlewareName)
+		}
+	}
+
 	// PassTLSClientCert
 	if config.PassTLSClientCert != nil {
 		if middleware != nil {
@@ -308,6 +427,16 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// RewriteBody
+	if config.RewriteBody != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return rewritebody.New(ctx, next, *config.RewriteBody, middlewareName)
+		}
+	}
+
 	// Retry
 	if config.Retry != nil {
 		if middleware != nil {
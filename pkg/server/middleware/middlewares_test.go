@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -18,7 +19,7 @@ func TestBuilder_BuildChainNilConfig(t *testing.T) {
 	testConfig := map[string]*runtime.MiddlewareInfo{
 		"empty": {},
 	}
-	middlewaresBuilder := NewBuilder(testConfig, nil, nil)
+	middlewaresBuilder := NewBuilder(testConfig, nil, nil, nil, nil, nil)
 
 	chain := middlewaresBuilder.BuildChain(context.Background(), []string{"empty"})
 	_, err := chain.Then(nil)
@@ -29,7 +30,7 @@ func TestBuilder_BuildChainNonExistentChain(t *testing.T) {
 	testConfig := map[string]*runtime.MiddlewareInfo{
 		"foobar": {},
 	}
-	middlewaresBuilder := NewBuilder(testConfig, nil, nil)
+	middlewaresBuilder := NewBuilder(testConfig, nil, nil, nil, nil, nil)
 
 	chain := middlewaresBuilder.BuildChain(context.Background(), []string{"empty"})
 	_, err := chain.Then(nil)
@@ -270,7 +271,7 @@ func TestBuilder_BuildChainWithContext(t *testing.T) {
 					Middlewares: test.configuration,
 				},
 			})
-			builder := NewBuilder(rtConf.Middlewares, nil, nil)
+			builder := NewBuilder(rtConf.Middlewares, nil, nil, nil, nil, nil)
 
 			result := builder.BuildChain(ctx, test.buildChain)
 
@@ -329,7 +330,7 @@ func TestBuilder_buildConstructor(t *testing.T) {
 			Middlewares: testConfig,
 		},
 	})
-	middlewaresBuilder := NewBuilder(rtConf.Middlewares, nil, nil)
+	middlewaresBuilder := NewBuilder(rtConf.Middlewares, nil, nil, nil, nil, nil)
 
 	testCases := []struct {
 		desc          string
@@ -382,3 +383,87 @@ func TestBuilder_buildConstructor(t *testing.T) {
 		})
 	}
 }
+
+func TestBuilder_BuildChainLimits(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		buildChain         []string
+		url                string
+		contentLength      int64
+		expectedStatusCode int
+	}{
+		{
+			desc:               "entry point limits",
+			buildChain:         []string{"entrypoint-limits"},
+			url:                "http://foo/",
+			contentLength:      50,
+			expectedStatusCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			desc:               "body limit overridden by the router limits",
+			buildChain:         []string{"entrypoint-limits", "router-limits"},
+			url:                "http://foo/",
+			contentLength:      50,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc:               "URL limit kept from the entry point limits",
+			buildChain:         []string{"entrypoint-limits", "router-limits"},
+			url:                "http://foo/a-path-longer-than-the-limit",
+			expectedStatusCode: http.StatusRequestURITooLong,
+		},
+		{
+			desc:               "limits overridden in a chain",
+			buildChain:         []string{"entrypoint-limits", "chain-limits"},
+			url:                "http://foo/",
+			contentLength:      50,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc:               "limits of a chain overridden",
+			buildChain:         []string{"chain-entrypoint-limits", "router-limits"},
+			url:                "http://foo/",
+			contentLength:      50,
+			expectedStatusCode: http.StatusOK,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			rtConf := runtime.NewConfig(dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{
+					Middlewares: map[string]*dynamic.Middleware{
+						"entrypoint-limits": {
+							Limits: &dynamic.Limits{MaxRequestBodyBytes: 10, MaxURLLength: 20},
+						},
+						"router-limits": {
+							Limits: &dynamic.Limits{MaxRequestBodyBytes: 100},
+						},
+						"chain-limits": {
+							Chain: &dynamic.Chain{Middlewares: []string{"router-limits"}},
+						},
+						"chain-entrypoint-limits": {
+							Chain: &dynamic.Chain{Middlewares: []string{"entrypoint-limits"}},
+						},
+					},
+				},
+			})
+			builder := NewBuilder(rtConf.Middlewares, nil, nil, nil, nil, nil)
+
+			handler, err := builder.BuildChain(context.Background(), test.buildChain).Then(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(http.StatusOK)
+			}))
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, test.url, strings.NewReader(strings.Repeat("a", int(test.contentLength))))
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatusCode, recorder.Code)
+		})
+	}
+}
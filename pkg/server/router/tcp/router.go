@@ -11,6 +11,8 @@ import (
 
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
 	"github.com/traefik/traefik/v2/pkg/rules"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
 	tcpservice "github.com/traefik/traefik/v2/pkg/server/service/tcp"
@@ -29,23 +31,29 @@ func NewManager(conf *runtime.Configuration,
 	httpHandlers map[string]http.Handler,
 	httpsHandlers map[string]http.Handler,
 	tlsManager *traefiktls.Manager,
+	metricsRegistry metrics.Registry,
+	accessLogger *accesslog.Handler,
 ) *Manager {
 	return &Manager{
-		serviceManager: serviceManager,
-		httpHandlers:   httpHandlers,
-		httpsHandlers:  httpsHandlers,
-		tlsManager:     tlsManager,
-		conf:           conf,
+		serviceManager:  serviceManager,
+		httpHandlers:    httpHandlers,
+		httpsHandlers:   httpsHandlers,
+		tlsManager:      tlsManager,
+		conf:            conf,
+		metricsRegistry: metricsRegistry,
+		accessLogger:    accessLogger,
 	}
 }
 
 // Manager is a route/router manager.
 type Manager struct {
-	serviceManager *tcpservice.Manager
-	httpHandlers   map[string]http.Handler
-	httpsHandlers  map[string]http.Handler
-	tlsManager     *traefiktls.Manager
-	conf           *runtime.Configuration
+	serviceManager  *tcpservice.Manager
+	httpHandlers    map[string]http.Handler
+	httpsHandlers   map[string]http.Handler
+	tlsManager      *traefiktls.Manager
+	conf            *runtime.Configuration
+	metricsRegistry metrics.Registry
+	accessLogger    *accesslog.Handler
 }
 
 func (m *Manager) getTCPRouters(ctx context.Context, entryPoints []string) map[string]map[string]*runtime.TCPRouterInfo {
@@ -77,7 +85,7 @@ func (m *Manager) BuildHandlers(rootCtx context.Context, entryPoints []string) m
 
 		ctx := log.With(rootCtx, log.Str(log.EntryPointName, entryPointName))
 
-		handler, err := m.buildEntryPointHandler(ctx, routers, entryPointsRoutersHTTP[entryPointName], m.httpHandlers[entryPointName], m.httpsHandlers[entryPointName])
+		handler, err := m.buildEntryPointHandler(ctx, entryPointName, routers, entryPointsRoutersHTTP[entryPointName], m.httpHandlers[entryPointName], m.httpsHandlers[entryPointName])
 		if err != nil {
 			log.FromContext(ctx).Error(err)
 			continue
@@ -92,7 +100,7 @@ type nameAndConfig struct {
 	TLSConfig  *tls.Config
 }
 
-func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string]*runtime.TCPRouterInfo, configsHTTP map[string]*runtime.RouterInfo, handlerHTTP, handlerHTTPS http.Handler) (*tcp.Router, error) {
+func (m *Manager) buildEntryPointHandler(ctx context.Context, entryPointName string, configs map[string]*runtime.TCPRouterInfo, configsHTTP map[string]*runtime.RouterInfo, handlerHTTP, handlerHTTPS http.Handler) (*tcp.Router, error) {
 	router := &tcp.Router{}
 	router.HTTPHandler(handlerHTTP)
 
@@ -221,6 +229,10 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 		}
 	}
 
+	if m.metricsRegistry != nil && m.metricsRegistry.IsEpEnabled() {
+		router.SetConnMetrics(tcp.NewEntryPointConnMetrics(m.metricsRegistry, entryPointName))
+	}
+
 	for routerName, routerConfig := range configs {
 		ctxRouter := log.With(provider.AddInContext(ctx, routerName), log.Str(log.RouterName, routerName))
 		logger := log.FromContext(ctxRouter)
@@ -246,6 +258,19 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 			continue
 		}
 
+		handler = tcp.NewRouterNameHandler(handler, routerName)
+
+		if m.metricsRegistry != nil && m.metricsRegistry.IsRouterEnabled() {
+			serviceName := provider.GetQualifiedName(ctxRouter, routerConfig.Service)
+			handler = tcp.NewMetricsHandler(handler, tcp.NewRouterConnMetrics(m.metricsRegistry, routerName, serviceName))
+		}
+
+		if m.accessLogger != nil {
+			handler = accesslog.NewTCPFieldHandler(handler, accesslog.RouterName, routerName)
+			handler = accesslog.NewTCPFieldHandler(handler, log.EntryPointName, entryPointName)
+			handler = accesslog.NewTCPHandler(m.accessLogger, handler)
+		}
+
 		domains, err := rules.ParseHostSNI(routerConfig.Rule)
 		if err != nil {
 			routerErr := fmt.Errorf("unknown rule %s", routerConfig.Rule)
@@ -284,7 +284,7 @@ func TestRuntimeConfiguration(t *testing.T) {
 				TCPServices: test.tcpServiceConfig,
 				TCPRouters:  test.tcpRouterConfig,
 			}
-			serviceManager := tcp.NewManager(conf)
+			serviceManager := tcp.NewManager(conf, nil, nil, nil)
 			tlsManager := traefiktls.NewManager()
 			tlsManager.UpdateConfigs(
 				context.Background(),
@@ -303,7 +303,7 @@ func TestRuntimeConfiguration(t *testing.T) {
 				[]*traefiktls.CertAndStores{})
 
 			routerManager := NewManager(conf, serviceManager,
-				nil, nil, tlsManager)
+				nil, nil, tlsManager, nil, nil)
 
 			_ = routerManager.BuildHandlers(context.Background(), entryPoints)
 
@@ -523,7 +523,7 @@ func TestDomainFronting(t *testing.T) {
 				Routers: test.routers,
 			}
 
-			serviceManager := tcp.NewManager(conf)
+			serviceManager := tcp.NewManager(conf, nil, nil, nil)
 
 			tlsManager := traefiktls.NewManager()
 			tlsManager.UpdateConfigs(context.Background(), map[string]traefiktls.Store{}, tlsOptions, []*traefiktls.CertAndStores{})
@@ -532,7 +532,7 @@ func TestDomainFronting(t *testing.T) {
 				"web": http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}),
 			}
 
-			routerManager := NewManager(conf, serviceManager, nil, httpsHandler, tlsManager)
+			routerManager := NewManager(conf, serviceManager, nil, httpsHandler, tlsManager, nil, nil)
 
 			routers := routerManager.BuildHandlers(context.Background(), entryPoints)
 
@@ -7,6 +7,8 @@ import (
 
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
 	udpservice "github.com/traefik/traefik/v2/pkg/server/service/udp"
 	"github.com/traefik/traefik/v2/pkg/udp"
@@ -15,17 +17,23 @@ import (
 // NewManager Creates a new Manager.
 func NewManager(conf *runtime.Configuration,
 	serviceManager *udpservice.Manager,
+	metricsRegistry metrics.Registry,
+	accessLogger *accesslog.Handler,
 ) *Manager {
 	return &Manager{
-		serviceManager: serviceManager,
-		conf:           conf,
+		serviceManager:  serviceManager,
+		conf:            conf,
+		metricsRegistry: metricsRegistry,
+		accessLogger:    accessLogger,
 	}
 }
 
 // Manager is a route/router manager.
 type Manager struct {
-	serviceManager *udpservice.Manager
-	conf           *runtime.Configuration
+	serviceManager  *udpservice.Manager
+	conf            *runtime.Configuration
+	metricsRegistry metrics.Registry
+	accessLogger    *accesslog.Handler
 }
 
 func (m *Manager) getUDPRouters(ctx context.Context, entryPoints []string) map[string]map[string]*runtime.UDPRouterInfo {
@@ -52,7 +60,7 @@ func (m *Manager) BuildHandlers(rootCtx context.Context, entryPoints []string) m
 			log.FromContext(ctx).Warn("Config has more than one udp router for a given entrypoint.")
 		}
 
-		handlers, err := m.buildEntryPointHandlers(ctx, routers)
+		handlers, err := m.buildEntryPointHandlers(ctx, entryPointName, routers)
 		if err != nil {
 			log.FromContext(ctx).Error(err)
 			continue
@@ -66,7 +74,7 @@ func (m *Manager) BuildHandlers(rootCtx context.Context, entryPoints []string) m
 	return entryPointHandlers
 }
 
-func (m *Manager) buildEntryPointHandlers(ctx context.Context, configs map[string]*runtime.UDPRouterInfo) ([]udp.Handler, error) {
+func (m *Manager) buildEntryPointHandlers(ctx context.Context, entryPointName string, configs map[string]*runtime.UDPRouterInfo) ([]udp.Handler, error) {
 	var rtNames []string
 	for routerName := range configs {
 		rtNames = append(rtNames, routerName)
@@ -98,6 +106,21 @@ func (m *Manager) buildEntryPointHandlers(ctx context.Context, configs map[strin
 			continue
 		}
 
+		if m.metricsRegistry != nil && m.metricsRegistry.IsRouterEnabled() {
+			serviceName := provider.GetQualifiedName(ctxRouter, routerConfig.Service)
+			handler = udp.NewMetricsHandler(handler, m.metricsRegistry.UDPRouterSessionsCounter().With("router", routerName, "service", serviceName))
+		}
+
+		if m.metricsRegistry != nil && m.metricsRegistry.IsEpEnabled() {
+			handler = udp.NewMetricsHandler(handler, m.metricsRegistry.UDPEntryPointSessionsCounter().With("entrypoint", entryPointName))
+		}
+
+		if m.accessLogger != nil {
+			handler = accesslog.NewUDPFieldHandler(handler, accesslog.RouterName, routerName)
+			handler = accesslog.NewUDPFieldHandler(handler, log.EntryPointName, entryPointName)
+			handler = accesslog.NewUDPHandler(m.accessLogger, handler)
+		}
+
 		handlers = append(handlers, handler)
 	}
 
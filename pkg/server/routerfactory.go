@@ -7,6 +7,7 @@ import (
 	"github.com/traefik/traefik/v2/pkg/config/static"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
 	"github.com/traefik/traefik/v2/pkg/server/middleware"
 	"github.com/traefik/traefik/v2/pkg/server/router"
 	routertcp "github.com/traefik/traefik/v2/pkg/server/router/tcp"
@@ -24,18 +25,24 @@ type RouterFactory struct {
 	entryPointsTCP []string
 	entryPointsUDP []string
 
-	managerFactory  *service.ManagerFactory
-	metricsRegistry metrics.Registry
+	managerFactory             *service.ManagerFactory
+	tcpServersTransportManager *tcp.ServersTransportManager
+	metricsRegistry            metrics.Registry
+	accessLogger               *accesslog.Handler
 
 	pluginBuilder middleware.PluginsBuilder
 
 	chainBuilder *middleware.ChainBuilder
 	tlsManager   *tls.Manager
+
+	// cancelPrevious stops the background tasks of the previous configuration,
+	// such as the DNS discoveries of the servers and the timers of the passive health checks.
+	cancelPrevious context.CancelFunc
 }
 
 // NewRouterFactory creates a new RouterFactory.
-func NewRouterFactory(staticConfiguration static.Configuration, managerFactory *service.ManagerFactory, tlsManager *tls.Manager,
-	chainBuilder *middleware.ChainBuilder, pluginBuilder middleware.PluginsBuilder, metricsRegistry metrics.Registry) *RouterFactory {
+func NewRouterFactory(staticConfiguration static.Configuration, managerFactory *service.ManagerFactory, tcpServersTransportManager *tcp.ServersTransportManager, tlsManager *tls.Manager,
+	chainBuilder *middleware.ChainBuilder, pluginBuilder middleware.PluginsBuilder, metricsRegistry metrics.Registry, accessLogger *accesslog.Handler) *RouterFactory {
 	var entryPointsTCP, entryPointsUDP []string
 	for name, cfg := range staticConfiguration.EntryPoints {
 		protocol, err := cfg.GetProtocol()
@@ -52,24 +59,27 @@ func NewRouterFactory(staticConfiguration static.Configuration, managerFactory *
 	}
 
 	return &RouterFactory{
-		entryPointsTCP:  entryPointsTCP,
-		entryPointsUDP:  entryPointsUDP,
-		managerFactory:  managerFactory,
-		metricsRegistry: metricsRegistry,
-		tlsManager:      tlsManager,
-		chainBuilder:    chainBuilder,
-		pluginBuilder:   pluginBuilder,
+		entryPointsTCP:             entryPointsTCP,
+		entryPointsUDP:             entryPointsUDP,
+		managerFactory:             managerFactory,
+		tcpServersTransportManager: tcpServersTransportManager,
+		metricsRegistry:            metricsRegistry,
+		accessLogger:               accessLogger,
+		tlsManager:                 tlsManager,
+		chainBuilder:               chainBuilder,
+		pluginBuilder:              pluginBuilder,
 	}
 }
 
 // CreateRouters creates new TCPRouters and UDPRouters.
 func (f *RouterFactory) CreateRouters(rtConf *runtime.Configuration) (map[string]*tcpCore.Router, map[string]udpCore.Handler) {
-	ctx := context.Background()
+	// The context is canceled once the routers are replaced by the ones of the next configuration.
+	ctx, cancel := context.WithCancel(context.Background())
 
 	// HTTP
 	serviceManager := f.managerFactory.Build(rtConf)
 
-	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, f.pluginBuilder)
+	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, f.pluginBuilder, f.managerFactory.CacheStores(), f.managerFactory.MaintenanceStates(), f.metricsRegistry)
 
 	routerManager := router.NewManager(rtConf, serviceManager, middlewaresBuilder, f.chainBuilder, f.metricsRegistry)
 
@@ -79,17 +89,25 @@ func (f *RouterFactory) CreateRouters(rtConf *runtime.Configuration) (map[string
 	serviceManager.LaunchHealthCheck()
 
 	// TCP
-	svcTCPManager := tcp.NewManager(rtConf)
+	svcTCPManager := tcp.NewManager(rtConf, f.tcpServersTransportManager, f.metricsRegistry, f.managerFactory.DNSCache())
 
-	rtTCPManager := routertcp.NewManager(rtConf, svcTCPManager, handlersNonTLS, handlersTLS, f.tlsManager)
+	rtTCPManager := routertcp.NewManager(rtConf, svcTCPManager, handlersNonTLS, handlersTLS, f.tlsManager, f.metricsRegistry, f.accessLogger)
 	routersTCP := rtTCPManager.BuildHandlers(ctx, f.entryPointsTCP)
 
 	// UDP
-	svcUDPManager := udp.NewManager(rtConf)
-	rtUDPManager := routerudp.NewManager(rtConf, svcUDPManager)
+	svcUDPManager := udp.NewManager(rtConf, f.metricsRegistry)
+	rtUDPManager := routerudp.NewManager(rtConf, svcUDPManager, f.metricsRegistry, f.accessLogger)
 	routersUDP := rtUDPManager.BuildHandlers(ctx, f.entryPointsUDP)
 
 	rtConf.PopulateUsedBy()
 
+	serviceManager.LaunchDNSDiscovery(ctx)
+	svcTCPManager.LaunchDNSDiscovery(ctx)
+
+	if f.cancelPrevious != nil {
+		f.cancelPrevious()
+	}
+	f.cancelPrevious = cancel
+
 	return routersTCP, routersUDP
 }
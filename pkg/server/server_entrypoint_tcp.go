@@ -7,6 +7,7 @@ import (
 	stdlog "log"
 	"net"
 	"net/http"
+	"os"
 	"sync"
 	"syscall"
 	"time"
@@ -18,6 +19,7 @@ import (
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/middlewares"
 	"github.com/traefik/traefik/v2/pkg/middlewares/forwardedheaders"
+	"github.com/traefik/traefik/v2/pkg/middlewares/proxyprotocol"
 	"github.com/traefik/traefik/v2/pkg/safe"
 	"github.com/traefik/traefik/v2/pkg/server/router"
 	"github.com/traefik/traefik/v2/pkg/tcp"
@@ -327,17 +329,38 @@ func (e *TCPEntryPoint) SwitchRouter(rt *tcp.Router) {
 type writeCloserWrapper struct {
 	net.Conn
 	writeCloser tcp.WriteCloser
+
+	remoteAddrOnce sync.Once
+	remoteAddr     net.Addr
 }
 
 func (c *writeCloserWrapper) CloseWrite() error {
 	return c.writeCloser.CloseWrite()
 }
 
+// RemoteAddr returns the remote address of the connection,
+// along with the TLVs of its PROXY protocol header, if any.
+func (c *writeCloserWrapper) RemoteAddr() net.Addr {
+	c.remoteAddrOnce.Do(func() {
+		if ppConn, ok := c.Conn.(*proxyproto.Conn); ok {
+			c.remoteAddr = tcp.ProxyProtocolRemoteAddr(ppConn)
+			return
+		}
+		c.remoteAddr = c.Conn.RemoteAddr()
+	})
+
+	return c.remoteAddr
+}
+
 // writeCloser returns the given connection, augmented with the WriteCloser
 // implementation, if any was found within the underlying conn.
 func writeCloser(conn net.Conn) (tcp.WriteCloser, error) {
 	switch typedConn := conn.(type) {
 	case *proxyproto.Conn:
+		if underlying, ok := typedConn.UnixConn(); ok {
+			return &writeCloserWrapper{writeCloser: underlying, Conn: typedConn}, nil
+		}
+
 		underlying, ok := typedConn.TCPConn()
 		if !ok {
 			return nil, fmt.Errorf("underlying connection is not a tcp connection")
@@ -345,6 +368,8 @@ func writeCloser(conn net.Conn) (tcp.WriteCloser, error) {
 		return &writeCloserWrapper{writeCloser: underlying, Conn: typedConn}, nil
 	case *net.TCPConn:
 		return typedConn, nil
+	case *net.UnixConn:
+		return typedConn, nil
 	default:
 		return nil, fmt.Errorf("unknown connection type %T", typedConn)
 	}
@@ -391,6 +416,13 @@ func buildProxyProtocolListener(ctx context.Context, entryPoint *static.EntryPoi
 	}
 
 	proxyListener.Policy = func(upstream net.Addr) (proxyproto.Policy, error) {
+		// The clients of a unix socket have no IP to check against the trusted IPs,
+		// so their headers are only used with the insecure mode.
+		if _, ok := upstream.(*net.UnixAddr); ok {
+			log.FromContext(ctx).Debug("Unix socket clients are not in trusted IPs list, ignoring ProxyProtocol Headers and bypass connection")
+			return proxyproto.IGNORE, nil
+		}
+
 		ipAddr, ok := upstream.(*net.TCPAddr)
 		if !ok {
 			return proxyproto.REJECT, fmt.Errorf("type error %v", upstream)
@@ -409,13 +441,11 @@ func buildProxyProtocolListener(ctx context.Context, entryPoint *static.EntryPoi
 }
 
 func buildListener(ctx context.Context, entryPoint *static.EntryPoint) (net.Listener, error) {
-	listener, err := net.Listen("tcp", entryPoint.GetAddress())
+	listener, err := listen(entryPoint)
 	if err != nil {
 		return nil, fmt.Errorf("error opening listener: %w", err)
 	}
 
-	listener = tcpKeepAliveListener{listener.(*net.TCPListener)}
-
 	if entryPoint.ProxyProtocol != nil {
 		listener, err = buildProxyProtocolListener(ctx, entryPoint, listener)
 		if err != nil {
@@ -425,6 +455,85 @@ func buildListener(ctx context.Context, entryPoint *static.EntryPoint) (net.List
 	return listener, nil
 }
 
+func listen(entryPoint *static.EntryPoint) (net.Listener, error) {
+	if entryPoint.IsUnixSocket() {
+		return buildUnixListener(entryPoint)
+	}
+
+	listener, err := net.Listen("tcp", entryPoint.GetAddress())
+	if err != nil {
+		return nil, err
+	}
+
+	return tcpKeepAliveListener{listener.(*net.TCPListener)}, nil
+}
+
+// buildUnixListener listens on the unix socket of the entry point.
+// The socket file is removed when the listener is closed.
+func buildUnixListener(entryPoint *static.EntryPoint) (net.Listener, error) {
+	path := entryPoint.GetAddress()
+
+	// A socket file left behind by a previous process which did not stop properly prevents listening,
+	// it is removed unless another process is still listening on it.
+	if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		if conn, err := net.Dial("unix", path); err == nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("unix socket %s is already in use", path)
+		}
+
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("error removing stale unix socket %s: %w", path, err)
+		}
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := setUnixSocketPermissions(path, entryPoint.UnixSocket); err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+
+	return listener, nil
+}
+
+func setUnixSocketPermissions(path string, config *static.UnixSocket) error {
+	if config == nil {
+		return nil
+	}
+
+	mode, ok, err := config.FileMode()
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		if err := os.Chmod(path, mode); err != nil {
+			return fmt.Errorf("error setting mode of unix socket %s: %w", path, err)
+		}
+	}
+
+	if config.UID == nil && config.GID == nil {
+		return nil
+	}
+
+	uid, gid := -1, -1
+	if config.UID != nil {
+		uid = *config.UID
+	}
+	if config.GID != nil {
+		gid = *config.GID
+	}
+
+	if err := os.Chown(path, uid, gid); err != nil {
+		return fmt.Errorf("error setting owner of unix socket %s: %w", path, err)
+	}
+
+	return nil
+}
+
 func newConnectionTracker() *connectionTracker {
 	return &connectionTracker{
 		conns: make(map[net.Conn]struct{}),
@@ -514,6 +623,10 @@ func createHTTPServer(ctx context.Context, ln net.Listener, configuration *stati
 		return nil, err
 	}
 
+	// The TLV headers are always removed from the incoming requests, so that they cannot be spoofed,
+	// even on the entry points without the PROXY protocol.
+	handler = proxyprotocol.NewTLVHeaders(handler)
+
 	if withH2c {
 		handler = h2c.NewHandler(handler, &http2.Server{})
 	}
@@ -524,6 +637,7 @@ func createHTTPServer(ctx context.Context, ln net.Listener, configuration *stati
 		ReadTimeout:  time.Duration(configuration.Transport.RespondingTimeouts.ReadTimeout),
 		WriteTimeout: time.Duration(configuration.Transport.RespondingTimeouts.WriteTimeout),
 		IdleTimeout:  time.Duration(configuration.Transport.RespondingTimeouts.IdleTimeout),
+		ConnContext:  proxyprotocol.ConnContext,
 	}
 
 	listener := newHTTPForwarder(ln)
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/healthcheck"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/traefik/traefik/v2/pkg/server/service/dnsdiscovery"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+// discoveredServers holds the servers of a service discovered with DNS,
+// and keeps all the load-balancers of the service up to date with them.
+type discoveredServers struct {
+	serviceName string
+	discovery   *dnsdiscovery.Discovery
+	scheme      string
+	serviceInfo *runtime.ServiceInfo // can be nil
+
+	mu        sync.RWMutex
+	targets   []dnsdiscovery.Target
+	servers   map[string]struct{}
+	balancers []healthcheck.Balancer
+}
+
+func (m *Manager) getDiscoveredServers(ctx context.Context, serviceName string, config *dynamic.DNSDiscovery) (*discoveredServers, error) {
+	if discovered, ok := m.discoveredServers[serviceName]; ok {
+		return discovered, nil
+	}
+
+	discovery, err := dnsdiscovery.New(m.dnsResolver, m.dnsCache, config.Name, config.RecordType, config.Port, time.Duration(config.RefreshInterval))
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := config.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+
+	discovered := &discoveredServers{
+		serviceName: serviceName,
+		discovery:   discovery,
+		scheme:      scheme,
+		serviceInfo: m.configs[serviceName],
+		servers:     make(map[string]struct{}),
+	}
+
+	// The name is resolved in the background, so the service starts with the servers resolved for the previous configuration,
+	// or without servers when it has not been resolved yet.
+	targets, ok := discovery.Cached()
+	if !ok {
+		log.FromContext(ctx).Debugf("%s is not resolved yet, the servers are added once it is", config.Name)
+	}
+	discovered.setTargets(targets)
+
+	m.discoveredServers[serviceName] = discovered
+
+	return discovered, nil
+}
+
+// LaunchDNSDiscovery launches the DNS discoveries of the servers, until the context is done.
+func (m *Manager) LaunchDNSDiscovery(ctx context.Context) {
+	for serviceName, discovered := range m.discoveredServers {
+		discovered := discovered
+		discoveryCtx := log.With(ctx, log.Str(log.ServiceName, serviceName))
+
+		safe.Go(func() {
+			discovered.discovery.Watch(discoveryCtx, discovered.getTargets(), func(targets []dnsdiscovery.Target) {
+				discovered.update(discoveryCtx, targets)
+			})
+		})
+	}
+}
+
+func (d *discoveredServers) url(target dnsdiscovery.Target) *url.URL {
+	return &url.URL{Scheme: d.scheme, Host: target.Address()}
+}
+
+func (d *discoveredServers) getTargets() []dnsdiscovery.Target {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.targets
+}
+
+func (d *discoveredServers) setTargets(targets []dnsdiscovery.Target) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.targets = targets
+	d.servers = make(map[string]struct{}, len(targets))
+
+	urls := make([]string, 0, len(targets))
+	for _, target := range targets {
+		u := d.url(target).String()
+		d.servers[u] = struct{}{}
+		urls = append(urls, u)
+	}
+
+	metrics.OnServersDiscovered("http", d.serviceName, urls)
+}
+
+// has reports whether the server is currently discovered.
+func (d *discoveredServers) has(u *url.URL) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	_, ok := d.servers[u.String()]
+	return ok
+}
+
+// addBalancer adds the discovered servers to the load-balancer, and keeps it up to date with the next resolutions.
+func (d *discoveredServers) addBalancer(lb healthcheck.Balancer) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, target := range d.targets {
+		if err := lb.UpsertServer(d.url(target), roundrobin.Weight(target.Weight)); err != nil {
+			return err
+		}
+	}
+
+	d.balancers = append(d.balancers, lb)
+	return nil
+}
+
+// update upserts the new targets into the load-balancers, and removes the targets which are not discovered anymore.
+func (d *discoveredServers) update(ctx context.Context, targets []dnsdiscovery.Target) {
+	logger := log.FromContext(ctx)
+
+	previous := d.getTargets()
+	d.setTargets(targets)
+
+	previousWeights := make(map[string]int, len(previous))
+	for _, target := range previous {
+		previousWeights[target.Address()] = target.Weight
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	for _, target := range targets {
+		// The unchanged servers are left as is, so that the servers removed by the health check stay out.
+		if weight, ok := previousWeights[target.Address()]; ok && weight == target.Weight {
+			continue
+		}
+
+		u := d.url(target)
+		for _, lb := range d.balancers {
+			if err := lb.UpsertServer(u, roundrobin.Weight(target.Weight)); err != nil {
+				logger.Errorf("Unable to add server %s: %v", u, err)
+			}
+		}
+	}
+
+	for _, target := range previous {
+		u := d.url(target)
+		if _, ok := d.servers[u.String()]; ok {
+			continue
+		}
+
+		logger.Debugf("Removing server %s, which is not resolved anymore", u)
+
+		for _, lb := range d.balancers {
+			// The server is not in the load-balancer anymore if the health check removed it.
+			_ = lb.RemoveServer(u)
+		}
+
+		if d.serviceInfo != nil {
+			d.serviceInfo.RemoveServerStatus(u.String())
+		}
+	}
+}
+
+// dnsBalancer is a load-balancer whose servers are discovered with DNS.
+// It ignores the servers which are not discovered anymore,
+// so that the health checks do not bring them back into the load-balancer.
+type dnsBalancer struct {
+	*healthcheck.LbStatusUpdater
+	discovered *discoveredServers
+}
+
+// UpsertServer adds the given server to the load-balancer, if it is still discovered.
+func (b *dnsBalancer) UpsertServer(u *url.URL, options ...roundrobin.ServerOption) error {
+	if !b.discovered.has(u) {
+		return nil
+	}
+
+	return b.LbStatusUpdater.UpsertServer(u, options...)
+}
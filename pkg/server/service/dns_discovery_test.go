@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/server/service/dnsdiscovery"
+	"github.com/traefik/traefik/v2/pkg/testhelpers"
+)
+
+// stubResolver resolves all the names to the IPs it is given.
+type stubResolver struct {
+	mu  sync.Mutex
+	ips []string
+}
+
+func (r *stubResolver) setIPs(ips ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.ips = ips
+}
+
+func (r *stubResolver) LookupIPAddr(_ context.Context, _ string) ([]net.IPAddr, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var addrs []net.IPAddr
+	for _, ip := range r.ips {
+		addrs = append(addrs, net.IPAddr{IP: net.ParseIP(ip)})
+	}
+	return addrs, nil
+}
+
+func (r *stubResolver) LookupSRV(_ context.Context, _, _, _ string) (string, []*net.SRV, error) {
+	return "", nil, nil
+}
+
+func TestManager_BuildDNSDiscovery(t *testing.T) {
+	var mu sync.Mutex
+	var hosts []string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		hosts = append(hosts, req.Host)
+		mu.Unlock()
+	}))
+	t.Cleanup(server.Close)
+
+	_, port, err := net.SplitHostPort(testhelpers.MustParseURL(server.URL).Host)
+	require.NoError(t, err)
+
+	services := map[string]*runtime.ServiceInfo{
+		"discovered@file": {
+			Service: &dynamic.Service{
+				LoadBalancer: &dynamic.ServersLoadBalancer{
+					DNSDiscovery: &dynamic.DNSDiscovery{
+						Name:       "backend.example.com",
+						RecordType: dynamic.DNSRecordA,
+						Port:       mustAtoi(t, port),
+					},
+				},
+			},
+		},
+	}
+
+	manager := NewManager(services, nil, nil, &RoundTripperManager{
+		roundTrippers: map[string]http.RoundTripper{
+			"default@internal": http.DefaultTransport,
+		},
+	})
+
+	resolver := &stubResolver{}
+	resolver.setIPs("127.0.0.1")
+	manager.dnsResolver = resolver
+
+	manager.dnsCache = dnsdiscovery.NewCache()
+
+	handler, err := manager.BuildHTTP(context.Background(), "discovered@file")
+	require.NoError(t, err)
+
+	// The name is not resolved yet.
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://foo.bar", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	manager.LaunchDNSDiscovery(ctx)
+
+	assert.Eventually(t, func() bool {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://foo.bar", nil))
+		return recorder.Code == http.StatusOK
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The service of the next configuration starts with the resolved server.
+	nextManager := NewManager(services, nil, nil, manager.roundTripperManager)
+	nextManager.dnsResolver = resolver
+	nextManager.dnsCache = manager.dnsCache
+
+	nextHandler, err := nextManager.BuildHTTP(context.Background(), "discovered@file")
+	require.NoError(t, err)
+
+	recorder = httptest.NewRecorder()
+	nextHandler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://foo.bar", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	discovered := manager.discoveredServers["discovered@file"]
+	require.NotNil(t, discovered)
+
+	// The server is not resolved anymore.
+	discovered.update(context.Background(), nil)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://foo.bar", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	// The health check cannot bring back a server which is not resolved anymore.
+	balancer := manager.balancers["discovered@file"][0]
+	require.NoError(t, balancer.UpsertServer(testhelpers.MustParseURL("http://127.0.0.1:"+port)))
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://foo.bar", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	discovered.update(context.Background(), []dnsdiscovery.Target{{Host: "127.0.0.1", Port: mustAtoi(t, port), Weight: 1}})
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://foo.bar", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Len(t, hosts, 3)
+}
+
+func TestManager_BuildDNSDiscoveryWithServers(t *testing.T) {
+	services := map[string]*runtime.ServiceInfo{
+		"discovered@file": {
+			Service: &dynamic.Service{
+				LoadBalancer: &dynamic.ServersLoadBalancer{
+					Servers: []dynamic.Server{{URL: "http://127.0.0.1:8080"}},
+					DNSDiscovery: &dynamic.DNSDiscovery{
+						Name: "backend.example.com",
+						Port: 8080,
+					},
+				},
+			},
+		},
+	}
+
+	manager := NewManager(services, nil, nil, &RoundTripperManager{
+		roundTrippers: map[string]http.RoundTripper{
+			"default@internal": http.DefaultTransport,
+		},
+	})
+	manager.dnsResolver = &stubResolver{}
+
+	_, err := manager.BuildHTTP(context.Background(), "discovered@file")
+	assert.Error(t, err)
+}
+
+func mustAtoi(t *testing.T, s string) int {
+	t.Helper()
+
+	i, err := strconv.Atoi(s)
+	require.NoError(t, err)
+
+	return i
+}
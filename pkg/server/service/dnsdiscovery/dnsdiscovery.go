@@ -0,0 +1,251 @@
+package dnsdiscovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+const (
+	// lookupTimeout is the maximum duration of a resolution of the name.
+	lookupTimeout = 5 * time.Second
+	// defaultRefreshInterval is the interval between two resolutions of the name, when none is configured.
+	defaultRefreshInterval = 30 * time.Second
+)
+
+// Resolver resolves the DNS records of a name.
+// It is satisfied by *net.Resolver.
+type Resolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+}
+
+// Target is a server discovered with DNS.
+type Target struct {
+	Host   string
+	Port   int
+	Weight int
+}
+
+// Address returns the host:port address of the target.
+func (t Target) Address() string {
+	return net.JoinHostPort(t.Host, strconv.Itoa(t.Port))
+}
+
+// Cache keeps the last targets resolved for each name.
+// It outlives the discoveries, so that the ones of a new configuration start with the targets resolved by the previous ones,
+// instead of waiting for a resolution.
+type Cache struct {
+	mu      sync.RWMutex
+	targets map[string][]Target
+}
+
+// NewCache creates a new Cache.
+func NewCache() *Cache {
+	return &Cache{targets: make(map[string][]Target)}
+}
+
+func (c *Cache) get(key string) ([]Target, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	targets, ok := c.targets[key]
+	return targets, ok
+}
+
+func (c *Cache) set(key string, targets []Target) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.targets[key] = targets
+}
+
+// Discovery discovers the servers of a service by resolving a DNS name.
+type Discovery struct {
+	resolver   Resolver
+	cache      *Cache // can be nil
+	name       string
+	recordType string
+	port       int
+	interval   time.Duration
+}
+
+// New creates a new Discovery, keeping its resolved targets in the given cache, when not nil.
+func New(resolver Resolver, cache *Cache, name, recordType string, port int, interval time.Duration) (*Discovery, error) {
+	if name == "" {
+		return nil, errors.New("the name of the DNS discovery must be defined")
+	}
+
+	switch recordType {
+	case "", dynamic.DNSRecordA:
+		recordType = dynamic.DNSRecordA
+		if port <= 0 {
+			return nil, fmt.Errorf("the port of the DNS discovery must be defined with %s records", dynamic.DNSRecordA)
+		}
+	case dynamic.DNSRecordSRV:
+	default:
+		return nil, fmt.Errorf("unknown DNS record type %q", recordType)
+	}
+
+	if interval < 0 {
+		return nil, fmt.Errorf("the refresh interval of the DNS discovery must be positive: %s", interval)
+	}
+
+	if interval == 0 {
+		interval = defaultRefreshInterval
+	}
+
+	return &Discovery{
+		resolver:   resolver,
+		cache:      cache,
+		name:       name,
+		recordType: recordType,
+		port:       port,
+		interval:   interval,
+	}, nil
+}
+
+// Cached returns the targets last resolved for the name, and false if it has not been resolved yet.
+func (d *Discovery) Cached() ([]Target, bool) {
+	if d.cache == nil {
+		return nil, false
+	}
+
+	return d.cache.get(d.key())
+}
+
+func (d *Discovery) key() string {
+	return d.recordType + " " + d.name + " " + strconv.Itoa(d.port)
+}
+
+// Lookup resolves the name, and returns the discovered targets sorted by address.
+func (d *Discovery) Lookup(ctx context.Context) ([]Target, error) {
+	ctx, cancel := context.WithTimeout(ctx, lookupTimeout)
+	defer cancel()
+
+	var targets []Target
+	switch d.recordType {
+	case dynamic.DNSRecordSRV:
+		_, records, err := d.resolver.LookupSRV(ctx, "", "", d.name)
+		if err != nil {
+			return nil, err
+		}
+
+		targets = srvTargets(records)
+	default:
+		addrs, err := d.resolver.LookupIPAddr(ctx, d.name)
+		if err != nil {
+			return nil, err
+		}
+
+		seen := make(map[string]struct{})
+		for _, addr := range addrs {
+			if _, ok := seen[addr.IP.String()]; ok {
+				continue
+			}
+			seen[addr.IP.String()] = struct{}{}
+
+			targets = append(targets, Target{Host: addr.IP.String(), Port: d.port, Weight: 1})
+		}
+	}
+
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].Address() < targets[j].Address()
+	})
+
+	if d.cache != nil {
+		d.cache.set(d.key(), targets)
+	}
+
+	return targets, nil
+}
+
+// srvTargets returns the targets of the SRV records with the lowest priority,
+// the records with a higher priority being only used when none are left.
+// A weight of 0 is handled as 1.
+func srvTargets(records []*net.SRV) []Target {
+	if len(records) == 0 {
+		return nil
+	}
+
+	priority := records[0].Priority
+	for _, record := range records {
+		if record.Priority < priority {
+			priority = record.Priority
+		}
+	}
+
+	var targets []Target
+	for _, record := range records {
+		if record.Priority != priority {
+			continue
+		}
+
+		weight := int(record.Weight)
+		if weight == 0 {
+			weight = 1
+		}
+
+		targets = append(targets, Target{
+			Host:   strings.TrimSuffix(record.Target, "."),
+			Port:   int(record.Port),
+			Weight: weight,
+		})
+	}
+
+	return targets
+}
+
+// Watch resolves the name right away, then at each refresh interval, until the context is done,
+// and calls update with all the targets each time they differ from the previous ones, starting from the given targets.
+// When the resolution fails, the previous targets are kept.
+func (d *Discovery) Watch(ctx context.Context, targets []Target, update func(targets []Target)) {
+	logger := log.FromContext(ctx)
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		newTargets, err := d.Lookup(ctx)
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil:
+			logger.Errorf("Unable to resolve %s: %v", d.name, err)
+		case !equal(targets, newTargets):
+			logger.Debugf("The servers resolved from %s changed: %v", d.name, newTargets)
+
+			targets = newTargets
+			update(targets)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func equal(a, b []Target) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
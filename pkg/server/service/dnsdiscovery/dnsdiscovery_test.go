@@ -0,0 +1,308 @@
+package dnsdiscovery
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+// dnsServer is a local DNS server answering with the records it is given.
+type dnsServer struct {
+	mu      sync.Mutex
+	records map[string][]string
+}
+
+func (s *dnsServer) setRecords(records map[string][]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records = records
+}
+
+func (s *dnsServer) ServeDNS(w dns.ResponseWriter, req *dns.Msg) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg := new(dns.Msg)
+	msg.SetReply(req)
+
+	for _, question := range req.Question {
+		records, ok := s.records[question.Name]
+		if !ok {
+			msg.Rcode = dns.RcodeNameError
+			continue
+		}
+
+		for _, record := range records {
+			rr, err := dns.NewRR(record)
+			if err != nil {
+				continue
+			}
+
+			if rr.Header().Rrtype == question.Qtype {
+				msg.Answer = append(msg.Answer, rr)
+			}
+		}
+	}
+
+	_ = w.WriteMsg(msg)
+}
+
+// startDNSServer starts a local DNS server, and returns a resolver querying it.
+func startDNSServer(t *testing.T, records map[string][]string) (*dnsServer, Resolver) {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	handler := &dnsServer{records: records}
+
+	started := make(chan struct{})
+	server := &dns.Server{PacketConn: conn, Handler: handler, NotifyStartedFunc: func() { close(started) }}
+	go func() { _ = server.ActivateAndServe() }()
+	t.Cleanup(func() { _ = server.Shutdown() })
+
+	<-started
+
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "udp", conn.LocalAddr().String())
+		},
+	}
+
+	return handler, resolver
+}
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		name          string
+		recordType    string
+		port          int
+		interval      time.Duration
+		expectedError bool
+	}{
+		{
+			desc:       "A records",
+			name:       "backend.example.com.",
+			recordType: dynamic.DNSRecordA,
+			port:       80,
+			interval:   time.Second,
+		},
+		{
+			desc:     "default record type",
+			name:     "backend.example.com.",
+			port:     80,
+			interval: time.Second,
+		},
+		{
+			desc:       "SRV records without port",
+			name:       "_http._tcp.example.com.",
+			recordType: dynamic.DNSRecordSRV,
+			interval:   time.Second,
+		},
+		{
+			desc:          "no name",
+			recordType:    dynamic.DNSRecordA,
+			port:          80,
+			interval:      time.Second,
+			expectedError: true,
+		},
+		{
+			desc:          "A records without port",
+			name:          "backend.example.com.",
+			recordType:    dynamic.DNSRecordA,
+			interval:      time.Second,
+			expectedError: true,
+		},
+		{
+			desc:          "unknown record type",
+			name:          "backend.example.com.",
+			recordType:    "MX",
+			port:          80,
+			interval:      time.Second,
+			expectedError: true,
+		},
+		{
+			desc:       "default interval",
+			name:       "backend.example.com.",
+			recordType: dynamic.DNSRecordA,
+			port:       80,
+		},
+		{
+			desc:          "negative interval",
+			name:          "backend.example.com.",
+			recordType:    dynamic.DNSRecordA,
+			port:          80,
+			interval:      -time.Second,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(&net.Resolver{}, nil, test.name, test.recordType, test.port, test.interval)
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDiscovery_Lookup(t *testing.T) {
+	_, resolver := startDNSServer(t, map[string][]string{
+		"backend.example.com.": {
+			"backend.example.com. 60 IN A 10.0.0.2",
+			"backend.example.com. 60 IN A 10.0.0.1",
+			"backend.example.com. 60 IN AAAA 2001:db8::1",
+		},
+		"_http._tcp.example.com.": {
+			"_http._tcp.example.com. 60 IN SRV 10 3 8080 backend1.example.com.",
+			"_http._tcp.example.com. 60 IN SRV 10 0 8081 backend2.example.com.",
+			"_http._tcp.example.com. 60 IN SRV 20 1 8080 backup.example.com.",
+		},
+	})
+
+	testCases := []struct {
+		desc          string
+		name          string
+		recordType    string
+		expected      []Target
+		expectedError bool
+	}{
+		{
+			desc:       "A and AAAA records",
+			name:       "backend.example.com.",
+			recordType: dynamic.DNSRecordA,
+			expected: []Target{
+				{Host: "10.0.0.1", Port: 80, Weight: 1},
+				{Host: "10.0.0.2", Port: 80, Weight: 1},
+				{Host: "2001:db8::1", Port: 80, Weight: 1},
+			},
+		},
+		{
+			desc:       "SRV records with the lowest priority",
+			name:       "_http._tcp.example.com.",
+			recordType: dynamic.DNSRecordSRV,
+			expected: []Target{
+				{Host: "backend1.example.com", Port: 8080, Weight: 3},
+				{Host: "backend2.example.com", Port: 8081, Weight: 1},
+			},
+		},
+		{
+			desc:          "unknown name",
+			name:          "unknown.example.com.",
+			recordType:    dynamic.DNSRecordA,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			discovery, err := New(resolver, nil, test.name, test.recordType, 80, time.Second)
+			require.NoError(t, err)
+
+			targets, err := discovery.Lookup(context.Background())
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, targets)
+		})
+	}
+}
+
+func TestDiscovery_Watch(t *testing.T) {
+	server, resolver := startDNSServer(t, map[string][]string{
+		"backend.example.com.": {"backend.example.com. 60 IN A 10.0.0.1"},
+	})
+
+	cache := NewCache()
+
+	discovery, err := New(resolver, cache, "backend.example.com.", dynamic.DNSRecordA, 80, 10*time.Millisecond)
+	require.NoError(t, err)
+
+	_, ok := discovery.Cached()
+	assert.False(t, ok)
+
+	updates := make(chan []Target, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go discovery.Watch(ctx, nil, func(targets []Target) {
+		updates <- targets
+	})
+
+	// The name is resolved right away.
+	select {
+	case targets := <-updates:
+		assert.Equal(t, []Target{{Host: "10.0.0.1", Port: 80, Weight: 1}}, targets)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the update of the targets")
+	}
+
+	// The resolved targets are cached for the discoveries of the same name.
+	other, err := New(resolver, cache, "backend.example.com.", dynamic.DNSRecordA, 80, time.Second)
+	require.NoError(t, err)
+
+	targets, ok := other.Cached()
+	assert.True(t, ok)
+	assert.Equal(t, []Target{{Host: "10.0.0.1", Port: 80, Weight: 1}}, targets)
+
+	server.setRecords(map[string][]string{
+		"backend.example.com.": {
+			"backend.example.com. 60 IN A 10.0.0.1",
+			"backend.example.com. 60 IN A 10.0.0.2",
+		},
+	})
+
+	select {
+	case targets := <-updates:
+		assert.Equal(t, []Target{
+			{Host: "10.0.0.1", Port: 80, Weight: 1},
+			{Host: "10.0.0.2", Port: 80, Weight: 1},
+		}, targets)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the update of the targets")
+	}
+
+	// The targets are kept when the resolution fails.
+	server.setRecords(nil)
+
+	select {
+	case targets := <-updates:
+		t.Fatalf("unexpected update of the targets: %v", targets)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	server.setRecords(map[string][]string{
+		"backend.example.com.": {"backend.example.com. 60 IN A 10.0.0.2"},
+	})
+
+	select {
+	case targets := <-updates:
+		assert.Equal(t, []Target{{Host: "10.0.0.2", Port: 80, Weight: 1}}, targets)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the update of the targets")
+	}
+}
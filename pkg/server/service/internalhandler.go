@@ -11,6 +11,7 @@ import (
 type serviceManager interface {
 	BuildHTTP(rootCtx context.Context, serviceName string) (http.Handler, error)
 	LaunchHealthCheck()
+	LaunchDNSDiscovery(ctx context.Context)
 }
 
 // InternalHandlers is the internal HTTP handlers builder.
@@ -0,0 +1,280 @@
+package failover
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/healthcheck"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+const (
+	statusUp   = "UP"
+	statusDown = "DOWN"
+)
+
+// Failover is an http.Handler that forwards the requests to a service,
+// or to a fallback service when the service has no servers up,
+// or when the service responds with one of the given status codes.
+type Failover struct {
+	statusCodes types.HTTPCodeRanges
+	maxBodySize int64
+	serviceInfo *runtime.ServiceInfo // can be nil
+
+	handler         http.Handler
+	fallbackHandler http.Handler
+
+	mutex          sync.RWMutex
+	handlerStatus  bool
+	fallbackStatus bool
+	updaters       []func(up bool)
+	up             bool
+}
+
+// New creates a new Failover.
+func New(statusCodes types.HTTPCodeRanges, maxBodySize int64, serviceInfo *runtime.ServiceInfo) *Failover {
+	return &Failover{
+		statusCodes:    statusCodes,
+		maxBodySize:    maxBodySize,
+		serviceInfo:    serviceInfo,
+		handlerStatus:  true,
+		fallbackStatus: true,
+		up:             true,
+	}
+}
+
+// SetHandler sets the handler of the service.
+// The handler must be a healthcheck.StatusUpdater, the requests go to the fallback while it has no servers up.
+func (f *Failover) SetHandler(name string, handler http.Handler) error {
+	f.handler = handler
+
+	return f.registerStatusUpdater(name, handler, func(up bool) {
+		f.handlerStatus = up
+	})
+}
+
+// SetFallbackHandler sets the handler of the fallback service.
+// The handler must be a healthcheck.StatusUpdater.
+func (f *Failover) SetFallbackHandler(name string, handler http.Handler) error {
+	f.fallbackHandler = handler
+
+	return f.registerStatusUpdater(name, handler, func(up bool) {
+		f.fallbackStatus = up
+	})
+}
+
+func (f *Failover) registerStatusUpdater(name string, handler http.Handler, setStatus func(up bool)) error {
+	statusUpdater, ok := handler.(healthcheck.StatusUpdater)
+	if !ok {
+		return fmt.Errorf("child service %s does not expose status", name)
+	}
+
+	err := statusUpdater.RegisterStatusUpdater(func(up bool) {
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+
+		setStatus(up)
+
+		if f.serviceInfo != nil {
+			status := statusDown
+			if up {
+				status = statusUp
+			}
+			f.serviceInfo.UpdateServerStatus(name, status)
+		}
+
+		f.updateStatus()
+	})
+	if err != nil {
+		return fmt.Errorf("service %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// updateStatus notifies the registered status updaters when the Failover has no services up anymore,
+// or has services up again. It must be called with the mutex held.
+func (f *Failover) updateStatus() {
+	up := f.handlerStatus || f.fallbackStatus
+	if up == f.up {
+		return
+	}
+
+	f.up = up
+	for _, fn := range f.updaters {
+		fn(up)
+	}
+}
+
+// RegisterStatusUpdater registers a function called with the current status of the Failover,
+// and then each time both services are down, or one of the services is up again.
+func (f *Failover) RegisterStatusUpdater(fn func(up bool)) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.updaters = append(f.updaters, fn)
+	fn(f.up)
+
+	return nil
+}
+
+func (f *Failover) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	f.mutex.RLock()
+	handlerStatus := f.handlerStatus
+	f.mutex.RUnlock()
+
+	if !handlerStatus {
+		log.FromContext(req.Context()).Debug("Service is down, forwarding the request to the fallback")
+		f.fallbackHandler.ServeHTTP(rw, req)
+		return
+	}
+
+	if len(f.statusCodes) == 0 {
+		f.handler.ServeHTTP(rw, req)
+		return
+	}
+
+	body, err := readBody(req, f.maxBodySize)
+	if errors.Is(err, errBodyTooLarge) {
+		log.FromContext(req.Context()).Debug("No failover on the response status, request body larger than allowed size")
+		req.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), req.Body))
+		f.handler.ServeHTTP(rw, req)
+		return
+	}
+	if err != nil {
+		http.Error(rw, http.StatusText(http.StatusInternalServerError)+fmt.Sprintf("error reading request body: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	recorder := &responseRecorder{ResponseWriter: rw, header: make(http.Header), statusCodes: f.statusCodes}
+	f.handler.ServeHTTP(recorder, withBody(req, body))
+
+	if recorder.failedStatus == 0 {
+		return
+	}
+
+	log.FromContext(req.Context()).Debugf("Service responded with status %d, forwarding the request to the fallback", recorder.failedStatus)
+	f.fallbackHandler.ServeHTTP(rw, withBody(req, body))
+}
+
+var errBodyTooLarge = errors.New("request body too large")
+
+// readBody reads the body of the request, up to maxBodySize bytes when maxBodySize is not negative.
+func readBody(req *http.Request, maxBodySize int64) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	if maxBodySize < 0 {
+		return io.ReadAll(req.Body)
+	}
+
+	// Reads one more byte than allowed to detect whether the body is too large.
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxBodySize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(body)) > maxBodySize {
+		return body, errBodyTooLarge
+	}
+
+	return body, nil
+}
+
+func withBody(req *http.Request, body []byte) *http.Request {
+	if body == nil {
+		return req
+	}
+
+	newReq := req.Clone(req.Context())
+	newReq.Body = io.NopCloser(bytes.NewReader(body))
+	newReq.ContentLength = int64(len(body))
+
+	return newReq
+}
+
+// responseRecorder forwards the response to the underlying ResponseWriter,
+// unless its status code is one of the given status codes,
+// in which case the response is discarded.
+type responseRecorder struct {
+	http.ResponseWriter
+	header      http.Header
+	statusCodes types.HTTPCodeRanges
+
+	wroteHeader  bool
+	failedStatus int
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) WriteHeader(statusCode int) {
+	if r.wroteHeader {
+		return
+	}
+
+	// Informational responses are forwarded as is.
+	if statusCode >= 100 && statusCode < 200 && statusCode != http.StatusSwitchingProtocols {
+		copyHeader(r.ResponseWriter.Header(), r.header)
+		r.ResponseWriter.WriteHeader(statusCode)
+		return
+	}
+
+	r.wroteHeader = true
+
+	if r.statusCodes.Contains(statusCode) {
+		r.failedStatus = statusCode
+		return
+	}
+
+	copyHeader(r.ResponseWriter.Header(), r.header)
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	if r.failedStatus != 0 {
+		return len(b), nil
+	}
+
+	return r.ResponseWriter.Write(b)
+}
+
+// Hijack hijacks the connection.
+func (r *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return r.ResponseWriter.(http.Hijacker).Hijack()
+}
+
+// Flush sends any buffered data to the client.
+func (r *responseRecorder) Flush() {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	if r.failedStatus != 0 {
+		return
+	}
+
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func copyHeader(dst, src http.Header) {
+	for k, vv := range src {
+		dst[k] = append([]string(nil), vv...)
+	}
+}
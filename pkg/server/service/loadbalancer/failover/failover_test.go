@@ -0,0 +1,142 @@
+package failover
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+type statusHandler struct {
+	http.Handler
+	updaters []func(up bool)
+}
+
+func (h *statusHandler) RegisterStatusUpdater(fn func(up bool)) error {
+	h.updaters = append(h.updaters, fn)
+	fn(true)
+	return nil
+}
+
+func (h *statusHandler) setStatus(up bool) {
+	for _, fn := range h.updaters {
+		fn(up)
+	}
+}
+
+func nameHandler(name string, statusCode int) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+
+		rw.Header().Set("server", name)
+		rw.WriteHeader(statusCode)
+		_, _ = rw.Write(append([]byte(name+":"), body...))
+	})
+}
+
+func TestFailover_Status(t *testing.T) {
+	serviceInfo := &runtime.ServiceInfo{}
+	failover := New(nil, -1, serviceInfo)
+
+	primary := &statusHandler{Handler: nameHandler("primary", http.StatusOK)}
+	require.NoError(t, failover.SetHandler("primary@file", primary))
+
+	fallback := &statusHandler{Handler: nameHandler("fallback", http.StatusOK)}
+	require.NoError(t, failover.SetFallbackHandler("fallback@file", fallback))
+
+	var statuses []bool
+	require.NoError(t, failover.RegisterStatusUpdater(func(up bool) {
+		statuses = append(statuses, up)
+	}))
+
+	recorder := httptest.NewRecorder()
+	failover.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, "primary", recorder.Header().Get("server"))
+
+	primary.setStatus(false)
+
+	recorder = httptest.NewRecorder()
+	failover.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, "fallback", recorder.Header().Get("server"))
+	assert.Equal(t, map[string]string{"primary@file": statusDown, "fallback@file": statusUp}, serviceInfo.GetAllStatus())
+
+	fallback.setStatus(false)
+	primary.setStatus(true)
+
+	recorder = httptest.NewRecorder()
+	failover.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, "primary", recorder.Header().Get("server"))
+	assert.Equal(t, map[string]string{"primary@file": statusUp, "fallback@file": statusDown}, serviceInfo.GetAllStatus())
+
+	// The failover is only down while both services are down.
+	assert.Equal(t, []bool{true, false, true}, statuses)
+}
+
+func TestFailover_NoStatus(t *testing.T) {
+	failover := New(nil, -1, nil)
+
+	err := failover.SetHandler("primary@file", nameHandler("primary", http.StatusOK))
+	assert.EqualError(t, err, "child service primary@file does not expose status")
+
+	err = failover.SetFallbackHandler("fallback@file", nameHandler("fallback", http.StatusOK))
+	assert.EqualError(t, err, "child service fallback@file does not expose status")
+}
+
+func TestFailover_StatusCodes(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		primaryStatus  int
+		maxBodySize    int64
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			desc:           "primary success",
+			primaryStatus:  http.StatusOK,
+			maxBodySize:    -1,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "primary:foo",
+		},
+		{
+			desc:           "primary failure",
+			primaryStatus:  http.StatusBadGateway,
+			maxBodySize:    -1,
+			expectedStatus: http.StatusCreated,
+			expectedBody:   "fallback:foo",
+		},
+		{
+			desc:           "primary failure with a body larger than allowed",
+			primaryStatus:  http.StatusBadGateway,
+			maxBodySize:    2,
+			expectedStatus: http.StatusBadGateway,
+			expectedBody:   "primary:foo",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			statusCodes, err := types.NewHTTPCodeRanges([]string{"500-599"})
+			require.NoError(t, err)
+
+			failover := New(statusCodes, test.maxBodySize, nil)
+			require.NoError(t, failover.SetHandler("primary", &statusHandler{Handler: nameHandler("primary", test.primaryStatus)}))
+			require.NoError(t, failover.SetFallbackHandler("fallback", &statusHandler{Handler: nameHandler("fallback", http.StatusCreated)}))
+
+			recorder := httptest.NewRecorder()
+			failover.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("foo")))
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+			assert.Equal(t, []string{strings.SplitN(test.expectedBody, ":", 2)[0]}, recorder.Header()["Server"])
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package loadbalancer
+
+import "hash/fnv"
+
+// Hash returns the hash of the given value, used to pick a server or a mirror consistently for the same value.
+func Hash(value string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(value))
+
+	// The MurmurHash3 finalizer spreads the FNV hashes of close values over the whole range.
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+
+	return x
+}
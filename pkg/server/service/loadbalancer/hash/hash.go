@@ -0,0 +1,294 @@
+package hash
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+// replicasPerWeight is the number of points each unit of weight of a server owns on the ring.
+const replicasPerWeight = 160
+
+type server struct {
+	url      *url.URL
+	weight   int
+	inFlight int64
+}
+
+type ringPoint struct {
+	hash   uint64
+	server *server
+}
+
+// Balancer is a consistent hashing load balancer with bounded loads.
+// (https://arxiv.org/abs/1608.01350)
+// Each server owns points on a hash ring, in proportion to its weight,
+// and a request goes to the server owning the first point following the hash of its key.
+// As adding or removing a server only moves the keys of the points it owns,
+// only a small fraction of the keys are remapped.
+// To avoid overloading a server with hot keys,
+// a server whose in-flight requests exceed loadFactor times its share of the total is skipped,
+// and the request goes to the next server on the ring.
+type Balancer struct {
+	next       http.Handler
+	key        func(req *http.Request) string
+	loadFactor float64
+
+	mutex         sync.Mutex
+	servers       []*server
+	ring          []ringPoint
+	totalWeight   int
+	totalInFlight int64
+}
+
+// New creates a new consistent hashing load balancer forwarding the requests to next.
+func New(next http.Handler, config *dynamic.HashStrategy) (*Balancer, error) {
+	if config == nil {
+		config = &dynamic.HashStrategy{}
+		config.SetDefaults()
+	}
+
+	key, err := keyFunc(config)
+	if err != nil {
+		return nil, err
+	}
+
+	loadFactor := config.LoadFactor
+	if loadFactor == 0 {
+		loadFactor = 1.25
+	}
+
+	if loadFactor < 1 {
+		return nil, fmt.Errorf("load factor must be greater than or equal to 1: %v", loadFactor)
+	}
+
+	return &Balancer{
+		next:       next,
+		key:        key,
+		loadFactor: loadFactor,
+	}, nil
+}
+
+func keyFunc(config *dynamic.HashStrategy) (func(req *http.Request) string, error) {
+	switch config.Key {
+	case "", dynamic.HashKeyClientIP:
+		return func(req *http.Request) string {
+			host, _, err := net.SplitHostPort(req.RemoteAddr)
+			if err != nil {
+				return req.RemoteAddr
+			}
+			return host
+		}, nil
+
+	case dynamic.HashKeyHeader:
+		if config.Name == "" {
+			return nil, errors.New("the header name is missing")
+		}
+
+		name := http.CanonicalHeaderKey(config.Name)
+		return func(req *http.Request) string {
+			return req.Header.Get(name)
+		}, nil
+
+	case dynamic.HashKeyCookie:
+		if config.Name == "" {
+			return nil, errors.New("the cookie name is missing")
+		}
+
+		return func(req *http.Request) string {
+			cookie, err := req.Cookie(config.Name)
+			if err != nil {
+				return ""
+			}
+			return cookie.Value
+		}, nil
+
+	case dynamic.HashKeyPath:
+		return func(req *http.Request) string {
+			return req.URL.Path
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown hash key %q", config.Key)
+	}
+}
+
+func (b *Balancer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	srv, err := b.acquire(loadbalancer.Hash(b.key(req)))
+	if err != nil {
+		log.FromContext(req.Context()).Errorf("Error while picking a server: %v", err)
+		http.Error(rw, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+	defer b.release(srv)
+
+	// make shallow copy of request before changing anything to avoid side effects
+	newReq := *req
+	u := *srv.url
+	newReq.URL = &u
+
+	b.next.ServeHTTP(rw, &newReq)
+}
+
+// acquire picks the server handling the given hash, and counts a new in-flight request on it.
+func (b *Balancer) acquire(hash uint64) (*server, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if len(b.ring) == 0 {
+		return nil, errors.New("no servers in the pool")
+	}
+
+	start := sort.Search(len(b.ring), func(i int) bool {
+		return b.ring[i].hash >= hash
+	})
+
+	var picked *server
+	visited := make(map[*server]struct{}, len(b.servers))
+	for i := 0; i < len(b.ring) && len(visited) < len(b.servers); i++ {
+		srv := b.ring[(start+i)%len(b.ring)].server
+		if _, ok := visited[srv]; ok {
+			continue
+		}
+		visited[srv] = struct{}{}
+
+		if picked == nil {
+			picked = srv
+		}
+
+		if srv.inFlight < b.capacity(srv) {
+			picked = srv
+			break
+		}
+	}
+
+	picked.inFlight++
+	b.totalInFlight++
+
+	return picked, nil
+}
+
+// capacity returns the maximum number of in-flight requests the server can handle,
+// including the one being balanced.
+func (b *Balancer) capacity(srv *server) int64 {
+	share := float64(b.totalInFlight+1) * float64(srv.weight) / float64(b.totalWeight)
+	return int64(math.Ceil(b.loadFactor * share))
+}
+
+func (b *Balancer) release(srv *server) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	srv.inFlight--
+	b.totalInFlight--
+}
+
+// Servers returns the URLs of the servers in the pool.
+func (b *Balancer) Servers() []*url.URL {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	urls := make([]*url.URL, 0, len(b.servers))
+	for _, srv := range b.servers {
+		u := *srv.url
+		urls = append(urls, &u)
+	}
+
+	return urls
+}
+
+// ServerWeight returns the weight of the server with the given URL.
+func (b *Balancer) ServerWeight(u *url.URL) (int, bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if i := b.indexOf(u); i >= 0 {
+		return b.servers[i].weight, true
+	}
+
+	return -1, false
+}
+
+// RemoveServer removes the server with the given URL from the pool.
+func (b *Balancer) RemoveServer(u *url.URL) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	i := b.indexOf(u)
+	if i < 0 {
+		return fmt.Errorf("server not found")
+	}
+
+	b.servers = append(b.servers[:i], b.servers[i+1:]...)
+	b.buildRing()
+
+	return nil
+}
+
+// UpsertServer adds a server to the pool, or updates its weight if it already exists.
+func (b *Balancer) UpsertServer(u *url.URL, options ...roundrobin.ServerOption) error {
+	if u == nil {
+		return fmt.Errorf("server URL can't be nil")
+	}
+
+	weight, err := loadbalancer.ServerWeight(u, options...)
+	if err != nil {
+		return err
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if i := b.indexOf(u); i >= 0 {
+		b.servers[i].weight = weight
+	} else {
+		srvURL := *u
+		b.servers = append(b.servers, &server{url: &srvURL, weight: weight})
+	}
+
+	b.buildRing()
+
+	return nil
+}
+
+func (b *Balancer) indexOf(u *url.URL) int {
+	for i, srv := range b.servers {
+		if srv.url.String() == u.String() {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// buildRing rebuilds the hash ring from the servers in the pool.
+func (b *Balancer) buildRing() {
+	b.ring = b.ring[:0]
+	b.totalWeight = 0
+
+	for _, srv := range b.servers {
+		b.totalWeight += srv.weight
+
+		for i := 0; i < srv.weight*replicasPerWeight; i++ {
+			b.ring = append(b.ring, ringPoint{
+				hash:   loadbalancer.Hash(srv.url.String() + "-" + strconv.Itoa(i)),
+				server: srv,
+			})
+		}
+	}
+
+	sort.Slice(b.ring, func(i, j int) bool {
+		return b.ring[i].hash < b.ring[j].hash
+	})
+}
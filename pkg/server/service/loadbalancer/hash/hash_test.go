@@ -0,0 +1,254 @@
+package hash
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        *dynamic.HashStrategy
+		expectedError string
+	}{
+		{
+			desc: "default configuration",
+		},
+		{
+			desc:   "header key",
+			config: &dynamic.HashStrategy{Key: dynamic.HashKeyHeader, Name: "X-User"},
+		},
+		{
+			desc:          "header key without name",
+			config:        &dynamic.HashStrategy{Key: dynamic.HashKeyHeader},
+			expectedError: "the header name is missing",
+		},
+		{
+			desc:          "cookie key without name",
+			config:        &dynamic.HashStrategy{Key: dynamic.HashKeyCookie},
+			expectedError: "the cookie name is missing",
+		},
+		{
+			desc:          "unknown key",
+			config:        &dynamic.HashStrategy{Key: "foo"},
+			expectedError: `unknown hash key "foo"`,
+		},
+		{
+			desc:          "load factor lower than 1",
+			config:        &dynamic.HashStrategy{Key: dynamic.HashKeyPath, LoadFactor: 0.5},
+			expectedError: "load factor must be greater than or equal to 1: 0.5",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(http.NotFoundHandler(), test.config)
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestBalancer_Keys(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config *dynamic.HashStrategy
+		setKey func(req *http.Request, key string)
+	}{
+		{
+			desc:   "client IP",
+			config: &dynamic.HashStrategy{Key: dynamic.HashKeyClientIP},
+			setKey: func(req *http.Request, key string) {
+				req.RemoteAddr = key + ":" + strconv.Itoa(int(req.ContentLength))
+			},
+		},
+		{
+			desc:   "header",
+			config: &dynamic.HashStrategy{Key: dynamic.HashKeyHeader, Name: "x-user"},
+			setKey: func(req *http.Request, key string) {
+				req.Header.Set("X-User", key)
+			},
+		},
+		{
+			desc:   "cookie",
+			config: &dynamic.HashStrategy{Key: dynamic.HashKeyCookie, Name: "session"},
+			setKey: func(req *http.Request, key string) {
+				req.AddCookie(&http.Cookie{Name: "session", Value: key})
+			},
+		},
+		{
+			desc:   "path",
+			config: &dynamic.HashStrategy{Key: dynamic.HashKeyPath},
+			setKey: func(req *http.Request, key string) {
+				req.URL.Path = "/" + key
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			balancer, err := New(serverNameHandler(), test.config)
+			require.NoError(t, err)
+
+			for i := 0; i < 3; i++ {
+				require.NoError(t, balancer.UpsertServer(serverURL(t, i), roundrobin.Weight(1)))
+			}
+
+			for i := 0; i < 50; i++ {
+				key := fmt.Sprintf("10.0.0.%d", i)
+
+				var picked []string
+				for port := 0; port < 3; port++ {
+					req := httptest.NewRequest(http.MethodGet, "/", nil)
+					req.ContentLength = int64(port)
+					test.setKey(req, key)
+
+					recorder := httptest.NewRecorder()
+					balancer.ServeHTTP(recorder, req)
+
+					picked = append(picked, recorder.Header().Get("server"))
+				}
+
+				assert.Equal(t, picked[0], picked[1], key)
+				assert.Equal(t, picked[0], picked[2], key)
+			}
+		})
+	}
+}
+
+func TestBalancer_RemapOnRemove(t *testing.T) {
+	balancer, err := New(serverNameHandler(), &dynamic.HashStrategy{Key: dynamic.HashKeyPath})
+	require.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, balancer.UpsertServer(serverURL(t, i), roundrobin.Weight(1)))
+	}
+
+	before := pickAll(balancer, 1000)
+
+	removed := serverURL(t, 2)
+	require.NoError(t, balancer.RemoveServer(removed))
+
+	after := pickAll(balancer, 1000)
+
+	for key, server := range before {
+		if server == removed.Host {
+			assert.NotEqual(t, removed.Host, after[key])
+			continue
+		}
+		assert.Equal(t, server, after[key], key)
+	}
+}
+
+func TestBalancer_Weights(t *testing.T) {
+	balancer, err := New(serverNameHandler(), &dynamic.HashStrategy{Key: dynamic.HashKeyPath})
+	require.NoError(t, err)
+
+	require.NoError(t, balancer.UpsertServer(serverURL(t, 0), roundrobin.Weight(1)))
+	require.NoError(t, balancer.UpsertServer(serverURL(t, 1), roundrobin.Weight(3)))
+
+	weight, ok := balancer.ServerWeight(serverURL(t, 1))
+	assert.True(t, ok)
+	assert.Equal(t, 3, weight)
+
+	counts := make(map[string]int)
+	for _, server := range pickAll(balancer, 4000) {
+		counts[server]++
+	}
+
+	assert.InDelta(t, 1000, counts[serverURL(t, 0).Host], 400)
+	assert.InDelta(t, 3000, counts[serverURL(t, 1).Host], 400)
+}
+
+func TestBalancer_BoundedLoad(t *testing.T) {
+	balancer, err := New(http.NotFoundHandler(), &dynamic.HashStrategy{Key: dynamic.HashKeyPath, LoadFactor: 1.25})
+	require.NoError(t, err)
+
+	for i := 0; i < 4; i++ {
+		require.NoError(t, balancer.UpsertServer(serverURL(t, i), roundrobin.Weight(1)))
+	}
+
+	// Keeps the requests of a single hot key in flight.
+	hash := loadbalancer.Hash("/hot")
+	var picked []*server
+	for i := 0; i < 40; i++ {
+		srv, err := balancer.acquire(hash)
+		require.NoError(t, err)
+		picked = append(picked, srv)
+	}
+
+	counts := make(map[*server]int)
+	for _, srv := range picked {
+		counts[srv]++
+	}
+
+	assert.Len(t, counts, 4)
+	for _, count := range counts {
+		// Each server is bounded to ceil(1.25 * 40 / 4) in-flight requests.
+		assert.LessOrEqual(t, count, 13)
+	}
+
+	for _, srv := range picked {
+		balancer.release(srv)
+	}
+	assert.Equal(t, int64(0), balancer.totalInFlight)
+}
+
+func TestBalancer_NoServers(t *testing.T) {
+	balancer, err := New(http.NotFoundHandler(), nil)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+}
+
+func serverNameHandler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", req.URL.Host)
+	})
+}
+
+func serverURL(t *testing.T, i int) *url.URL {
+	t.Helper()
+
+	u, err := url.Parse(fmt.Sprintf("http://10.10.10.%d:80", i))
+	require.NoError(t, err)
+
+	return u
+}
+
+// pickAll returns the server picked for n different paths.
+func pickAll(balancer *Balancer, n int) map[string]string {
+	picked := make(map[string]string, n)
+	for i := 0; i < n; i++ {
+		path := "/" + strconv.Itoa(i)
+
+		recorder := httptest.NewRecorder()
+		balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+
+		picked[path] = recorder.Header().Get("server")
+	}
+
+	return picked
+}
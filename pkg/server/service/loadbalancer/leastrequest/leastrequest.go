@@ -0,0 +1,269 @@
+package leastrequest
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+const defaultDecayTime = 10 * time.Second
+
+type server struct {
+	url      *url.URL
+	weight   int
+	inFlight int64
+
+	// latency is the moving average of the server latency, in nanoseconds.
+	latency    float64
+	lastSample time.Time
+}
+
+// Balancer is a load balancer forwarding the requests to the server with the least outstanding requests,
+// relative to its weight.
+// With the power of two choices enabled, only two servers picked at random are compared,
+// which avoids herding all the requests on the same least loaded server.
+// With EWMA enabled, the outstanding requests of each server are weighted
+// by an exponentially weighted moving average of its latency, so that slow servers get fewer requests.
+type Balancer struct {
+	next              http.Handler
+	powerOfTwoChoices bool
+	ewma              bool
+	decayTime         time.Duration
+	now               func() time.Time
+
+	mutex   sync.Mutex
+	rand    *rand.Rand
+	servers []*server
+}
+
+// New creates a new least-request load balancer forwarding the requests to next.
+func New(next http.Handler, powerOfTwoChoices bool, config *dynamic.LeastRequestStrategy) (*Balancer, error) {
+	if config == nil {
+		config = &dynamic.LeastRequestStrategy{}
+		config.SetDefaults()
+	}
+
+	decayTime := time.Duration(config.DecayTime)
+	if decayTime < 0 {
+		return nil, fmt.Errorf("decay time must be positive: %s", decayTime)
+	}
+
+	if decayTime == 0 {
+		decayTime = defaultDecayTime
+	}
+
+	return &Balancer{
+		next:              next,
+		powerOfTwoChoices: powerOfTwoChoices,
+		ewma:              config.EWMA,
+		decayTime:         decayTime,
+		now:               time.Now,
+		rand:              rand.New(rand.NewSource(time.Now().UnixNano())),
+	}, nil
+}
+
+func (b *Balancer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	srv, err := b.acquire()
+	if err != nil {
+		log.FromContext(req.Context()).Errorf("Error while picking a server: %v", err)
+		http.Error(rw, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+
+	start := b.now()
+	defer func() {
+		b.release(srv, b.now().Sub(start))
+	}()
+
+	// make shallow copy of request before changing anything to avoid side effects
+	newReq := *req
+	u := *srv.url
+	newReq.URL = &u
+
+	b.next.ServeHTTP(rw, &newReq)
+}
+
+// acquire picks the least busy server, and counts a new in-flight request on it.
+func (b *Balancer) acquire() (*server, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	srv := b.pick()
+	if srv == nil {
+		return nil, errors.New("no servers in the pool")
+	}
+
+	srv.inFlight++
+
+	return srv, nil
+}
+
+func (b *Balancer) pick() *server {
+	switch len(b.servers) {
+	case 0:
+		return nil
+	case 1:
+		return b.servers[0]
+	}
+
+	if b.powerOfTwoChoices {
+		first := b.servers[b.rand.Intn(len(b.servers))]
+		// Picks a second server distinct from the first one.
+		second := b.servers[b.rand.Intn(len(b.servers)-1)]
+		if second == first {
+			second = b.servers[len(b.servers)-1]
+		}
+
+		if b.score(second) < b.score(first) {
+			return second
+		}
+		return first
+	}
+
+	// Starts from a random offset so that ties are not always won by the first server.
+	offset := b.rand.Intn(len(b.servers))
+	best := b.servers[offset]
+	bestScore := b.score(best)
+	for i := 1; i < len(b.servers); i++ {
+		srv := b.servers[(offset+i)%len(b.servers)]
+		if score := b.score(srv); score < bestScore {
+			best, bestScore = srv, score
+		}
+	}
+
+	return best
+}
+
+// score returns the load the server would have with one more request, relative to its weight.
+func (b *Balancer) score(srv *server) float64 {
+	load := float64(srv.inFlight + 1)
+	if b.ewma {
+		load *= srv.latency + 1
+	}
+
+	return load / float64(srv.weight)
+}
+
+func (b *Balancer) release(srv *server, latency time.Duration) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	srv.inFlight--
+
+	if !b.ewma {
+		return
+	}
+
+	now := b.now()
+	if srv.lastSample.IsZero() {
+		srv.latency = float64(latency)
+	} else {
+		decay := math.Exp(-float64(now.Sub(srv.lastSample)) / float64(b.decayTime))
+		srv.latency = srv.latency*decay + float64(latency)*(1-decay)
+	}
+	srv.lastSample = now
+}
+
+// Servers returns the URLs of the servers in the pool.
+func (b *Balancer) Servers() []*url.URL {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	urls := make([]*url.URL, 0, len(b.servers))
+	for _, srv := range b.servers {
+		u := *srv.url
+		urls = append(urls, &u)
+	}
+
+	return urls
+}
+
+// ServerWeight returns the weight of the server with the given URL.
+func (b *Balancer) ServerWeight(u *url.URL) (int, bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if i := b.indexOf(u); i >= 0 {
+		return b.servers[i].weight, true
+	}
+
+	return -1, false
+}
+
+// RemoveServer removes the server with the given URL from the pool.
+func (b *Balancer) RemoveServer(u *url.URL) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	i := b.indexOf(u)
+	if i < 0 {
+		return fmt.Errorf("server not found")
+	}
+
+	b.servers = append(b.servers[:i], b.servers[i+1:]...)
+
+	return nil
+}
+
+// UpsertServer adds a server to the pool, or updates its weight if it already exists.
+func (b *Balancer) UpsertServer(u *url.URL, options ...roundrobin.ServerOption) error {
+	if u == nil {
+		return fmt.Errorf("server URL can't be nil")
+	}
+
+	weight, err := loadbalancer.ServerWeight(u, options...)
+	if err != nil {
+		return err
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if i := b.indexOf(u); i >= 0 {
+		b.servers[i].weight = weight
+	} else {
+		srvURL := *u
+		// A new server starts with the average latency of the pool,
+		// so that it neither gets flooded nor starved before its first response.
+		b.servers = append(b.servers, &server{url: &srvURL, weight: weight, latency: b.averageLatency()})
+	}
+
+	return nil
+}
+
+func (b *Balancer) averageLatency() float64 {
+	var sum float64
+	var count int
+	for _, srv := range b.servers {
+		if !srv.lastSample.IsZero() {
+			sum += srv.latency
+			count++
+		}
+	}
+
+	if count == 0 {
+		return 0
+	}
+
+	return sum / float64(count)
+}
+
+func (b *Balancer) indexOf(u *url.URL) int {
+	for i, srv := range b.servers {
+		if srv.url.String() == u.String() {
+			return i
+		}
+	}
+
+	return -1
+}
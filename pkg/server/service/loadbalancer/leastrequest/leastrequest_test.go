@@ -0,0 +1,165 @@
+package leastrequest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+func TestNew(t *testing.T) {
+	_, err := New(http.NotFoundHandler(), false, nil)
+	require.NoError(t, err)
+
+	_, err = New(http.NotFoundHandler(), true, &dynamic.LeastRequestStrategy{DecayTime: ptypes.Duration(-time.Second)})
+	assert.EqualError(t, err, "decay time must be positive: -1s")
+}
+
+func TestBalancer_LeastRequest(t *testing.T) {
+	balancer, err := New(serverNameHandler(), false, nil)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, balancer.UpsertServer(serverURL(t, i), roundrobin.Weight(1)))
+	}
+
+	// Each acquired request stays in flight, so the requests are spread evenly.
+	counts := make(map[string]int)
+	for i := 0; i < 30; i++ {
+		srv, err := balancer.acquire()
+		require.NoError(t, err)
+		counts[srv.url.Host]++
+	}
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, 10, counts[serverURL(t, i).Host])
+	}
+}
+
+func TestBalancer_Weights(t *testing.T) {
+	balancer, err := New(serverNameHandler(), false, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, balancer.UpsertServer(serverURL(t, 0), roundrobin.Weight(1)))
+	require.NoError(t, balancer.UpsertServer(serverURL(t, 1), roundrobin.Weight(3)))
+
+	weight, ok := balancer.ServerWeight(serverURL(t, 1))
+	assert.True(t, ok)
+	assert.Equal(t, 3, weight)
+
+	counts := make(map[string]int)
+	for i := 0; i < 40; i++ {
+		srv, err := balancer.acquire()
+		require.NoError(t, err)
+		counts[srv.url.Host]++
+	}
+
+	assert.Equal(t, 10, counts[serverURL(t, 0).Host])
+	assert.Equal(t, 30, counts[serverURL(t, 1).Host])
+}
+
+func TestBalancer_PowerOfTwoChoices(t *testing.T) {
+	balancer, err := New(serverNameHandler(), true, nil)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, balancer.UpsertServer(serverURL(t, i), roundrobin.Weight(1)))
+	}
+
+	busy := balancer.servers[0]
+	busy.inFlight = 100
+
+	for i := 0; i < 100; i++ {
+		srv, err := balancer.acquire()
+		require.NoError(t, err)
+		assert.NotEqual(t, busy, srv)
+		balancer.release(srv, 0)
+	}
+}
+
+func TestBalancer_EWMA(t *testing.T) {
+	latencies := map[string]time.Duration{
+		serverURL(t, 0).Host: 100 * time.Millisecond,
+		serverURL(t, 1).Host: 10 * time.Millisecond,
+	}
+
+	balancer, err := New(nil, false, &dynamic.LeastRequestStrategy{EWMA: true, DecayTime: ptypes.Duration(10 * time.Second)})
+	require.NoError(t, err)
+
+	now := time.Now()
+	balancer.now = func() time.Time { return now }
+	balancer.next = http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		now = now.Add(latencies[req.URL.Host])
+		rw.Header().Set("server", req.URL.Host)
+	})
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, balancer.UpsertServer(serverURL(t, i), roundrobin.Weight(1)))
+	}
+
+	counts := make(map[string]int)
+	for i := 0; i < 100; i++ {
+		recorder := httptest.NewRecorder()
+		balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		counts[recorder.Header().Get("server")]++
+	}
+
+	// Once both servers have been sampled, the requests only go to the fastest one.
+	assert.LessOrEqual(t, counts[serverURL(t, 0).Host], 1)
+	assert.GreaterOrEqual(t, counts[serverURL(t, 1).Host], 99)
+
+	for _, srv := range balancer.servers {
+		assert.Equal(t, int64(0), srv.inFlight)
+	}
+}
+
+func TestBalancer_RemoveServer(t *testing.T) {
+	balancer, err := New(serverNameHandler(), false, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, balancer.UpsertServer(serverURL(t, 0), roundrobin.Weight(1)))
+	require.NoError(t, balancer.UpsertServer(serverURL(t, 1), roundrobin.Weight(1)))
+
+	require.NoError(t, balancer.RemoveServer(serverURL(t, 0)))
+	assert.Error(t, balancer.RemoveServer(serverURL(t, 0)))
+	assert.Equal(t, []*url.URL{serverURL(t, 1)}, balancer.Servers())
+
+	for i := 0; i < 10; i++ {
+		recorder := httptest.NewRecorder()
+		balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, serverURL(t, 1).Host, recorder.Header().Get("server"))
+	}
+}
+
+func TestBalancer_NoServers(t *testing.T) {
+	balancer, err := New(http.NotFoundHandler(), true, nil)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+}
+
+func serverNameHandler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", req.URL.Host)
+	})
+}
+
+func serverURL(t *testing.T, i int) *url.URL {
+	t.Helper()
+
+	u, err := url.Parse(fmt.Sprintf("http://10.10.10.%d:80", i))
+	require.NoError(t, err)
+
+	return u
+}
@@ -0,0 +1,296 @@
+package mirror
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"hash"
+	"mime"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+const (
+	reasonStatus = "status"
+	reasonHeader = "header"
+	reasonBody   = "body"
+)
+
+// The headers which always differ between two responses, and whose relevant differences are covered by the body.
+var alwaysIgnoredHeaders = []string{"Date", "Content-Length"}
+
+// Comparator compares the responses of the mirrors with the response of the mirrored service,
+// and reports the mismatches to the metrics and to the ServiceInfo.
+type Comparator struct {
+	serviceName       string
+	ignoredHeaders    map[string]struct{}
+	ignoredJSONFields [][]string
+	maxBodySize       int64
+	sampleSize        int
+	serviceInfo       *runtime.ServiceInfo // can be nil
+	mismatches        gokitmetrics.Counter // can be nil
+}
+
+// NewComparator creates a new Comparator.
+func NewComparator(serviceName string, config *dynamic.MirroringCompare, serviceInfo *runtime.ServiceInfo, mismatches gokitmetrics.Counter) (*Comparator, error) {
+	if config.MaxBodySize != nil && *config.MaxBodySize < 0 {
+		return nil, errors.New("the max body size of the comparison must not be negative")
+	}
+	if config.SampleSize != nil && *config.SampleSize < 0 {
+		return nil, errors.New("the sample size of the comparison must not be negative")
+	}
+
+	c := &Comparator{
+		serviceName:    serviceName,
+		ignoredHeaders: make(map[string]struct{}),
+		maxBodySize:    1024 * 1024,
+		sampleSize:     10,
+		serviceInfo:    serviceInfo,
+		mismatches:     mismatches,
+	}
+
+	for _, name := range alwaysIgnoredHeaders {
+		c.ignoredHeaders[name] = struct{}{}
+	}
+	for _, name := range config.IgnoredHeaders {
+		c.ignoredHeaders[http.CanonicalHeaderKey(name)] = struct{}{}
+	}
+
+	for _, field := range config.IgnoredJSONFields {
+		c.ignoredJSONFields = append(c.ignoredJSONFields, strings.Split(field, "."))
+	}
+
+	if config.MaxBodySize != nil {
+		c.maxBodySize = *config.MaxBodySize
+	}
+
+	if config.SampleSize != nil {
+		c.sampleSize = *config.SampleSize
+	}
+
+	return c, nil
+}
+
+// compare compares the response of a mirror with the response of the service, for the given request.
+func (c *Comparator) compare(req *http.Request, mirrorName string, resp, mirrorResp *responseCapture) {
+	// The upgraded connections cannot be compared.
+	if resp.hijacked || mirrorResp.hijacked {
+		return
+	}
+
+	mismatch := runtime.MirrorMismatch{
+		Time:         time.Now(),
+		Mirror:       mirrorName,
+		Method:       req.Method,
+		Path:         req.URL.Path,
+		Status:       resp.code,
+		MirrorStatus: mirrorResp.code,
+	}
+
+	if resp.code != mirrorResp.code {
+		mismatch.Reasons = append(mismatch.Reasons, reasonStatus)
+	}
+
+	mismatch.Headers = c.diffHeaders(resp.sentHeader, mirrorResp.sentHeader)
+	if len(mismatch.Headers) > 0 {
+		mismatch.Reasons = append(mismatch.Reasons, reasonHeader)
+	}
+
+	bodyHash, mirrorBodyHash := c.bodyHash(resp), c.bodyHash(mirrorResp)
+	if bodyHash != mirrorBodyHash {
+		mismatch.Reasons = append(mismatch.Reasons, reasonBody)
+		mismatch.BodyHash = bodyHash
+		mismatch.MirrorBodyHash = mirrorBodyHash
+	}
+
+	if len(mismatch.Reasons) == 0 {
+		return
+	}
+
+	log.FromContext(req.Context()).Debugf("Response of mirror %s differs from the response of the service: %s", mirrorName, strings.Join(mismatch.Reasons, ", "))
+
+	if c.mismatches != nil {
+		for _, reason := range mismatch.Reasons {
+			c.mismatches.With("service", c.serviceName, "mirror", mirrorName, "reason", reason).Add(1)
+		}
+	}
+
+	if c.serviceInfo != nil {
+		c.serviceInfo.AddMirrorMismatch(mismatch, c.sampleSize)
+	}
+}
+
+// diffHeaders returns the sorted names of the headers which differ, except the ignored ones.
+func (c *Comparator) diffHeaders(header, mirrorHeader http.Header) []string {
+	names := make(map[string]struct{})
+	for name := range header {
+		names[name] = struct{}{}
+	}
+	for name := range mirrorHeader {
+		names[name] = struct{}{}
+	}
+
+	var diff []string
+	for name := range names {
+		if _, ok := c.ignoredHeaders[http.CanonicalHeaderKey(name)]; ok {
+			continue
+		}
+
+		if strings.Join(header.Values(name), ",") != strings.Join(mirrorHeader.Values(name), ",") {
+			diff = append(diff, name)
+		}
+	}
+
+	sort.Strings(diff)
+	return diff
+}
+
+// bodyHash returns the hash of the body of the response.
+// The ignored fields are removed from the JSON bodies, which are hashed in a canonical form.
+func (c *Comparator) bodyHash(resp *responseCapture) string {
+	if resp.body == nil || resp.truncated {
+		return hex.EncodeToString(resp.hash.Sum(nil))
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(resp.body.Bytes()))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return hex.EncodeToString(resp.hash.Sum(nil))
+	}
+
+	for _, field := range c.ignoredJSONFields {
+		removeJSONField(value, field)
+	}
+
+	// The keys of the objects are sorted by the encoding.
+	canonical, err := json.Marshal(value)
+	if err != nil {
+		return hex.EncodeToString(resp.hash.Sum(nil))
+	}
+
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:])
+}
+
+// removeJSONField removes the field at the given path from the value,
+// applying the rest of the path to each element of the arrays along the way.
+func removeJSONField(value interface{}, path []string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(path) == 1 {
+			delete(v, path[0])
+			return
+		}
+
+		removeJSONField(v[path[0]], path[1:])
+	case []interface{}:
+		for _, elem := range v {
+			removeJSONField(elem, path)
+		}
+	}
+}
+
+// responseCapture records the status code, the headers, and the hash of the body of a response,
+// while forwarding it to the underlying ResponseWriter.
+// The JSON bodies are also kept in memory, up to maxBodySize bytes, to ignore some of their fields.
+type responseCapture struct {
+	rw          http.ResponseWriter
+	maxBodySize int64
+
+	code       int
+	sentHeader http.Header
+	hash       hash.Hash
+	body       *bytes.Buffer // nil if the body is not JSON
+	truncated  bool
+	hijacked   bool
+}
+
+func newResponseCapture(rw http.ResponseWriter, maxBodySize int64) *responseCapture {
+	return &responseCapture{
+		rw:          rw,
+		maxBodySize: maxBodySize,
+		hash:        sha256.New(),
+	}
+}
+
+func (r *responseCapture) Header() http.Header {
+	return r.rw.Header()
+}
+
+func (r *responseCapture) WriteHeader(code int) {
+	// Informational responses are forwarded as is.
+	if r.code != 0 || (code >= 100 && code < 200 && code != http.StatusSwitchingProtocols) {
+		r.rw.WriteHeader(code)
+		return
+	}
+
+	r.code = code
+	r.sentHeader = r.rw.Header().Clone()
+
+	if mediaType, _, err := mime.ParseMediaType(r.sentHeader.Get("Content-Type")); err == nil && strings.Contains(mediaType, "json") {
+		r.body = &bytes.Buffer{}
+	}
+
+	r.rw.WriteHeader(code)
+}
+
+func (r *responseCapture) Write(b []byte) (int, error) {
+	if r.code == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	_, _ = r.hash.Write(b)
+
+	if r.body != nil && !r.truncated {
+		if r.maxBodySize >= 0 && int64(r.body.Len()+len(b)) > r.maxBodySize {
+			r.truncated = true
+			r.body.Reset()
+		} else {
+			r.body.Write(b)
+		}
+	}
+
+	return r.rw.Write(b)
+}
+
+// Hijack hijacks the connection.
+func (r *responseCapture) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.rw.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not implement http.Hijacker")
+	}
+
+	r.hijacked = true
+	return hijacker.Hijack()
+}
+
+// Flush sends any buffered data to the client.
+func (r *responseCapture) Flush() {
+	if f, ok := r.rw.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// discardResponseWriter is a blackHoleResponseWriter which keeps the headers of the response,
+// so that they can be compared.
+type discardResponseWriter struct {
+	blackHoleResponseWriter
+	header http.Header
+}
+
+func (d *discardResponseWriter) Header() http.Header {
+	return d.header
+}
@@ -0,0 +1,235 @@
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/traefik/traefik/v2/pkg/testhelpers"
+)
+
+type response struct {
+	code   int
+	header map[string]string
+	body   string
+}
+
+func (r response) handler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		for name, value := range r.header {
+			rw.Header().Set(name, value)
+		}
+		rw.WriteHeader(r.code)
+		_, _ = rw.Write([]byte(r.body))
+	})
+}
+
+func TestComparator(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		config          dynamic.MirroringCompare
+		resp            response
+		mirrorResp      response
+		expectedReasons []string
+		expectedHeaders []string
+	}{
+		{
+			desc:       "identical responses",
+			resp:       response{code: http.StatusOK, body: "foo"},
+			mirrorResp: response{code: http.StatusOK, body: "foo"},
+		},
+		{
+			desc:            "different status codes",
+			resp:            response{code: http.StatusOK},
+			mirrorResp:      response{code: http.StatusInternalServerError},
+			expectedReasons: []string{reasonStatus},
+		},
+		{
+			desc:            "different headers",
+			resp:            response{code: http.StatusOK, header: map[string]string{"X-Foo": "foo", "X-Bar": "bar"}},
+			mirrorResp:      response{code: http.StatusOK, header: map[string]string{"X-Foo": "bar"}},
+			expectedReasons: []string{reasonHeader},
+			expectedHeaders: []string{"X-Bar", "X-Foo"},
+		},
+		{
+			desc:       "ignored headers",
+			config:     dynamic.MirroringCompare{IgnoredHeaders: []string{"x-request-id"}},
+			resp:       response{code: http.StatusOK, header: map[string]string{"X-Request-Id": "1", "Date": "Mon, 01 Jan 2024 00:00:00 GMT"}},
+			mirrorResp: response{code: http.StatusOK, header: map[string]string{"X-Request-Id": "2", "Date": "Mon, 01 Jan 2024 00:00:01 GMT"}},
+		},
+		{
+			desc:            "different bodies",
+			resp:            response{code: http.StatusOK, body: "foo"},
+			mirrorResp:      response{code: http.StatusOK, body: "bar"},
+			expectedReasons: []string{reasonBody},
+		},
+		{
+			desc:       "equivalent JSON bodies",
+			resp:       response{code: http.StatusOK, header: map[string]string{"Content-Type": "application/json"}, body: `{"a":1,"b":[1,2]}`},
+			mirrorResp: response{code: http.StatusOK, header: map[string]string{"Content-Type": "application/json"}, body: `{ "b": [1, 2], "a": 1 }`},
+		},
+		{
+			desc:   "ignored JSON fields",
+			config: dynamic.MirroringCompare{IgnoredJSONFields: []string{"id", "items.updatedAt"}},
+			resp: response{
+				code:   http.StatusOK,
+				header: map[string]string{"Content-Type": "application/json; charset=utf-8"},
+				body:   `{"id":"1","items":[{"name":"foo","updatedAt":1},{"name":"bar","updatedAt":2}]}`,
+			},
+			mirrorResp: response{
+				code:   http.StatusOK,
+				header: map[string]string{"Content-Type": "application/json; charset=utf-8"},
+				body:   `{"id":"2","items":[{"name":"foo","updatedAt":3},{"name":"bar","updatedAt":4}]}`,
+			},
+		},
+		{
+			desc:   "JSON bodies differing outside of the ignored fields",
+			config: dynamic.MirroringCompare{IgnoredJSONFields: []string{"id"}},
+			resp: response{
+				code:   http.StatusOK,
+				header: map[string]string{"Content-Type": "application/json"},
+				body:   `{"id":"1","name":"foo"}`,
+			},
+			mirrorResp: response{
+				code:   http.StatusOK,
+				header: map[string]string{"Content-Type": "application/json"},
+				body:   `{"id":"2","name":"bar"}`,
+			},
+			expectedReasons: []string{reasonBody},
+		},
+		{
+			desc:   "JSON bodies larger than the max body size",
+			config: dynamic.MirroringCompare{IgnoredJSONFields: []string{"id"}, MaxBodySize: int64Ptr(10)},
+			resp: response{
+				code:   http.StatusOK,
+				header: map[string]string{"Content-Type": "application/json"},
+				body:   `{"id":"1","name":"foo"}`,
+			},
+			mirrorResp: response{
+				code:   http.StatusOK,
+				header: map[string]string{"Content-Type": "application/json"},
+				body:   `{"id":"2","name":"foo"}`,
+			},
+			expectedReasons: []string{reasonBody},
+		},
+		{
+			desc:            "all the reasons",
+			resp:            response{code: http.StatusOK, header: map[string]string{"X-Foo": "foo"}, body: "foo"},
+			mirrorResp:      response{code: http.StatusNotFound, body: "bar"},
+			expectedReasons: []string{reasonStatus, reasonHeader, reasonBody},
+			expectedHeaders: []string{"X-Foo"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			counter := &testhelpers.CollectingCounter{}
+			serviceInfo := &runtime.ServiceInfo{}
+			comparator, err := NewComparator("foo", &test.config, serviceInfo, counter)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "/bar", nil)
+
+			resp := newResponseCapture(httptest.NewRecorder(), comparator.maxBodySize)
+			test.resp.handler().ServeHTTP(resp, req)
+
+			mirrorResp := newResponseCapture(&discardResponseWriter{header: make(http.Header)}, comparator.maxBodySize)
+			test.mirrorResp.handler().ServeHTTP(mirrorResp, req)
+
+			comparator.compare(req, "mirror", resp, mirrorResp)
+
+			mismatches := serviceInfo.GetMirrorMismatches()
+			if len(test.expectedReasons) == 0 {
+				assert.Empty(t, mismatches)
+				assert.Equal(t, float64(0), counter.CounterValue)
+				return
+			}
+
+			require.Len(t, mismatches, 1)
+			assert.Equal(t, "mirror", mismatches[0].Mirror)
+			assert.Equal(t, http.MethodGet, mismatches[0].Method)
+			assert.Equal(t, "/bar", mismatches[0].Path)
+			assert.Equal(t, test.resp.code, mismatches[0].Status)
+			assert.Equal(t, test.mirrorResp.code, mismatches[0].MirrorStatus)
+			assert.Equal(t, test.expectedReasons, mismatches[0].Reasons)
+			assert.Equal(t, test.expectedHeaders, mismatches[0].Headers)
+
+			assert.Equal(t, float64(len(test.expectedReasons)), counter.CounterValue)
+			assert.Equal(t, []string{"service", "foo", "mirror", "mirror", "reason", test.expectedReasons[len(test.expectedReasons)-1]}, counter.LastLabelValues)
+		})
+	}
+}
+
+func TestComparator_sampleSize(t *testing.T) {
+	serviceInfo := &runtime.ServiceInfo{}
+	comparator, err := NewComparator("foo", &dynamic.MirroringCompare{SampleSize: intPtr(3)}, serviceInfo, nil)
+	require.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/%d", i), nil)
+
+		resp := newResponseCapture(httptest.NewRecorder(), comparator.maxBodySize)
+		response{code: http.StatusOK}.handler().ServeHTTP(resp, req)
+
+		mirrorResp := newResponseCapture(&discardResponseWriter{header: make(http.Header)}, comparator.maxBodySize)
+		response{code: http.StatusBadGateway}.handler().ServeHTTP(mirrorResp, req)
+
+		comparator.compare(req, "mirror", resp, mirrorResp)
+	}
+
+	mismatches := serviceInfo.GetMirrorMismatches()
+	require.Len(t, mismatches, 3)
+	assert.Equal(t, "/2", mismatches[0].Path)
+	assert.Equal(t, "/3", mismatches[1].Path)
+	assert.Equal(t, "/4", mismatches[2].Path)
+}
+
+func TestMirroringWithComparator(t *testing.T) {
+	handler := response{code: http.StatusOK, header: map[string]string{"X-Version": "1"}, body: "foo"}.handler()
+
+	pool := safe.NewPool(context.Background())
+	mirror := New(handler, pool, defaultMaxBodySize)
+
+	serviceInfo := &runtime.ServiceInfo{}
+	counter := &testhelpers.CollectingCounter{}
+	comparator, err := NewComparator("foo", &dynamic.MirroringCompare{}, serviceInfo, counter)
+	require.NoError(t, err)
+	mirror.SetComparator(comparator)
+
+	err = mirror.AddMirror("same", response{code: http.StatusOK, header: map[string]string{"X-Version": "1"}, body: "foo"}.handler(), 100)
+	require.NoError(t, err)
+
+	err = mirror.AddMirror("other", response{code: http.StatusOK, header: map[string]string{"X-Version": "2"}, body: "foo"}.handler(), 100)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	mirror.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	pool.Stop()
+
+	// The response of the service is forwarded untouched.
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "1", recorder.Header().Get("X-Version"))
+	assert.Equal(t, "foo", recorder.Body.String())
+
+	mismatches := serviceInfo.GetMirrorMismatches()
+	require.Len(t, mismatches, 1)
+	assert.Equal(t, "other", mismatches[0].Mirror)
+	assert.Equal(t, []string{reasonHeader}, mismatches[0].Reasons)
+	assert.Equal(t, []string{"X-Version"}, mismatches[0].Headers)
+	assert.Equal(t, float64(1), counter.CounterValue)
+}
+
+func intPtr(v int) *int { return &v }
+
+func int64Ptr(v int64) *int64 { return &v }
@@ -13,7 +13,9 @@ import (
 
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/traefik/traefik/v2/pkg/rules"
 	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer"
 )
 
 // Mirroring is an http.Handler that can mirror requests.
@@ -24,9 +26,7 @@ type Mirroring struct {
 	routinePool    *safe.Pool
 
 	maxBodySize int64
-
-	lock  sync.RWMutex
-	total uint64
+	comparator  *Comparator // can be nil
 }
 
 // New returns a new instance of *Mirroring.
@@ -39,40 +39,58 @@ func New(handler http.Handler, pool *safe.Pool, maxBodySize int64) *Mirroring {
 	}
 }
 
-func (m *Mirroring) inc() uint64 {
-	m.lock.Lock()
-	defer m.lock.Unlock()
-	m.total++
-	return m.total
+// SetComparator enables the comparison of the responses of the mirrors with the response of the handler.
+func (m *Mirroring) SetComparator(comparator *Comparator) {
+	m.comparator = comparator
 }
 
 type mirrorHandler struct {
 	http.Handler
-	percent int
+	name       string
+	percent    int
+	matcher    *rules.Matcher // can be nil
+	hashHeader string
 
 	lock  sync.RWMutex
+	total uint64
 	count uint64
 }
 
-func (m *Mirroring) getActiveMirrors() []http.Handler {
-	total := m.inc()
+// active reports whether the request is mirrored to the handler.
+// The percent applies to the requests matching the rule.
+func (h *mirrorHandler) active(req *http.Request) bool {
+	if h.matcher != nil && !h.matcher.Match(req) {
+		return false
+	}
+
+	if h.hashHeader != "" {
+		value := req.Header.Get(h.hashHeader)
+		return value != "" && loadbalancer.Hash(value)%100 < uint64(h.percent)
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.total++
+	if h.count*100 < h.total*uint64(h.percent) {
+		h.count++
+		return true
+	}
+	return false
+}
 
-	var mirrors []http.Handler
+func (m *Mirroring) getActiveMirrors(req *http.Request) []*mirrorHandler {
+	var mirrors []*mirrorHandler
 	for _, handler := range m.mirrorHandlers {
-		handler.lock.Lock()
-		if handler.count*100 < total*uint64(handler.percent) {
-			handler.count++
-			handler.lock.Unlock()
+		if handler.active(req) {
 			mirrors = append(mirrors, handler)
-		} else {
-			handler.lock.Unlock()
 		}
 	}
 	return mirrors
 }
 
 func (m *Mirroring) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
-	mirrors := m.getActiveMirrors()
+	mirrors := m.getActiveMirrors(req)
 	if len(mirrors) == 0 {
 		m.handler.ServeHTTP(rw, req)
 		return
@@ -93,6 +111,12 @@ func (m *Mirroring) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	var resp *responseCapture
+	if m.comparator != nil {
+		resp = newResponseCapture(rw, m.comparator.maxBodySize)
+		rw = resp
+	}
+
 	m.handler.ServeHTTP(rw, rr.clone(req.Context()))
 
 	select {
@@ -120,17 +144,42 @@ func (m *Mirroring) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 			// which would trigger a cancellation of the ongoing mirrored requests.
 			// Therefore, we give a new, non-cancellable context  to each of the mirrored calls,
 			// so they can terminate by themselves.
-			handler.ServeHTTP(m.rw, r.WithContext(contextStopPropagation{ctx}))
+			if m.comparator == nil {
+				handler.ServeHTTP(m.rw, r.WithContext(contextStopPropagation{ctx}))
+				continue
+			}
+
+			mirrorResp := newResponseCapture(&discardResponseWriter{header: make(http.Header)}, m.comparator.maxBodySize)
+			handler.ServeHTTP(mirrorResp, r.WithContext(contextStopPropagation{ctx}))
+			m.comparator.compare(req, handler.name, resp, mirrorResp)
 		}
 	})
 }
 
 // AddMirror adds an httpHandler to mirror to.
-func (m *Mirroring) AddMirror(handler http.Handler, percent int) error {
+func (m *Mirroring) AddMirror(name string, handler http.Handler, percent int) error {
+	return m.AddMatchingMirror(name, handler, percent, "", "")
+}
+
+// AddMatchingMirror adds an httpHandler to mirror the requests matching the rule to.
+// When hashHeader is set, the mirrored requests are selected by the hash of the value of this header,
+// so that the requests with the same value are either all mirrored or none of them.
+func (m *Mirroring) AddMatchingMirror(name string, handler http.Handler, percent int, rule, hashHeader string) error {
 	if percent < 0 || percent > 100 {
 		return errors.New("percent must be between 0 and 100")
 	}
-	m.mirrorHandlers = append(m.mirrorHandlers, &mirrorHandler{Handler: handler, percent: percent})
+
+	mirror := &mirrorHandler{Handler: handler, name: name, percent: percent, hashHeader: hashHeader}
+
+	if rule != "" {
+		matcher, err := rules.NewMatcher(rule)
+		if err != nil {
+			return fmt.Errorf("invalid rule for mirror %s: %w", name, err)
+		}
+		mirror.matcher = matcher
+	}
+
+	m.mirrorHandlers = append(m.mirrorHandlers, mirror)
 	return nil
 }
 
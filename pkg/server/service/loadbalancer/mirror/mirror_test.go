@@ -3,9 +3,11 @@ package mirror
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"sync/atomic"
 	"testing"
 
@@ -22,12 +24,12 @@ func TestMirroringOn100(t *testing.T) {
 	})
 	pool := safe.NewPool(context.Background())
 	mirror := New(handler, pool, defaultMaxBodySize)
-	err := mirror.AddMirror(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+	err := mirror.AddMirror("mirror", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		atomic.AddInt32(&countMirror1, 1)
 	}), 10)
 	assert.NoError(t, err)
 
-	err = mirror.AddMirror(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+	err = mirror.AddMirror("mirror", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		atomic.AddInt32(&countMirror2, 1)
 	}), 50)
 	assert.NoError(t, err)
@@ -51,12 +53,12 @@ func TestMirroringOn10(t *testing.T) {
 	})
 	pool := safe.NewPool(context.Background())
 	mirror := New(handler, pool, defaultMaxBodySize)
-	err := mirror.AddMirror(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+	err := mirror.AddMirror("mirror", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		atomic.AddInt32(&countMirror1, 1)
 	}), 10)
 	assert.NoError(t, err)
 
-	err = mirror.AddMirror(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+	err = mirror.AddMirror("mirror", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		atomic.AddInt32(&countMirror2, 1)
 	}), 50)
 	assert.NoError(t, err)
@@ -73,18 +75,87 @@ func TestMirroringOn10(t *testing.T) {
 	assert.Equal(t, 5, int(val2))
 }
 
+func TestMirroringWithRule(t *testing.T) {
+	var countMirror int32
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+	pool := safe.NewPool(context.Background())
+	mirror := New(handler, pool, defaultMaxBodySize)
+	err := mirror.AddMatchingMirror("mirror", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&countMirror, 1)
+	}), 50, "Method(`POST`) && Path(`/orders`)", "")
+	assert.NoError(t, err)
+
+	for i := 0; i < 100; i++ {
+		mirror.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders", nil))
+		mirror.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/orders", nil))
+		mirror.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/users", nil))
+	}
+
+	pool.Stop()
+
+	// The percent applies to the matching requests only.
+	val := atomic.LoadInt32(&countMirror)
+	assert.Equal(t, 50, int(val))
+}
+
+func TestMirroringWithInvalidRule(t *testing.T) {
+	mirror := New(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}), safe.NewPool(context.Background()), defaultMaxBodySize)
+	err := mirror.AddMatchingMirror("mirror", nil, 100, "Foo(`bar`)", "")
+	assert.Error(t, err)
+}
+
+func TestMirroringWithHashHeader(t *testing.T) {
+	var lock sync.Mutex
+	mirroredUsers := make(map[string]int)
+
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+	pool := safe.NewPool(context.Background())
+	mirror := New(handler, pool, defaultMaxBodySize)
+	err := mirror.AddMatchingMirror("mirror", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		lock.Lock()
+		mirroredUsers[req.Header.Get("X-User")]++
+		lock.Unlock()
+	}), 20, "", "X-User")
+	assert.NoError(t, err)
+
+	for i := 0; i < 1000; i++ {
+		for j := 0; j < 3; j++ {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("X-User", fmt.Sprintf("user-%d", i))
+			mirror.ServeHTTP(httptest.NewRecorder(), req)
+		}
+	}
+
+	// The requests without the header are not mirrored.
+	mirror.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	pool.Stop()
+
+	// The users are sampled according to the percent.
+	assert.InDelta(t, 200, len(mirroredUsers), 50)
+
+	// All the requests of a sampled user are mirrored.
+	for user, count := range mirroredUsers {
+		assert.Equal(t, 3, count, user)
+	}
+}
+
 func TestInvalidPercent(t *testing.T) {
 	mirror := New(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}), safe.NewPool(context.Background()), defaultMaxBodySize)
-	err := mirror.AddMirror(nil, -1)
+	err := mirror.AddMirror("mirror", nil, -1)
 	assert.Error(t, err)
 
-	err = mirror.AddMirror(nil, 101)
+	err = mirror.AddMirror("mirror", nil, 101)
 	assert.Error(t, err)
 
-	err = mirror.AddMirror(nil, 100)
+	err = mirror.AddMirror("mirror", nil, 100)
 	assert.NoError(t, err)
 
-	err = mirror.AddMirror(nil, 0)
+	err = mirror.AddMirror("mirror", nil, 0)
 	assert.NoError(t, err)
 }
 
@@ -96,7 +167,7 @@ func TestHijack(t *testing.T) {
 	mirror := New(handler, pool, defaultMaxBodySize)
 
 	var mirrorRequest bool
-	err := mirror.AddMirror(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+	err := mirror.AddMirror("mirror", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		hijacker, ok := rw.(http.Hijacker)
 		assert.Equal(t, true, ok)
 
@@ -120,7 +191,7 @@ func TestFlush(t *testing.T) {
 	mirror := New(handler, pool, defaultMaxBodySize)
 
 	var mirrorRequest bool
-	err := mirror.AddMirror(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+	err := mirror.AddMirror("mirror", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		hijacker, ok := rw.(http.Flusher)
 		assert.Equal(t, true, ok)
 
@@ -157,7 +228,7 @@ func TestMirroringWithBody(t *testing.T) {
 	mirror := New(handler, pool, defaultMaxBodySize)
 
 	for i := 0; i < numMirrors; i++ {
-		err := mirror.AddMirror(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		err := mirror.AddMirror("mirror", http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
 			assert.NotNil(t, r.Body)
 			bb, err := io.ReadAll(r.Body)
 			assert.NoError(t, err)
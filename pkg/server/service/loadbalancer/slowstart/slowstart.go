@@ -0,0 +1,233 @@
+package slowstart
+
+import (
+	"math"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/healthcheck"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+// weightScale is the factor applied to the weights of the servers,
+// so that the weights can be ramped up with an integer granularity.
+// A server starts with a tenth of its weight.
+const weightScale = 10
+
+// Tracker keeps track of the time the servers of the services started,
+// so that the ramp up of a server goes on when the load balancers are rebuilt on a configuration change.
+type Tracker struct {
+	mutex  sync.Mutex
+	starts map[string]map[string]time.Time // keyed by service name, and then by server URL.
+}
+
+// NewTracker creates a new Tracker.
+func NewTracker() *Tracker {
+	return &Tracker{starts: make(map[string]map[string]time.Time)}
+}
+
+// start returns the time the given server started, which is now if it was not tracked yet.
+func (t *Tracker) start(serviceName, serverURL string, now time.Time) time.Time {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.starts[serviceName] == nil {
+		t.starts[serviceName] = make(map[string]time.Time)
+	}
+
+	start, ok := t.starts[serviceName][serverURL]
+	if !ok {
+		start = now
+		t.starts[serviceName][serverURL] = start
+	}
+
+	return start
+}
+
+func (t *Tracker) remove(serviceName, serverURL string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.starts[serviceName], serverURL)
+}
+
+// Retain stops tracking the servers which are not part of the given services anymore,
+// so that they ramp up again if they are added back later.
+// The servers discovered with DNS are not part of the configuration,
+// and stop being tracked when the discovery removes them from the load balancers instead.
+func (t *Tracker) Retain(services map[string]*runtime.ServiceInfo) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for serviceName, starts := range t.starts {
+		info, ok := services[serviceName]
+		if !ok || info.LoadBalancer == nil {
+			delete(t.starts, serviceName)
+			continue
+		}
+
+		if info.LoadBalancer.DNSDiscovery != nil {
+			continue
+		}
+
+		servers := make(map[string]struct{})
+		for _, server := range info.LoadBalancer.Servers {
+			servers[server.URL] = struct{}{}
+		}
+
+		for serverURL := range starts {
+			if _, ok := servers[serverURL]; !ok {
+				delete(starts, serverURL)
+			}
+		}
+	}
+}
+
+type server struct {
+	url    *url.URL
+	weight int
+	start  time.Time
+	timer  *time.Timer
+}
+
+// Balancer wraps a BalancerHandler to ramp up linearly the weight of the servers added to it,
+// from a tenth of their weight to their weight, over a duration.
+// As the weights are scaled, the BalancerHandler must only be used through the Balancer.
+type Balancer struct {
+	healthcheck.BalancerHandler
+
+	serviceName string
+	duration    time.Duration
+	tracker     *Tracker
+	now         func() time.Time
+
+	mutex   sync.Mutex
+	servers map[string]*server
+	stopped bool
+}
+
+// New creates a new Balancer wrapping the given BalancerHandler.
+func New(serviceName string, duration time.Duration, tracker *Tracker, handler healthcheck.BalancerHandler) *Balancer {
+	return &Balancer{
+		BalancerHandler: handler,
+		serviceName:     serviceName,
+		duration:        duration,
+		tracker:         tracker,
+		now:             time.Now,
+		servers:         make(map[string]*server),
+	}
+}
+
+// ServerWeight returns the weight of the server with the given URL, once ramped up.
+func (b *Balancer) ServerWeight(u *url.URL) (int, bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	srv, ok := b.servers[u.String()]
+	if !ok {
+		return -1, false
+	}
+
+	return srv.weight, true
+}
+
+// RemoveServer removes the server with the given URL from the BalancerHandler.
+// The server ramps up again when it is added back.
+func (b *Balancer) RemoveServer(u *url.URL) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if err := b.BalancerHandler.RemoveServer(u); err != nil {
+		return err
+	}
+
+	if srv, ok := b.servers[u.String()]; ok && srv.timer != nil {
+		srv.timer.Stop()
+	}
+	delete(b.servers, u.String())
+	b.tracker.remove(b.serviceName, u.String())
+
+	return nil
+}
+
+// UpsertServer adds the server with the given URL to the BalancerHandler,
+// with a weight ramping up from a tenth of its weight, or updates its weight.
+func (b *Balancer) UpsertServer(u *url.URL, options ...roundrobin.ServerOption) error {
+	weight, err := loadbalancer.ServerWeight(u, options...)
+	if err != nil {
+		return err
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	srv, ok := b.servers[u.String()]
+	if !ok {
+		srvURL := *u
+		srv = &server{
+			url:   &srvURL,
+			start: b.tracker.start(b.serviceName, u.String(), b.now()),
+		}
+		b.servers[u.String()] = srv
+	}
+	srv.weight = weight
+
+	return b.update(srv)
+}
+
+// update sets the ramped up weight of the server, and schedules its next update until it is fully ramped up.
+// It must be called with the mutex held.
+func (b *Balancer) update(srv *server) error {
+	progress := float64(b.now().Sub(srv.start)) / float64(b.duration)
+	if progress >= 1 {
+		return b.BalancerHandler.UpsertServer(srv.url, roundrobin.Weight(srv.weight*weightScale))
+	}
+
+	weight := int(math.Ceil(float64(srv.weight*weightScale) * math.Max(progress, 1.0/weightScale)))
+	if err := b.BalancerHandler.UpsertServer(srv.url, roundrobin.Weight(weight)); err != nil {
+		return err
+	}
+
+	if srv.timer == nil && !b.stopped {
+		srv.timer = time.AfterFunc(b.duration/weightScale, func() {
+			b.step(srv)
+		})
+	}
+
+	return nil
+}
+
+// Stop stops ramping up the weight of the servers,
+// once the Balancer is replaced by the one of a new configuration which goes on with the ramp up.
+func (b *Balancer) Stop() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.stopped = true
+	for _, srv := range b.servers {
+		if srv.timer != nil {
+			srv.timer.Stop()
+			srv.timer = nil
+		}
+	}
+}
+
+func (b *Balancer) step(srv *server) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	// The server has been removed, or the Balancer stopped, in the meantime.
+	if b.stopped || b.servers[srv.url.String()] != srv {
+		return
+	}
+
+	srv.timer = nil
+	if err := b.update(srv); err != nil {
+		log.WithoutContext().WithField(log.ServiceName, b.serviceName).
+			Errorf("Error while ramping up the weight of server %s: %v", srv.url, err)
+	}
+}
@@ -0,0 +1,157 @@
+package slowstart
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/testhelpers"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+func TestBalancer_RampUp(t *testing.T) {
+	now := time.Now()
+
+	rr, err := roundrobin.New(http.NotFoundHandler())
+	require.NoError(t, err)
+
+	balancer := New("foo", time.Hour, NewTracker(), rr)
+	balancer.now = func() time.Time { return now }
+
+	u := testhelpers.MustParseURL("http://10.10.10.1")
+	require.NoError(t, balancer.UpsertServer(u, roundrobin.Weight(2)))
+
+	assertWeights(t, balancer, rr, 2, 2)
+
+	now = now.Add(30 * time.Minute)
+	balancer.step(balancer.servers[u.String()])
+	assertWeights(t, balancer, rr, 2, 10)
+
+	now = now.Add(30 * time.Minute)
+	balancer.step(balancer.servers[u.String()])
+	assertWeights(t, balancer, rr, 2, 20)
+
+	// A recovered server ramps up again.
+	require.NoError(t, balancer.RemoveServer(u))
+	require.NoError(t, balancer.UpsertServer(u, roundrobin.Weight(2)))
+	assertWeights(t, balancer, rr, 2, 2)
+}
+
+func TestBalancer_Rebuild(t *testing.T) {
+	now := time.Now()
+	tracker := NewTracker()
+
+	rr, err := roundrobin.New(http.NotFoundHandler())
+	require.NoError(t, err)
+
+	balancer := New("foo", time.Hour, tracker, rr)
+	balancer.now = func() time.Time { return now }
+
+	u := testhelpers.MustParseURL("http://10.10.10.1")
+	require.NoError(t, balancer.UpsertServer(u, roundrobin.Weight(1)))
+	assertWeights(t, balancer, rr, 1, 1)
+
+	// The load balancer is rebuilt on a configuration change.
+	now = now.Add(30 * time.Minute)
+
+	rebuiltRR, err := roundrobin.New(http.NotFoundHandler())
+	require.NoError(t, err)
+
+	rebuilt := New("foo", time.Hour, tracker, rebuiltRR)
+	rebuilt.now = func() time.Time { return now }
+
+	require.NoError(t, rebuilt.UpsertServer(u, roundrobin.Weight(1)))
+	assertWeights(t, rebuilt, rebuiltRR, 1, 5)
+
+	// The server is removed from the configuration, and added back.
+	tracker.Retain(map[string]*runtime.ServiceInfo{
+		"foo": {Service: &dynamic.Service{LoadBalancer: &dynamic.ServersLoadBalancer{}}},
+	})
+
+	rebuiltRR, err = roundrobin.New(http.NotFoundHandler())
+	require.NoError(t, err)
+
+	rebuilt = New("foo", time.Hour, tracker, rebuiltRR)
+	rebuilt.now = func() time.Time { return now }
+
+	require.NoError(t, rebuilt.UpsertServer(u, roundrobin.Weight(1)))
+	assertWeights(t, rebuilt, rebuiltRR, 1, 1)
+}
+
+func TestTracker_RetainDNSDiscovery(t *testing.T) {
+	tracker := NewTracker()
+
+	now := time.Now()
+	start := tracker.start("foo", "http://10.10.10.1:80", now)
+
+	// The discovered servers are not part of the configuration, but they are still tracked.
+	tracker.Retain(map[string]*runtime.ServiceInfo{
+		"foo": {Service: &dynamic.Service{LoadBalancer: &dynamic.ServersLoadBalancer{
+			DNSDiscovery: &dynamic.DNSDiscovery{Name: "foo.local", Port: 80},
+		}}},
+	})
+	assert.Equal(t, start, tracker.start("foo", "http://10.10.10.1:80", now.Add(time.Minute)))
+
+	// The service does not discover its servers anymore.
+	tracker.Retain(map[string]*runtime.ServiceInfo{
+		"foo": {Service: &dynamic.Service{LoadBalancer: &dynamic.ServersLoadBalancer{}}},
+	})
+	assert.Equal(t, now.Add(time.Minute), tracker.start("foo", "http://10.10.10.1:80", now.Add(time.Minute)))
+}
+
+func TestBalancer_Stop(t *testing.T) {
+	rr, err := roundrobin.New(http.NotFoundHandler())
+	require.NoError(t, err)
+
+	balancer := New("foo", time.Hour, NewTracker(), rr)
+
+	u := testhelpers.MustParseURL("http://10.10.10.1")
+	require.NoError(t, balancer.UpsertServer(u, roundrobin.Weight(1)))
+	require.NotNil(t, balancer.servers[u.String()].timer)
+
+	balancer.Stop()
+	assert.Nil(t, balancer.servers[u.String()].timer)
+
+	// The weight is not ramped up anymore once the Balancer is stopped.
+	srv := balancer.servers[u.String()]
+	srv.start = srv.start.Add(-30 * time.Minute)
+	balancer.step(srv)
+	assertWeights(t, balancer, rr, 1, 1)
+
+	require.NoError(t, balancer.UpsertServer(u, roundrobin.Weight(1)))
+	assert.Nil(t, balancer.servers[u.String()].timer)
+}
+
+func TestBalancer_FullyRampedUp(t *testing.T) {
+	rr, err := roundrobin.New(http.NotFoundHandler())
+	require.NoError(t, err)
+
+	balancer := New("foo", 50*time.Millisecond, NewTracker(), rr)
+
+	u := testhelpers.MustParseURL("http://10.10.10.1")
+	require.NoError(t, balancer.UpsertServer(u, roundrobin.Weight(3)))
+
+	assert.Eventually(t, func() bool {
+		balancer.mutex.Lock()
+		defer balancer.mutex.Unlock()
+
+		weight, _ := rr.ServerWeight(u)
+		return weight == 30
+	}, time.Second, 10*time.Millisecond)
+}
+
+func assertWeights(t *testing.T, balancer *Balancer, rr *roundrobin.RoundRobin, expectedWeight, expectedEffectiveWeight int) {
+	t.Helper()
+
+	weight, ok := balancer.ServerWeight(testhelpers.MustParseURL("http://10.10.10.1"))
+	assert.True(t, ok)
+	assert.Equal(t, expectedWeight, weight)
+
+	effectiveWeight, ok := rr.ServerWeight(testhelpers.MustParseURL("http://10.10.10.1"))
+	assert.True(t, ok)
+	assert.Equal(t, expectedEffectiveWeight, effectiveWeight)
+}
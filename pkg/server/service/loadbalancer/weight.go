@@ -0,0 +1,28 @@
+package loadbalancer
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+// ServerWeight returns the weight set by the given server options.
+// As the oxy server options can only be applied to an oxy balancer, the weight is read from a throwaway one.
+func ServerWeight(u *url.URL, options ...roundrobin.ServerOption) (int, error) {
+	rr, err := roundrobin.New(nil)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := rr.UpsertServer(u, options...); err != nil {
+		return 0, err
+	}
+
+	weight, _ := rr.ServerWeight(u)
+	if weight < 0 {
+		return 0, fmt.Errorf("invalid weight %d for server %s", weight, u)
+	}
+
+	return weight, nil
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer"
 )
 
 type namedHandler struct {
@@ -24,6 +25,31 @@ type stickyCookie struct {
 	httpOnly bool
 }
 
+type override struct {
+	http.Handler
+	name   string
+	header *dynamic.WRRMatch
+	cookie *dynamic.WRRMatch
+}
+
+func (o *override) matches(req *http.Request) bool {
+	if o.header != nil {
+		values := req.Header.Values(o.header.Name)
+		if len(values) > 0 && (o.header.Value == "" || o.header.Value == values[0]) {
+			return true
+		}
+	}
+
+	if o.cookie != nil {
+		cookie, err := req.Cookie(o.cookie.Name)
+		if err == nil && (o.cookie.Value == "" || o.cookie.Value == cookie.Value) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Balancer is a WeightedRoundRobin load balancer based on Earliest Deadline First (EDF).
 // (https://en.wikipedia.org/wiki/Earliest_deadline_first_scheduling)
 // Each pick from the schedule has the earliest deadline entry selected.
@@ -31,15 +57,21 @@ type stickyCookie struct {
 // providing weighted round robin behavior with floating point weights and an O(log n) pick time.
 type Balancer struct {
 	stickyCookie *stickyCookie
+	stickyUser   *dynamic.StickyUser
+	overrides    []*override
 
 	mutex       sync.RWMutex
 	handlers    []*namedHandler
 	curDeadline float64
+	// services holds the handlers in the order they were added,
+	// as the order of the handlers changes with each pick.
+	services    []*namedHandler
+	totalWeight float64
 }
 
 // New creates a new load balancer.
-func New(sticky *dynamic.Sticky) *Balancer {
-	balancer := &Balancer{}
+func New(sticky *dynamic.Sticky, stickyUser *dynamic.StickyUser) *Balancer {
+	balancer := &Balancer{stickyUser: stickyUser}
 	if sticky != nil && sticky.Cookie != nil {
 		balancer.stickyCookie = &stickyCookie{
 			name:     sticky.Cookie.Name,
@@ -102,7 +134,71 @@ func (b *Balancer) nextServer() (*namedHandler, error) {
 	return handler, nil
 }
 
+// userServer picks a handler from the hash of the given user ID, according to the weights,
+// so that the requests of a user always go to the same handler as long as the weights do not change.
+func (b *Balancer) userServer(userID string) (*namedHandler, error) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	if len(b.services) == 0 {
+		return nil, fmt.Errorf("no servers in the pool")
+	}
+
+	// Maps the hash to a point in [0, totalWeight), using the 53 bits of precision of a float64.
+	point := float64(loadbalancer.Hash(userID)>>11) / (1 << 53) * b.totalWeight
+
+	handler := b.services[len(b.services)-1]
+	for _, service := range b.services {
+		if point < service.weight {
+			handler = service
+			break
+		}
+		point -= service.weight
+	}
+
+	log.WithoutContext().Debugf("Service selected by user hash: %s", handler.name)
+	return handler, nil
+}
+
+// userID returns the ID of the user of the request, from the header or else from the cookie.
+func (b *Balancer) userID(req *http.Request) string {
+	if b.stickyUser.Header != "" {
+		if userID := req.Header.Get(b.stickyUser.Header); userID != "" {
+			return userID
+		}
+	}
+
+	if b.stickyUser.Cookie != "" {
+		if cookie, err := req.Cookie(b.stickyUser.Cookie); err == nil {
+			return cookie.Value
+		}
+	}
+
+	return ""
+}
+
 func (b *Balancer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	for _, o := range b.overrides {
+		if o.matches(req) {
+			log.WithoutContext().Debugf("Service selected by override: %s", o.name)
+			o.ServeHTTP(w, req)
+			return
+		}
+	}
+
+	if b.stickyUser != nil {
+		if userID := b.userID(req); userID != "" {
+			server, err := b.userServer(userID)
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusInternalServerError)+err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			server.ServeHTTP(w, req)
+			return
+		}
+	}
+
 	if b.stickyCookie != nil {
 		cookie, err := req.Cookie(b.stickyCookie.name)
 
@@ -154,4 +250,14 @@ func (b *Balancer) AddService(name string, handler http.Handler, weight *int) {
 	b.mutex.RUnlock()
 
 	heap.Push(b, h)
+
+	b.services = append(b.services, h)
+	b.totalWeight += h.weight
+}
+
+// AddOverride adds a handler to which the requests matching the given header or cookie are forwarded,
+// regardless of the weights. The overrides are evaluated in the order they are added.
+// It is not thread safe with ServeHTTP.
+func (b *Balancer) AddOverride(name string, handler http.Handler, header, cookie *dynamic.WRRMatch) {
+	b.overrides = append(b.overrides, &override{Handler: handler, name: name, header: header, cookie: cookie})
 }
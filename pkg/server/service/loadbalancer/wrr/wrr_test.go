@@ -1,6 +1,7 @@
 package wrr
 
 import (
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -24,7 +25,7 @@ func (r *responseRecorder) WriteHeader(statusCode int) {
 }
 
 func TestBalancer(t *testing.T) {
-	balancer := New(nil)
+	balancer := New(nil, nil)
 
 	balancer.AddService("first", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		rw.Header().Set("server", "first")
@@ -46,7 +47,7 @@ func TestBalancer(t *testing.T) {
 }
 
 func TestBalancerNoService(t *testing.T) {
-	balancer := New(nil)
+	balancer := New(nil, nil)
 
 	recorder := httptest.NewRecorder()
 	balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
@@ -55,7 +56,7 @@ func TestBalancerNoService(t *testing.T) {
 }
 
 func TestBalancerOneServerZeroWeight(t *testing.T) {
-	balancer := New(nil)
+	balancer := New(nil, nil)
 
 	balancer.AddService("first", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		rw.Header().Set("server", "first")
@@ -73,7 +74,7 @@ func TestBalancerOneServerZeroWeight(t *testing.T) {
 }
 
 func TestBalancerAllServersZeroWeight(t *testing.T) {
-	balancer := New(nil)
+	balancer := New(nil, nil)
 
 	balancer.AddService("test", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}), Int(0))
 	balancer.AddService("test2", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}), Int(0))
@@ -87,7 +88,7 @@ func TestBalancerAllServersZeroWeight(t *testing.T) {
 func TestSticky(t *testing.T) {
 	balancer := New(&dynamic.Sticky{
 		Cookie: &dynamic.Cookie{Name: "test"},
-	})
+	}, nil)
 
 	balancer.AddService("first", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		rw.Header().Set("server", "first")
@@ -115,10 +116,113 @@ func TestSticky(t *testing.T) {
 	assert.Equal(t, 3, recorder.save["second"])
 }
 
+func TestOverrides(t *testing.T) {
+	balancer := New(nil, nil)
+
+	balancer.AddService("stable", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "stable")
+		rw.WriteHeader(http.StatusOK)
+	}), Int(1))
+
+	canary := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "canary")
+		rw.WriteHeader(http.StatusOK)
+	})
+	balancer.AddService("canary", canary, Int(0))
+	balancer.AddOverride("canary", canary, &dynamic.WRRMatch{Name: "X-Canary", Value: "always"}, &dynamic.WRRMatch{Name: "canary"})
+
+	testCases := []struct {
+		desc     string
+		header   string
+		cookie   *http.Cookie
+		expected string
+	}{
+		{
+			desc:     "no header nor cookie",
+			expected: "stable",
+		},
+		{
+			desc:     "matching header",
+			header:   "always",
+			expected: "canary",
+		},
+		{
+			desc:     "header with another value",
+			header:   "never",
+			expected: "stable",
+		},
+		{
+			desc:     "cookie with any value",
+			cookie:   &http.Cookie{Name: "canary", Value: "foo"},
+			expected: "canary",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if test.header != "" {
+				req.Header.Set("X-Canary", test.header)
+			}
+			if test.cookie != nil {
+				req.AddCookie(test.cookie)
+			}
+
+			recorder := httptest.NewRecorder()
+			balancer.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expected, recorder.Header().Get("server"))
+		})
+	}
+}
+
+func TestStickyUser(t *testing.T) {
+	balancer := New(nil, &dynamic.StickyUser{Header: "X-User", Cookie: "user"})
+
+	balancer.AddService("first", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "first")
+		rw.WriteHeader(http.StatusOK)
+	}), Int(1))
+
+	balancer.AddService("second", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "second")
+		rw.WriteHeader(http.StatusOK)
+	}), Int(3))
+
+	recorder := &responseRecorder{ResponseRecorder: httptest.NewRecorder(), save: map[string]int{}}
+
+	var servers []string
+	for i := 0; i < 1000; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-User", fmt.Sprintf("user-%d", i))
+
+		recorder.ResponseRecorder = httptest.NewRecorder()
+		balancer.ServeHTTP(recorder, req)
+
+		servers = append(servers, recorder.Header().Get("server"))
+	}
+
+	// The users are spread according to the weights.
+	assert.InDelta(t, 250, recorder.save["first"], 50)
+	assert.InDelta(t, 750, recorder.save["second"], 50)
+
+	// The requests of a user always go to the same service, whether the user ID is read from the header or the cookie.
+	for i := 0; i < 1000; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(&http.Cookie{Name: "user", Value: fmt.Sprintf("user-%d", i)})
+
+		recorder.ResponseRecorder = httptest.NewRecorder()
+		balancer.ServeHTTP(recorder, req)
+
+		assert.Equal(t, servers[i], recorder.Header().Get("server"))
+	}
+}
+
 // TestBalancerBias makes sure that the WRR algorithm spreads elements evenly right from the start,
 // and that it does not "over-favor" the high-weighted ones with a biased start-up regime.
 func TestBalancerBias(t *testing.T) {
-	balancer := New(nil)
+	balancer := New(nil, nil)
 
 	balancer.AddService("first", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		rw.Header().Set("server", "A")
@@ -6,8 +6,13 @@ import (
 	"github.com/traefik/traefik/v2/pkg/api"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/middlewares/cache"
+	"github.com/traefik/traefik/v2/pkg/middlewares/maintenance"
 	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/traefik/traefik/v2/pkg/server/service/dnsdiscovery"
+	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer/slowstart"
 )
 
 // ManagerFactory a factory of service manager.
@@ -24,6 +29,18 @@ type ManagerFactory struct {
 	acmeHTTPHandler  http.Handler
 
 	routinesPool *safe.Pool
+
+	// slowStartTracker outlives the service managers, so that the servers keep ramping up across configuration changes.
+	slowStartTracker *slowstart.Tracker
+
+	// cacheStores outlive the middlewares, so that the cached responses are kept across configuration changes.
+	cacheStores *cache.Stores
+
+	// maintenanceStates outlive the middlewares, so that the maintenance mode toggled through the API is kept across configuration changes.
+	maintenanceStates *maintenance.States
+
+	// dnsCache outlives the service managers, so that the servers discovered with DNS are kept across configuration changes.
+	dnsCache *dnsdiscovery.Cache
 }
 
 // NewManagerFactory creates a new ManagerFactory.
@@ -33,10 +50,29 @@ func NewManagerFactory(staticConfiguration static.Configuration, routinesPool *s
 		routinesPool:        routinesPool,
 		roundTripperManager: roundTripperManager,
 		acmeHTTPHandler:     acmeHTTPHandler,
+		slowStartTracker:    slowstart.NewTracker(),
+		dnsCache:            dnsdiscovery.NewCache(),
+	}
+
+	var cacheDiskPath string
+	if staticConfiguration.Cache != nil {
+		cacheDiskPath = staticConfiguration.Cache.DiskPath
 	}
+	factory.cacheStores = cache.NewStores(metricsRegistry, cacheDiskPath)
 
+	var maintenanceStorage string
 	if staticConfiguration.API != nil {
-		factory.api = api.NewBuilder(staticConfiguration)
+		maintenanceStorage = staticConfiguration.API.MaintenanceStorage
+	}
+
+	var err error
+	factory.maintenanceStates, err = maintenance.NewStates(maintenanceStorage)
+	if err != nil {
+		log.WithoutContext().Errorf("Error loading maintenance states, starting with the configured ones: %v", err)
+	}
+
+	if staticConfiguration.API != nil {
+		factory.api = api.NewBuilder(staticConfiguration, factory.cacheStores, factory.maintenanceStates)
 
 		if staticConfiguration.API.Dashboard {
 			factory.dashboardHandler = api.DashboardHandler{Assets: staticConfiguration.API.DashboardAssets}
@@ -61,10 +97,31 @@ func NewManagerFactory(staticConfiguration static.Configuration, routinesPool *s
 	return factory
 }
 
+// CacheStores returns the stores of the cache middlewares.
+func (f *ManagerFactory) CacheStores() *cache.Stores {
+	return f.cacheStores
+}
+
+// MaintenanceStates returns the states of the maintenance middlewares toggled through the API.
+func (f *ManagerFactory) MaintenanceStates() *maintenance.States {
+	return f.maintenanceStates
+}
+
+// DNSCache returns the cache of the servers discovered with DNS.
+func (f *ManagerFactory) DNSCache() *dnsdiscovery.Cache {
+	return f.dnsCache
+}
+
 // Build creates a service manager.
 func (f *ManagerFactory) Build(configuration *runtime.Configuration) *InternalHandlers {
 	svcManager := NewManager(configuration.Services, f.metricsRegistry, f.routinesPool, f.roundTripperManager)
 
+	f.slowStartTracker.Retain(configuration.Services)
+	svcManager.slowStartTracker = f.slowStartTracker
+	svcManager.dnsCache = f.dnsCache
+
+	f.cacheStores.Retain(configuration.Middlewares)
+
 	var apiHandler http.Handler
 	if f.api != nil {
 		apiHandler = f.api(configuration)
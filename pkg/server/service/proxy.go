@@ -14,6 +14,7 @@ import (
 	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/types"
 )
 
 // StatusClientClosedRequest non-standard HTTP status code for client disconnection.
@@ -36,6 +37,12 @@ func buildProxy(passHostHeader *bool, responseForwarding *dynamic.ResponseForwar
 
 	proxy := &httputil.ReverseProxy{
 		Director: func(outReq *http.Request) {
+			// The path of the unix socket of the server is replaced with a host standing for it,
+			// from which the transport dials the socket.
+			if outReq.URL.Scheme == types.UnixSocketScheme && outReq.URL.Host == "" {
+				outReq.URL.Host = types.UnixSocketHost(outReq.URL.Path)
+			}
+
 			u := outReq.URL
 			if outReq.RequestURI != "" {
 				parsedURL, err := url.ParseRequestURI(outReq.RequestURI)
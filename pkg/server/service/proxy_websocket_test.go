@@ -20,6 +20,8 @@ import (
 
 func Bool(v bool) *bool { return &v }
 
+func Int(v int) *int { return &v }
+
 func TestWebSocketTCPClose(t *testing.T) {
 	f, err := buildProxy(Bool(true), nil, http.DefaultTransport, nil)
 	require.NoError(t, err)
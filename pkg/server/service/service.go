@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
@@ -11,6 +12,7 @@ import (
 	"time"
 
 	"github.com/containous/alice"
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/healthcheck"
@@ -23,8 +25,14 @@ import (
 	"github.com/traefik/traefik/v2/pkg/safe"
 	"github.com/traefik/traefik/v2/pkg/server/cookie"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
+	"github.com/traefik/traefik/v2/pkg/server/service/dnsdiscovery"
+	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer/failover"
+	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer/hash"
+	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer/leastrequest"
 	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer/mirror"
+	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer/slowstart"
 	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer/wrr"
+	"github.com/traefik/traefik/v2/pkg/types"
 	"github.com/vulcand/oxy/roundrobin"
 	"github.com/vulcand/oxy/roundrobin/stickycookie"
 )
@@ -50,6 +58,10 @@ func NewManager(configs map[string]*runtime.ServiceInfo, metricsRegistry metrics
 		roundTripperManager: roundTripperManager,
 		balancers:           make(map[string]healthcheck.Balancers),
 		configs:             configs,
+		slowStartTracker:    slowstart.NewTracker(),
+		dnsResolver:         net.DefaultResolver,
+		discoveredServers:   make(map[string]*discoveredServers),
+		passiveHealthChecks: make(map[string][]*healthcheck.PassiveHealthCheck),
 	}
 }
 
@@ -65,6 +77,15 @@ type Manager struct {
 	// which is why there is not just one Balancer per service name.
 	balancers map[string]healthcheck.Balancers
 	configs   map[string]*runtime.ServiceInfo
+	// slowStartTracker keeps track of the ramp up of the servers of the services with a slow start.
+	slowStartTracker *slowstart.Tracker
+	dnsResolver      dnsdiscovery.Resolver
+	// dnsCache keeps the targets resolved with DNS across configuration changes, it can be nil.
+	dnsCache *dnsdiscovery.Cache
+	// discoveredServers is the map of the servers discovered with DNS, keyed by service name.
+	discoveredServers map[string]*discoveredServers
+	// passiveHealthChecks is the map of the passive health checks, keyed by service name.
+	passiveHealthChecks map[string][]*healthcheck.PassiveHealthCheck
 }
 
 // BuildHTTP Creates a http.Handler for a service configuration.
@@ -111,7 +132,14 @@ func (m *Manager) BuildHTTP(rootCtx context.Context, serviceName string) (http.H
 		}
 	case conf.Mirroring != nil:
 		var err error
-		lb, err = m.getMirrorServiceHandler(ctx, conf.Mirroring)
+		lb, err = m.getMirrorServiceHandler(ctx, serviceName, conf.Mirroring)
+		if err != nil {
+			conf.AddError(err, true)
+			return nil, err
+		}
+	case conf.Failover != nil:
+		var err error
+		lb, err = m.getFailoverServiceHandler(ctx, serviceName, conf.Failover)
 		if err != nil {
 			conf.AddError(err, true)
 			return nil, err
@@ -125,7 +153,7 @@ func (m *Manager) BuildHTTP(rootCtx context.Context, serviceName string) (http.H
 	return lb, nil
 }
 
-func (m *Manager) getMirrorServiceHandler(ctx context.Context, config *dynamic.Mirroring) (http.Handler, error) {
+func (m *Manager) getMirrorServiceHandler(ctx context.Context, serviceName string, config *dynamic.Mirroring) (http.Handler, error) {
 	serviceHandler, err := m.BuildHTTP(ctx, config.Service)
 	if err != nil {
 		return nil, err
@@ -142,11 +170,63 @@ func (m *Manager) getMirrorServiceHandler(ctx context.Context, config *dynamic.M
 			return nil, err
 		}
 
-		err = handler.AddMirror(mirrorHandler, mirrorConfig.Percent)
+		err = handler.AddMatchingMirror(mirrorConfig.Name, mirrorHandler, mirrorConfig.Percent, mirrorConfig.Rule, mirrorConfig.HashHeader)
 		if err != nil {
 			return nil, err
 		}
 	}
+
+	if config.Compare != nil {
+		var mismatches gokitmetrics.Counter
+		if m.metricsRegistry != nil && m.metricsRegistry.IsSvcEnabled() {
+			mismatches = m.metricsRegistry.ServiceMirrorMismatchesCounter()
+		}
+
+		comparator, err := mirror.NewComparator(serviceName, config.Compare, m.configs[serviceName], mismatches)
+		if err != nil {
+			return nil, err
+		}
+		handler.SetComparator(comparator)
+	}
+
+	return handler, nil
+}
+
+func (m *Manager) getFailoverServiceHandler(ctx context.Context, serviceName string, config *dynamic.Failover) (http.Handler, error) {
+	if config.Service == "" || config.Fallback == "" {
+		return nil, errors.New("both the service and the fallback of a failover must be defined")
+	}
+
+	statusCodes, err := types.NewHTTPCodeRanges(config.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	maxBodySize := defaultMaxBodySize
+	if config.MaxBodySize != nil {
+		maxBodySize = *config.MaxBodySize
+	}
+
+	handler := failover.New(statusCodes, maxBodySize, m.configs[serviceName])
+
+	serviceHandler, err := m.BuildHTTP(ctx, config.Service)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := handler.SetHandler(provider.GetQualifiedName(ctx, config.Service), serviceHandler); err != nil {
+		return nil, err
+	}
+
+	fallbackHandler, err := m.BuildHTTP(ctx, config.Fallback)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := handler.SetFallbackHandler(provider.GetQualifiedName(ctx, config.Fallback), fallbackHandler); err != nil {
+		return nil, err
+	}
+
 	return handler, nil
 }
 
@@ -156,15 +236,40 @@ func (m *Manager) getWRRServiceHandler(ctx context.Context, serviceName string,
 		config.Sticky.Cookie.Name = cookie.GetName(config.Sticky.Cookie.Name, serviceName)
 	}
 
-	balancer := wrr.New(config.Sticky)
+	if config.StickyUser != nil {
+		if config.Sticky != nil && config.Sticky.Cookie != nil {
+			return nil, errors.New("sticky cookie and sticky user cannot be used together")
+		}
+		if config.StickyUser.Header == "" && config.StickyUser.Cookie == "" {
+			return nil, errors.New("sticky user must have a header or a cookie")
+		}
+	}
+
+	balancer := wrr.New(config.Sticky, config.StickyUser)
+	serviceHandlers := make(map[string]http.Handler)
 	for _, service := range config.Services {
 		serviceHandler, err := m.BuildHTTP(ctx, service.Name)
 		if err != nil {
 			return nil, err
 		}
 
+		serviceHandlers[service.Name] = serviceHandler
 		balancer.AddService(service.Name, serviceHandler, service.Weight)
 	}
+
+	for _, override := range config.Overrides {
+		serviceHandler, ok := serviceHandlers[override.Service]
+		if !ok {
+			return nil, fmt.Errorf("override service %s is not one of the weighted services", override.Service)
+		}
+
+		if (override.Header == nil || override.Header.Name == "") && (override.Cookie == nil || override.Cookie.Name == "") {
+			return nil, fmt.Errorf("override for service %s must have a header or a cookie name", override.Service)
+		}
+
+		balancer.AddOverride(override.Service, serviceHandler, override.Header, override.Cookie)
+	}
+
 	return balancer, nil
 }
 
@@ -235,6 +340,10 @@ func (m *Manager) LaunchHealthCheck() {
 		if backendHealthCheck != nil {
 			backendConfigs[serviceName] = backendHealthCheck
 		}
+
+		for _, passiveHealthCheck := range m.passiveHealthChecks[serviceName] {
+			passiveHealthCheck.SetActiveHealthCheck(backendHealthCheck)
+		}
 	}
 
 	healthcheck.GetHealthCheck(m.metricsRegistry).SetBackendsConfiguration(context.Background(), backendConfigs)
@@ -299,6 +408,91 @@ func (m *Manager) getLoadBalancer(ctx context.Context, serviceName string, servi
 	logger := log.FromContext(ctx)
 	logger.Debug("Creating load-balancer")
 
+	if service.Sticky != nil && service.Strategy != "" && service.Strategy != dynamic.StrategyWRR {
+		return nil, fmt.Errorf("sticky sessions are not supported with the %s strategy", service.Strategy)
+	}
+
+	if service.SlowStart < 0 {
+		return nil, fmt.Errorf("slow start duration must be positive: %s", time.Duration(service.SlowStart))
+	}
+
+	// The weights of the servers set the points they own on the hash ring,
+	// so ramping them up would rebuild the ring, and move the keys, at each step.
+	if service.SlowStart > 0 && service.Strategy == dynamic.StrategyHash {
+		return nil, fmt.Errorf("slow start is not supported with the %s strategy", service.Strategy)
+	}
+
+	if service.DNSDiscovery != nil && len(service.Servers) > 0 {
+		return nil, errors.New("the servers and the DNS discovery of a load balancer cannot be both defined")
+	}
+
+	var passiveHealthCheck *healthcheck.PassiveHealthCheck
+	if service.PassiveHealthCheck != nil {
+		opts := buildPassiveHealthCheckOptions(service.PassiveHealthCheck)
+		logger.Debugf("Setting up passive healthcheck for service %s with %s", serviceName, opts)
+
+		serverUpGauge := metrics.NewVoidRegistry().ServiceServerUpGauge()
+		if m.metricsRegistry != nil {
+			serverUpGauge = m.metricsRegistry.ServiceServerUpGauge()
+		}
+
+		passiveHealthCheck = healthcheck.NewPassiveHealthCheck(serviceName, opts, serverUpGauge)
+		fwd = passiveHealthCheck.Wrap(fwd)
+
+		m.passiveHealthChecks[serviceName] = append(m.passiveHealthChecks[serviceName], passiveHealthCheck)
+		stopOnDone(ctx, passiveHealthCheck.Stop)
+	}
+
+	var lb healthcheck.BalancerHandler
+	var err error
+	switch service.Strategy {
+	case "", dynamic.StrategyWRR:
+		lb, err = m.getRoundRobinBalancer(ctx, serviceName, service, fwd)
+	case dynamic.StrategyHash:
+		lb, err = hash.New(fwd, service.Hash)
+	case dynamic.StrategyLeastRequest, dynamic.StrategyP2C:
+		lb, err = leastrequest.New(fwd, service.Strategy == dynamic.StrategyP2C, service.LeastRequest)
+	default:
+		return nil, fmt.Errorf("unknown load-balancing strategy %q", service.Strategy)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if service.SlowStart > 0 {
+		slowStart := slowstart.New(serviceName, time.Duration(service.SlowStart), m.slowStartTracker, lb)
+		stopOnDone(ctx, slowStart.Stop)
+		lb = slowStart
+	}
+
+	lbsu := healthcheck.NewLBStatusUpdater(lb, m.configs[serviceName])
+
+	var balancer healthcheck.BalancerHandler = lbsu
+	if service.DNSDiscovery != nil {
+		discovered, err := m.getDiscoveredServers(ctx, serviceName, service.DNSDiscovery)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := discovered.addBalancer(lbsu); err != nil {
+			return nil, fmt.Errorf("error configuring load balancer for service %s: %w", serviceName, err)
+		}
+
+		balancer = &dnsBalancer{LbStatusUpdater: lbsu, discovered: discovered}
+	} else if err := m.upsertServers(ctx, lbsu, service.Servers); err != nil {
+		return nil, fmt.Errorf("error configuring load balancer for service %s: %w", serviceName, err)
+	}
+
+	if passiveHealthCheck != nil {
+		passiveHealthCheck.SetBalancer(balancer)
+	}
+
+	return balancer, nil
+}
+
+func (m *Manager) getRoundRobinBalancer(ctx context.Context, serviceName string, service *dynamic.ServersLoadBalancer, fwd http.Handler) (healthcheck.BalancerHandler, error) {
+	logger := log.FromContext(ctx)
+
 	var options []roundrobin.LBOption
 
 	var cookieName string
@@ -322,17 +516,56 @@ func (m *Manager) getLoadBalancer(ctx context.Context, serviceName string, servi
 		logger.Debugf("Sticky session cookie name: %v", cookieName)
 	}
 
-	lb, err := roundrobin.New(fwd, options...)
-	if err != nil {
-		return nil, err
+	return roundrobin.New(fwd, options...)
+}
+
+// stopOnDone calls stop once the context is done, that is, once the handlers built with it are replaced by the ones of a new configuration.
+func stopOnDone(ctx context.Context, stop func()) {
+	done := ctx.Done()
+	if done == nil {
+		return
 	}
 
-	lbsu := healthcheck.NewLBStatusUpdater(lb, m.configs[serviceName])
-	if err := m.upsertServers(ctx, lbsu, service.Servers); err != nil {
-		return nil, fmt.Errorf("error configuring load balancer for service %s: %w", serviceName, err)
+	go func() {
+		<-done
+		stop()
+	}()
+}
+
+func buildPassiveHealthCheckOptions(phc *dynamic.PassiveHealthCheck) healthcheck.PassiveOptions {
+	defaults := &dynamic.PassiveHealthCheck{}
+	defaults.SetDefaults()
+
+	opts := healthcheck.PassiveOptions{
+		ConsecutiveErrors:  phc.ConsecutiveErrors,
+		ErrorRate:          phc.ErrorRate,
+		MinRequests:        phc.MinRequests,
+		Interval:           time.Duration(phc.Interval),
+		BaseEjectionTime:   time.Duration(phc.BaseEjectionTime),
+		MaxEjectionTime:    time.Duration(phc.MaxEjectionTime),
+		MaxEjectionPercent: phc.MaxEjectionPercent,
+	}
+
+	if opts.MinRequests <= 0 {
+		opts.MinRequests = defaults.MinRequests
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = time.Duration(defaults.Interval)
+	}
+	if opts.BaseEjectionTime <= 0 {
+		opts.BaseEjectionTime = time.Duration(defaults.BaseEjectionTime)
+	}
+	if opts.MaxEjectionTime < opts.BaseEjectionTime {
+		opts.MaxEjectionTime = time.Duration(defaults.MaxEjectionTime)
+		if opts.MaxEjectionTime < opts.BaseEjectionTime {
+			opts.MaxEjectionTime = opts.BaseEjectionTime
+		}
+	}
+	if opts.MaxEjectionPercent <= 0 || opts.MaxEjectionPercent > 100 {
+		opts.MaxEjectionPercent = defaults.MaxEjectionPercent
 	}
 
-	return lbsu, nil
+	return opts
 }
 
 func (m *Manager) upsertServers(ctx context.Context, lb healthcheck.BalancerHandler, servers []dynamic.Server) error {
@@ -346,7 +579,12 @@ func (m *Manager) upsertServers(ctx context.Context, lb healthcheck.BalancerHand
 
 		logger.WithField(log.ServerName, name).Debugf("Creating server %d %s", name, u)
 
-		if err := lb.UpsertServer(u, roundrobin.Weight(1)); err != nil {
+		weight := 1
+		if srv.Weight != nil {
+			weight = *srv.Weight
+		}
+
+		if err := lb.UpsertServer(u, roundrobin.Weight(weight)); err != nil {
 			return fmt.Errorf("error adding server %s to load balancer: %w", srv.URL, err)
 		}
 
@@ -2,16 +2,22 @@ package service
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/healthcheck"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
+	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer/slowstart"
 	"github.com/traefik/traefik/v2/pkg/testhelpers"
 )
 
@@ -22,7 +28,10 @@ func (MockForwarder) ServeHTTP(http.ResponseWriter, *http.Request) {
 }
 
 func TestGetLoadBalancer(t *testing.T) {
-	sm := Manager{}
+	sm := Manager{
+		slowStartTracker:    slowstart.NewTracker(),
+		passiveHealthChecks: make(map[string][]*healthcheck.PassiveHealthCheck),
+	}
 
 	testCases := []struct {
 		desc        string
@@ -60,6 +69,106 @@ func TestGetLoadBalancer(t *testing.T) {
 			fwd:         &MockForwarder{},
 			expectError: false,
 		},
+		{
+			desc:        "Succeeds with the hash strategy and weighted servers",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				Strategy: dynamic.StrategyHash,
+				Hash:     &dynamic.HashStrategy{Key: dynamic.HashKeyHeader, Name: "X-User"},
+				Servers: []dynamic.Server{
+					{URL: "http://10.10.10.1:80", Weight: Int(2)},
+					{URL: "http://10.10.10.2:80"},
+				},
+			},
+			fwd:         &MockForwarder{},
+			expectError: false,
+		},
+		{
+			desc:        "Fails with the hash strategy and sticky sessions",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				Strategy: dynamic.StrategyHash,
+				Sticky:   &dynamic.Sticky{Cookie: &dynamic.Cookie{}},
+			},
+			fwd:         &MockForwarder{},
+			expectError: true,
+		},
+		{
+			desc:        "Succeeds with the power of two choices strategy and EWMA",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				Strategy:     dynamic.StrategyP2C,
+				LeastRequest: &dynamic.LeastRequestStrategy{EWMA: true},
+				Servers: []dynamic.Server{
+					{URL: "http://10.10.10.1:80"},
+					{URL: "http://10.10.10.2:80"},
+				},
+			},
+			fwd:         &MockForwarder{},
+			expectError: false,
+		},
+		{
+			desc:        "Fails with the least-request strategy and sticky sessions",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				Strategy: dynamic.StrategyLeastRequest,
+				Sticky:   &dynamic.Sticky{Cookie: &dynamic.Cookie{}},
+			},
+			fwd:         &MockForwarder{},
+			expectError: true,
+		},
+		{
+			desc:        "Succeeds with a passive health check",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				PassiveHealthCheck: &dynamic.PassiveHealthCheck{ConsecutiveErrors: 3},
+				Servers: []dynamic.Server{
+					{URL: "http://10.10.10.1:80"},
+				},
+			},
+			fwd:         &MockForwarder{},
+			expectError: false,
+		},
+		{
+			desc:        "Succeeds with a slow start",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				SlowStart: ptypes.Duration(time.Minute),
+				Servers: []dynamic.Server{
+					{URL: "http://10.10.10.1:80", Weight: Int(2)},
+				},
+			},
+			fwd:         &MockForwarder{},
+			expectError: false,
+		},
+		{
+			desc:        "Fails with a negative slow start",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				SlowStart: ptypes.Duration(-time.Minute),
+			},
+			fwd:         &MockForwarder{},
+			expectError: true,
+		},
+		{
+			desc:        "Fails with a slow start and the hash strategy",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				Strategy:  dynamic.StrategyHash,
+				SlowStart: ptypes.Duration(time.Minute),
+			},
+			fwd:         &MockForwarder{},
+			expectError: true,
+		},
+		{
+			desc:        "Fails with an unknown strategy",
+			serviceName: "test",
+			service: &dynamic.ServersLoadBalancer{
+				Strategy: "foobar",
+			},
+			fwd:         &MockForwarder{},
+			expectError: true,
+		},
 	}
 
 	for _, test := range testCases {
@@ -79,6 +188,19 @@ func TestGetLoadBalancer(t *testing.T) {
 	}
 }
 
+func TestBuildPassiveHealthCheckOptions(t *testing.T) {
+	opts := buildPassiveHealthCheckOptions(&dynamic.PassiveHealthCheck{ErrorRate: 50})
+
+	// The consecutive errors detection is disabled, the other options are defaulted.
+	assert.Equal(t, 0, opts.ConsecutiveErrors)
+	assert.Equal(t, 50, opts.ErrorRate)
+	assert.Equal(t, 20, opts.MinRequests)
+	assert.Equal(t, 10*time.Second, opts.Interval)
+	assert.Equal(t, 30*time.Second, opts.BaseEjectionTime)
+	assert.Equal(t, 300*time.Second, opts.MaxEjectionTime)
+	assert.Equal(t, 50, opts.MaxEjectionPercent)
+}
+
 func TestGetLoadBalancerServiceHandler(t *testing.T) {
 	sm := NewManager(nil, nil, nil, &RoundTripperManager{
 		roundTrippers: map[string]http.RoundTripper{
@@ -408,3 +530,262 @@ func TestMultipleTypeOnBuildHTTP(t *testing.T) {
 }
 
 // FIXME Add healthcheck tests
+
+func TestManager_BuildFailover(t *testing.T) {
+	primaryServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "primary")
+	}))
+	t.Cleanup(primaryServer.Close)
+
+	fallbackServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", "fallback")
+	}))
+	t.Cleanup(fallbackServer.Close)
+
+	services := map[string]*runtime.ServiceInfo{
+		"failover@file": {
+			Service: &dynamic.Service{
+				Failover: &dynamic.Failover{Service: "primary", Fallback: "fallback"},
+			},
+		},
+		"primary@file": {
+			Service: &dynamic.Service{
+				LoadBalancer: &dynamic.ServersLoadBalancer{
+					Servers: []dynamic.Server{{URL: primaryServer.URL}},
+				},
+			},
+		},
+		"fallback@file": {
+			Service: &dynamic.Service{
+				LoadBalancer: &dynamic.ServersLoadBalancer{
+					Servers: []dynamic.Server{{URL: fallbackServer.URL}},
+				},
+			},
+		},
+	}
+
+	manager := NewManager(services, nil, nil, &RoundTripperManager{
+		roundTrippers: map[string]http.RoundTripper{
+			"default@internal": http.DefaultTransport,
+		},
+	})
+
+	handler, err := manager.BuildHTTP(context.Background(), "failover@file")
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://foo.bar", nil))
+	assert.Equal(t, "primary", recorder.Header().Get("server"))
+
+	// Simulates the health check removing the only server of the primary service.
+	require.NoError(t, manager.balancers["primary@file"].RemoveServer(testhelpers.MustParseURL(primaryServer.URL)))
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://foo.bar", nil))
+	assert.Equal(t, "fallback", recorder.Header().Get("server"))
+	assert.Equal(t, map[string]string{"primary@file": "DOWN", "fallback@file": "UP"}, services["failover@file"].GetAllStatus())
+}
+
+func TestManager_BuildMirroringCompare(t *testing.T) {
+	negativeSize := -1
+	negativeBodySize := int64(-1)
+
+	testCases := []struct {
+		desc        string
+		compare     *dynamic.MirroringCompare
+		expectedErr string
+	}{
+		{
+			desc:    "default values",
+			compare: &dynamic.MirroringCompare{},
+		},
+		{
+			desc:        "negative sample size",
+			compare:     &dynamic.MirroringCompare{SampleSize: &negativeSize},
+			expectedErr: "the sample size of the comparison must not be negative",
+		},
+		{
+			desc:        "negative max body size",
+			compare:     &dynamic.MirroringCompare{MaxBodySize: &negativeBodySize},
+			expectedErr: "the max body size of the comparison must not be negative",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			services := map[string]*runtime.ServiceInfo{
+				"mirroring@file": {
+					Service: &dynamic.Service{
+						Mirroring: &dynamic.Mirroring{Service: "main", Compare: test.compare},
+					},
+				},
+				"main@file": {
+					Service: &dynamic.Service{
+						LoadBalancer: &dynamic.ServersLoadBalancer{
+							Servers: []dynamic.Server{{URL: "http://127.0.0.1:8080"}},
+						},
+					},
+				},
+			}
+
+			manager := NewManager(services, nil, nil, &RoundTripperManager{
+				roundTrippers: map[string]http.RoundTripper{
+					"default@internal": http.DefaultTransport,
+				},
+			})
+
+			_, err := manager.BuildHTTP(context.Background(), "mirroring@file")
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestManager_BuildUnixSocketServer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.sock")
+
+	listener, err := net.Listen("unix", path)
+	require.NoError(t, err)
+
+	server := &http.Server{Handler: http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-Host", req.Host)
+		rw.Header().Set("X-Path", req.URL.Path)
+	})}
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(func() { _ = server.Close() })
+
+	testCases := []struct {
+		desc           string
+		passHostHeader bool
+		expectedHost   string
+	}{
+		{
+			desc:           "pass host header",
+			passHostHeader: true,
+			expectedHost:   "foo.bar",
+		},
+		{
+			desc:           "do not pass host header",
+			passHostHeader: false,
+			expectedHost:   "localhost",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			services := map[string]*runtime.ServiceInfo{
+				"unix@file": {
+					Service: &dynamic.Service{
+						LoadBalancer: &dynamic.ServersLoadBalancer{
+							PassHostHeader: Bool(test.passHostHeader),
+							Servers:        []dynamic.Server{{URL: "unix://" + path}},
+						},
+					},
+				},
+			}
+
+			rtManager := NewRoundTripperManager()
+			rtManager.Update(map[string]*dynamic.ServersTransport{"default@internal": {}})
+
+			manager := NewManager(services, nil, nil, rtManager)
+
+			handler, err := manager.BuildHTTP(context.Background(), "unix@file")
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://foo.bar/foo/bar", nil))
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, test.expectedHost, recorder.Header().Get("X-Host"))
+			assert.Equal(t, "/foo/bar", recorder.Header().Get("X-Path"))
+		})
+	}
+}
+
+func TestManager_BuildWeighted(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		weighted    *dynamic.WeightedRoundRobin
+		expectError bool
+	}{
+		{
+			desc: "override and sticky user",
+			weighted: &dynamic.WeightedRoundRobin{
+				Services:   []dynamic.WRRService{{Name: "stable", Weight: Int(1)}, {Name: "canary", Weight: Int(0)}},
+				StickyUser: &dynamic.StickyUser{Header: "X-User"},
+				Overrides:  []dynamic.WRROverride{{Service: "canary", Header: &dynamic.WRRMatch{Name: "X-Canary", Value: "always"}}},
+			},
+		},
+		{
+			desc: "override of an unknown service",
+			weighted: &dynamic.WeightedRoundRobin{
+				Services:  []dynamic.WRRService{{Name: "stable", Weight: Int(1)}},
+				Overrides: []dynamic.WRROverride{{Service: "canary", Header: &dynamic.WRRMatch{Name: "X-Canary"}}},
+			},
+			expectError: true,
+		},
+		{
+			desc: "override without header nor cookie",
+			weighted: &dynamic.WeightedRoundRobin{
+				Services:  []dynamic.WRRService{{Name: "stable", Weight: Int(1)}, {Name: "canary", Weight: Int(0)}},
+				Overrides: []dynamic.WRROverride{{Service: "canary"}},
+			},
+			expectError: true,
+		},
+		{
+			desc: "sticky user without header nor cookie",
+			weighted: &dynamic.WeightedRoundRobin{
+				Services:   []dynamic.WRRService{{Name: "stable", Weight: Int(1)}},
+				StickyUser: &dynamic.StickyUser{},
+			},
+			expectError: true,
+		},
+		{
+			desc: "sticky user and sticky cookie",
+			weighted: &dynamic.WeightedRoundRobin{
+				Services:   []dynamic.WRRService{{Name: "stable", Weight: Int(1)}},
+				Sticky:     &dynamic.Sticky{Cookie: &dynamic.Cookie{}},
+				StickyUser: &dynamic.StickyUser{Cookie: "user"},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			services := map[string]*runtime.ServiceInfo{
+				"weighted@file": {Service: &dynamic.Service{Weighted: test.weighted}},
+				"stable@file":   {Service: &dynamic.Service{LoadBalancer: &dynamic.ServersLoadBalancer{}}},
+				"canary@file":   {Service: &dynamic.Service{LoadBalancer: &dynamic.ServersLoadBalancer{}}},
+			}
+
+			manager := NewManager(services, nil, nil, &RoundTripperManager{
+				roundTrippers: map[string]http.RoundTripper{
+					"default@internal": http.DefaultTransport,
+				},
+			})
+
+			handler, err := manager.BuildHTTP(context.Background(), "weighted@file")
+			if test.expectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotNil(t, handler)
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package tcp
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/traefik/traefik/v2/pkg/server/service/dnsdiscovery"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+// dnsLoadBalancer is a load-balancer whose servers are discovered with DNS.
+// The underlying load-balancer is rebuilt each time the discovered servers change.
+type dnsLoadBalancer struct {
+	serviceName     string
+	discovery       *dnsdiscovery.Discovery
+	newLoadBalancer func() (tcp.Handler, addServerFunc)
+	newServer       func(address string) (tcp.Handler, *tcp.Proxy, error)
+
+	mu      sync.RWMutex
+	handler tcp.Handler
+	targets []dnsdiscovery.Target
+	// servers are reused by the successive load-balancers, so that the active connections of the servers are still counted.
+	servers map[string]dnsServer
+}
+
+type dnsServer struct {
+	handler tcp.Handler
+	proxy   *tcp.Proxy
+}
+
+func (m *Manager) newDNSLoadBalancer(ctx context.Context, serviceName string, config *dynamic.TCPDNSDiscovery, newLoadBalancer func() (tcp.Handler, addServerFunc), newServer func(address string) (tcp.Handler, *tcp.Proxy, error)) (*dnsLoadBalancer, error) {
+	discovery, err := dnsdiscovery.New(m.dnsResolver, m.dnsCache, config.Name, config.RecordType, config.Port, time.Duration(config.RefreshInterval))
+	if err != nil {
+		return nil, err
+	}
+
+	lb := &dnsLoadBalancer{
+		serviceName:     serviceName,
+		discovery:       discovery,
+		newLoadBalancer: newLoadBalancer,
+		newServer:       newServer,
+	}
+
+	// The name is resolved in the background, so the service starts with the servers resolved for the previous configuration,
+	// or without servers when it has not been resolved yet.
+	targets, ok := discovery.Cached()
+	if !ok {
+		log.FromContext(ctx).Debugf("%s is not resolved yet, the servers are added once it is", config.Name)
+	}
+	lb.update(ctx, targets)
+
+	m.dnsLoadBalancers = append(m.dnsLoadBalancers, lb)
+
+	return lb, nil
+}
+
+// LaunchDNSDiscovery launches the DNS discoveries of the servers, until the context is done.
+func (m *Manager) LaunchDNSDiscovery(ctx context.Context) {
+	for _, lb := range m.dnsLoadBalancers {
+		lb := lb
+
+		lb.mu.RLock()
+		targets := lb.targets
+		lb.mu.RUnlock()
+
+		safe.Go(func() {
+			lb.discovery.Watch(ctx, targets, func(targets []dnsdiscovery.Target) {
+				lb.update(ctx, targets)
+			})
+		})
+	}
+}
+
+// ServeTCP forwards the connection to one of the discovered servers.
+func (b *dnsLoadBalancer) ServeTCP(conn tcp.WriteCloser) {
+	b.mu.RLock()
+	handler := b.handler
+	b.mu.RUnlock()
+
+	handler.ServeTCP(conn)
+}
+
+// update replaces the load-balancer with a new one, balancing between the given targets.
+func (b *dnsLoadBalancer) update(ctx context.Context, targets []dnsdiscovery.Target) {
+	logger := log.FromContext(ctx)
+
+	handler, addServer := b.newLoadBalancer()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	servers := make(map[string]dnsServer, len(targets))
+	for _, target := range targets {
+		address := target.Address()
+
+		server, ok := b.servers[address]
+		if !ok {
+			serverHandler, proxy, err := b.newServer(address)
+			if err != nil {
+				logger.Errorf("Unable to create server %s: %v", address, err)
+				continue
+			}
+
+			server = dnsServer{handler: serverHandler, proxy: proxy}
+		}
+
+		servers[address] = server
+		addServer(server.handler, server.proxy, target.Weight)
+	}
+
+	b.handler = handler
+	b.targets = targets
+	b.servers = servers
+
+	addresses := make([]string, 0, len(servers))
+	for address := range servers {
+		addresses = append(addresses, address)
+	}
+	metrics.OnServersDiscovered("tcp", b.serviceName, addresses)
+}
@@ -0,0 +1,51 @@
+package tcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/server/service/dnsdiscovery"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+func TestDNSLoadBalancer_update(t *testing.T) {
+	newLoadBalancer, err := loadBalancerFactory("")
+	require.NoError(t, err)
+
+	var created []string
+	lb := &dnsLoadBalancer{
+		newLoadBalancer: newLoadBalancer,
+		newServer: func(address string) (tcp.Handler, *tcp.Proxy, error) {
+			created = append(created, address)
+
+			proxy, err := tcp.NewProxy(address, 0, nil)
+			if err != nil {
+				return nil, nil, err
+			}
+			return proxy, proxy, nil
+		},
+	}
+
+	lb.update(context.Background(), []dnsdiscovery.Target{
+		{Host: "127.0.0.1", Port: 8080, Weight: 1},
+		{Host: "127.0.0.2", Port: 8080, Weight: 1},
+	})
+
+	assert.Equal(t, []string{"127.0.0.1:8080", "127.0.0.2:8080"}, created)
+	assert.Len(t, lb.servers, 2)
+
+	previous := lb.servers["127.0.0.2:8080"]
+
+	lb.update(context.Background(), []dnsdiscovery.Target{
+		{Host: "127.0.0.2", Port: 8080, Weight: 2},
+		{Host: "127.0.0.3", Port: 8080, Weight: 1},
+	})
+
+	// The server still discovered is reused.
+	assert.Equal(t, []string{"127.0.0.1:8080", "127.0.0.2:8080", "127.0.0.3:8080"}, created)
+	assert.Len(t, lb.servers, 2)
+	assert.Same(t, previous.proxy, lb.servers["127.0.0.2:8080"].proxy)
+	assert.NotContains(t, lb.servers, "127.0.0.1:8080")
+}
@@ -0,0 +1,122 @@
+package tcp
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	traefiktls "github.com/traefik/traefik/v2/pkg/tls"
+)
+
+// NewServersTransportManager creates a new ServersTransportManager.
+func NewServersTransportManager() *ServersTransportManager {
+	return &ServersTransportManager{
+		tlsConfigs: make(map[string]*tls.Config),
+		configs:    make(map[string]*dynamic.TCPServersTransport),
+	}
+}
+
+// ServersTransportManager handles the TLS configurations used to originate TLS connections to the TCP servers.
+type ServersTransportManager struct {
+	lock       sync.RWMutex
+	tlsConfigs map[string]*tls.Config
+	configs    map[string]*dynamic.TCPServersTransport
+}
+
+// Update updates the TLS configurations.
+func (s *ServersTransportManager) Update(newConfigs map[string]*dynamic.TCPServersTransport) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for configName, config := range s.configs {
+		newConfig, ok := newConfigs[configName]
+		if !ok {
+			delete(s.configs, configName)
+			delete(s.tlsConfigs, configName)
+			continue
+		}
+
+		if reflect.DeepEqual(newConfig, config) {
+			continue
+		}
+
+		var err error
+		s.tlsConfigs[configName], err = createTLSConfig(newConfig)
+		if err != nil {
+			log.WithoutContext().Errorf("Could not configure TCP servers transport %s: %v", configName, err)
+			delete(s.tlsConfigs, configName)
+		}
+	}
+
+	for newConfigName, newConfig := range newConfigs {
+		if _, ok := s.configs[newConfigName]; ok {
+			continue
+		}
+
+		var err error
+		s.tlsConfigs[newConfigName], err = createTLSConfig(newConfig)
+		if err != nil {
+			log.WithoutContext().Errorf("Could not configure TCP servers transport %s: %v", newConfigName, err)
+			delete(s.tlsConfigs, newConfigName)
+		}
+	}
+
+	s.configs = newConfigs
+}
+
+// Get gets the TLS configuration of a servers transport by name.
+func (s *ServersTransportManager) Get(name string) (*tls.Config, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if config, ok := s.tlsConfigs[name]; ok {
+		return config, nil
+	}
+
+	return nil, fmt.Errorf("TCP servers transport not found %s", name)
+}
+
+// createTLSConfig creates the tls.Config configured with the servers transport settings.
+func createTLSConfig(cfg *dynamic.TCPServersTransport) (*tls.Config, error) {
+	if cfg == nil {
+		return nil, errors.New("no servers transport configuration given")
+	}
+
+	rootCAs, err := createRootCACertPool(cfg.RootCAs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		ServerName:         cfg.ServerName,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+		RootCAs:            rootCAs,
+		Certificates:       cfg.Certificates.GetCertificates(),
+	}, nil
+}
+
+func createRootCACertPool(rootCAs []traefiktls.FileOrContent) (*x509.CertPool, error) {
+	if len(rootCAs) == 0 {
+		return nil, nil
+	}
+
+	roots := x509.NewCertPool()
+
+	for _, cert := range rootCAs {
+		certContent, err := cert.Read()
+		if err != nil {
+			return nil, fmt.Errorf("reading root CA: %w", err)
+		}
+
+		if !roots.AppendCertsFromPEM(certContent) {
+			return nil, errors.New("no valid certificate found in root CA")
+		}
+	}
+
+	return roots, nil
+}
@@ -2,26 +2,42 @@ package tcp
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"net"
 	"time"
 
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
+	"github.com/traefik/traefik/v2/pkg/server/service/dnsdiscovery"
 	"github.com/traefik/traefik/v2/pkg/tcp"
+	"github.com/traefik/traefik/v2/pkg/types"
 )
 
 // Manager is the TCPHandlers factory.
 type Manager struct {
-	configs map[string]*runtime.TCPServiceInfo
+	configs                 map[string]*runtime.TCPServiceInfo
+	serversTransportManager *ServersTransportManager
+	metricsRegistry         metrics.Registry
+	dnsResolver             dnsdiscovery.Resolver
+	dnsCache                *dnsdiscovery.Cache
+	dnsLoadBalancers        []*dnsLoadBalancer
 }
 
 // NewManager creates a new manager.
-func NewManager(conf *runtime.Configuration) *Manager {
+// The servers discovered with DNS are kept across configuration changes in the given cache, when not nil.
+func NewManager(conf *runtime.Configuration, serversTransportManager *ServersTransportManager, metricsRegistry metrics.Registry, dnsCache *dnsdiscovery.Cache) *Manager {
 	return &Manager{
-		configs: conf.TCPServices,
+		configs:                 conf.TCPServices,
+		serversTransportManager: serversTransportManager,
+		metricsRegistry:         metricsRegistry,
+		dnsResolver:             net.DefaultResolver,
+		dnsCache:                dnsCache,
 	}
 }
 
@@ -45,7 +61,17 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 	logger := log.FromContext(ctx)
 	switch {
 	case conf.LoadBalancer != nil:
-		loadBalancer := tcp.NewWRRLoadBalancer()
+		if conf.LoadBalancer.DNSDiscovery != nil && len(conf.LoadBalancer.Servers) > 0 {
+			err := errors.New("the servers and the DNS discovery of a load balancer cannot be both defined")
+			conf.AddError(err, true)
+			return nil, err
+		}
+
+		newLoadBalancer, err := loadBalancerFactory(conf.LoadBalancer.Strategy)
+		if err != nil {
+			conf.AddError(err, true)
+			return nil, err
+		}
 
 		if conf.LoadBalancer.TerminationDelay == nil {
 			defaultTerminationDelay := 100
@@ -53,22 +79,71 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 		}
 		duration := time.Duration(*conf.LoadBalancer.TerminationDelay) * time.Millisecond
 
+		var tlsConfig *tls.Config
+		if conf.LoadBalancer.ServersTransport != "" {
+			conf.LoadBalancer.ServersTransport = provider.GetQualifiedName(ctx, conf.LoadBalancer.ServersTransport)
+
+			var err error
+			tlsConfig, err = m.getTLSConfig(conf.LoadBalancer.ServersTransport)
+			if err != nil {
+				conf.AddError(err, true)
+				return nil, err
+			}
+		}
+
+		var serviceMetrics *tcp.ConnMetrics
+		if m.metricsRegistry != nil && m.metricsRegistry.IsSvcEnabled() {
+			serviceMetrics = tcp.NewServiceConnMetrics(m.metricsRegistry, serviceQualifiedName)
+		}
+
+		newServer := func(address string) (tcp.Handler, *tcp.Proxy, error) {
+			proxy, err := tcp.NewProxy(address, duration, conf.LoadBalancer.ProxyProtocol)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if tlsConfig != nil {
+				if err = proxy.SetTLSConfig(tlsConfig); err != nil {
+					return nil, nil, err
+				}
+			}
+
+			if serviceMetrics != nil {
+				proxy.SetMetrics(serviceMetrics)
+				proxy.SetOpenConnsGauge(m.metricsRegistry.TCPServiceServerOpenConnsGauge().With("service", serviceQualifiedName, "address", address))
+			}
+
+			return accesslog.NewTCPFieldHandler(proxy, accesslog.ServiceAddr, address), proxy, nil
+		}
+
+		if conf.LoadBalancer.DNSDiscovery != nil {
+			loadBalancer, err := m.newDNSLoadBalancer(ctx, serviceQualifiedName, conf.LoadBalancer.DNSDiscovery, newLoadBalancer, newServer)
+			if err != nil {
+				conf.AddError(err, true)
+				return nil, err
+			}
+			return accesslog.NewTCPFieldHandler(loadBalancer, accesslog.ServiceName, serviceQualifiedName), nil
+		}
+
+		loadBalancer, addServer := newLoadBalancer()
 		for name, server := range conf.LoadBalancer.Servers {
-			if _, _, err := net.SplitHostPort(server.Address); err != nil {
-				logger.Errorf("In service %q: %v", serviceQualifiedName, err)
-				continue
+			if _, ok := types.UnixSocketPath(server.Address); !ok {
+				if _, _, err := net.SplitHostPort(server.Address); err != nil {
+					logger.Errorf("In service %q: %v", serviceQualifiedName, err)
+					continue
+				}
 			}
 
-			handler, err := tcp.NewProxy(server.Address, duration, conf.LoadBalancer.ProxyProtocol)
+			handler, proxy, err := newServer(server.Address)
 			if err != nil {
 				logger.Errorf("In service %q server %q: %v", serviceQualifiedName, server.Address, err)
 				continue
 			}
 
-			loadBalancer.AddServer(handler)
+			addServer(handler, proxy, 1)
 			logger.WithField(log.ServerName, name).Debugf("Creating TCP server %d at %s", name, server.Address)
 		}
-		return loadBalancer, nil
+		return accesslog.NewTCPFieldHandler(loadBalancer, accesslog.ServiceName, serviceQualifiedName), nil
 	case conf.Weighted != nil:
 		loadBalancer := tcp.NewWRRLoadBalancer()
 		for _, service := range conf.Weighted.Services {
@@ -79,10 +154,52 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 			}
 			loadBalancer.AddWeightServer(handler, service.Weight)
 		}
-		return loadBalancer, nil
+		return accesslog.NewTCPFieldHandler(loadBalancer, accesslog.ServiceName, serviceQualifiedName), nil
 	default:
 		err := fmt.Errorf("the service %q does not have any type defined", serviceQualifiedName)
 		conf.AddError(err, true)
 		return nil, err
 	}
 }
+
+// addServerFunc adds a server to a load-balancer, with the proxy counting the connections of the server.
+type addServerFunc func(handler tcp.Handler, proxy *tcp.Proxy, weight int)
+
+// loadBalancerFactory returns a function creating the empty load-balancers of the given strategy.
+func loadBalancerFactory(strategy string) (func() (tcp.Handler, addServerFunc), error) {
+	switch strategy {
+	case "", dynamic.TCPStrategyWRR:
+		return func() (tcp.Handler, addServerFunc) {
+			lb := tcp.NewWRRLoadBalancer()
+			return lb, func(handler tcp.Handler, _ *tcp.Proxy, weight int) { lb.AddWeightServer(handler, &weight) }
+		}, nil
+	case dynamic.TCPStrategyLeastConn, dynamic.TCPStrategyP2C:
+		powerOfTwoChoices := strategy == dynamic.TCPStrategyP2C
+		return func() (tcp.Handler, addServerFunc) {
+			lb := tcp.NewLeastConnLoadBalancer(powerOfTwoChoices)
+			return lb, func(handler tcp.Handler, proxy *tcp.Proxy, _ int) {
+				lb.AddServer(connCounterHandler{Handler: handler, counter: proxy})
+			}
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown load-balancing strategy %q", strategy)
+	}
+}
+
+// connCounterHandler is a tcp.ConnCounter serving the connections with a handler wrapping the counted server.
+type connCounterHandler struct {
+	tcp.Handler
+	counter tcp.ConnCounter
+}
+
+func (h connCounterHandler) ActiveConns() int64 {
+	return h.counter.ActiveConns()
+}
+
+func (m *Manager) getTLSConfig(serversTransportName string) (*tls.Config, error) {
+	if m.serversTransportManager == nil {
+		return nil, fmt.Errorf("TCP servers transport not found %s", serversTransportName)
+	}
+
+	return m.serversTransportManager.Get(serversTransportName)
+}
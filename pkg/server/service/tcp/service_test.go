@@ -65,6 +65,73 @@ func TestManager_BuildTCP(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc:        "DNS discovery",
+			serviceName: "test",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"test": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							DNSDiscovery: &dynamic.TCPDNSDiscovery{
+								Name: "localhost",
+								Port: 8080,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc:        "DNS discovery with servers",
+			serviceName: "test",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"test": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{Address: "127.0.0.1:8080"},
+							},
+							DNSDiscovery: &dynamic.TCPDNSDiscovery{
+								Name: "localhost",
+								Port: 8080,
+							},
+						},
+					},
+				},
+			},
+			expectedError: "the servers and the DNS discovery of a load balancer cannot be both defined",
+		},
+		{
+			desc:        "DNS discovery without port",
+			serviceName: "test",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"test": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							DNSDiscovery: &dynamic.TCPDNSDiscovery{
+								Name: "localhost",
+							},
+						},
+					},
+				},
+			},
+			expectedError: "the port of the DNS discovery must be defined with A records",
+		},
+		{
+			desc:        "unix socket server",
+			serviceName: "test",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"test": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{Address: "unix:///run/app.sock"},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			desc:        "Simple service name",
 			serviceName: "serviceName",
@@ -171,8 +238,90 @@ func TestManager_BuildTCP(t *testing.T) {
 			},
 			providerName: "provider-1",
 		},
+		{
+			desc:        "least connections strategy",
+			serviceName: "serviceName",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Strategy: dynamic.TCPStrategyLeastConn,
+							Servers: []dynamic.TCPServer{
+								{Address: "192.168.0.12:80"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc:        "power of two choices strategy",
+			serviceName: "serviceName",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Strategy: dynamic.TCPStrategyP2C,
+							Servers: []dynamic.TCPServer{
+								{Address: "192.168.0.12:80"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc:        "unknown strategy",
+			serviceName: "serviceName",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Strategy: "foobar",
+						},
+					},
+				},
+			},
+			expectedError: `unknown load-balancing strategy "foobar"`,
+		},
+		{
+			desc:        "servers transport",
+			serviceName: "serviceName",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName@file": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							ServersTransport: "tls",
+							Servers: []dynamic.TCPServer{
+								{Address: "192.168.0.12:80"},
+							},
+						},
+					},
+				},
+			},
+			providerName: "file",
+		},
+		{
+			desc:        "unknown servers transport",
+			serviceName: "serviceName",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							ServersTransport: "foobar",
+						},
+					},
+				},
+			},
+			expectedError: "TCP servers transport not found foobar",
+		},
 	}
 
+	serversTransportManager := NewServersTransportManager()
+	serversTransportManager.Update(map[string]*dynamic.TCPServersTransport{
+		"tls@file": {InsecureSkipVerify: true},
+	})
+
 	for _, test := range testCases {
 		test := test
 		t.Run(test.desc, func(t *testing.T) {
@@ -180,7 +329,7 @@ func TestManager_BuildTCP(t *testing.T) {
 
 			manager := NewManager(&runtime.Configuration{
 				TCPServices: test.configs,
-			})
+			}, serversTransportManager, nil, nil)
 
 			ctx := context.Background()
 			if len(test.providerName) > 0 {
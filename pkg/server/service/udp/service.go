@@ -8,19 +8,23 @@ import (
 
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
 	"github.com/traefik/traefik/v2/pkg/udp"
 )
 
 // Manager handles UDP services creation.
 type Manager struct {
-	configs map[string]*runtime.UDPServiceInfo
+	configs         map[string]*runtime.UDPServiceInfo
+	metricsRegistry metrics.Registry
 }
 
 // NewManager creates a new manager.
-func NewManager(conf *runtime.Configuration) *Manager {
+func NewManager(conf *runtime.Configuration, metricsRegistry metrics.Registry) *Manager {
 	return &Manager{
-		configs: conf.UDPServices,
+		configs:         conf.UDPServices,
+		metricsRegistry: metricsRegistry,
 	}
 }
 
@@ -46,6 +50,11 @@ func (m *Manager) BuildUDP(rootCtx context.Context, serviceName string) (udp.Han
 	case conf.LoadBalancer != nil:
 		loadBalancer := udp.NewWRRLoadBalancer()
 
+		var serviceMetrics *udp.ServiceMetrics
+		if m.metricsRegistry != nil && m.metricsRegistry.IsSvcEnabled() {
+			serviceMetrics = udp.NewServiceMetrics(m.metricsRegistry, serviceQualifiedName)
+		}
+
 		for name, server := range conf.LoadBalancer.Servers {
 			if _, _, err := net.SplitHostPort(server.Address); err != nil {
 				logger.Errorf("In udp service %q: %v", serviceQualifiedName, err)
@@ -58,10 +67,14 @@ func (m *Manager) BuildUDP(rootCtx context.Context, serviceName string) (udp.Han
 				continue
 			}
 
-			loadBalancer.AddServer(handler)
+			if serviceMetrics != nil {
+				handler.SetMetrics(serviceMetrics)
+			}
+
+			loadBalancer.AddServer(accesslog.NewUDPFieldHandler(handler, accesslog.ServiceAddr, server.Address))
 			logger.WithField(log.ServerName, name).Debugf("Creating UDP server %d at %s", name, server.Address)
 		}
-		return loadBalancer, nil
+		return accesslog.NewUDPFieldHandler(loadBalancer, accesslog.ServiceName, serviceQualifiedName), nil
 	case conf.Weighted != nil:
 		loadBalancer := udp.NewWRRLoadBalancer()
 		for _, service := range conf.Weighted.Services {
@@ -72,7 +85,7 @@ func (m *Manager) BuildUDP(rootCtx context.Context, serviceName string) (udp.Han
 			}
 			loadBalancer.AddWeightedServer(handler, service.Weight)
 		}
-		return loadBalancer, nil
+		return accesslog.NewUDPFieldHandler(loadBalancer, accesslog.ServiceName, serviceQualifiedName), nil
 	default:
 		err := fmt.Errorf("the udp service %q does not have any type defined", serviceQualifiedName)
 		conf.AddError(err, true)
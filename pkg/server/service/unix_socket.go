@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+// unixSocketTransport forwards the requests to the servers listening on unix sockets,
+// whose URLs have the unix scheme and the host standing for the path of the socket.
+type unixSocketTransport struct {
+	*http.Transport
+}
+
+func newUnixSocketTransport(transport *http.Transport, dialer *net.Dialer) *unixSocketTransport {
+	unixTransport := transport.Clone()
+	unixTransport.Proxy = nil
+	unixTransport.DialContext = func(ctx context.Context, _, addr string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		path, err := types.UnixSocketHostPath(host)
+		if err != nil {
+			return nil, err
+		}
+
+		return dialer.DialContext(ctx, "unix", path)
+	}
+
+	return &unixSocketTransport{Transport: unixTransport}
+}
+
+func (t *unixSocketTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = "http"
+
+	// The host standing for the socket is not meaningful to the server.
+	if req.Host == "" || req.Host == req.URL.Host {
+		req.Host = "localhost"
+	}
+
+	return t.Transport.RoundTrip(req)
+}
@@ -0,0 +1,90 @@
+package tcp
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// ConnCounter is a Handler that keeps track of the connections it is currently handling.
+type ConnCounter interface {
+	Handler
+	ActiveConns() int64
+}
+
+// LeastConnLoadBalancer is a load balancer for TCP services that forwards connections
+// to the server with the least active connections.
+// With the power of two choices enabled, only two servers picked at random are compared,
+// which avoids herding all the new connections on the same least loaded server.
+type LeastConnLoadBalancer struct {
+	servers           []ConnCounter
+	lock              sync.Mutex
+	powerOfTwoChoices bool
+	rand              *rand.Rand
+}
+
+// NewLeastConnLoadBalancer creates a new LeastConnLoadBalancer.
+func NewLeastConnLoadBalancer(powerOfTwoChoices bool) *LeastConnLoadBalancer {
+	return &LeastConnLoadBalancer{
+		powerOfTwoChoices: powerOfTwoChoices,
+		rand:              rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// ServeTCP forwards the connection to the right service.
+func (b *LeastConnLoadBalancer) ServeTCP(conn WriteCloser) {
+	next, err := b.next()
+	if err != nil {
+		log.WithoutContext().Errorf("Error during load balancing: %v", err)
+		conn.Close()
+		return
+	}
+
+	next.ServeTCP(conn)
+}
+
+// AddServer appends a server to the existing list.
+func (b *LeastConnLoadBalancer) AddServer(server ConnCounter) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.servers = append(b.servers, server)
+}
+
+func (b *LeastConnLoadBalancer) next() (ConnCounter, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	switch len(b.servers) {
+	case 0:
+		return nil, errors.New("no servers in the pool")
+	case 1:
+		return b.servers[0], nil
+	}
+
+	if b.powerOfTwoChoices {
+		first := b.rand.Intn(len(b.servers))
+		// Picks a second index distinct from the first one.
+		second := (first + 1 + b.rand.Intn(len(b.servers)-1)) % len(b.servers)
+
+		if b.servers[second].ActiveConns() < b.servers[first].ActiveConns() {
+			return b.servers[second], nil
+		}
+		return b.servers[first], nil
+	}
+
+	// Starts from a random offset so that ties are not always won by the first server.
+	offset := b.rand.Intn(len(b.servers))
+	best := b.servers[offset]
+	for i := 1; i < len(b.servers); i++ {
+		srv := b.servers[(offset+i)%len(b.servers)]
+		if srv.ActiveConns() < best.ActiveConns() {
+			best = srv
+		}
+	}
+
+	return best, nil
+}
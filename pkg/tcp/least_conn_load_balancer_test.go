@@ -0,0 +1,98 @@
+package tcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeConnCounter struct {
+	name        string
+	activeConns int64
+}
+
+func (f *fakeConnCounter) ServeTCP(conn WriteCloser) {
+	_, _ = conn.Write([]byte(f.name))
+}
+
+func (f *fakeConnCounter) ActiveConns() int64 {
+	return f.activeConns
+}
+
+func TestLeastConnLoadBalancer(t *testing.T) {
+	testCases := []struct {
+		desc              string
+		powerOfTwoChoices bool
+		activeConns       map[string]int64
+		totalCall         int
+		expected          map[string]int
+	}{
+		{
+			desc: "least connections",
+			activeConns: map[string]int64{
+				"h1": 3,
+				"h2": 1,
+				"h3": 2,
+			},
+			totalCall: 10,
+			expected: map[string]int{
+				"h2": 10,
+			},
+		},
+		{
+			desc:              "power of two choices with two servers",
+			powerOfTwoChoices: true,
+			activeConns: map[string]int64{
+				"h1": 3,
+				"h2": 1,
+			},
+			totalCall: 10,
+			expected: map[string]int{
+				"h2": 10,
+			},
+		},
+		{
+			desc:              "power of two choices never picks the busiest server",
+			powerOfTwoChoices: true,
+			activeConns: map[string]int64{
+				"h1": 1,
+				"h2": 2,
+				"h3": 10,
+			},
+			totalCall: 100,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			balancer := NewLeastConnLoadBalancer(test.powerOfTwoChoices)
+			for name, activeConns := range test.activeConns {
+				balancer.AddServer(&fakeConnCounter{name: name, activeConns: activeConns})
+			}
+
+			conn := &fakeConn{call: make(map[string]int)}
+			for i := 0; i < test.totalCall; i++ {
+				balancer.ServeTCP(conn)
+			}
+
+			if test.expected != nil {
+				assert.Equal(t, test.expected, conn.call)
+				return
+			}
+
+			assert.Zero(t, conn.call["h3"])
+			assert.Equal(t, test.totalCall, conn.call["h1"]+conn.call["h2"])
+		})
+	}
+}
+
+func TestLeastConnLoadBalancer_noServer(t *testing.T) {
+	balancer := NewLeastConnLoadBalancer(false)
+
+	_, err := balancer.next()
+	require.Error(t, err)
+}
@@ -0,0 +1,148 @@
+package tcp
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+)
+
+// ConnMetrics holds the metrics recorded for the connections going through a TCP handler.
+type ConnMetrics struct {
+	ConnsCounter          gokitmetrics.Counter
+	OpenConnsGauge        gokitmetrics.Gauge
+	ConnDurationHistogram metrics.ScalableHistogram
+	// BytesCounter is partitioned by direction:
+	// "in" for the bytes received from the client, and "out" for the bytes sent to it.
+	BytesCounter gokitmetrics.Counter
+	// Labels are the label names and values shared by all the metrics.
+	Labels []string
+}
+
+// NewEntryPointConnMetrics returns the metrics of the TCP connections handled on an entry point.
+func NewEntryPointConnMetrics(registry metrics.Registry, entryPointName string) *ConnMetrics {
+	return &ConnMetrics{
+		ConnsCounter:          registry.TCPEntryPointConnsCounter(),
+		OpenConnsGauge:        registry.TCPEntryPointOpenConnsGauge(),
+		ConnDurationHistogram: registry.TCPEntryPointConnDurationHistogram(),
+		BytesCounter:          registry.TCPEntryPointBytesCounter(),
+		Labels:                []string{"entrypoint", entryPointName},
+	}
+}
+
+// NewRouterConnMetrics returns the metrics of the TCP connections handled on a router.
+func NewRouterConnMetrics(registry metrics.Registry, routerName, serviceName string) *ConnMetrics {
+	return &ConnMetrics{
+		ConnsCounter:          registry.TCPRouterConnsCounter(),
+		OpenConnsGauge:        registry.TCPRouterOpenConnsGauge(),
+		ConnDurationHistogram: registry.TCPRouterConnDurationHistogram(),
+		BytesCounter:          registry.TCPRouterBytesCounter(),
+		Labels:                []string{"router", routerName, "service", serviceName},
+	}
+}
+
+// NewServiceConnMetrics returns the metrics of the TCP connections handled on a service.
+func NewServiceConnMetrics(registry metrics.Registry, serviceName string) *ConnMetrics {
+	return &ConnMetrics{
+		ConnsCounter:          registry.TCPServiceConnsCounter(),
+		OpenConnsGauge:        registry.TCPServiceOpenConnsGauge(),
+		ConnDurationHistogram: registry.TCPServiceConnDurationHistogram(),
+		BytesCounter:          registry.TCPServiceBytesCounter(),
+		Labels:                []string{"service", serviceName},
+	}
+}
+
+// NewMetricsHandler returns a Handler recording the given metrics for each connection before forwarding it to next.
+func NewMetricsHandler(next Handler, m *ConnMetrics) Handler {
+	return HandlerFunc(func(conn WriteCloser) {
+		mConn, done := m.track(conn)
+		defer done()
+
+		next.ServeTCP(mConn)
+	})
+}
+
+// track records a new connection, and returns the connection wrapped to count the bytes going through it,
+// along with the func to call once the connection is over.
+func (m *ConnMetrics) track(conn WriteCloser) (WriteCloser, func()) {
+	start := time.Now()
+
+	m.ConnsCounter.With(m.Labels...).Add(1)
+
+	openConns := m.OpenConnsGauge.With(m.Labels...)
+	openConns.Add(1)
+
+	mConn := &meteredConn{
+		WriteCloser: conn,
+		bytesIn:     m.BytesCounter.With(m.withLabels("direction", "in")...),
+		bytesOut:    m.BytesCounter.With(m.withLabels("direction", "out")...),
+	}
+
+	return mConn, func() {
+		openConns.Add(-1)
+		m.ConnDurationHistogram.With(m.Labels...).ObserveFromStart(start)
+	}
+}
+
+// trackUntilClose records a new connection, and returns the connection wrapped to count the bytes going through it,
+// and to record the end of the connection once it is closed.
+// It is meant for the connections which outlive the handler they go through, such as the ones forwarded to the HTTP servers.
+func (m *ConnMetrics) trackUntilClose(conn WriteCloser) WriteCloser {
+	mConn, done := m.track(conn)
+
+	return &closeTrackedConn{WriteCloser: mConn, done: done}
+}
+
+func (m *ConnMetrics) withLabels(labelValues ...string) []string {
+	labels := make([]string, 0, len(m.Labels)+len(labelValues))
+	labels = append(labels, m.Labels...)
+	return append(labels, labelValues...)
+}
+
+// meteredConn counts the bytes read from and written to the underlying connection.
+type meteredConn struct {
+	WriteCloser
+	bytesIn  gokitmetrics.Counter
+	bytesOut gokitmetrics.Counter
+}
+
+// NetConn returns the underlying connection.
+func (c *meteredConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
+
+func (c *meteredConn) Read(p []byte) (int, error) {
+	n, err := c.WriteCloser.Read(p)
+	if n > 0 {
+		c.bytesIn.Add(float64(n))
+	}
+	return n, err
+}
+
+func (c *meteredConn) Write(p []byte) (int, error) {
+	n, err := c.WriteCloser.Write(p)
+	if n > 0 {
+		c.bytesOut.Add(float64(n))
+	}
+	return n, err
+}
+
+// closeTrackedConn calls done when the connection is closed for the first time.
+type closeTrackedConn struct {
+	WriteCloser
+	once sync.Once
+	done func()
+}
+
+// NetConn returns the underlying connection.
+func (c *closeTrackedConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
+
+func (c *closeTrackedConn) Close() error {
+	err := c.WriteCloser.Close()
+	c.once.Do(c.done)
+	return err
+}
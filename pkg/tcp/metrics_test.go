@@ -0,0 +1,87 @@
+package tcp
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/metrics/generic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/testhelpers"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	histogram, err := metrics.NewHistogramWithScale(generic.NewHistogram("duration", 10), time.Second)
+	require.NoError(t, err)
+
+	connsCounter := &testhelpers.CollectingCounter{}
+	openConnsGauge := &testhelpers.CollectingGauge{}
+	bytesCounter := &testhelpers.CollectingCounter{}
+
+	connMetrics := &ConnMetrics{
+		ConnsCounter:          connsCounter,
+		OpenConnsGauge:        openConnsGauge,
+		ConnDurationHistogram: histogram,
+		BytesCounter:          bytesCounter,
+		Labels:                []string{"service", "foo"},
+	}
+
+	handler := NewMetricsHandler(HandlerFunc(func(conn WriteCloser) {
+		assert.Equal(t, float64(1), openConnsGauge.GaugeValue)
+
+		_, err := conn.Write([]byte("foo"))
+		require.NoError(t, err)
+		_, err = conn.Write([]byte("bar"))
+		require.NoError(t, err)
+	}), connMetrics)
+
+	handler.ServeTCP(&fakeConn{call: map[string]int{}})
+	handler.ServeTCP(&fakeConn{call: map[string]int{}})
+
+	assert.Equal(t, float64(2), connsCounter.CounterValue)
+	assert.Equal(t, []string{"service", "foo"}, connsCounter.LastLabelValues)
+	assert.Equal(t, []string{"service", "foo"}, openConnsGauge.LastLabelValues)
+	assert.Equal(t, float64(12), bytesCounter.CounterValue)
+	assert.Equal(t, []string{"service", "foo", "direction", "out"}, bytesCounter.LastLabelValues)
+}
+
+func TestRouter_connMetrics(t *testing.T) {
+	histogram, err := metrics.NewHistogramWithScale(generic.NewHistogram("duration", 10), time.Second)
+	require.NoError(t, err)
+
+	connsCounter := &testhelpers.CollectingCounter{}
+	openConnsGauge := &testhelpers.CollectingGauge{}
+
+	router := &Router{}
+	router.SetConnMetrics(&ConnMetrics{
+		ConnsCounter:          connsCounter,
+		OpenConnsGauge:        openConnsGauge,
+		ConnDurationHistogram: histogram,
+		BytesCounter:          &testhelpers.CollectingCounter{},
+		Labels:                []string{"entrypoint", "foo"},
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	client, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.Write([]byte("GET / HTTP/1.1\r\n\r\n"))
+	require.NoError(t, err)
+
+	conn, err := listener.Accept()
+	require.NoError(t, err)
+
+	// The connection is not routed anywhere, and is closed by the router.
+	router.ServeTCP(conn.(*net.TCPConn))
+
+	assert.Equal(t, float64(1), connsCounter.CounterValue)
+	assert.Equal(t, []string{"entrypoint", "foo"}, connsCounter.LastLabelValues)
+	// The last update of the gauge is the decrement done once the connection is closed.
+	assert.Equal(t, float64(-1), openConnsGauge.GaugeValue)
+}
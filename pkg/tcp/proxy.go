@@ -1,36 +1,60 @@
 package tcp
 
 import (
+	"crypto/tls"
 	"fmt"
 	"io"
 	"net"
+	"sync/atomic"
 	"time"
 
+	"github.com/go-kit/kit/metrics"
 	"github.com/pires/go-proxyproto"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/types"
 )
 
+// tlsHandshakeTimeout is the maximum amount of time waiting for the TLS handshake with a backend.
+const tlsHandshakeTimeout = 10 * time.Second
+
 // Proxy forwards a TCP request to a TCP service.
 type Proxy struct {
+	// activeConns is accessed atomically and must stay 64-bit aligned.
+	activeConns int64
+
 	address          string
+	unixSocket       string
 	target           *net.TCPAddr
 	terminationDelay time.Duration
 	proxyProtocol    *dynamic.ProxyProtocol
+	tlsConfig        *tls.Config
 	refreshTarget    bool
+	openConnsGauge   metrics.Gauge
+	metrics          *ConnMetrics
 }
 
 // NewProxy creates a new Proxy.
+// The address is either a host:port address, or the one of a unix socket (e.g. unix:///run/app.sock).
 func NewProxy(address string, terminationDelay time.Duration, proxyProtocol *dynamic.ProxyProtocol) (*Proxy, error) {
+	if proxyProtocol != nil && (proxyProtocol.Version < 1 || proxyProtocol.Version > 2) {
+		return nil, fmt.Errorf("unknown proxyProtocol version: %d", proxyProtocol.Version)
+	}
+
+	if path, ok := types.UnixSocketPath(address); ok {
+		return &Proxy{
+			address:          address,
+			unixSocket:       path,
+			terminationDelay: terminationDelay,
+			proxyProtocol:    proxyProtocol,
+		}, nil
+	}
+
 	tcpAddr, err := net.ResolveTCPAddr("tcp", address)
 	if err != nil {
 		return nil, err
 	}
 
-	if proxyProtocol != nil && (proxyProtocol.Version < 1 || proxyProtocol.Version > 2) {
-		return nil, fmt.Errorf("unknown proxyProtocol version: %d", proxyProtocol.Version)
-	}
-
 	// enable the refresh of the target only if the address in not an IP
 	refreshTarget := false
 	if host, _, err := net.SplitHostPort(address); err == nil && net.ParseIP(host) == nil {
@@ -46,6 +70,42 @@ func NewProxy(address string, terminationDelay time.Duration, proxyProtocol *dyn
 	}, nil
 }
 
+// SetTLSConfig sets the configuration used to originate TLS connections to the backend.
+// When the configuration has no server name, the host of the backend address is used.
+// As a unix socket address has no host, the server name is then required, unless the verification is skipped.
+func (p *Proxy) SetTLSConfig(config *tls.Config) error {
+	if config != nil && config.ServerName == "" {
+		if p.unixSocket != "" {
+			if !config.InsecureSkipVerify {
+				return fmt.Errorf("a server name is required to originate TLS connections to the unix socket %s", p.address)
+			}
+		} else {
+			config = config.Clone()
+			if host, _, err := net.SplitHostPort(p.address); err == nil {
+				config.ServerName = host
+			}
+		}
+	}
+
+	p.tlsConfig = config
+	return nil
+}
+
+// SetOpenConnsGauge sets the gauge updated each time a connection to the backend is opened or closed.
+func (p *Proxy) SetOpenConnsGauge(gauge metrics.Gauge) {
+	p.openConnsGauge = gauge
+}
+
+// SetMetrics sets the metrics recorded for each connection handled by the proxy.
+func (p *Proxy) SetMetrics(m *ConnMetrics) {
+	p.metrics = m
+}
+
+// ActiveConns returns the number of connections currently handled by the proxy.
+func (p *Proxy) ActiveConns() int64 {
+	return atomic.LoadInt64(&p.activeConns)
+}
+
 // ServeTCP forwards the connection to a service.
 func (p *Proxy) ServeTCP(conn WriteCloser) {
 	log.WithoutContext().Debugf("Handling connection from %s", conn.RemoteAddr())
@@ -53,24 +113,50 @@ func (p *Proxy) ServeTCP(conn WriteCloser) {
 	// needed because of e.g. server.trackedConnection
 	defer conn.Close()
 
-	connBackend, err := p.dialBackend()
+	p.trackConn(1)
+	defer p.trackConn(-1)
+
+	if p.metrics != nil {
+		var done func()
+		conn, done = p.metrics.track(conn)
+		defer done()
+	}
+
+	connBackend, err := p.dial()
 	if err != nil {
 		log.WithoutContext().Errorf("Error while connecting to backend: %v", err)
 		return
 	}
 
 	// maybe not needed, but just in case
-	defer connBackend.Close()
+	defer func() { _ = connBackend.Close() }()
 	errChan := make(chan error)
 
+	// The PROXY protocol header is sent before the TLS handshake, as the backend reads it from the raw connection.
 	if p.proxyProtocol != nil && p.proxyProtocol.Version > 0 && p.proxyProtocol.Version < 3 {
-		header := proxyproto.HeaderProxyFromAddrs(byte(p.proxyProtocol.Version), conn.RemoteAddr(), conn.LocalAddr())
+		header := proxyproto.HeaderProxyFromAddrs(byte(p.proxyProtocol.Version), unwrapAddr(conn.RemoteAddr()), conn.LocalAddr())
+		if p.proxyProtocol.Version == 2 && p.proxyProtocol.TLVs != nil {
+			if err := header.SetTLVs(proxyProtocolTLVs(conn, p.proxyProtocol.TLVs)); err != nil {
+				log.WithoutContext().Errorf("Error while setting proxy protocol TLVs: %v", err)
+				return
+			}
+		}
+
 		if _, err := header.WriteTo(connBackend); err != nil {
 			log.WithoutContext().Errorf("Error while writing proxy protocol headers to backend connection: %v", err)
 			return
 		}
 	}
 
+	if p.tlsConfig != nil {
+		tlsConn, err := p.startTLS(connBackend)
+		if err != nil {
+			log.WithoutContext().Errorf("Error while connecting to backend: %v", err)
+			return
+		}
+		connBackend = tlsConn
+	}
+
 	go p.connCopy(conn, connBackend, errChan)
 	go p.connCopy(connBackend, conn, errChan)
 
@@ -82,9 +168,52 @@ func (p *Proxy) ServeTCP(conn WriteCloser) {
 	<-errChan
 }
 
-func (p Proxy) dialBackend() (*net.TCPConn, error) {
+func (p *Proxy) trackConn(delta int64) {
+	count := atomic.AddInt64(&p.activeConns, delta)
+	if p.openConnsGauge != nil {
+		p.openConnsGauge.Set(float64(count))
+	}
+}
+
+// startTLS originates a TLS connection to the backend over the given connection.
+func (p Proxy) startTLS(conn WriteCloser) (WriteCloser, error) {
+	tlsConn := tls.Client(conn, p.tlsConfig)
+
+	if err := tlsConn.SetDeadline(time.Now().Add(tlsHandshakeTimeout)); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	if err := tlsConn.Handshake(); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("TLS handshake with %s: %w", p.address, err)
+	}
+
+	if err := tlsConn.SetDeadline(time.Time{}); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	return tlsConn, nil
+}
+
+func (p Proxy) dial() (WriteCloser, error) {
+	if p.unixSocket != "" {
+		conn, err := net.DialUnix("unix", nil, &net.UnixAddr{Name: p.unixSocket, Net: "unix"})
+		if err != nil {
+			return nil, err
+		}
+
+		return conn, nil
+	}
+
 	if !p.refreshTarget {
-		return net.DialTCP("tcp", nil, p.target)
+		conn, err := net.DialTCP("tcp", nil, p.target)
+		if err != nil {
+			return nil, err
+		}
+
+		return conn, nil
 	}
 
 	conn, err := net.Dial("tcp", p.address)
@@ -0,0 +1,118 @@
+package tcp
+
+import (
+	"crypto/tls"
+	"net"
+
+	"github.com/pires/go-proxyproto"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// PP2TypeRouterName is the custom PROXY protocol v2 TLV type holding the name of the router which handled the connection.
+const PP2TypeRouterName proxyproto.PP2Type = 0xE0
+
+// ProxyProtocolAddr is the remote address of a connection which started with a PROXY protocol header.
+type ProxyProtocolAddr struct {
+	net.Addr
+
+	// TLVs are the Type-Length-Value vectors of the PROXY protocol v2 header.
+	TLVs []proxyproto.TLV
+}
+
+// ProxyProtocolRemoteAddr returns the remote address of the given connection,
+// along with the TLVs of its PROXY protocol header, if any.
+func ProxyProtocolRemoteAddr(conn *proxyproto.Conn) net.Addr {
+	addr := conn.RemoteAddr()
+
+	header := conn.ProxyHeader()
+	if header == nil {
+		return addr
+	}
+
+	tlvs, err := header.TLVs()
+	if err != nil {
+		log.WithoutContext().Debugf("Error while parsing proxy protocol TLVs from %s: %v", addr, err)
+		return addr
+	}
+
+	if len(tlvs) == 0 {
+		return addr
+	}
+
+	return &ProxyProtocolAddr{Addr: addr, TLVs: tlvs}
+}
+
+// unwrapAddr returns the address wrapped by a ProxyProtocolAddr, or the given address otherwise.
+func unwrapAddr(addr net.Addr) net.Addr {
+	if ppAddr, ok := addr.(*ProxyProtocolAddr); ok {
+		return ppAddr.Addr
+	}
+	return addr
+}
+
+// NewRouterNameHandler returns a Handler forwarding the connections to next,
+// annotated with the name of the router which handled them.
+func NewRouterNameHandler(next Handler, routerName string) Handler {
+	return HandlerFunc(func(conn WriteCloser) {
+		next.ServeTCP(&routedConn{WriteCloser: conn, routerName: routerName})
+	})
+}
+
+// routedConn is a connection annotated with the name of the router which handled it.
+type routedConn struct {
+	WriteCloser
+	routerName string
+}
+
+// NetConn returns the wrapped connection.
+func (c *routedConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
+
+// walkConn calls fn on conn, then on each connection it wraps, until fn returns false.
+func walkConn(conn net.Conn, fn func(net.Conn) bool) {
+	for conn != nil && fn(conn) {
+		wrapper, ok := conn.(interface{ NetConn() net.Conn })
+		if !ok {
+			return
+		}
+		conn = wrapper.NetConn()
+	}
+}
+
+// proxyProtocolTLVs returns the TLVs to append to the PROXY protocol v2 header sent for the given connection.
+func proxyProtocolTLVs(conn net.Conn, opts *dynamic.ProxyProtocolTLVs) []proxyproto.TLV {
+	var routerName, serverName, alpn string
+
+	walkConn(conn, func(c net.Conn) bool {
+		switch typedConn := c.(type) {
+		case *routedConn:
+			if routerName == "" {
+				routerName = typedConn.routerName
+			}
+		case *tls.Conn:
+			state := typedConn.ConnectionState()
+			serverName = state.ServerName
+			alpn = state.NegotiatedProtocol
+			return false
+		case *Conn:
+			serverName = typedConn.ServerName
+			return false
+		}
+		return true
+	})
+
+	var tlvs []proxyproto.TLV
+	if opts.ALPN && alpn != "" {
+		tlvs = append(tlvs, proxyproto.TLV{Type: proxyproto.PP2_TYPE_ALPN, Value: []byte(alpn)})
+	}
+	if opts.SNI && serverName != "" {
+		tlvs = append(tlvs, proxyproto.TLV{Type: proxyproto.PP2_TYPE_AUTHORITY, Value: []byte(serverName)})
+	}
+	if opts.RouterName && routerName != "" {
+		tlvs = append(tlvs, proxyproto.TLV{Type: PP2TypeRouterName, Value: []byte(routerName)})
+	}
+
+	return tlvs
+}
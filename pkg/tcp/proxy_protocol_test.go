@@ -0,0 +1,161 @@
+package tcp
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pires/go-proxyproto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestProxyProtocolTLVs(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		conn     net.Conn
+		opts     *dynamic.ProxyProtocolTLVs
+		expected []proxyproto.TLV
+	}{
+		{
+			desc: "no TLVs enabled",
+			conn: &routedConn{
+				WriteCloser: &Conn{WriteCloser: &fakeConn{}, ServerName: "foo.bar"},
+				routerName:  "router@file",
+			},
+			opts: &dynamic.ProxyProtocolTLVs{},
+		},
+		{
+			desc: "SNI and router name",
+			conn: &routedConn{
+				WriteCloser: &Conn{WriteCloser: &fakeConn{}, ServerName: "foo.bar"},
+				routerName:  "router@file",
+			},
+			opts: &dynamic.ProxyProtocolTLVs{SNI: true, ALPN: true, RouterName: true},
+			expected: []proxyproto.TLV{
+				{Type: proxyproto.PP2_TYPE_AUTHORITY, Value: []byte("foo.bar")},
+				{Type: PP2TypeRouterName, Value: []byte("router@file")},
+			},
+		},
+		{
+			desc: "SNI through a metered connection",
+			conn: &meteredConn{
+				WriteCloser: &routedConn{
+					WriteCloser: &Conn{WriteCloser: &fakeConn{}, ServerName: "foo.bar"},
+					routerName:  "router@file",
+				},
+			},
+			opts: &dynamic.ProxyProtocolTLVs{SNI: true},
+			expected: []proxyproto.TLV{
+				{Type: proxyproto.PP2_TYPE_AUTHORITY, Value: []byte("foo.bar")},
+			},
+		},
+		{
+			desc: "no SNI",
+			conn: &routedConn{
+				WriteCloser: &Conn{WriteCloser: &fakeConn{}},
+				routerName:  "router@file",
+			},
+			opts: &dynamic.ProxyProtocolTLVs{SNI: true},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, proxyProtocolTLVs(test.conn, test.opts))
+		})
+	}
+}
+
+func TestProxyProtocol_TLVs(t *testing.T) {
+	backendListener, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+
+	headers := make(chan *proxyproto.Header, 1)
+	proxyBackendListener := proxyproto.Listener{
+		Listener: backendListener,
+		ValidateHeader: func(h *proxyproto.Header) error {
+			headers <- h
+			return nil
+		},
+	}
+	defer proxyBackendListener.Close()
+
+	go fakeRedis(t, &proxyBackendListener)
+
+	_, port, err := net.SplitHostPort(proxyBackendListener.Addr().String())
+	require.NoError(t, err)
+
+	proxy, err := NewProxy(":"+port, 10*time.Millisecond, &dynamic.ProxyProtocol{
+		Version: 2,
+		TLVs:    &dynamic.ProxyProtocolTLVs{SNI: true, RouterName: true},
+	})
+	require.NoError(t, err)
+
+	proxyListener, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+
+	go func() {
+		for {
+			conn, err := proxyListener.Accept()
+			require.NoError(t, err)
+
+			// Simulates a connection accepted by an entrypoint with the PROXY protocol enabled.
+			ppConn := &proxyProtocolConn{
+				TCPConn: conn.(*net.TCPConn),
+				tlvs:    []proxyproto.TLV{{Type: proxyproto.PP2_TYPE_UNIQUE_ID, Value: []byte("foo")}},
+			}
+			proxy.ServeTCP(&routedConn{
+				WriteCloser: &Conn{WriteCloser: ppConn, ServerName: "foo.bar"},
+				routerName:  "router@file",
+			})
+		}
+	}()
+
+	_, port, err = net.SplitHostPort(proxyListener.Addr().String())
+	require.NoError(t, err)
+
+	conn, err := net.Dial("tcp", ":"+port)
+	require.NoError(t, err)
+
+	_, err = conn.Write([]byte("ping\n"))
+	require.NoError(t, err)
+
+	err = conn.(*net.TCPConn).CloseWrite()
+	require.NoError(t, err)
+
+	var buf []byte
+	buffer := bytes.NewBuffer(buf)
+	_, err = io.Copy(buffer, conn)
+	require.NoError(t, err)
+
+	assert.Equal(t, "PONG", buffer.String())
+
+	header := <-headers
+	assert.Equal(t, conn.LocalAddr().String(), header.SourceAddr.String())
+
+	tlvs, err := header.TLVs()
+	require.NoError(t, err)
+
+	expected := []proxyproto.TLV{
+		{Type: proxyproto.PP2_TYPE_AUTHORITY, Value: []byte("foo.bar")},
+		{Type: PP2TypeRouterName, Value: []byte("router@file")},
+	}
+	assert.Equal(t, expected, tlvs)
+}
+
+type proxyProtocolConn struct {
+	*net.TCPConn
+	tlvs []proxyproto.TLV
+}
+
+func (c *proxyProtocolConn) RemoteAddr() net.Addr {
+	return &ProxyProtocolAddr{Addr: c.TCPConn.RemoteAddr(), TLVs: c.tlvs}
+}
@@ -2,10 +2,12 @@ package tcp
 
 import (
 	"bytes"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
 	"net"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -88,6 +90,108 @@ func TestCloseWrite(t *testing.T) {
 	require.Equal(t, "PONG", buffer.String())
 }
 
+func TestCloseWriteUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "redis.sock")
+
+	backendListener, err := net.Listen("unix", path)
+	require.NoError(t, err)
+
+	go fakeRedis(t, backendListener)
+
+	proxy, err := NewProxy("unix://"+path, 10*time.Millisecond, nil)
+	require.NoError(t, err)
+
+	proxyListener, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+
+	go func() {
+		for {
+			conn, err := proxyListener.Accept()
+			require.NoError(t, err)
+			proxy.ServeTCP(conn.(*net.TCPConn))
+		}
+	}()
+
+	_, port, err := net.SplitHostPort(proxyListener.Addr().String())
+	require.NoError(t, err)
+
+	conn, err := net.Dial("tcp", ":"+port)
+	require.NoError(t, err)
+
+	_, err = conn.Write([]byte("ping\n"))
+	require.NoError(t, err)
+
+	err = conn.(*net.TCPConn).CloseWrite()
+	require.NoError(t, err)
+
+	var buf []byte
+	buffer := bytes.NewBuffer(buf)
+	n, err := io.Copy(buffer, conn)
+	require.NoError(t, err)
+	require.Equal(t, int64(4), n)
+	require.Equal(t, "PONG", buffer.String())
+}
+
+func TestProxy_SetTLSConfig(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		address            string
+		config             *tls.Config
+		expectedServerName string
+		expectedErr        bool
+	}{
+		{
+			desc:               "server name from the address",
+			address:            "localhost:8080",
+			config:             &tls.Config{},
+			expectedServerName: "localhost",
+		},
+		{
+			desc:               "server name from the configuration",
+			address:            "localhost:8080",
+			config:             &tls.Config{ServerName: "example.com"},
+			expectedServerName: "example.com",
+		},
+		{
+			desc:               "unix socket with a server name",
+			address:            "unix:///run/app.sock",
+			config:             &tls.Config{ServerName: "example.com"},
+			expectedServerName: "example.com",
+		},
+		{
+			desc:    "unix socket without verification",
+			address: "unix:///run/app.sock",
+			config:  &tls.Config{InsecureSkipVerify: true},
+		},
+		{
+			desc:        "unix socket without server name",
+			address:     "unix:///run/app.sock",
+			config:      &tls.Config{},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			proxy, err := NewProxy(test.address, 10*time.Millisecond, nil)
+			require.NoError(t, err)
+
+			err = proxy.SetTLSConfig(test.config)
+			if test.expectedErr {
+				assert.Error(t, err)
+				assert.Nil(t, proxy.tlsConfig)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedServerName, proxy.tlsConfig.ServerName)
+		})
+	}
+}
+
 func TestProxyProtocol(t *testing.T) {
 	testCases := []struct {
 		desc    string
@@ -199,7 +303,7 @@ func TestLookupAddress(t *testing.T) {
 
 			require.NotNil(t, proxy.target)
 
-			conn, err := proxy.dialBackend()
+			conn, err := proxy.dial()
 			require.NoError(t, err)
 
 			if test.expectRefresh {
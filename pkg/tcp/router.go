@@ -25,6 +25,13 @@ type Router struct {
 	httpsTLSConfig    *tls.Config // default TLS config
 	catchAllNoTLS     Handler
 	hostHTTPTLSConfig map[string]*tls.Config // TLS configs keyed by SNI
+	connMetrics       *ConnMetrics
+}
+
+// SetConnMetrics sets the metrics recorded for all the connections handled by the router,
+// including the ones forwarded to the HTTP servers, and the ones which are not routed.
+func (r *Router) SetConnMetrics(m *ConnMetrics) {
+	r.connMetrics = m
 }
 
 // GetTLSGetClientInfo is called after a ClientHello is received from a client.
@@ -41,6 +48,11 @@ func (r *Router) GetTLSGetClientInfo() func(info *tls.ClientHelloInfo) (*tls.Con
 func (r *Router) ServeTCP(conn WriteCloser) {
 	// FIXME -- Check if ProxyProtocol changes the first bytes of the request
 
+	// The connections forwarded to the HTTP servers outlive this call, so they are tracked until they are closed.
+	if r.connMetrics != nil {
+		conn = r.connMetrics.trackUntilClose(conn)
+	}
+
 	if r.catchAllNoTLS != nil && len(r.routingTable) == 0 {
 		r.catchAllNoTLS.ServeTCP(conn)
 		return
@@ -80,19 +92,19 @@ func (r *Router) ServeTCP(conn WriteCloser) {
 	serverName = types.CanonicalDomain(serverName)
 	if r.routingTable != nil && serverName != "" {
 		if target, ok := r.routingTable[serverName]; ok {
-			target.ServeTCP(r.GetConn(conn, peeked))
+			target.ServeTCP(r.getTLSConn(conn, peeked, serverName))
 			return
 		}
 	}
 
 	// FIXME Needs tests
 	if target, ok := r.routingTable["*"]; ok {
-		target.ServeTCP(r.GetConn(conn, peeked))
+		target.ServeTCP(r.getTLSConn(conn, peeked, serverName))
 		return
 	}
 
 	if r.httpsForwarder != nil {
-		r.httpsForwarder.ServeTCP(r.GetConn(conn, peeked))
+		r.httpsForwarder.ServeTCP(r.getTLSConn(conn, peeked, serverName))
 	} else {
 		conn.Close()
 	}
@@ -137,6 +149,15 @@ func (r *Router) GetConn(conn WriteCloser, peeked string) WriteCloser {
 	return conn
 }
 
+// getTLSConn creates a connection proxy with a peeked string, for a connection which started with a TLS ClientHello.
+func (r *Router) getTLSConn(conn WriteCloser, peeked, serverName string) WriteCloser {
+	return &Conn{
+		Peeked:      []byte(peeked),
+		ServerName:  serverName,
+		WriteCloser: conn,
+	}
+}
+
 // GetHTTPHandler gets the attached http handler.
 func (r *Router) GetHTTPHandler() http.Handler {
 	return r.httpHandler
@@ -182,6 +203,9 @@ type Conn struct {
 	// by Read calls. It set to nil by Read when fully consumed.
 	Peeked []byte
 
+	// ServerName is the server name (SNI) sent by the client in its TLS ClientHello, if any.
+	ServerName string
+
 	// Conn is the underlying connection.
 	// It can be type asserted against *net.TCPConn or other types
 	// as needed. It should not be read from directly unless
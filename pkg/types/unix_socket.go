@@ -0,0 +1,38 @@
+package types
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// UnixSocketScheme is the scheme of the addresses of unix sockets, e.g. unix:///run/traefik.sock.
+const UnixSocketScheme = "unix"
+
+const unixSocketPrefix = UnixSocketScheme + "://"
+
+// UnixSocketPath returns the path of the unix socket of the given address,
+// and whether the address is the one of a unix socket.
+func UnixSocketPath(address string) (string, bool) {
+	if !strings.HasPrefix(address, unixSocketPrefix) {
+		return "", false
+	}
+
+	return strings.TrimPrefix(address, unixSocketPrefix), true
+}
+
+// UnixSocketHost returns the host standing for the unix socket of the given path in the URLs of the HTTP requests,
+// so that the HTTP transports pool the connections per unix socket.
+func UnixSocketHost(path string) string {
+	return hex.EncodeToString([]byte(path))
+}
+
+// UnixSocketHostPath returns the path of the unix socket the given host stands for.
+func UnixSocketHostPath(host string) (string, error) {
+	path, err := hex.DecodeString(host)
+	if err != nil {
+		return "", fmt.Errorf("invalid unix socket host %q: %w", host, err)
+	}
+
+	return string(path), nil
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"net"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -188,6 +189,11 @@ func (l *Listener) newConn(rAddr net.Addr) *Conn {
 
 // Conn represents an on-going session with a client, over UDP packets.
 type Conn struct {
+	// bytesRead and bytesWritten are accessed atomically,
+	// and are kept first in the struct to guarantee their 64-bit alignment.
+	bytesRead    int64
+	bytesWritten int64
+
 	listener *Listener
 	rAddr    net.Addr
 
@@ -202,6 +208,9 @@ type Conn struct {
 	timeout  time.Duration // for timeouts
 	doneOnce sync.Once
 	doneCh   chan struct{}
+
+	muValues sync.RWMutex
+	values   map[interface{}]interface{} // the values attached to the session by the handlers
 }
 
 // readLoop waits for data to come from the listener's readLoop.
@@ -254,6 +263,7 @@ func (c *Conn) Read(p []byte) (int, error) {
 	select {
 	case c.readCh <- p:
 		n := <-c.sizeCh
+		atomic.AddInt64(&c.bytesRead, int64(n))
 		c.muActivity.Lock()
 		c.lastActivity = time.Now()
 		c.muActivity.Unlock()
@@ -273,7 +283,45 @@ func (c *Conn) Write(p []byte) (n int, err error) {
 	c.muActivity.Lock()
 	c.lastActivity = time.Now()
 	c.muActivity.Unlock()
-	return l.pConn.WriteTo(p, c.rAddr)
+
+	n, err = l.pConn.WriteTo(p, c.rAddr)
+	atomic.AddInt64(&c.bytesWritten, int64(n))
+	return n, err
+}
+
+// RemoteAddr returns the address of the client.
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.rAddr
+}
+
+// BytesRead returns the number of bytes received from the client so far.
+func (c *Conn) BytesRead() int64 {
+	return atomic.LoadInt64(&c.bytesRead)
+}
+
+// BytesWritten returns the number of bytes sent to the client so far.
+func (c *Conn) BytesWritten() int64 {
+	return atomic.LoadInt64(&c.bytesWritten)
+}
+
+// SetValue attaches the given value to the session, under the given key,
+// so that the handlers serving the session can share data, as they would with a context.
+func (c *Conn) SetValue(key, value interface{}) {
+	c.muValues.Lock()
+	defer c.muValues.Unlock()
+
+	if c.values == nil {
+		c.values = make(map[interface{}]interface{})
+	}
+	c.values[key] = value
+}
+
+// Value returns the value attached to the session under the given key, or nil if there is none.
+func (c *Conn) Value(key interface{}) interface{} {
+	c.muValues.RLock()
+	defer c.muValues.RUnlock()
+
+	return c.values[key]
 }
 
 func (c *Conn) close() {
@@ -0,0 +1,77 @@
+package udp
+
+import (
+	"io"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+)
+
+// NewMetricsHandler returns a Handler incrementing the given sessions counter
+// for each session before forwarding it to next.
+func NewMetricsHandler(next Handler, sessionsCounter gokitmetrics.Counter) Handler {
+	return HandlerFunc(func(conn *Conn) {
+		sessionsCounter.Add(1)
+
+		next.ServeUDP(conn)
+	})
+}
+
+// ServiceMetrics holds the metrics recorded for the sessions handled by a UDP service.
+type ServiceMetrics struct {
+	SessionsCounter gokitmetrics.Counter
+	// DatagramsCounter and BytesCounter are partitioned by direction:
+	// "in" for the datagrams received from the client, and "out" for the ones sent to it.
+	DatagramsCounter gokitmetrics.Counter
+	BytesCounter     gokitmetrics.Counter
+	// Labels are the label names and values shared by all the metrics.
+	Labels []string
+}
+
+// NewServiceMetrics returns the metrics of the UDP sessions handled on a service.
+func NewServiceMetrics(registry metrics.Registry, serviceName string) *ServiceMetrics {
+	return &ServiceMetrics{
+		SessionsCounter:  registry.UDPServiceSessionsCounter(),
+		DatagramsCounter: registry.UDPServiceDatagramsCounter(),
+		BytesCounter:     registry.UDPServiceBytesCounter(),
+		Labels:           []string{"service", serviceName},
+	}
+}
+
+func (m *ServiceMetrics) withLabels(labelValues ...string) []string {
+	labels := make([]string, 0, len(m.Labels)+len(labelValues))
+	labels = append(labels, m.Labels...)
+	return append(labels, labelValues...)
+}
+
+// meteredReader counts the datagrams read from the client.
+type meteredReader struct {
+	io.Reader
+	datagrams gokitmetrics.Counter
+	bytes     gokitmetrics.Counter
+}
+
+func (r *meteredReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if n > 0 {
+		r.datagrams.Add(1)
+		r.bytes.Add(float64(n))
+	}
+	return n, err
+}
+
+// meteredWriteCloser counts the datagrams written to the client.
+type meteredWriteCloser struct {
+	io.WriteCloser
+	datagrams gokitmetrics.Counter
+	bytes     gokitmetrics.Counter
+}
+
+func (w *meteredWriteCloser) Write(p []byte) (int, error) {
+	n, err := w.WriteCloser.Write(p)
+	if n > 0 {
+		w.datagrams.Add(1)
+		w.bytes.Add(float64(n))
+	}
+	return n, err
+}
@@ -10,7 +10,8 @@ import (
 // Proxy is a reverse-proxy implementation of the Handler interface.
 type Proxy struct {
 	// TODO: maybe optimize by pre-resolving it at proxy creation time
-	target string
+	target  string
+	metrics *ServiceMetrics
 }
 
 // NewProxy creates a new Proxy.
@@ -18,6 +19,11 @@ func NewProxy(address string) (*Proxy, error) {
 	return &Proxy{target: address}, nil
 }
 
+// SetMetrics sets the metrics recorded for each session handled by the proxy.
+func (p *Proxy) SetMetrics(m *ServiceMetrics) {
+	p.metrics = m
+}
+
 // ServeUDP implements the Handler interface.
 func (p *Proxy) ServeUDP(conn *Conn) {
 	log.Debugf("Handling connection from %s", conn.rAddr)
@@ -34,9 +40,26 @@ func (p *Proxy) ServeUDP(conn *Conn) {
 	// maybe not needed, but just in case
 	defer connBackend.Close()
 
+	var src io.Reader = conn
+	var dst io.WriteCloser = conn
+	if p.metrics != nil {
+		p.metrics.SessionsCounter.With(p.metrics.Labels...).Add(1)
+
+		src = &meteredReader{
+			Reader:    conn,
+			datagrams: p.metrics.DatagramsCounter.With(p.metrics.withLabels("direction", "in")...),
+			bytes:     p.metrics.BytesCounter.With(p.metrics.withLabels("direction", "in")...),
+		}
+		dst = &meteredWriteCloser{
+			WriteCloser: conn,
+			datagrams:   p.metrics.DatagramsCounter.With(p.metrics.withLabels("direction", "out")...),
+			bytes:       p.metrics.BytesCounter.With(p.metrics.withLabels("direction", "out")...),
+		}
+	}
+
 	errChan := make(chan error)
-	go p.connCopy(conn, connBackend, errChan)
-	go p.connCopy(connBackend, conn, errChan)
+	go p.connCopy(dst, connBackend, errChan)
+	go p.connCopy(connBackend, src, errChan)
 
 	err = <-errChan
 	if err != nil {